@@ -23,6 +23,7 @@ import (
 	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 	"github.com/Juniper/contrail-operator/pkg/controller"
 	"github.com/Juniper/contrail-operator/pkg/controller/contrailcni"
+	"github.com/Juniper/contrail-operator/pkg/controller/contrailmonitor"
 	"github.com/Juniper/contrail-operator/pkg/controller/kubemanager"
 	"github.com/Juniper/contrail-operator/pkg/k8s"
 	"github.com/Juniper/contrail-operator/pkg/openshift"
@@ -101,7 +102,13 @@ func main() {
 
 	var kubemanagerClusterInfo v1alpha1.KubemanagerClusterInfo
 	var cniClusterInfo v1alpha1.CNIClusterInfo
-	if os.Getenv("CLUSTER_TYPE") == "Openshift" {
+	isOpenShift, err := openshift.IsOpenShift(clientset.Discovery())
+	if err != nil {
+		log.Error(err, "Cluster type could not be discovered")
+		os.Exit(1)
+	}
+	if isOpenShift {
+		log.Info("Discovered OpenShift cluster.")
 		dynamicClient, err := dynamic.NewForConfig(cfg)
 		if err != nil {
 			log.Error(err, "Dynamic client could not be gathered")
@@ -136,6 +143,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := contrailmonitor.Add(mgr, isOpenShift); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	log.Info("Starting the Cmd.")
 
 	// Start the Cmd
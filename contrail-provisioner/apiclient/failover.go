@@ -0,0 +1,285 @@
+// Package apiclient provides a Contrail API client which fails over between API servers
+// and refreshes keystone tokens rejected by the API server.
+package apiclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+)
+
+var apiClientInfoLog = log.New(os.Stdout, fmt.Sprintf("%-15s ", "apiclient:"), log.LstdFlags|log.Lmsgprefix)
+
+// Time for which an API server is skipped after it failed to respond
+const defaultCooldown = 30 * time.Second
+
+// FailoverClient sends requests to API servers in a round-robin fashion. When an API
+// server is unavailable the request is sent to the next one and the failed server is
+// skipped for a cooldown period. Create requests are not idempotent, so they are sent to
+// the next server only when the connection failed. Requests rejected with 401 Unauthorized are retried
+// once with a new keystone token. It is safe for concurrent use.
+type FailoverClient struct {
+	servers  []*server
+	auth     *KeystoneAuthenticator
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	next int
+}
+
+type server struct {
+	client   *contrail.Client
+	failures int
+	retryAt  time.Time
+}
+
+var _ contrailclient.ApiClient = &FailoverClient{}
+
+// NewFailoverClient returns client which uses the given API server clients. When auth is
+// not nil it is set as authenticator of all of them.
+func NewFailoverClient(clients []*contrail.Client, auth *KeystoneAuthenticator) *FailoverClient {
+	c := &FailoverClient{auth: auth, cooldown: defaultCooldown, now: time.Now}
+	for _, client := range clients {
+		if auth != nil {
+			client.SetAuthenticator(auth)
+		}
+		c.servers = append(c.servers, &server{client: client})
+	}
+	return c
+}
+
+// NewTLSConfig returns TLS configuration using the given CA and client certificate files.
+func NewTLSConfig(caFile, keyFile, certFile string, insecure bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if insecure {
+		tlsConfig.InsecureSkipVerify = true
+		return tlsConfig, nil
+	}
+	if caFile != "" {
+		caCert, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = caCertPool
+	}
+	if certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// do sends the request to API servers until one of them handles it. Requests which are
+// not idempotent are sent to the next server only when the connection to the previous
+// one could not be established, so that they are never made twice.
+func (c *FailoverClient) do(idempotent bool, call func(*contrail.Client) error) error {
+	if len(c.servers) == 0 {
+		return errors.New("no API servers configured")
+	}
+	var err error
+	for _, s := range c.order() {
+		err = c.callServer(s, call)
+		if !unavailable(err) {
+			c.markAvailable(s)
+			return err
+		}
+		c.markUnavailable(s, err)
+		if !idempotent && !notConnected(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *FailoverClient) callServer(s *server, call func(*contrail.Client) error) error {
+	err := call(s.client)
+	if c.auth != nil && unauthorized(err) {
+		apiClientInfoLog.Printf("Token rejected by %s, authenticating again\n", s.client.GetServer())
+		c.auth.Invalidate()
+		err = call(s.client)
+	}
+	return err
+}
+
+// order returns servers in the order in which they should be tried: available servers
+// starting from the next one in turn, then servers in cooldown, soonest to recover first.
+func (c *FailoverClient) order() []*server {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	var available, cooling []*server
+	for i := range c.servers {
+		s := c.servers[(c.next+i)%len(c.servers)]
+		if now.Before(s.retryAt) {
+			cooling = append(cooling, s)
+		} else {
+			available = append(available, s)
+		}
+	}
+	c.next = (c.next + 1) % len(c.servers)
+	sort.SliceStable(cooling, func(i, j int) bool { return cooling[i].retryAt.Before(cooling[j].retryAt) })
+	return append(available, cooling...)
+}
+
+func (c *FailoverClient) markUnavailable(s *server, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s.failures++
+	s.retryAt = c.now().Add(c.cooldown)
+	apiClientInfoLog.Printf("API server %s unavailable (%d failures): %v\n", s.client.GetServer(), s.failures, err)
+}
+
+func (c *FailoverClient) markAvailable(s *server) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s.failures > 0 {
+		apiClientInfoLog.Printf("API server %s available again\n", s.client.GetServer())
+	}
+	s.failures = 0
+	s.retryAt = time.Time{}
+}
+
+// unavailable returns true when the request did not reach the API server or the API
+// server could not handle it, so that it is worth sending it to another one.
+func unavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return true
+	}
+	for _, status := range []string{"502 ", "503 ", "504 "} {
+		if strings.HasPrefix(err.Error(), status) {
+			return true
+		}
+	}
+	return false
+}
+
+// notConnected returns true when the connection to the API server could not be
+// established, so that the request was not sent.
+func notConnected(err error) bool {
+	var opError *net.OpError
+	return errors.As(err, &opError) && opError.Op == "dial"
+}
+
+func unauthorized(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "401 ")
+}
+
+func (c *FailoverClient) Create(ptr contrail.IObject) error {
+	return c.do(false, func(client *contrail.Client) error {
+		return client.Create(ptr)
+	})
+}
+
+func (c *FailoverClient) Update(ptr contrail.IObject) error {
+	return c.do(true, func(client *contrail.Client) error {
+		return client.Update(ptr)
+	})
+}
+
+func (c *FailoverClient) DeleteByUuid(typename, uuid string) error {
+	return c.do(true, func(client *contrail.Client) error {
+		return client.DeleteByUuid(typename, uuid)
+	})
+}
+
+func (c *FailoverClient) Delete(ptr contrail.IObject) error {
+	return c.do(true, func(client *contrail.Client) error {
+		return client.Delete(ptr)
+	})
+}
+
+func (c *FailoverClient) FindByUuid(typename string, uuid string) (obj contrail.IObject, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		obj, err = client.FindByUuid(typename, uuid)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) UuidByName(typename string, fqn string) (uuid string, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		uuid, err = client.UuidByName(typename, fqn)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) FQNameByUuid(uuid string) (fqName []string, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		fqName, err = client.FQNameByUuid(uuid)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) FindByName(typename string, fqn string) (obj contrail.IObject, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		obj, err = client.FindByName(typename, fqn)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) List(typename string) (results []contrail.ListResult, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		results, err = client.List(typename)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) ListByParent(typename string, parentID string) (results []contrail.ListResult, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		results, err = client.ListByParent(typename, parentID)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) ListDetail(typename string, fields []string) (objs []contrail.IObject, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		objs, err = client.ListDetail(typename, fields)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) ListDetailByParent(typename string, parentID string, fields []string) (objs []contrail.IObject, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		objs, err = client.ListDetailByParent(typename, parentID, fields)
+		return
+	})
+	return
+}
+
+func (c *FailoverClient) ReadListResult(typename string, result *contrail.ListResult) (obj contrail.IObject, err error) {
+	err = c.do(true, func(client *contrail.Client) (err error) {
+		obj, err = client.ReadListResult(typename, result)
+		return
+	})
+	return
+}
@@ -0,0 +1,161 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+)
+
+func newContrailClient(t *testing.T, server *httptest.Server) *contrail.Client {
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	return contrail.NewClient(u.Hostname(), port)
+}
+
+func newAPIServer(requests *int32, handler http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		handler(w, r)
+	}))
+}
+
+func listResponse(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"global-system-configs": []interface{}{}})
+}
+
+func newKeystone(t *testing.T, tokens *int32, expiresIn time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/auth/tokens", r.URL.Path)
+		token := atomic.AddInt32(tokens, 1)
+		now := time.Now().UTC()
+		w.Header().Set("X-Subject-Token", "token-"+strconv.Itoa(int(token)))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"token": map[string]interface{}{
+				"issued_at":  now.Format(time.RFC3339),
+				"expires_at": now.Add(expiresIn).Format(time.RFC3339),
+			},
+		})
+	}))
+}
+
+func TestFailoverClientFailsOverToAvailableServer(t *testing.T) {
+	var downRequests, upRequests int32
+	down := newAPIServer(&downRequests, listResponse)
+	downClient := newContrailClient(t, down)
+	down.Close()
+	up := newAPIServer(&upRequests, listResponse)
+	defer up.Close()
+	client := NewFailoverClient([]*contrail.Client{downClient, newContrailClient(t, up)}, nil)
+
+	for i := 0; i < 4; i++ {
+		_, err := client.List("global-system-config")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(4), upRequests)
+	assert.Equal(t, 1, client.servers[0].failures, "server in cooldown should not be retried")
+}
+
+func TestFailoverClientBalancesRequests(t *testing.T) {
+	var firstRequests, secondRequests int32
+	first := newAPIServer(&firstRequests, listResponse)
+	defer first.Close()
+	second := newAPIServer(&secondRequests, listResponse)
+	defer second.Close()
+	client := NewFailoverClient([]*contrail.Client{newContrailClient(t, first), newContrailClient(t, second)}, nil)
+
+	for i := 0; i < 4; i++ {
+		_, err := client.List("global-system-config")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), firstRequests)
+	assert.Equal(t, int32(2), secondRequests)
+}
+
+func TestFailoverClientDoesNotFailOverOnClientErrors(t *testing.T) {
+	var firstRequests, secondRequests int32
+	notFound := func(w http.ResponseWriter, r *http.Request) { http.Error(w, "not found", http.StatusNotFound) }
+	first := newAPIServer(&firstRequests, notFound)
+	defer first.Close()
+	second := newAPIServer(&secondRequests, notFound)
+	defer second.Close()
+	client := NewFailoverClient([]*contrail.Client{newContrailClient(t, first), newContrailClient(t, second)}, nil)
+
+	_, err := client.List("global-system-config")
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), firstRequests+secondRequests)
+}
+
+func TestFailoverClientFailsOverCreateOnlyWhenNotConnected(t *testing.T) {
+	var firstRequests, secondRequests int32
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+	}
+	first := newAPIServer(&firstRequests, unavailable)
+	defer first.Close()
+	second := newAPIServer(&secondRequests, unavailable)
+	defer second.Close()
+	client := NewFailoverClient([]*contrail.Client{newContrailClient(t, first), newContrailClient(t, second)}, nil)
+
+	err := client.Create(&contrailtypes.GlobalSystemConfig{})
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), firstRequests+secondRequests, "create handled by an API server should not be sent again")
+
+	var downRequests, upRequests int32
+	down := newAPIServer(&downRequests, unavailable)
+	downClient := newContrailClient(t, down)
+	down.Close()
+	up := newAPIServer(&upRequests, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"global-system-config": map[string]interface{}{
+			"fq_name": []string{"default-global-system-config"},
+			"uuid":    "uuid-1",
+			"name":    "default-global-system-config",
+		}})
+	})
+	defer up.Close()
+	client = NewFailoverClient([]*contrail.Client{downClient, newContrailClient(t, up)}, nil)
+
+	require.NoError(t, client.Create(&contrailtypes.GlobalSystemConfig{}))
+	assert.Equal(t, int32(1), upRequests)
+}
+
+func TestFailoverClientRefreshesRejectedToken(t *testing.T) {
+	var tokens, requests int32
+	keystone := newKeystone(t, &tokens, time.Hour)
+	defer keystone.Close()
+	api := newAPIServer(&requests, func(w http.ResponseWriter, r *http.Request) {
+		// The API server revokes the first token
+		if r.Header.Get("X-Auth-Token") == "token-1" {
+			http.Error(w, "token expired", http.StatusUnauthorized)
+			return
+		}
+		listResponse(w, r)
+	})
+	defer api.Close()
+	auth := NewKeystoneAuthenticator(KeystoneConfig{AuthURL: keystone.URL + "/v3/auth", Username: "admin", Password: "secret"})
+	client := NewFailoverClient([]*contrail.Client{newContrailClient(t, api)}, auth)
+
+	_, err := client.List("global-system-config")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), tokens)
+	assert.Equal(t, int32(2), requests)
+}
@@ -0,0 +1,144 @@
+package apiclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeystoneConfig contains credentials used to obtain keystone tokens. When
+// ApplicationCredentialSecret is set, the application credential given by ID, or by
+// name together with Username, is used instead of the password. UserDomain is the ID
+// of the domain of Username.
+type KeystoneConfig struct {
+	// AuthURL is the keystone v3 auth endpoint, e.g. https://keystone:5000/v3/auth
+	AuthURL                     string
+	Username                    string
+	Password                    string
+	ApplicationCredentialID     string
+	ApplicationCredentialName   string
+	ApplicationCredentialSecret string
+	UserDomain                  string
+	TLSConfig                   *tls.Config
+}
+
+// KeystoneAuthenticator adds keystone tokens to requests made to the Contrail API
+// server. A token is refreshed when half of its lifetime passed or when it is
+// invalidated after the API server rejected it. It is safe for concurrent use.
+type KeystoneAuthenticator struct {
+	config     KeystoneConfig
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+// NewKeystoneAuthenticator returns authenticator which obtains tokens with the given
+// credentials.
+func NewKeystoneAuthenticator(config KeystoneConfig) *KeystoneAuthenticator {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if config.TLSConfig != nil {
+		httpClient.Transport = &http.Transport{TLSClientConfig: config.TLSConfig}
+	}
+	return &KeystoneAuthenticator{config: config, httpClient: httpClient, now: time.Now}
+}
+
+// AddAuthentication implements contrail.Authenticator.
+func (k *KeystoneAuthenticator) AddAuthentication(req *http.Request) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.token == "" || !k.now().Before(k.refreshAt) {
+		if err := k.authenticate(); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("X-Auth-Token", k.token)
+	return nil
+}
+
+// Invalidate drops the current token so that a new one is obtained for the next request.
+func (k *KeystoneAuthenticator) Invalidate() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.token = ""
+}
+
+type keystoneTokenResponse struct {
+	Token struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		IssuedAt  time.Time `json:"issued_at"`
+	} `json:"token"`
+}
+
+func (k *KeystoneAuthenticator) authenticate() error {
+	data, err := json.Marshal(k.authRequest())
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(k.config.AuthURL, "/") + "/tokens"
+	resp, err := k.httpClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("keystone authentication failed: %s: %s", resp.Status, body)
+	}
+	var response keystoneTokenResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	k.token = resp.Header.Get("X-Subject-Token")
+	if k.token == "" {
+		return fmt.Errorf("keystone authentication failed: no X-Subject-Token in response")
+	}
+	issuedAt := response.Token.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = k.now()
+	}
+	k.refreshAt = issuedAt.Add(response.Token.ExpiresAt.Sub(issuedAt) / 2)
+	return nil
+}
+
+func (k *KeystoneAuthenticator) authRequest() map[string]interface{} {
+	identity := map[string]interface{}{}
+	auth := map[string]interface{}{"identity": identity}
+	if k.config.ApplicationCredentialSecret != "" {
+		credential := map[string]interface{}{"secret": k.config.ApplicationCredentialSecret}
+		if k.config.ApplicationCredentialID != "" {
+			credential["id"] = k.config.ApplicationCredentialID
+		} else {
+			credential["name"] = k.config.ApplicationCredentialName
+			credential["user"] = map[string]interface{}{
+				"name":   k.config.Username,
+				"domain": map[string]string{"id": k.config.UserDomain},
+			}
+		}
+		identity["methods"] = []string{"application_credential"}
+		identity["application_credential"] = credential
+		// Scope of application credentials is fixed when they are created
+		return map[string]interface{}{"auth": auth}
+	}
+	identity["methods"] = []string{"password"}
+	identity["password"] = map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":     k.config.Username,
+			"password": k.config.Password,
+			"domain":   map[string]string{"id": k.config.UserDomain},
+		},
+	}
+	auth["scope"] = map[string]interface{}{"system": map[string]bool{"all": true}}
+	return map[string]interface{}{"auth": auth}
+}
@@ -0,0 +1,61 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystoneAuthenticatorRefreshesTokenAfterHalfOfLifetime(t *testing.T) {
+	var tokens int32
+	keystone := newKeystone(t, &tokens, time.Hour)
+	defer keystone.Close()
+	auth := NewKeystoneAuthenticator(KeystoneConfig{AuthURL: keystone.URL + "/v3/auth", Username: "admin", Password: "secret"})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	require.NoError(t, auth.AddAuthentication(req))
+	require.NoError(t, auth.AddAuthentication(req))
+	assert.Equal(t, "token-1", req.Header.Get("X-Auth-Token"))
+
+	auth.now = func() time.Time { return time.Now().Add(31 * time.Minute) }
+	require.NoError(t, auth.AddAuthentication(req))
+	assert.Equal(t, "token-2", req.Header.Get("X-Auth-Token"))
+}
+
+func TestKeystoneAuthenticatorRequests(t *testing.T) {
+	t.Run("password", func(t *testing.T) {
+		auth := NewKeystoneAuthenticator(KeystoneConfig{Username: "admin", Password: "secret", UserDomain: "default"})
+		request := toJSON(t, auth.authRequest())
+		assert.JSONEq(t, `{"auth": {
+			"identity": {"methods": ["password"], "password": {"user": {"name": "admin", "password": "secret", "domain": {"id": "default"}}}},
+			"scope": {"system": {"all": true}}
+		}}`, request)
+	})
+	t.Run("application credential by id", func(t *testing.T) {
+		auth := NewKeystoneAuthenticator(KeystoneConfig{ApplicationCredentialID: "id", ApplicationCredentialSecret: "secret"})
+		request := toJSON(t, auth.authRequest())
+		assert.JSONEq(t, `{"auth": {
+			"identity": {"methods": ["application_credential"], "application_credential": {"id": "id", "secret": "secret"}}
+		}}`, request)
+	})
+	t.Run("application credential by name", func(t *testing.T) {
+		auth := NewKeystoneAuthenticator(KeystoneConfig{Username: "provisioner", ApplicationCredentialName: "name", ApplicationCredentialSecret: "secret", UserDomain: "services"})
+		request := toJSON(t, auth.authRequest())
+		assert.JSONEq(t, `{"auth": {
+			"identity": {"methods": ["application_credential"], "application_credential": {
+				"name": "name", "secret": "secret", "user": {"name": "provisioner", "domain": {"id": "services"}}
+			}}
+		}}`, request)
+	})
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
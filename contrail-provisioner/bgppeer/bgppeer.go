@@ -0,0 +1,243 @@
+package bgppeer
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+)
+
+// BgpPeer struct defines an external BGP router peering with Contrail control nodes
+type BgpPeer struct {
+	contrailnode.Node `yaml:",inline"`
+	ASN               int      `yaml:"asn,omitempty"`
+	Vendor            string   `yaml:"vendor,omitempty"`
+	Port              int      `yaml:"port,omitempty"`
+	HoldTime          int      `yaml:"holdTime,omitempty"`
+	AddressFamilies   []string `yaml:"addressFamilies,omitempty"`
+	AuthKey           string   `yaml:"authKey,omitempty" diff:"secret"`
+	// PeeredWithControlNodes is set when the peer has BGP sessions with all control nodes.
+	// It is not read from the input, as peers are always peered with all control nodes.
+	PeeredWithControlNodes bool `yaml:"-"`
+}
+
+const (
+	nodeType            contrailnode.ContrailNodeType = contrailnode.BgpPeer
+	bgpRouterType       string                        = "bgp-router"
+	peerRouterType      string                        = "router"
+	controlNodeType     string                        = "control-node"
+	defaultVendor       string                        = "contrail"
+	defaultPort         int                           = 179
+	defaultHoldTime     int                           = 90
+	md5AuthKeyType      string                        = "md5"
+	routingInstanceType string                        = "routing-instance"
+)
+
+var defaultAddressFamilies = []string{"route-target", "inet-vpn", "inet6-vpn", "e-vpn", "erm-vpn"}
+
+var bgpPeerInfoLog *log.Logger
+
+func init() {
+	prefix := fmt.Sprintf("%-15s ", nodeType+":")
+	bgpPeerInfoLog = log.New(os.Stdout, prefix, log.LstdFlags|log.Lmsgprefix)
+}
+
+// Create creates a BgpRouter instance for the peer and peers it with all control nodes
+func (c *BgpPeer) Create(contrailClient contrailclient.ApiClient) error {
+	bgpPeerInfoLog.Printf("Creating %s %s\n", c.Hostname, nodeType)
+	bgpRouter := &contrailtypes.BgpRouter{}
+	bgpRouter.SetFQName(routingInstanceType, fqName(c.Hostname))
+	if err := c.setParameters(contrailClient, bgpRouter); err != nil {
+		return err
+	}
+	return contrailClient.Create(bgpRouter)
+}
+
+// Update updates a BgpRouter instance of the peer
+func (c *BgpPeer) Update(contrailClient contrailclient.ApiClient) error {
+	bgpPeerInfoLog.Printf("Updating %s %s\n", c.Hostname, nodeType)
+	obj, err := contrailclient.GetContrailObjectByName(contrailClient, bgpRouterType, c.Hostname)
+	if err != nil {
+		return err
+	}
+	bgpRouter := obj.(*contrailtypes.BgpRouter)
+	if err := c.setParameters(contrailClient, bgpRouter); err != nil {
+		return err
+	}
+	return contrailClient.Update(bgpRouter)
+}
+
+// Delete deletes a BgpRouter instance of the peer
+func (c *BgpPeer) Delete(contrailClient contrailclient.ApiClient) error {
+	bgpPeerInfoLog.Printf("Deleting %s %s\n", c.Hostname, nodeType)
+	obj, err := contrailclient.GetContrailObjectByName(contrailClient, bgpRouterType, c.Hostname)
+	if err != nil {
+		return err
+	}
+	return contrailClient.Delete(obj)
+}
+
+func (c *BgpPeer) GetHostname() string {
+	return c.Hostname
+}
+
+func (c *BgpPeer) GetAnnotations() map[string]string {
+	return c.Annotations
+}
+
+func (c *BgpPeer) SetAnnotations(annotations map[string]string) {
+	c.Annotations = annotations
+}
+
+func (c *BgpPeer) setParameters(contrailClient contrailclient.ApiClient, bgpRouter *contrailtypes.BgpRouter) error {
+	annotations := contrailclient.ConvertMapToContrailKeyValuePairs(c.Annotations)
+	bgpRouter.SetAnnotations(&annotations)
+	bgpRouter.SetBgpRouterParameters(c.bgpRouterParams())
+	controlNodes, err := getControlNodeRouters(contrailClient)
+	if err != nil {
+		return err
+	}
+	var peers []contrail.ReferencePair
+	for _, controlNode := range controlNodes {
+		peers = append(peers, contrail.ReferencePair{Object: controlNode, Attribute: contrailtypes.BgpPeeringAttributes{}})
+	}
+	bgpRouter.SetBgpRouterList(peers)
+	return nil
+}
+
+// UnmarshalYAML sets defaults of fields missing in the peer definition, so that the peer
+// compares equal to its BgpRouter read from the API server.
+func (c *BgpPeer) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawBgpPeer BgpPeer
+	raw := rawBgpPeer{
+		Vendor:          defaultVendor,
+		Port:            defaultPort,
+		HoldTime:        defaultHoldTime,
+		AddressFamilies: defaultAddressFamilies,
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	raw.PeeredWithControlNodes = true
+	*c = BgpPeer(raw)
+	return nil
+}
+
+func (c *BgpPeer) bgpRouterParams() *contrailtypes.BgpRouterParams {
+	params := &contrailtypes.BgpRouterParams{
+		Address:          c.IPAddress,
+		Identifier:       c.IPAddress,
+		AutonomousSystem: c.ASN,
+		Vendor:           c.Vendor,
+		RouterType:       peerRouterType,
+		Port:             c.Port,
+		HoldTime:         c.HoldTime,
+		AddressFamilies:  &contrailtypes.AddressFamilies{Family: c.AddressFamilies},
+	}
+	if c.AuthKey != "" {
+		params.AuthData = &contrailtypes.AuthenticationData{
+			KeyType:  md5AuthKeyType,
+			KeyItems: []contrailtypes.AuthenticationKeyItem{{KeyId: 0, Key: c.AuthKey}},
+		}
+	}
+	return params
+}
+
+// GetContrailNodesFromApiServer returns BGP routers which are not control nodes
+func GetContrailNodesFromApiServer(contrailClient contrailclient.ApiClient) ([]contrailnode.ContrailNode, error) {
+	nodesInApiServer := []contrailnode.ContrailNode{}
+	bgpRouters, err := getBgpRouters(contrailClient)
+	if err != nil {
+		return nodesInApiServer, err
+	}
+	controlNodes := map[string]bool{}
+	for _, bgpRouter := range bgpRouters {
+		if bgpRouter.GetBgpRouterParameters().RouterType == controlNodeType {
+			controlNodes[bgpRouter.GetUuid()] = true
+		}
+	}
+	for _, bgpRouter := range bgpRouters {
+		params := bgpRouter.GetBgpRouterParameters()
+		if params.RouterType == controlNodeType {
+			continue
+		}
+		peeredWithControlNodes, err := isPeeredWith(bgpRouter, controlNodes)
+		if err != nil {
+			return nodesInApiServer, err
+		}
+		node := &BgpPeer{
+			Node: contrailnode.Node{
+				IPAddress:   params.Address,
+				Hostname:    bgpRouter.GetName(),
+				Annotations: contrailclient.ConvertContrailKeyValuePairsToMap(bgpRouter.GetAnnotations()),
+			},
+			ASN:      params.AutonomousSystem,
+			Vendor:   params.Vendor,
+			Port:     params.Port,
+			HoldTime: params.HoldTime,
+
+			PeeredWithControlNodes: peeredWithControlNodes,
+		}
+		if params.AddressFamilies != nil {
+			node.AddressFamilies = params.AddressFamilies.Family
+		}
+		if params.AuthData != nil && len(params.AuthData.KeyItems) > 0 {
+			node.AuthKey = params.AuthData.KeyItems[0].Key
+		}
+		nodesInApiServer = append(nodesInApiServer, node)
+	}
+	return nodesInApiServer, nil
+}
+
+func isPeeredWith(bgpRouter *contrailtypes.BgpRouter, routers map[string]bool) (bool, error) {
+	refs, err := bgpRouter.GetBgpRouterRefs()
+	if err != nil {
+		return false, err
+	}
+	peered := map[string]bool{}
+	for _, ref := range refs {
+		if routers[ref.Uuid] {
+			peered[ref.Uuid] = true
+		}
+	}
+	return len(peered) == len(routers), nil
+}
+
+func getControlNodeRouters(contrailClient contrailclient.ApiClient) ([]*contrailtypes.BgpRouter, error) {
+	bgpRouters, err := getBgpRouters(contrailClient)
+	if err != nil {
+		return nil, err
+	}
+	var controlNodes []*contrailtypes.BgpRouter
+	for _, bgpRouter := range bgpRouters {
+		if bgpRouter.GetBgpRouterParameters().RouterType == controlNodeType {
+			controlNodes = append(controlNodes, bgpRouter)
+		}
+	}
+	return controlNodes, nil
+}
+
+func getBgpRouters(contrailClient contrailclient.ApiClient) ([]*contrailtypes.BgpRouter, error) {
+	listResults, err := contrailClient.List(bgpRouterType)
+	if err != nil {
+		return nil, err
+	}
+	var bgpRouters []*contrailtypes.BgpRouter
+	for _, listResult := range listResults {
+		obj, err := contrailClient.ReadListResult(bgpRouterType, &listResult)
+		if err != nil {
+			return nil, err
+		}
+		bgpRouters = append(bgpRouters, obj.(*contrailtypes.BgpRouter))
+	}
+	return bgpRouters, nil
+}
+
+func fqName(hostname string) []string {
+	return []string{"default-domain", "default-project", "ip-fabric", "__default__", hostname}
+}
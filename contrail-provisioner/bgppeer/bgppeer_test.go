@@ -0,0 +1,146 @@
+package bgppeer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/fake"
+)
+
+func newBgpRouter(uuid, name, address, routerType string) *contrailtypes.BgpRouter {
+	bgpRouter := &contrailtypes.BgpRouter{}
+	bgpRouter.SetUuid(uuid)
+	bgpRouter.SetFQName(routingInstanceType, fqName(name))
+	bgpRouter.SetBgpRouterParameters(&contrailtypes.BgpRouterParams{
+		Address:          address,
+		AutonomousSystem: 64512,
+		Vendor:           defaultVendor,
+		RouterType:       routerType,
+		Port:             defaultPort,
+		HoldTime:         defaultHoldTime,
+		AddressFamilies:  &contrailtypes.AddressFamilies{Family: []string{"inet-vpn"}},
+	})
+	return bgpRouter
+}
+
+func newFakeClient(bgpRouters ...*contrailtypes.BgpRouter) *fake.FakeContrailClient {
+	fakeContrailClient := fake.GetDefaultFakeContrailClient()
+	fakeContrailClient.ListFake = func(string) ([]contrail.ListResult, error) {
+		var results []contrail.ListResult
+		for _, bgpRouter := range bgpRouters {
+			results = append(results, contrail.ListResult{Uuid: bgpRouter.GetUuid()})
+		}
+		return results, nil
+	}
+	fakeContrailClient.ReadListResultFake = func(_ string, result *contrail.ListResult) (contrail.IObject, error) {
+		for _, bgpRouter := range bgpRouters {
+			if bgpRouter.GetUuid() == result.Uuid {
+				return bgpRouter, nil
+			}
+		}
+		return nil, nil
+	}
+	return fakeContrailClient
+}
+
+func TestUnmarshalSetsDefaults(t *testing.T) {
+	var peers []*BgpPeer
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- hostname: mx1
+  ipAddress: 10.0.0.1
+  asn: 64513
+- hostname: mx2
+  ipAddress: 10.0.0.2
+  asn: 64513
+  port: 1179
+  addressFamilies: [inet-vpn]
+  authKey: secret
+`), &peers))
+
+	assert.Equal(t, []*BgpPeer{
+		{
+			Node:                   contrailnode.Node{Hostname: "mx1", IPAddress: "10.0.0.1"},
+			ASN:                    64513,
+			Vendor:                 defaultVendor,
+			Port:                   defaultPort,
+			HoldTime:               defaultHoldTime,
+			AddressFamilies:        defaultAddressFamilies,
+			PeeredWithControlNodes: true,
+		},
+		{
+			Node:                   contrailnode.Node{Hostname: "mx2", IPAddress: "10.0.0.2"},
+			ASN:                    64513,
+			Vendor:                 defaultVendor,
+			Port:                   1179,
+			HoldTime:               defaultHoldTime,
+			AddressFamilies:        []string{"inet-vpn"},
+			AuthKey:                "secret",
+			PeeredWithControlNodes: true,
+		},
+	}, peers)
+}
+
+func TestCreatePeersWithControlNodes(t *testing.T) {
+	controlNode := newBgpRouter("control-uuid", "control1", "10.0.0.10", controlNodeType)
+	fakeContrailClient := newFakeClient(controlNode)
+	var created *contrailtypes.BgpRouter
+	fakeContrailClient.CreateFake = func(obj contrail.IObject) error {
+		created = obj.(*contrailtypes.BgpRouter)
+		return nil
+	}
+	peer := &BgpPeer{
+		Node:            contrailnode.Node{Hostname: "mx1", IPAddress: "10.0.0.1"},
+		ASN:             64513,
+		Vendor:          "mx",
+		Port:            defaultPort,
+		HoldTime:        defaultHoldTime,
+		AddressFamilies: []string{"inet-vpn"},
+		AuthKey:         "secret",
+	}
+
+	require.NoError(t, peer.Create(fakeContrailClient))
+
+	require.NotNil(t, created)
+	assert.Equal(t, fqName("mx1"), created.GetFQName())
+	params := created.GetBgpRouterParameters()
+	assert.Equal(t, peerRouterType, params.RouterType)
+	assert.Equal(t, "mx", params.Vendor)
+	assert.Equal(t, 64513, params.AutonomousSystem)
+	assert.Equal(t, &contrailtypes.AuthenticationData{
+		KeyType:  md5AuthKeyType,
+		KeyItems: []contrailtypes.AuthenticationKeyItem{{Key: "secret"}},
+	}, params.AuthData)
+	refs, err := created.GetBgpRouterRefs()
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	assert.Equal(t, "control-uuid", refs[0].Uuid)
+}
+
+func TestGetContrailNodesFromApiServerSkipsControlNodes(t *testing.T) {
+	controlNode := newBgpRouter("control-uuid", "control1", "10.0.0.10", controlNodeType)
+	peered := newBgpRouter("peered-uuid", "mx1", "10.0.0.1", peerRouterType)
+	peered.SetBgpRouterList([]contrail.ReferencePair{{Object: controlNode, Attribute: contrailtypes.BgpPeeringAttributes{}}})
+	notPeered := newBgpRouter("not-peered-uuid", "mx2", "10.0.0.2", peerRouterType)
+
+	nodes, err := GetContrailNodesFromApiServer(newFakeClient(controlNode, peered, notPeered))
+
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, &BgpPeer{
+		Node:                   contrailnode.Node{Hostname: "mx1", IPAddress: "10.0.0.1", Annotations: map[string]string{}},
+		ASN:                    64512,
+		Vendor:                 defaultVendor,
+		Port:                   defaultPort,
+		HoldTime:               defaultHoldTime,
+		AddressFamilies:        []string{"inet-vpn"},
+		PeeredWithControlNodes: true,
+	}, nodes[0])
+	assert.False(t, nodes[1].(*BgpPeer).PeeredWithControlNodes)
+}
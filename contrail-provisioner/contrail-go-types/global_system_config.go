@@ -15,6 +15,7 @@ const (
 	global_system_config_enable_4byte_as
 	global_system_config_config_version
 	global_system_config_graceful_restart_parameters
+	global_system_config_fast_convergence_parameters
 	global_system_config_plugin_tuning
 	global_system_config_data_center_interconnect_loopback_namespace
 	global_system_config_data_center_interconnect_asn_namespace
@@ -79,6 +80,7 @@ type GlobalSystemConfig struct {
 	enable_4byte_as                             bool
 	config_version                              string
 	graceful_restart_parameters                 GracefulRestartParametersType
+	fast_convergence_parameters                 FastConvergenceParametersType
 	plugin_tuning                               PluginProperties
 	data_center_interconnect_loopback_namespace SubnetListType
 	data_center_interconnect_asn_namespace      AsnRangeType
@@ -221,6 +223,15 @@ func (obj *GlobalSystemConfig) SetGracefulRestartParameters(value *GracefulResta
 	obj.modified[global_system_config_graceful_restart_parameters] = true
 }
 
+func (obj *GlobalSystemConfig) GetFastConvergenceParameters() FastConvergenceParametersType {
+	return obj.fast_convergence_parameters
+}
+
+func (obj *GlobalSystemConfig) SetFastConvergenceParameters(value *FastConvergenceParametersType) {
+	obj.fast_convergence_parameters = *value
+	obj.modified[global_system_config_fast_convergence_parameters] = true
+}
+
 func (obj *GlobalSystemConfig) GetPluginTuning() PluginProperties {
 	return obj.plugin_tuning
 }
@@ -1250,6 +1261,15 @@ func (obj *GlobalSystemConfig) MarshalJSON() ([]byte, error) {
 		msg["graceful_restart_parameters"] = &value
 	}
 
+	if obj.modified[global_system_config_fast_convergence_parameters] {
+		var value json.RawMessage
+		value, err := json.Marshal(&obj.fast_convergence_parameters)
+		if err != nil {
+			return nil, err
+		}
+		msg["fast_convergence_parameters"] = &value
+	}
+
 	if obj.modified[global_system_config_plugin_tuning] {
 		var value json.RawMessage
 		value, err := json.Marshal(&obj.plugin_tuning)
@@ -1505,6 +1525,12 @@ func (obj *GlobalSystemConfig) UnmarshalJSON(body []byte) error {
 				obj.valid[global_system_config_graceful_restart_parameters] = true
 			}
 			break
+		case "fast_convergence_parameters":
+			err = json.Unmarshal(value, &obj.fast_convergence_parameters)
+			if err == nil {
+				obj.valid[global_system_config_fast_convergence_parameters] = true
+			}
+			break
 		case "plugin_tuning":
 			err = json.Unmarshal(value, &obj.plugin_tuning)
 			if err == nil {
@@ -1886,6 +1912,15 @@ func (obj *GlobalSystemConfig) UpdateObject() ([]byte, error) {
 		msg["graceful_restart_parameters"] = &value
 	}
 
+	if obj.modified[global_system_config_fast_convergence_parameters] {
+		var value json.RawMessage
+		value, err := json.Marshal(&obj.fast_convergence_parameters)
+		if err != nil {
+			return nil, err
+		}
+		msg["fast_convergence_parameters"] = &value
+	}
+
 	if obj.modified[global_system_config_plugin_tuning] {
 		var value json.RawMessage
 		value, err := json.Marshal(&obj.plugin_tuning)
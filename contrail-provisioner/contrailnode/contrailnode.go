@@ -10,6 +10,8 @@ const (
 	AnalyticsNode ContrailNodeType = "analytics-node"
 	ControlNode   ContrailNodeType = "control-node"
 	ConfigNode    ContrailNodeType = "config-node"
+	BgpPeer       ContrailNodeType = "bgp-peer"
+	VirtualDNS    ContrailNodeType = "virtual-DNS"
 )
 
 type Node struct {
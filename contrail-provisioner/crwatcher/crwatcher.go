@@ -0,0 +1,151 @@
+// Package crwatcher provisions Contrail nodes based on custom resources watched in the
+// Kubernetes API, as an alternative to watching node lists rendered into files.
+package crwatcher
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/nodemanager"
+	nodereconcile "github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// Time after which nodes which failed to be provisioned are retried
+const retryInterval = 10 * time.Second
+
+var watcherInfoLog = log.New(os.Stdout, fmt.Sprintf("%-15s ", "crwatcher:"), log.LstdFlags|log.Lmsgprefix)
+
+// Options configures the reconciler.
+type Options struct {
+	// DriftInterval is the interval after which nodes are reconciled again even if no
+	// resource changed, so that changes made directly in the API server are reverted.
+	// Zero disables periodic drift detection.
+	DriftInterval time.Duration
+	// Report, if set, is called with changes made by every reconciliation.
+	Report func(*nodemanager.Report)
+	// Execution controls parallelism and retries of changes of single nodes.
+	Execution nodereconcile.Options
+}
+
+// Add creates a controller which provisions Contrail nodes whenever Config, Control,
+// Vrouter or Cassandra resources in the namespace change. The reconcile request name is the
+// node type to provision.
+func Add(mgr manager.Manager, namespace string, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, options Options) error {
+	r := NewReconciler(mgr.GetAPIReader(), namespace, contrailClient, requiredAnnotations, options)
+	c, err := controller.New("provisioner-crwatcher", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	watches := []struct {
+		object    runtime.Object
+		nodeTypes []contrailnode.ContrailNodeType
+	}{
+		{&v1alpha1.Config{}, []contrailnode.ContrailNodeType{contrailnode.ConfigNode, contrailnode.AnalyticsNode}},
+		{&v1alpha1.Control{}, []contrailnode.ContrailNodeType{contrailnode.ControlNode}},
+		{&v1alpha1.Vrouter{}, []contrailnode.ContrailNodeType{contrailnode.VrouterNode}},
+		{&v1alpha1.Cassandra{}, []contrailnode.ContrailNodeType{contrailnode.DatabaseNode}},
+	}
+	for _, w := range watches {
+		if err := c.Watch(&source.Kind{Type: w.object}, nodeTypeHandler(namespace, w.nodeTypes), nodesChanged()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nodeTypeHandler(namespace string, nodeTypes []contrailnode.ContrailNodeType) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(handler.MapObject) []reconcile.Request {
+			var requests []reconcile.Request
+			for _, nodeType := range nodeTypes {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: string(nodeType), Namespace: namespace},
+				})
+			}
+			return requests
+		}),
+	}
+}
+
+// nodesChanged filters out updates which do not change nodes of the resource, e.g. service
+// status reported periodically by the statusmonitor.
+func nodesChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(statusNodes(e.ObjectOld), statusNodes(e.ObjectNew))
+		},
+	}
+}
+
+func statusNodes(object runtime.Object) interface{} {
+	switch o := object.(type) {
+	case *v1alpha1.Config:
+		return o.Status.Nodes
+	case *v1alpha1.Control:
+		return []interface{}{o.Status.Nodes, o.Status.Ports.ASNNumber}
+	case *v1alpha1.Vrouter:
+		return o.Status.Nodes
+	case *v1alpha1.Cassandra:
+		return o.Status.Nodes
+	}
+	return nil
+}
+
+// NewReconciler returns reconciler which provisions nodes of the type given in the request.
+func NewReconciler(reader client.Reader, namespace string, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, options Options) *Reconciler {
+	return &Reconciler{
+		reader:              reader,
+		namespace:           namespace,
+		contrailClient:      contrailClient,
+		requiredAnnotations: requiredAnnotations,
+		options:             options,
+	}
+}
+
+// Reconciler provisions nodes of a single type in the Contrail API server.
+type Reconciler struct {
+	reader              client.Reader
+	namespace           string
+	contrailClient      contrailclient.ApiClient
+	requiredAnnotations map[string]string
+	options             Options
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconcile provisions nodes of the type given as request name.
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	nodeType := contrailnode.ContrailNodeType(request.Name)
+	nodes, err := RequiredNodes(r.reader, r.namespace, nodeType)
+	if err != nil {
+		watcherInfoLog.Printf("Failed to get required %s nodes: %v\n", nodeType, err)
+		return reconcile.Result{RequeueAfter: retryInterval}, nil
+	}
+	watcherInfoLog.Printf("Provisioning %d %s nodes\n", len(nodes), nodeType)
+	report, err := nodemanager.ManageContrailNodes(nodes, r.requiredAnnotations, nodeType, r.contrailClient, r.options.Execution)
+	if report != nil && r.options.Report != nil {
+		r.options.Report(report)
+	}
+	if err != nil {
+		watcherInfoLog.Printf("%s node manager failed: %v\n", nodeType, err)
+		return reconcile.Result{RequeueAfter: retryInterval}, nil
+	}
+	return reconcile.Result{RequeueAfter: r.options.DriftInterval}, nil
+}
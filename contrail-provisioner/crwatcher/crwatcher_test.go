@@ -0,0 +1,153 @@
+package crwatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/controlnode"
+	fakecontrail "github.com/Juniper/contrail-operator/contrail-provisioner/fake"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/nodemanager"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/vrouternode"
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme, err := v1alpha1.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, corev1.SchemeBuilder.AddToScheme(scheme))
+	return scheme
+}
+
+func newPod(name, hostname string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec:       corev1.PodSpec{Hostname: hostname},
+	}
+}
+
+func TestRequiredNodes(t *testing.T) {
+	scheme := newScheme(t)
+	hostNetworkPod := newPod("vrouter1-vrouter-ds-abcde", "", nil)
+	hostNetworkPod.Spec.HostNetwork = true
+	hostNetworkPod.Spec.NodeName = "worker1"
+	unscheduledPod := newPod("vrouter1-vrouter-ds-fghij", "", nil)
+	unscheduledPod.Spec.HostNetwork = true
+	cl := fake.NewFakeClientWithScheme(scheme,
+		&v1alpha1.Control{
+			ObjectMeta: metav1.ObjectMeta{Name: "control1", Namespace: "default"},
+			Status: v1alpha1.ControlStatus{
+				Nodes: map[string]string{"control1-control-statefulset-1": "10.0.0.2", "control1-control-statefulset-0": "10.0.0.1"},
+				Ports: v1alpha1.ControlStatusPorts{ASNNumber: "64512"},
+			},
+		},
+		newPod("control1-control-statefulset-0", "control0", nil),
+		newPod("control1-control-statefulset-1", "control1", map[string]string{"dataSubnetIP": "172.16.0.2"}),
+		&v1alpha1.Vrouter{
+			ObjectMeta: metav1.ObjectMeta{Name: "vrouter1", Namespace: "default"},
+			Status: v1alpha1.VrouterStatus{
+				Nodes: map[string]string{"vrouter1-vrouter-ds-abcde": "10.0.0.10", "vrouter1-vrouter-ds-fghij": "10.0.0.11"},
+			},
+		},
+		hostNetworkPod,
+		unscheduledPod,
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker1"},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeHostName, Address: "worker1.example.com"}},
+			},
+		},
+	)
+
+	t.Run("should return control nodes with data IP and ASN", func(t *testing.T) {
+		nodes, err := RequiredNodes(cl, "default", contrailnode.ControlNode)
+		require.NoError(t, err)
+		assert.Equal(t, []contrailnode.ContrailNode{
+			&controlnode.ControlNode{Node: contrailnode.Node{IPAddress: "10.0.0.1", Hostname: "control0"}, ASN: 64512},
+			&controlnode.ControlNode{Node: contrailnode.Node{IPAddress: "172.16.0.2", Hostname: "control1"}, ASN: 64512},
+		}, nodes)
+	})
+
+	t.Run("should resolve hostname of host network pods and skip unscheduled ones", func(t *testing.T) {
+		nodes, err := RequiredNodes(cl, "default", contrailnode.VrouterNode)
+		require.NoError(t, err)
+		assert.Equal(t, []contrailnode.ContrailNode{
+			&vrouternode.VrouterNode{Node: contrailnode.Node{IPAddress: "10.0.0.10", Hostname: "worker1.example.com"}, Vhost0Interface: true},
+		}, nodes)
+	})
+
+	t.Run("should return no nodes when there are no resources", func(t *testing.T) {
+		nodes, err := RequiredNodes(cl, "default", contrailnode.DatabaseNode)
+		require.NoError(t, err)
+		assert.Empty(t, nodes)
+	})
+
+	t.Run("should return error for unknown node type", func(t *testing.T) {
+		_, err := RequiredNodes(cl, "default", contrailnode.ContrailNodeType("unknown"))
+		assert.Error(t, err)
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	scheme := newScheme(t)
+	cl := fake.NewFakeClientWithScheme(scheme,
+		&v1alpha1.Cassandra{
+			ObjectMeta: metav1.ObjectMeta{Name: "cassandra1", Namespace: "default"},
+			Status:     v1alpha1.CassandraStatus{Nodes: map[string]string{"cassandra1-cassandra-statefulset-0": "10.0.0.5"}},
+		},
+		newPod("cassandra1-cassandra-statefulset-0", "database0", nil),
+	)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: string(contrailnode.DatabaseNode), Namespace: "default"}}
+	annotations := map[string]string{"managed_by": "provisionmanager"}
+
+	t.Run("should create required nodes in the Contrail API server", func(t *testing.T) {
+		contrailClient := fakecontrail.GetDefaultFakeContrailClient()
+		var created []contrail.IObject
+		contrailClient.CreateFake = func(object contrail.IObject) error {
+			created = append(created, object)
+			return nil
+		}
+		var reports []*nodemanager.Report
+		r := NewReconciler(cl, "default", contrailClient, annotations, Options{
+			DriftInterval: time.Minute,
+			Report:        func(report *nodemanager.Report) { reports = append(reports, report) },
+		})
+
+		result, err := r.Reconcile(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, result)
+		require.Len(t, created, 1)
+		assert.Equal(t, "database0", created[0].GetName())
+		require.Len(t, reports, 1)
+		assert.Equal(t, contrailnode.DatabaseNode, reports[0].NodeType)
+		assert.False(t, reports[0].DryRun)
+		require.Len(t, reports[0].Changes, 1)
+		assert.Equal(t, "create", reports[0].Changes[0].Action)
+		assert.Equal(t, "database0", reports[0].Changes[0].Hostname)
+	})
+
+	t.Run("should requeue when provisioning fails", func(t *testing.T) {
+		contrailClient := fakecontrail.GetDefaultFakeContrailClient()
+		contrailClient.ListFake = func(string) ([]contrail.ListResult, error) {
+			return nil, assert.AnError
+		}
+		r := NewReconciler(cl, "default", contrailClient, annotations, Options{DriftInterval: time.Minute})
+
+		result, err := r.Reconcile(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, retryInterval, result.RequeueAfter)
+	})
+}
@@ -0,0 +1,155 @@
+package crwatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/analyticsnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/confignode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/controlnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/databasenode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/vrouternode"
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// Annotation set by the control controller on pods with an address in the data subnet
+const dataSubnetIPAnnotation = "dataSubnetIP"
+
+type podNode struct {
+	contrailnode.Node
+	pod *corev1.Pod
+}
+
+// RequiredNodes returns nodes of the given type which should exist in the Contrail API
+// server. Nodes are taken from the status of custom resources in the namespace.
+func RequiredNodes(cl client.Reader, namespace string, nodeType contrailnode.ContrailNodeType) ([]contrailnode.ContrailNode, error) {
+	listOps := &client.ListOptions{Namespace: namespace}
+	var contrailNodes []contrailnode.ContrailNode
+	switch nodeType {
+	case contrailnode.ConfigNode, contrailnode.AnalyticsNode:
+		configList := &v1alpha1.ConfigList{}
+		if err := cl.List(context.Background(), configList, listOps); err != nil {
+			return nil, err
+		}
+		for _, config := range configList.Items {
+			nodes, err := podNodes(cl, namespace, config.Status.Nodes)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				if nodeType == contrailnode.ConfigNode {
+					contrailNodes = append(contrailNodes, &confignode.ConfigNode{Node: n.Node})
+				} else {
+					contrailNodes = append(contrailNodes, &analyticsnode.AnalyticsNode{Node: n.Node})
+				}
+			}
+		}
+	case contrailnode.ControlNode:
+		controlList := &v1alpha1.ControlList{}
+		if err := cl.List(context.Background(), controlList, listOps); err != nil {
+			return nil, err
+		}
+		for _, control := range controlList.Items {
+			nodes, err := podNodes(cl, namespace, control.Status.Nodes)
+			if err != nil {
+				return nil, err
+			}
+			if len(nodes) == 0 {
+				continue
+			}
+			asn, err := strconv.Atoi(control.Status.Ports.ASNNumber)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ASN of control %s: %v", control.Name, err)
+			}
+			for _, n := range nodes {
+				if dataIP := n.pod.Annotations[dataSubnetIPAnnotation]; dataIP != "" {
+					n.IPAddress = dataIP
+				}
+				contrailNodes = append(contrailNodes, &controlnode.ControlNode{Node: n.Node, ASN: asn})
+			}
+		}
+	case contrailnode.VrouterNode:
+		vrouterList := &v1alpha1.VrouterList{}
+		if err := cl.List(context.Background(), vrouterList, listOps); err != nil {
+			return nil, err
+		}
+		for _, vrouter := range vrouterList.Items {
+			nodes, err := podNodes(cl, namespace, vrouter.Status.Nodes)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				contrailNodes = append(contrailNodes, &vrouternode.VrouterNode{Node: n.Node, Vhost0Interface: true})
+			}
+		}
+	case contrailnode.DatabaseNode:
+		cassandraList := &v1alpha1.CassandraList{}
+		if err := cl.List(context.Background(), cassandraList, listOps); err != nil {
+			return nil, err
+		}
+		for _, cassandra := range cassandraList.Items {
+			nodes, err := podNodes(cl, namespace, cassandra.Status.Nodes)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				contrailNodes = append(contrailNodes, &databasenode.DatabaseNode{Node: n.Node})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported node type %q", nodeType)
+	}
+	return contrailNodes, nil
+}
+
+// podNodes resolves hostnames of pods listed in the status of a custom resource and
+// returns them sorted by IP address. Pods which are not scheduled yet are skipped.
+func podNodes(cl client.Reader, namespace string, statusNodes map[string]string) ([]podNode, error) {
+	var nodes []podNode
+	for podName, ipAddress := range statusNodes {
+		pod := &corev1.Pod{}
+		if err := cl.Get(context.Background(), types.NamespacedName{Name: podName, Namespace: namespace}, pod); err != nil {
+			return nil, err
+		}
+		hostname, err := podHostname(cl, pod)
+		if err != nil {
+			return nil, err
+		}
+		if hostname == "" {
+			continue
+		}
+		nodes = append(nodes, podNode{
+			Node: contrailnode.Node{IPAddress: ipAddress, Hostname: hostname},
+			pod:  pod,
+		})
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].IPAddress < nodes[j].IPAddress })
+	return nodes, nil
+}
+
+func podHostname(cl client.Reader, pod *corev1.Pod) (string, error) {
+	if !pod.Spec.HostNetwork {
+		return pod.Spec.Hostname, nil
+	}
+	if pod.Spec.NodeName == "" {
+		return "", nil
+	}
+	node := &corev1.Node{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		return "", err
+	}
+	for _, a := range node.Status.Addresses {
+		if a.Type == corev1.NodeHostName {
+			return a.Address, nil
+		}
+	}
+	return "", errors.New("couldn't get pods hostname")
+}
@@ -0,0 +1,125 @@
+// Package globalsystemconfig provisions the default global-system-config object.
+package globalsystemconfig
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/nodemanager"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+)
+
+const (
+	objectType string = "global-system-config"
+	objectName string = "default-global-system-config"
+)
+
+// GlobalSystemConfiguration defines fields of the global-system-config managed by the
+// provisioner. Fields which are not set are left unchanged in the API server.
+type GlobalSystemConfiguration struct {
+	AutonomousSystem *int                       `yaml:"autonomousSystem,omitempty"`
+	IBGPAutoMesh     *bool                      `yaml:"ibgpAutoMesh,omitempty"`
+	FastConvergence  *FastConvergenceParameters `yaml:"fastConvergence,omitempty"`
+}
+
+// FastConvergenceParameters configure detection of unreachable next hops.
+type FastConvergenceParameters struct {
+	Enable              bool `yaml:"enable"`
+	NHReachabilityCheck bool `yaml:"nhReachabilityCheck,omitempty"`
+	XMPPHoldTime        int  `yaml:"xmppHoldTime,omitempty"`
+	BGPHoldTime         int  `yaml:"bgpHoldTime,omitempty"`
+}
+
+var globalSystemConfigInfoLog = log.New(os.Stdout, fmt.Sprintf("%-15s ", objectType+":"), log.LstdFlags|log.Lmsgprefix)
+
+// Plan reports fields of the default global-system-config which differ from the
+// configuration.
+func Plan(contrailClient contrailclient.ApiClient, configuration GlobalSystemConfiguration) (*nodemanager.Report, error) {
+	gsc, err := get(contrailClient)
+	if err != nil {
+		return nil, err
+	}
+	return newReport(true, apply(gsc, configuration)), nil
+}
+
+// Ensure updates the default global-system-config if it differs from the configuration
+// and reports the updated fields.
+func Ensure(contrailClient contrailclient.ApiClient, configuration GlobalSystemConfiguration) (*nodemanager.Report, error) {
+	gsc, err := get(contrailClient)
+	if err != nil {
+		return nil, err
+	}
+	fields := apply(gsc, configuration)
+	if len(fields) == 0 {
+		return newReport(false, nil), nil
+	}
+	for _, field := range fields {
+		globalSystemConfigInfoLog.Printf("Setting %s to %s (was %s)\n", field.Field, field.Required, field.Current)
+	}
+	if err := contrailClient.Update(gsc); err != nil {
+		return nil, err
+	}
+	return newReport(false, fields), nil
+}
+
+func newReport(dryRun bool, fields []reconcile.FieldChange) *nodemanager.Report {
+	var changes []reconcile.Change
+	if len(fields) > 0 {
+		changes = append(changes, reconcile.Change{Action: "update", Hostname: objectName, Fields: fields})
+	}
+	return nodemanager.NewReport(contrailnode.ContrailNodeType(objectType), dryRun, changes)
+}
+
+func get(contrailClient contrailclient.ApiClient) (*contrailtypes.GlobalSystemConfig, error) {
+	obj, err := contrailClient.FindByName(objectType, objectName)
+	if err != nil {
+		return nil, err
+	}
+	gsc, ok := obj.(*contrailtypes.GlobalSystemConfig)
+	if !ok {
+		return nil, fmt.Errorf("%s %s not found", objectType, objectName)
+	}
+	return gsc, nil
+}
+
+func apply(gsc *contrailtypes.GlobalSystemConfig, configuration GlobalSystemConfiguration) []reconcile.FieldChange {
+	var changes []reconcile.FieldChange
+	if asn := configuration.AutonomousSystem; asn != nil && gsc.GetAutonomousSystem() != *asn {
+		changes = append(changes, reconcile.FieldChange{
+			Field:    "autonomousSystem",
+			Current:  strconv.Itoa(gsc.GetAutonomousSystem()),
+			Required: strconv.Itoa(*asn),
+		})
+		gsc.SetAutonomousSystem(*asn)
+	}
+	if mesh := configuration.IBGPAutoMesh; mesh != nil && gsc.GetIbgpAutoMesh() != *mesh {
+		changes = append(changes, reconcile.FieldChange{
+			Field:    "ibgpAutoMesh",
+			Current:  strconv.FormatBool(gsc.GetIbgpAutoMesh()),
+			Required: strconv.FormatBool(*mesh),
+		})
+		gsc.SetIbgpAutoMesh(*mesh)
+	}
+	if fc := configuration.FastConvergence; fc != nil {
+		required := contrailtypes.FastConvergenceParametersType{
+			Enable:              fc.Enable,
+			NhReachabilityCheck: fc.NHReachabilityCheck,
+			XmppHoldTime:        fc.XMPPHoldTime,
+			BgpHoldTime:         fc.BGPHoldTime,
+		}
+		if current := gsc.GetFastConvergenceParameters(); current != required {
+			changes = append(changes, reconcile.FieldChange{
+				Field:    "fastConvergence",
+				Current:  fmt.Sprintf("%+v", current),
+				Required: fmt.Sprintf("%+v", required),
+			})
+			gsc.SetFastConvergenceParameters(&required)
+		}
+	}
+	return changes
+}
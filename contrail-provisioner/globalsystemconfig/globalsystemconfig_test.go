@@ -0,0 +1,99 @@
+package globalsystemconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/fake"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+)
+
+func newFakeClient(asn int, ibgpAutoMesh bool) (*fake.FakeContrailClient, *int) {
+	fakeContrailClient := fake.GetDefaultFakeContrailClient()
+	fakeContrailClient.FindByNameFake = func(string, string) (contrail.IObject, error) {
+		gsc := &contrailtypes.GlobalSystemConfig{}
+		gsc.SetAutonomousSystem(asn)
+		gsc.SetIbgpAutoMesh(ibgpAutoMesh)
+		return gsc, nil
+	}
+	updates := 0
+	fakeContrailClient.UpdateFake = func(contrail.IObject) error {
+		updates++
+		return nil
+	}
+	return fakeContrailClient, &updates
+}
+
+func TestEnsure(t *testing.T) {
+	asn := 64513
+	autoMesh := true
+
+	t.Run("should update fields which differ", func(t *testing.T) {
+		fakeContrailClient, updates := newFakeClient(64512, true)
+
+		report, err := Ensure(fakeContrailClient, GlobalSystemConfiguration{AutonomousSystem: &asn, IBGPAutoMesh: &autoMesh})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, *updates)
+		require.Len(t, report.Changes, 1)
+		assert.Equal(t, []reconcile.FieldChange{{Field: "autonomousSystem", Current: "64512", Required: "64513"}}, report.Changes[0].Fields)
+	})
+
+	t.Run("should not update when nothing differs", func(t *testing.T) {
+		fakeContrailClient, updates := newFakeClient(64513, true)
+
+		report, err := Ensure(fakeContrailClient, GlobalSystemConfiguration{AutonomousSystem: &asn, IBGPAutoMesh: &autoMesh})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, *updates)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should not update fields which are not set", func(t *testing.T) {
+		fakeContrailClient, updates := newFakeClient(64512, false)
+
+		report, err := Ensure(fakeContrailClient, GlobalSystemConfiguration{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, *updates)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should update fast convergence parameters", func(t *testing.T) {
+		fakeContrailClient, updates := newFakeClient(64512, true)
+		var updated *contrailtypes.GlobalSystemConfig
+		fakeContrailClient.UpdateFake = func(obj contrail.IObject) error {
+			*updates++
+			updated = obj.(*contrailtypes.GlobalSystemConfig)
+			return nil
+		}
+
+		report, err := Ensure(fakeContrailClient, GlobalSystemConfiguration{
+			FastConvergence: &FastConvergenceParameters{Enable: true, NHReachabilityCheck: true, XMPPHoldTime: 10},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, *updates)
+		require.Len(t, report.Changes, 1)
+		assert.Equal(t, "fastConvergence", report.Changes[0].Fields[0].Field)
+		assert.Equal(t, contrailtypes.FastConvergenceParametersType{Enable: true, NhReachabilityCheck: true, XmppHoldTime: 10}, updated.GetFastConvergenceParameters())
+	})
+}
+
+func TestPlanDoesNotUpdate(t *testing.T) {
+	fakeContrailClient, updates := newFakeClient(64512, true)
+	autoMesh := false
+
+	report, err := Plan(fakeContrailClient, GlobalSystemConfiguration{IBGPAutoMesh: &autoMesh})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, *updates)
+	assert.True(t, report.DryRun)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "ibgpAutoMesh", report.Changes[0].Fields[0].Field)
+}
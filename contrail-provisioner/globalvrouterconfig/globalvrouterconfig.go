@@ -0,0 +1,237 @@
+// Package globalvrouterconfig provisions the default global-vrouter-config object.
+package globalvrouterconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/nodemanager"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+)
+
+const (
+	objectType string = "global-vrouter-config"
+	objectName string = "default-global-vrouter-config"
+)
+
+var fqName = []string{"default-global-system-config", objectName}
+
+// GlobalVrouterConfiguration defines fields of the global-vrouter-config managed by the
+// provisioner. Fields which are not set are left unchanged in the API server.
+type GlobalVrouterConfiguration struct {
+	EcmpHashingIncludeFields   EcmpHashingIncludeFields `json:"ecmpHashingIncludeFields,omitempty"`
+	EncapsulationPriorities    string                   `json:"encapPriority,omitempty"`
+	VxlanNetworkIdentifierMode string                   `json:"vxlanNetworkIdentifierMode,omitempty"`
+	LinklocalServices          []LinklocalService       `json:"linklocalServices,omitempty"`
+	FlowExportRate             *int                     `json:"flowExportRate,omitempty"`
+	FlowAgingTimeouts          []FlowAgingTimeout       `json:"flowAgingTimeouts,omitempty"`
+	ForwardingMode             string                   `json:"forwardingMode,omitempty"`
+	PortTranslationPools       []PortTranslationPool    `json:"portTranslationPools,omitempty"`
+}
+
+type EcmpHashingIncludeFields struct {
+	HashingConfigured bool `json:"hashingConfigured,omitempty"`
+	SourceIp          bool `json:"sourceIp,omitempty"`
+	DestinationIp     bool `json:"destinationIp,omitempty"`
+	IpProtocol        bool `json:"ipProtocol,omitempty"`
+	SourcePort        bool `json:"sourcePort,omitempty"`
+	DestinationPort   bool `json:"destinationPort,omitempty"`
+}
+
+// LinklocalService maps a link-local address, e.g. of the metadata service, to a service
+// in the IP fabric given by DNS name or IP addresses.
+type LinklocalService struct {
+	Name                 string   `json:"name"`
+	IP                   string   `json:"ip"`
+	Port                 int      `json:"port"`
+	FabricDNSServiceName string   `json:"fabricDNSServiceName,omitempty"`
+	FabricIPs            []string `json:"fabricIPs,omitempty"`
+	FabricPort           int      `json:"fabricPort"`
+}
+
+type FlowAgingTimeout struct {
+	Protocol       string `json:"protocol"`
+	Port           int    `json:"port,omitempty"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+}
+
+type PortTranslationPool struct {
+	Protocol  string `json:"protocol"`
+	StartPort int    `json:"startPort,omitempty"`
+	EndPort   int    `json:"endPort,omitempty"`
+	PortCount int    `json:"portCount,omitempty"`
+}
+
+var globalVrouterConfigInfoLog = log.New(os.Stdout, fmt.Sprintf("%-15s ", objectType+":"), log.LstdFlags|log.Lmsgprefix)
+
+// Plan reports fields of the default global-vrouter-config which differ from the
+// configuration.
+func Plan(contrailClient contrailclient.ApiClient, configuration GlobalVrouterConfiguration) (*nodemanager.Report, error) {
+	gvc, err := get(contrailClient)
+	if err != nil {
+		return nil, err
+	}
+	if gvc == nil {
+		return newReport(true, "create", apply(newGlobalVrouterConfig(), configuration)), nil
+	}
+	return newReport(true, "update", apply(gvc, configuration)), nil
+}
+
+// Ensure creates the default global-vrouter-config or updates it if it differs from the
+// configuration and reports the changed fields.
+func Ensure(contrailClient contrailclient.ApiClient, configuration GlobalVrouterConfiguration) (*nodemanager.Report, error) {
+	gvc, err := get(contrailClient)
+	if err != nil {
+		return nil, err
+	}
+	if gvc == nil {
+		gvc = newGlobalVrouterConfig()
+		fields := apply(gvc, configuration)
+		globalVrouterConfigInfoLog.Printf("Creating %s\n", objectName)
+		if err := contrailClient.Create(gvc); err != nil {
+			return nil, err
+		}
+		return newReport(false, "create", fields), nil
+	}
+	fields := apply(gvc, configuration)
+	if len(fields) == 0 {
+		return newReport(false, "update", nil), nil
+	}
+	for _, field := range fields {
+		globalVrouterConfigInfoLog.Printf("Setting %s to %s (was %s)\n", field.Field, field.Required, field.Current)
+	}
+	if err := contrailClient.Update(gvc); err != nil {
+		return nil, err
+	}
+	return newReport(false, "update", fields), nil
+}
+
+func newReport(dryRun bool, action string, fields []reconcile.FieldChange) *nodemanager.Report {
+	var changes []reconcile.Change
+	if action == "create" || len(fields) > 0 {
+		changes = append(changes, reconcile.Change{Action: action, Hostname: objectName, Fields: fields})
+	}
+	return nodemanager.NewReport(contrailnode.ContrailNodeType(objectType), dryRun, changes)
+}
+
+func newGlobalVrouterConfig() *contrailtypes.GlobalVrouterConfig {
+	gvc := &contrailtypes.GlobalVrouterConfig{}
+	gvc.SetFQName("", fqName)
+	return gvc
+}
+
+// get returns the default global-vrouter-config or nil if it does not exist.
+func get(contrailClient contrailclient.ApiClient) (*contrailtypes.GlobalVrouterConfig, error) {
+	obj, err := contrailClient.FindByName(objectType, strings.Join(fqName, ":"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "404 ") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	gvc, ok := obj.(*contrailtypes.GlobalVrouterConfig)
+	if !ok {
+		return nil, nil
+	}
+	return gvc, nil
+}
+
+func apply(gvc *contrailtypes.GlobalVrouterConfig, configuration GlobalVrouterConfiguration) []reconcile.FieldChange {
+	var changes []reconcile.FieldChange
+	// Values are compared as they are sent to the API server, so that e.g. missing and
+	// empty lists are equal
+	changed := func(field string, current, required interface{}) bool {
+		currentJSON, _ := json.Marshal(current)
+		requiredJSON, _ := json.Marshal(required)
+		if bytes.Equal(currentJSON, requiredJSON) {
+			return false
+		}
+		changes = append(changes, reconcile.FieldChange{
+			Field:    field,
+			Current:  string(currentJSON),
+			Required: string(requiredJSON),
+		})
+		return true
+	}
+
+	if ecmp := configuration.EcmpHashingIncludeFields; ecmp != (EcmpHashingIncludeFields{}) {
+		required := contrailtypes.EcmpHashingIncludeFields{
+			HashingConfigured: ecmp.HashingConfigured,
+			SourceIp:          ecmp.SourceIp,
+			DestinationIp:     ecmp.DestinationIp,
+			IpProtocol:        ecmp.IpProtocol,
+			SourcePort:        ecmp.SourcePort,
+			DestinationPort:   ecmp.DestinationPort,
+		}
+		if changed("ecmpHashingIncludeFields", gvc.GetEcmpHashingIncludeFields(), required) {
+			gvc.SetEcmpHashingIncludeFields(&required)
+		}
+	}
+	if configuration.EncapsulationPriorities != "" {
+		required := contrailtypes.EncapsulationPrioritiesType{Encapsulation: strings.Split(configuration.EncapsulationPriorities, ",")}
+		if changed("encapPriority", gvc.GetEncapsulationPriorities(), required) {
+			gvc.SetEncapsulationPriorities(&required)
+		}
+	}
+	if mode := configuration.VxlanNetworkIdentifierMode; mode != "" && changed("vxlanNetworkIdentifierMode", gvc.GetVxlanNetworkIdentifierMode(), mode) {
+		gvc.SetVxlanNetworkIdentifierMode(mode)
+	}
+	if configuration.LinklocalServices != nil {
+		required := contrailtypes.LinklocalServicesTypes{}
+		for _, service := range configuration.LinklocalServices {
+			required.AddLinklocalServiceEntry(&contrailtypes.LinklocalServiceEntryType{
+				LinklocalServiceName:   service.Name,
+				LinklocalServiceIp:     service.IP,
+				LinklocalServicePort:   service.Port,
+				IpFabricDnsServiceName: service.FabricDNSServiceName,
+				IpFabricServicePort:    service.FabricPort,
+				IpFabricServiceIp:      service.FabricIPs,
+			})
+		}
+		if changed("linklocalServices", gvc.GetLinklocalServices(), required) {
+			gvc.SetLinklocalServices(&required)
+		}
+	}
+	if rate := configuration.FlowExportRate; rate != nil && changed("flowExportRate", gvc.GetFlowExportRate(), *rate) {
+		gvc.SetFlowExportRate(*rate)
+	}
+	if configuration.FlowAgingTimeouts != nil {
+		required := contrailtypes.FlowAgingTimeoutList{}
+		for _, timeout := range configuration.FlowAgingTimeouts {
+			required.AddFlowAgingTimeout(&contrailtypes.FlowAgingTimeout{
+				Protocol:         timeout.Protocol,
+				Port:             timeout.Port,
+				TimeoutInSeconds: timeout.TimeoutSeconds,
+			})
+		}
+		if changed("flowAgingTimeouts", gvc.GetFlowAgingTimeoutList(), required) {
+			gvc.SetFlowAgingTimeoutList(&required)
+		}
+	}
+	if mode := configuration.ForwardingMode; mode != "" && changed("forwardingMode", gvc.GetForwardingMode(), mode) {
+		gvc.SetForwardingMode(mode)
+	}
+	if configuration.PortTranslationPools != nil {
+		required := contrailtypes.PortTranslationPools{}
+		for _, pool := range configuration.PortTranslationPools {
+			requiredPool := contrailtypes.PortTranslationPool{Protocol: pool.Protocol}
+			if pool.PortCount != 0 {
+				requiredPool.PortCount = fmt.Sprint(pool.PortCount)
+			} else {
+				requiredPool.PortRange = &contrailtypes.PortType{StartPort: pool.StartPort, EndPort: pool.EndPort}
+			}
+			required.AddPortTranslationPool(&requiredPool)
+		}
+		if changed("portTranslationPools", gvc.GetPortTranslationPools(), required) {
+			gvc.SetPortTranslationPools(&required)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,132 @@
+package globalvrouterconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	contrail "github.com/Juniper/contrail-go-api"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/fake"
+)
+
+type fakeAPIServer struct {
+	gvc     *contrailtypes.GlobalVrouterConfig
+	created int
+	updated int
+}
+
+func newFakeClient(server *fakeAPIServer) *fake.FakeContrailClient {
+	fakeContrailClient := fake.GetDefaultFakeContrailClient()
+	fakeContrailClient.FindByNameFake = func(typename string, fqn string) (contrail.IObject, error) {
+		if server.gvc == nil {
+			return nil, errors.New("404 Not Found: " + fqn)
+		}
+		return server.gvc, nil
+	}
+	fakeContrailClient.CreateFake = func(obj contrail.IObject) error {
+		server.created++
+		server.gvc = obj.(*contrailtypes.GlobalVrouterConfig)
+		return nil
+	}
+	fakeContrailClient.UpdateFake = func(obj contrail.IObject) error {
+		server.updated++
+		server.gvc = obj.(*contrailtypes.GlobalVrouterConfig)
+		return nil
+	}
+	return fakeContrailClient
+}
+
+var metadataService = LinklocalService{
+	Name:       "metadata",
+	IP:         "169.254.169.254",
+	Port:       80,
+	FabricIPs:  []string{"127.0.0.1"},
+	FabricPort: 8775,
+}
+
+func TestEnsure(t *testing.T) {
+	flowExportRate := 100
+	configuration := GlobalVrouterConfiguration{
+		EncapsulationPriorities:    "VXLAN,MPLSoUDP",
+		VxlanNetworkIdentifierMode: "automatic",
+		LinklocalServices:          []LinklocalService{metadataService},
+		FlowExportRate:             &flowExportRate,
+		FlowAgingTimeouts:          []FlowAgingTimeout{{Protocol: "tcp", Port: 80, TimeoutSeconds: 180}},
+		ForwardingMode:             "l2_l3",
+		PortTranslationPools: []PortTranslationPool{
+			{Protocol: "tcp", StartPort: 56000, EndPort: 57023},
+			{Protocol: "udp", PortCount: 1024},
+		},
+	}
+
+	t.Run("should create missing global-vrouter-config", func(t *testing.T) {
+		server := &fakeAPIServer{}
+
+		report, err := Ensure(newFakeClient(server), configuration)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, server.created)
+		require.Len(t, report.Changes, 1)
+		assert.Equal(t, "create", report.Changes[0].Action)
+		assert.Equal(t, []string{"default-global-system-config", "default-global-vrouter-config"}, server.gvc.GetFQName())
+		assert.Equal(t, 100, server.gvc.GetFlowExportRate())
+		assert.Equal(t, "l2_l3", server.gvc.GetForwardingMode())
+		assert.Equal(t, []contrailtypes.LinklocalServiceEntryType{{
+			LinklocalServiceName: "metadata",
+			LinklocalServiceIp:   "169.254.169.254",
+			LinklocalServicePort: 80,
+			IpFabricServiceIp:    []string{"127.0.0.1"},
+			IpFabricServicePort:  8775,
+		}}, server.gvc.GetLinklocalServices().LinklocalServiceEntry)
+		assert.Equal(t, []contrailtypes.PortTranslationPool{
+			{Protocol: "tcp", PortRange: &contrailtypes.PortType{StartPort: 56000, EndPort: 57023}},
+			{Protocol: "udp", PortCount: "1024"},
+		}, server.gvc.GetPortTranslationPools().PortTranslationPool)
+	})
+
+	t.Run("should not update global-vrouter-config in sync", func(t *testing.T) {
+		server := &fakeAPIServer{}
+		_, err := Ensure(newFakeClient(server), configuration)
+		require.NoError(t, err)
+
+		report, err := Ensure(newFakeClient(server), configuration)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, server.updated)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should update only fields which differ", func(t *testing.T) {
+		server := &fakeAPIServer{gvc: newGlobalVrouterConfig()}
+		server.gvc.SetForwardingMode("l2")
+		server.gvc.SetLinklocalServices(&contrailtypes.LinklocalServicesTypes{})
+
+		report, err := Ensure(newFakeClient(server), GlobalVrouterConfiguration{ForwardingMode: "l2_l3", LinklocalServices: []LinklocalService{metadataService}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, server.updated)
+		require.Len(t, report.Changes, 1)
+		var fields []string
+		for _, field := range report.Changes[0].Fields {
+			fields = append(fields, field.Field)
+		}
+		assert.Equal(t, []string{"linklocalServices", "forwardingMode"}, fields)
+		assert.Equal(t, `"l2"`, report.Changes[0].Fields[1].Current)
+	})
+}
+
+func TestPlanDoesNotChangeAnything(t *testing.T) {
+	server := &fakeAPIServer{}
+
+	report, err := Plan(newFakeClient(server), GlobalVrouterConfiguration{ForwardingMode: "l3"})
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "create", report.Changes[0].Action)
+	assert.Equal(t, 0, server.created)
+}
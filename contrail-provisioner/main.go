@@ -11,17 +11,26 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 
 	contrail "github.com/Juniper/contrail-go-api"
 
-	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/apiclient"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/crwatcher"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/globalsystemconfig"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/globalvrouterconfig"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/nodemanager"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 )
 
 // APIServer struct contains API Server configuration
@@ -44,22 +53,15 @@ type KeystoneAuthParameters struct {
 	AuthUrl       string     `yaml:"auth_url,omitempty"`
 	TenantName    string     `yaml:"tenant_name,omitempty"`
 	Encryption    encryption `yaml:"encryption,omitempty"`
+	// Application credentials are used instead of the admin password when the secret is set
+	ApplicationCredentialID     string `yaml:"application_credential_id,omitempty"`
+	ApplicationCredentialName   string `yaml:"application_credential_name,omitempty"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret,omitempty"`
+	// UserDomain is the ID of the domain of admin_user, "default" when not set
+	UserDomain string `yaml:"user_domain,omitempty"`
 }
 
-type EcmpHashingIncludeFields struct {
-	HashingConfigured bool `json:"hashingConfigured,omitempty"`
-	SourceIp          bool `json:"sourceIp,omitempty"`
-	DestinationIp     bool `json:"destinationIp,omitempty"`
-	IpProtocol        bool `json:"ipProtocol,omitempty"`
-	SourcePort        bool `json:"sourcePort,omitempty"`
-	DestinationPort   bool `json:"destinationPort,omitempty"`
-}
-
-type GlobalVrouterConfiguration struct {
-	EcmpHashingIncludeFields   EcmpHashingIncludeFields `json:"ecmpHashingIncludeFields,omitempty"`
-	EncapsulationPriorities    string                   `json:"encapPriority,omitempty"`
-	VxlanNetworkIdentifierMode string                   `json:"vxlanNetworkIdentifierMode,omitempty"`
-}
+const defaultUserDomain = "default"
 
 const RequiredAnnotationsKey = "managed_by"
 const MaxRetryAttempts = 5
@@ -84,27 +86,122 @@ func check(err error) {
 	}
 }
 
-func runNodeManager(filePath string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string) {
+func runNodeManager(filePath string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, reportWriter *ReportWriter, options reconcile.Options) {
 	requiredNodesData := loadBytesFromFile(filePath)
-	err := retry(MaxRetryAttempts, BackoffTimeSeconds*time.Second, func() (err error) {
-		return nodemanager.ManageNodes(requiredNodesData, requiredAnnotations, nodeType, contrailClient)
+	err := retry(MaxRetryAttempts, BackoffTimeSeconds*time.Second, func() error {
+		report, err := nodemanager.ManageNodes(requiredNodesData, requiredAnnotations, nodeType, contrailClient, options)
+		if report != nil {
+			reportWriter.Write(report)
+		}
+		// Failed nodes were already retried one by one and are left for the next run,
+		// so that they do not block provisioning of the other nodes.
+		var nodeErrors *reconcile.NodeErrors
+		if errors.As(err, &nodeErrors) {
+			log.Printf("%s node manager: %v\n", nodeType, err)
+			return nil
+		}
+		return err
 	})
 	if err != nil {
 		log.Fatalf("%s node manager failed after %d attempts with error: %s\n", nodeType, MaxRetryAttempts, err)
 	}
 }
 
-func setupNodeFileWatcher(filePath string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string) *FileWatcher {
-	log.Printf("Initial run of node manager for %s\n", nodeType)
-	runNodeManager(filePath, nodeType, contrailClient, requiredAnnotations)
-	log.Printf("Setting up file watcher for %s listed in %s\n", nodeType, filePath)
+func runNodePlan(filePath string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, reportWriter *ReportWriter) {
+	requiredNodesData := loadBytesFromFile(filePath)
+	report, err := nodemanager.PlanNodes(requiredNodesData, requiredAnnotations, nodeType, contrailClient)
+	if err != nil {
+		log.Fatalf("%s node plan failed with error: %s\n", nodeType, err)
+	}
+	check(report.WriteDiff(os.Stdout))
+	reportWriter.Write(report)
+}
+
+func setupNodeFileWatcher(filePath string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, reportWriter *ReportWriter, options reconcile.Options, driftInterval time.Duration) *FileWatcher {
+	return setupFileWatcher(filePath, string(nodeType), driftInterval, func() {
+		runNodeManager(filePath, nodeType, contrailClient, requiredAnnotations, reportWriter, options)
+	})
+}
+
+func setupGlobalSystemConfigWatcher(filePath string, contrailClient contrailclient.ApiClient, reportWriter *ReportWriter, driftInterval time.Duration) *FileWatcher {
+	return setupFileWatcher(filePath, "global-system-config", driftInterval, func() {
+		configuration := getGlobalSystemConfigFromFile(filePath)
+		err := retry(MaxRetryAttempts, BackoffTimeSeconds*time.Second, func() error {
+			report, err := globalsystemconfig.Ensure(contrailClient, configuration)
+			if report != nil {
+				reportWriter.Write(report)
+			}
+			return err
+		})
+		if err != nil {
+			log.Fatalf("global-system-config update failed after %d attempts with error: %s\n", MaxRetryAttempts, err)
+		}
+	})
+}
+
+// setupGlobalVrouterConfigWatcher ensures the global-vrouter-config exists and, if the file
+// exists, watches it for changes.
+func setupGlobalVrouterConfigWatcher(filePath string, contrailClient contrailclient.ApiClient, reportWriter *ReportWriter, driftInterval time.Duration) *FileWatcher {
+	ensure := func() {
+		configuration := getGlobalVrouterConfigFromFile(filePath)
+		err := retry(MaxRetryAttempts, BackoffTimeSeconds*time.Second, func() error {
+			report, err := globalvrouterconfig.Ensure(contrailClient, configuration)
+			if report != nil {
+				reportWriter.Write(report)
+			}
+			return err
+		})
+		if err != nil {
+			log.Fatalf("global-vrouter-config update failed after %d attempts with error: %s\n", MaxRetryAttempts, err)
+		}
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		ensure()
+		return nil
+	}
+	return setupFileWatcher(filePath, "global-vrouter-config", driftInterval, ensure)
+}
+
+// setupControlPlaneWatchers watches files with BGP peers and virtual DNS servers, if given.
+func setupControlPlaneWatchers(bgpPeersPath, virtualDNSPath string, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, reportWriter *ReportWriter, options reconcile.Options, driftInterval time.Duration) []*FileWatcher {
+	var watchers []*FileWatcher
+	if bgpPeersPath != "" {
+		watchers = append(watchers, setupNodeFileWatcher(bgpPeersPath, contrailnode.BgpPeer, contrailClient, requiredAnnotations, reportWriter, options, driftInterval))
+	}
+	if virtualDNSPath != "" {
+		watchers = append(watchers, setupNodeFileWatcher(virtualDNSPath, contrailnode.VirtualDNS, contrailClient, requiredAnnotations, reportWriter, options, driftInterval))
+	}
+	return watchers
+}
+
+// setupFileWatcher calls run initially, whenever the file changes and, if driftInterval is
+// not zero, periodically to revert changes made directly in the API server.
+func setupFileWatcher(filePath string, name string, driftInterval time.Duration, run func()) *FileWatcher {
+	// File events and drift detection must not reconcile the same objects concurrently
+	var mu sync.Mutex
+	locked := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		run()
+	}
+	log.Printf("Initial run of node manager for %s\n", name)
+	locked()
+	log.Printf("Setting up file watcher for %s listed in %s\n", name, filePath)
 	watchFile := strings.Split(filePath, "/")
 	watchPath := strings.TrimSuffix(filePath, watchFile[len(watchFile)-1])
 	nodeWatcher, err := WatchFile(watchPath, time.Second, func() {
-		log.Printf("%s node event\n", nodeType)
-		runNodeManager(filePath, nodeType, contrailClient, requiredAnnotations)
+		log.Printf("%s node event\n", name)
+		locked()
 	})
 	check(err)
+	if driftInterval > 0 {
+		log.Printf("Checking %s for drift every %s\n", name, driftInterval)
+		go func() {
+			for range time.Tick(driftInterval) {
+				locked()
+			}
+		}()
+	}
 	return nodeWatcher
 }
 
@@ -118,116 +215,108 @@ func main() {
 	apiserverPtr := flag.String("apiserver", "/provision.yaml", "path to apiserver yaml file")
 	keystoneAuthConfPtr := flag.String("keystoneAuthConf", "/provision.yaml", "path to keystone authentication configuration file")
 	globalVrouterConfPtr := flag.String("globalVrouterConf", "/provision.yaml", "path to global vrouter configuration file")
+	globalSystemConfPtr := flag.String("globalSystemConf", "", "path to global system configuration yaml file")
+	bgpPeersPtr := flag.String("bgpPeers", "", "path to external BGP peers yaml file")
+	virtualDNSPtr := flag.String("virtualDNS", "", "path to virtual DNS servers yaml file")
 	requiredAnnotationsPtr := flag.String("requiredAnnotations", "/etc/provision/metadata/managed_by", "path to file with required annotation value")
-	modePtr := flag.String("mode", "watch", "watch/run")
+	modePtr := flag.String("mode", "watch", "watch/run/plan/kubernetes")
+	namespacePtr := flag.String("namespace", os.Getenv("POD_NAMESPACE"), "namespace of resources watched in kubernetes mode")
+	reportPtr := flag.String("report", "", "path to file to which JSON reports of node changes are appended")
+	driftIntervalPtr := flag.Duration("driftInterval", 5*time.Minute, "interval of drift detection in watch and kubernetes modes, 0 disables it")
+	statusPtr := flag.String("status", "", "path to JSON file with nodes which failed to be provisioned")
+	workersPtr := flag.Int("workers", 10, "maximum number of nodes changed in parallel")
+	ratePtr := flag.Float64("rate", 20, "maximum number of node changes per second, 0 disables the limit")
+	nodeAttemptsPtr := flag.Int("nodeAttempts", 5, "maximum number of attempts to change a single node")
+	nodeBackoffPtr := flag.Duration("nodeBackoff", time.Second, "initial backoff between attempts to change a single node")
+	nodeMaxBackoffPtr := flag.Duration("nodeMaxBackoff", 30*time.Second, "maximum backoff between attempts to change a single node")
 	flag.Parse()
 
+	reportWriter := NewReportWriter(*reportPtr, *statusPtr)
+	executionOptions := reconcile.Options{
+		Workers:    *workersPtr,
+		Limiter:    reconcile.NewLimiter(*ratePtr),
+		Attempts:   *nodeAttemptsPtr,
+		Backoff:    *nodeBackoffPtr,
+		MaxBackoff: *nodeMaxBackoffPtr,
+	}
+
 	requiredAnnotationValue := string(loadBytesFromFile(*requiredAnnotationsPtr))
 	requiredAnnotations := map[string]string{RequiredAnnotationsKey: requiredAnnotationValue}
 
 	log.Printf("Required annotations for all objects managed by contrail-provisioner: %v", requiredAnnotations)
 
-	if *modePtr == "watch" {
-
-		var apiServer APIServer
-		apiServerYaml, err := ioutil.ReadFile(*apiserverPtr)
-		if err != nil {
-			panic(err)
-		}
-		err = yaml.Unmarshal(apiServerYaml, &apiServer)
-		if err != nil {
-			panic(err)
-		}
-
-		var keystoneAuthParameters *KeystoneAuthParameters = &KeystoneAuthParameters{}
-		if _, err := os.Stat(*keystoneAuthConfPtr); err == nil {
-			keystoneAuthParameters = getKeystoneAuthParametersFromFile(*keystoneAuthConfPtr)
+	if *modePtr == "watch" || *modePtr == "kubernetes" {
+		contrailClient := getAPIClientWithRetry(*apiserverPtr, *keystoneAuthConfPtr)
+		if vrouterConfigWatcher := setupGlobalVrouterConfigWatcher(*globalVrouterConfPtr, contrailClient, reportWriter, *driftIntervalPtr); vrouterConfigWatcher != nil {
+			defer vrouterConfigWatcher.Close()
 		}
 
-		var contrailClient *contrail.Client
-		err = retry(5, 10*time.Second, func() (err error) {
-			contrailClient, err = getAPIClient(&apiServer, keystoneAuthParameters)
-			return
-
-		})
-		if err != nil {
-			if !connectionError(err) {
-				panic(err)
-			}
+		if *globalSystemConfPtr != "" {
+			configWatcher := setupGlobalSystemConfigWatcher(*globalSystemConfPtr, contrailClient, reportWriter, *driftIntervalPtr)
+			defer func() {
+				configWatcher.Close()
+			}()
 		}
 
-		globalVrouterConfiguration := &GlobalVrouterConfiguration{}
-		if _, err := os.Stat(*globalVrouterConfPtr); err == nil {
-			globalVrouterConfiguration = getGlobalVrouterConfigFromFile(*globalVrouterConfPtr)
-		}
-		globalVrouterConfFQName := []string{"default-global-system-config", "default-global-vrouter-config"}
-		encapPriority := strings.Split(globalVrouterConfiguration.EncapsulationPriorities, ",")
-		encapPriorityObj := &contrailtypes.EncapsulationPrioritiesType{Encapsulation: encapPriority}
-		ecmpObj := globalVrouterConfiguration.EcmpHashingIncludeFields
-		ecmpHashingIncludeFieldsObj := &contrailtypes.EcmpHashingIncludeFields{ecmpObj.HashingConfigured, ecmpObj.SourceIp, ecmpObj.DestinationIp, ecmpObj.IpProtocol, ecmpObj.SourcePort, ecmpObj.DestinationPort}
-		GlobalVrouterConfig := &contrailtypes.GlobalVrouterConfig{}
-		GlobalVrouterConfig.SetFQName("", globalVrouterConfFQName)
-		GlobalVrouterConfig.SetEncapsulationPriorities(encapPriorityObj)
-		GlobalVrouterConfig.SetEcmpHashingIncludeFields(ecmpHashingIncludeFieldsObj)
-		GlobalVrouterConfig.SetVxlanNetworkIdentifierMode(globalVrouterConfiguration.VxlanNetworkIdentifierMode)
-		if err = contrailClient.Create(GlobalVrouterConfig); err != nil {
-			if !strings.Contains(err.Error(), "409 Conflict") {
-				panic(err)
-			}
-			obj, err := contrailClient.FindByName("global-vrouter-config", strings.Join(globalVrouterConfFQName, ":"))
-			if err != nil {
-				panic(err)
-			}
-			obj.(*contrailtypes.GlobalVrouterConfig).SetEncapsulationPriorities(encapPriorityObj)
-			obj.(*contrailtypes.GlobalVrouterConfig).SetEcmpHashingIncludeFields(ecmpHashingIncludeFieldsObj)
-			obj.(*contrailtypes.GlobalVrouterConfig).SetVxlanNetworkIdentifierMode(globalVrouterConfiguration.VxlanNetworkIdentifierMode)
-			if err = contrailClient.Update(obj); err != nil {
-				panic(err)
+		if *modePtr == "kubernetes" {
+			log.Printf("start watching resources in namespace %s\n", *namespacePtr)
+			for _, nodeWatcher := range setupControlPlaneWatchers(*bgpPeersPtr, *virtualDNSPtr, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr) {
+				defer nodeWatcher.Close()
 			}
+			check(runCustomResourceWatcher(*namespacePtr, contrailClient, requiredAnnotations, crwatcher.Options{
+				DriftInterval: *driftIntervalPtr,
+				Report:        reportWriter.Write,
+				Execution:     executionOptions,
+			}))
+			return
 		}
 
 		log.Println("start watcher")
 		done := make(chan bool)
 
 		if controlNodesPtr != nil {
-			nodeWatcher := setupNodeFileWatcher(*controlNodesPtr, contrailnode.ControlNode, contrailClient, requiredAnnotations)
+			nodeWatcher := setupNodeFileWatcher(*controlNodesPtr, contrailnode.ControlNode, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr)
 			defer func() {
 				nodeWatcher.Close()
 			}()
 		}
 
 		if vrouterNodesPtr != nil {
-			nodeWatcher := setupNodeFileWatcher(*vrouterNodesPtr, contrailnode.VrouterNode, contrailClient, requiredAnnotations)
+			nodeWatcher := setupNodeFileWatcher(*vrouterNodesPtr, contrailnode.VrouterNode, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr)
 			defer func() {
 				nodeWatcher.Close()
 			}()
 		}
 
 		if analyticsNodesPtr != nil {
-			nodeWatcher := setupNodeFileWatcher(*analyticsNodesPtr, contrailnode.AnalyticsNode, contrailClient, requiredAnnotations)
+			nodeWatcher := setupNodeFileWatcher(*analyticsNodesPtr, contrailnode.AnalyticsNode, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr)
 			defer func() {
 				nodeWatcher.Close()
 			}()
 		}
 
 		if configNodesPtr != nil {
-			nodeWatcher := setupNodeFileWatcher(*configNodesPtr, contrailnode.ConfigNode, contrailClient, requiredAnnotations)
+			nodeWatcher := setupNodeFileWatcher(*configNodesPtr, contrailnode.ConfigNode, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr)
 			defer func() {
 				nodeWatcher.Close()
 			}()
 		}
 
 		if databaseNodesPtr != nil {
-			nodeWatcher := setupNodeFileWatcher(*databaseNodesPtr, contrailnode.DatabaseNode, contrailClient, requiredAnnotations)
+			nodeWatcher := setupNodeFileWatcher(*databaseNodesPtr, contrailnode.DatabaseNode, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr)
 			defer func() {
 				nodeWatcher.Close()
 			}()
 		}
 
+		for _, nodeWatcher := range setupControlPlaneWatchers(*bgpPeersPtr, *virtualDNSPtr, contrailClient, requiredAnnotations, reportWriter, executionOptions, *driftIntervalPtr) {
+			defer nodeWatcher.Close()
+		}
+
 		<-done
 	}
 
-	if *modePtr == "run" {
+	if *modePtr == "run" || *modePtr == "plan" {
 
 		var apiServer APIServer
 
@@ -250,26 +339,115 @@ func main() {
 			panic(err.Error())
 		}
 
+		runNodes := func(filePath string, nodeType contrailnode.ContrailNodeType) {
+			runNodeManager(filePath, nodeType, contrailClient, requiredAnnotations, reportWriter, executionOptions)
+		}
+		if *modePtr == "plan" {
+			runNodes = func(filePath string, nodeType contrailnode.ContrailNodeType) {
+				runNodePlan(filePath, nodeType, contrailClient, requiredAnnotations, reportWriter)
+			}
+		}
+
 		if controlNodesPtr != nil {
-			runNodeManager(*controlNodesPtr, contrailnode.ControlNode, contrailClient, requiredAnnotations)
+			runNodes(*controlNodesPtr, contrailnode.ControlNode)
 		}
 
 		if vrouterNodesPtr != nil {
-			runNodeManager(*vrouterNodesPtr, contrailnode.VrouterNode, contrailClient, requiredAnnotations)
+			runNodes(*vrouterNodesPtr, contrailnode.VrouterNode)
 		}
 
 		if configNodesPtr != nil {
-			runNodeManager(*configNodesPtr, contrailnode.ConfigNode, contrailClient, requiredAnnotations)
+			runNodes(*configNodesPtr, contrailnode.ConfigNode)
 		}
 
 		if analyticsNodesPtr != nil {
-			runNodeManager(*analyticsNodesPtr, contrailnode.AnalyticsNode, contrailClient, requiredAnnotations)
+			runNodes(*analyticsNodesPtr, contrailnode.AnalyticsNode)
 		}
 
 		if databaseNodesPtr != nil {
-			runNodeManager(*databaseNodesPtr, contrailnode.DatabaseNode, contrailClient, requiredAnnotations)
+			runNodes(*databaseNodesPtr, contrailnode.DatabaseNode)
+		}
+
+		if *bgpPeersPtr != "" {
+			runNodes(*bgpPeersPtr, contrailnode.BgpPeer)
+		}
+
+		if *virtualDNSPtr != "" {
+			runNodes(*virtualDNSPtr, contrailnode.VirtualDNS)
+		}
+
+		if *globalSystemConfPtr != "" {
+			configuration := getGlobalSystemConfigFromFile(*globalSystemConfPtr)
+			if *modePtr == "plan" {
+				report, err := globalsystemconfig.Plan(contrailClient, configuration)
+				check(err)
+				check(report.WriteDiff(os.Stdout))
+				reportWriter.Write(report)
+			} else {
+				report, err := globalsystemconfig.Ensure(contrailClient, configuration)
+				check(err)
+				reportWriter.Write(report)
+			}
+		}
+
+		if _, err := os.Stat(*globalVrouterConfPtr); err == nil {
+			configuration := getGlobalVrouterConfigFromFile(*globalVrouterConfPtr)
+			if *modePtr == "plan" {
+				report, err := globalvrouterconfig.Plan(contrailClient, configuration)
+				check(err)
+				check(report.WriteDiff(os.Stdout))
+				reportWriter.Write(report)
+			} else {
+				report, err := globalvrouterconfig.Ensure(contrailClient, configuration)
+				check(err)
+				reportWriter.Write(report)
+			}
+		}
+	}
+}
+
+func getAPIClientWithRetry(apiserverPath, keystoneAuthConfPath string) contrailclient.ApiClient {
+	var apiServer APIServer
+	apiServerYaml, err := ioutil.ReadFile(apiserverPath)
+	if err != nil {
+		panic(err)
+	}
+	err = yaml.Unmarshal(apiServerYaml, &apiServer)
+	if err != nil {
+		panic(err)
+	}
+
+	var keystoneAuthParameters *KeystoneAuthParameters = &KeystoneAuthParameters{}
+	if _, err := os.Stat(keystoneAuthConfPath); err == nil {
+		keystoneAuthParameters = getKeystoneAuthParametersFromFile(keystoneAuthConfPath)
+	}
+
+	var contrailClient contrailclient.ApiClient
+	err = retry(5, 10*time.Second, func() (err error) {
+		contrailClient, err = getAPIClient(&apiServer, keystoneAuthParameters)
+		return
+
+	})
+	if err != nil {
+		if !connectionError(err) {
+			panic(err)
 		}
 	}
+	return contrailClient
+}
+
+func runCustomResourceWatcher(namespace string, contrailClient contrailclient.ApiClient, requiredAnnotations map[string]string, options crwatcher.Options) error {
+	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{Namespace: namespace, MetricsBindAddress: "0"})
+	if err != nil {
+		return err
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := crwatcher.Add(mgr, namespace, contrailClient, requiredAnnotations, options); err != nil {
+		return err
+	}
+	return mgr.Start(signals.SetupSignalHandler())
 }
 
 func retry(attempts int, sleep time.Duration, f func() error) (err error) {
@@ -323,13 +501,15 @@ func connectionError(err error) bool {
 	return false
 }
 
-func getAPIClient(apiServerObj *APIServer, keystoneAuthParameters *KeystoneAuthParameters) (*contrail.Client, error) {
-	var contrailClient *contrail.Client
+// getAPIClient returns client which fails over between all servers from the API server
+// list and, if keystone is configured, authenticates with keystone tokens.
+func getAPIClient(apiServerObj *APIServer, keystoneAuthParameters *KeystoneAuthParameters) (contrailclient.ApiClient, error) {
+	var clients []*contrail.Client
 	for _, apiServer := range apiServerObj.APIServerList {
 		apiServerSlice := strings.Split(apiServer, ":")
 		apiPortInt, err := strconv.Atoi(apiServerSlice[1])
 		if err != nil {
-			return contrailClient, err
+			return nil, err
 		}
 		log.Printf("api server %s:%d\n", apiServerSlice[0], apiPortInt)
 		contrailClient := contrail.NewClient(apiServerSlice[0], apiPortInt)
@@ -337,61 +517,45 @@ func getAPIClient(apiServerObj *APIServer, keystoneAuthParameters *KeystoneAuthP
 		if err != nil {
 			return nil, err
 		}
-		if keystoneAuthParameters.AuthUrl != "" {
-			setupAuthKeystone(contrailClient, keystoneAuthParameters)
-		}
-		//contrailClient.AddHTTPParameter(1)
-		_, err = contrailClient.List("global-system-config")
-		if err == nil {
-			return contrailClient, nil
+		clients = append(clients, contrailClient)
+	}
+	var keystone *apiclient.KeystoneAuthenticator
+	if keystoneAuthParameters.AuthUrl != "" {
+		var err error
+		if keystone, err = getKeystoneAuthenticator(keystoneAuthParameters); err != nil {
+			return nil, err
 		}
 	}
-	return contrailClient, fmt.Errorf("%s", "cannot get api server")
-
+	contrailClient := apiclient.NewFailoverClient(clients, keystone)
+	if _, err := contrailClient.List("global-system-config"); err != nil {
+		return nil, fmt.Errorf("cannot get api server: %w", err)
+	}
+	return contrailClient, nil
 }
 
-func setupAuthKeystone(client *contrail.Client, keystoneAuthParameters *KeystoneAuthParameters) {
-	var keystone *contrail.KeepaliveKeystoneClient
+func getKeystoneAuthenticator(keystoneAuthParameters *KeystoneAuthParameters) (*apiclient.KeystoneAuthenticator, error) {
+	config := apiclient.KeystoneConfig{
+		AuthURL:                     keystoneAuthParameters.AuthUrl,
+		Username:                    keystoneAuthParameters.AdminUsername,
+		Password:                    keystoneAuthParameters.AdminPassword,
+		ApplicationCredentialID:     keystoneAuthParameters.ApplicationCredentialID,
+		ApplicationCredentialName:   keystoneAuthParameters.ApplicationCredentialName,
+		ApplicationCredentialSecret: keystoneAuthParameters.ApplicationCredentialSecret,
+		UserDomain:                  keystoneAuthParameters.UserDomain,
+	}
 	if strings.HasPrefix(keystoneAuthParameters.AuthUrl, "https") {
-		// AddEncryption expected http url in older versions of contrail-go-api
-		// https://github.com/Juniper/contrail-go-api/commit/4c876ba038a8ecec211376133375d467b6098202
-		keystone = contrail.NewKeepaliveKeystoneClient(
-			strings.Replace(keystoneAuthParameters.AuthUrl, "https", "http", 1),
-			keystoneAuthParameters.TenantName,
-			keystoneAuthParameters.AdminUsername,
-			keystoneAuthParameters.AdminPassword,
-			"",
-		)
-		err := keystone.AddEncryption(
-			keystoneAuthParameters.Encryption.CA,
-			keystoneAuthParameters.Encryption.Key,
-			keystoneAuthParameters.Encryption.Cert,
-			keystoneAuthParameters.Encryption.Insecure)
-
+		encryption := keystoneAuthParameters.Encryption
+		tlsConfig, err := apiclient.NewTLSConfig(encryption.CA, encryption.Key, encryption.Cert, encryption.Insecure)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
-	} else {
-		keystone = contrail.NewKeepaliveKeystoneClient(
-			keystoneAuthParameters.AuthUrl,
-			keystoneAuthParameters.TenantName,
-			keystoneAuthParameters.AdminUsername,
-			keystoneAuthParameters.AdminPassword,
-			"",
-		)
-	}
-	err := keystone.AuthenticateV3()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+			return nil, err
+		}
+		config.TLSConfig = tlsConfig
 	}
-	client.SetAuthenticator(keystone)
-
+	return apiclient.NewKeystoneAuthenticator(config), nil
 }
 
 func getKeystoneAuthParametersFromFile(authParamsFilePath string) *KeystoneAuthParameters {
-	var keystoneAuthParameters *KeystoneAuthParameters
+	keystoneAuthParameters := &KeystoneAuthParameters{UserDomain: defaultUserDomain}
 	keystoneAuthYaml, err := ioutil.ReadFile(authParamsFilePath)
 	if err != nil {
 		panic(err)
@@ -402,14 +566,17 @@ func getKeystoneAuthParametersFromFile(authParamsFilePath string) *KeystoneAuthP
 	return keystoneAuthParameters
 }
 
-func getGlobalVrouterConfigFromFile(globalVrouterFilePath string) *GlobalVrouterConfiguration {
-	var globalVrouterConfig *GlobalVrouterConfiguration
-	globalVrouterJson, err := ioutil.ReadFile(globalVrouterFilePath)
-	if err != nil {
-		panic(err)
-	}
-	if err = json.Unmarshal([]byte(globalVrouterJson), &globalVrouterConfig); err != nil {
-		panic(err)
+func getGlobalSystemConfigFromFile(globalSystemConfigFilePath string) globalsystemconfig.GlobalSystemConfiguration {
+	var globalSystemConfig globalsystemconfig.GlobalSystemConfiguration
+	check(yaml.Unmarshal(loadBytesFromFile(globalSystemConfigFilePath), &globalSystemConfig))
+	return globalSystemConfig
+}
+
+// getGlobalVrouterConfigFromFile returns empty configuration if the file does not exist.
+func getGlobalVrouterConfigFromFile(globalVrouterFilePath string) globalvrouterconfig.GlobalVrouterConfiguration {
+	var globalVrouterConfig globalvrouterconfig.GlobalVrouterConfiguration
+	if globalVrouterJson := loadBytesFromFile(globalVrouterFilePath); len(globalVrouterJson) > 0 {
+		check(json.Unmarshal(globalVrouterJson, &globalVrouterConfig))
 	}
 	return globalVrouterConfig
 }
@@ -4,12 +4,14 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/Juniper/contrail-operator/contrail-provisioner/analyticsnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/bgppeer"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/confignode"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/controlnode"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/databasenode"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/virtualdns"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/vrouternode"
 )
 
@@ -61,6 +63,24 @@ func getContrailNodesFromBytes(data []byte, nodeType contrailnode.ContrailNodeTy
 		for _, v := range databaseNodes {
 			contrailNodes = append(contrailNodes, v)
 		}
+	case contrailnode.BgpPeer:
+		var bgpPeers []*bgppeer.BgpPeer
+		err := yaml.Unmarshal(data, &bgpPeers)
+		if err != nil {
+			panic(err)
+		}
+		for _, v := range bgpPeers {
+			contrailNodes = append(contrailNodes, v)
+		}
+	case contrailnode.VirtualDNS:
+		var virtualDNSServers []*virtualdns.VirtualDNS
+		err := yaml.Unmarshal(data, &virtualDNSServers)
+		if err != nil {
+			panic(err)
+		}
+		for _, v := range virtualDNSServers {
+			contrailNodes = append(contrailNodes, v)
+		}
 	}
 	return contrailNodes
 }
@@ -79,16 +99,39 @@ func getContrailNodesInApiServer(contrailClient contrailclient.ApiClient, nodeTy
 		contrailNodesInApiServer, err = controlnode.GetContrailNodesFromApiServer(contrailClient)
 	case contrailnode.DatabaseNode:
 		contrailNodesInApiServer, err = databasenode.GetContrailNodesFromApiServer(contrailClient)
+	case contrailnode.BgpPeer:
+		contrailNodesInApiServer, err = bgppeer.GetContrailNodesFromApiServer(contrailClient)
+	case contrailnode.VirtualDNS:
+		contrailNodesInApiServer, err = virtualdns.GetContrailNodesFromApiServer(contrailClient)
 	}
 	return contrailNodesInApiServer, err
 }
 
-func ManageNodes(requiredNodesData []byte, requiredAnnotations map[string]string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient) error {
+func ManageNodes(requiredNodesData []byte, requiredAnnotations map[string]string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, options reconcile.Options) (*Report, error) {
+	requiredNodes := getContrailNodesFromBytes(requiredNodesData, nodeType)
+	return ManageContrailNodes(requiredNodes, requiredAnnotations, nodeType, contrailClient, options)
+}
+
+// ManageContrailNodes reconciles nodes of the given type in the Contrail API server with
+// the list of required nodes and reports the changes which were made. When only some
+// of the changes failed the report is returned together with *reconcile.NodeErrors.
+func ManageContrailNodes(requiredNodes []contrailnode.ContrailNode, requiredAnnotations map[string]string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient, options reconcile.Options) (*Report, error) {
+	reconciler := reconcile.NewReconciler(contrailClient, requiredNodes, requiredAnnotations, options)
+	nodesInApiServer, err := getContrailNodesInApiServer(contrailClient, nodeType)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := reconciler.ReconcileNodes(nodesInApiServer)
+	return NewReport(nodeType, false, changes), err
+}
+
+// PlanNodes reports the changes which ManageNodes would make without making them.
+func PlanNodes(requiredNodesData []byte, requiredAnnotations map[string]string, nodeType contrailnode.ContrailNodeType, contrailClient contrailclient.ApiClient) (*Report, error) {
 	requiredNodes := getContrailNodesFromBytes(requiredNodesData, nodeType)
-	reconciler := reconcile.NewReconciler(contrailClient, requiredNodes, requiredAnnotations)
+	reconciler := reconcile.NewReconciler(contrailClient, requiredNodes, requiredAnnotations, reconcile.Options{})
 	nodesInApiServer, err := getContrailNodesInApiServer(contrailClient, nodeType)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return reconciler.ReconcileNodes(nodesInApiServer)
+	return NewReport(nodeType, true, reconciler.Plan(nodesInApiServer)), nil
 }
@@ -0,0 +1,70 @@
+package nodemanager
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+)
+
+// Report lists changes of nodes of a single type. When DryRun is set the changes were only
+// planned, otherwise they were made in the API server.
+type Report struct {
+	NodeType contrailnode.ContrailNodeType `json:"nodeType"`
+	DryRun   bool                          `json:"dryRun"`
+	Time     time.Time                     `json:"time"`
+	Changes  []reconcile.Change            `json:"changes"`
+}
+
+// NewReport returns report of changes of objects of the given type.
+func NewReport(nodeType contrailnode.ContrailNodeType, dryRun bool, changes []reconcile.Change) *Report {
+	if changes == nil {
+		changes = []reconcile.Change{}
+	}
+	return &Report{NodeType: nodeType, DryRun: dryRun, Time: time.Now().UTC(), Changes: changes}
+}
+
+var diffPrefixes = map[string]string{
+	"create": "+",
+	"update": "~",
+	"delete": "-",
+}
+
+// WriteDiff writes changes from the report in a diff-like format, e.g.
+//
+//   + virtual-router node-1
+//   ~ virtual-router node-2
+//       ipAddress: "1.1.1.1" => "1.1.1.2"
+//   - virtual-router node-3
+//       error: 409 Conflict
+func (r *Report) WriteDiff(w io.Writer) error {
+	for _, change := range r.Changes {
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", diffPrefixes[change.Action], r.NodeType, change.Hostname); err != nil {
+			return err
+		}
+		for _, field := range change.Fields {
+			if _, err := fmt.Fprintf(w, "    %s: %q => %q\n", field.Field, field.Current, field.Required); err != nil {
+				return err
+			}
+		}
+		if change.Error != "" {
+			if _, err := fmt.Fprintf(w, "    error: %s\n", change.Error); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Failed returns changes which could not be made.
+func (r *Report) Failed() []reconcile.Change {
+	var failed []reconcile.Change
+	for _, change := range r.Changes {
+		if change.Error != "" {
+			failed = append(failed, change)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,171 @@
+package reconcile
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options control how the reconciler executes changes.
+type Options struct {
+	// Workers is the maximum number of nodes changed in parallel. Values lower than 1 mean 1.
+	Workers int
+	// Limiter limits the rate of requests made to the API server. It is meant to be
+	// shared by all reconcilers of the process. Nil means no limit.
+	Limiter *Limiter
+	// Attempts is the maximum number of attempts to change a single node. Values lower
+	// than 1 mean 1.
+	Attempts int
+	// Backoff is the base of the exponential backoff between attempts to change a node.
+	// Each backoff is randomized between half and the full value.
+	Backoff time.Duration
+	// MaxBackoff limits the backoff between attempts. Zero means no limit.
+	MaxBackoff time.Duration
+}
+
+// NodeErrors is returned when changes of some nodes failed. Changes of all other nodes
+// were made regardless.
+type NodeErrors struct {
+	Failed []Change
+}
+
+func (e *NodeErrors) Error() string {
+	var errs []string
+	for _, change := range e.Failed {
+		errs = append(errs, fmt.Sprintf("%s %s: %s", change.Action, change.Hostname, change.Error))
+	}
+	return fmt.Sprintf("%d node changes failed: %s", len(e.Failed), strings.Join(errs, "; "))
+}
+
+var reconcileInfoLog = log.New(os.Stdout, fmt.Sprintf("%-15s ", "reconcile:"), log.LstdFlags|log.Lmsgprefix)
+
+var sleep = time.Sleep
+
+// execute makes the changes using a bounded pool of workers. Every change is retried
+// with a jittered exponential backoff. Failures of a change do not stop the others, they
+// are recorded in the change and returned as NodeErrors.
+func (r *Reconciler) execute(changes []Change) ([]Change, error) {
+	workers := r.options.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(changes) {
+		workers = len(changes)
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := r.executeWithRetry(changes[i]); err != nil {
+					changes[i].Error = err.Error()
+				}
+			}
+		}()
+	}
+	for i := range changes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failed []Change
+	for _, change := range changes {
+		if change.Error != "" {
+			failed = append(failed, change)
+		}
+	}
+	if len(failed) > 0 {
+		return changes, &NodeErrors{Failed: failed}
+	}
+	return changes, nil
+}
+
+func (r *Reconciler) executeWithRetry(change Change) error {
+	attempts := r.options.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			reconcileInfoLog.Printf("Retrying %s of %s after error: %v\n", change.Action, change.Hostname, err)
+			sleep(r.backoff(attempt))
+		}
+		r.options.Limiter.Wait()
+		if err = r.executeChange(change); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (r *Reconciler) executeChange(change Change) error {
+	switch change.action {
+	case updateAction:
+		return change.node.Update(r.cl)
+	case createAction:
+		return change.node.Create(r.cl)
+	case deleteAction:
+		return change.node.Delete(r.cl)
+	}
+	return nil
+}
+
+func (r *Reconciler) backoff(attempt int) time.Duration {
+	backoff := r.options.Backoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if r.options.MaxBackoff > 0 && backoff >= r.options.MaxBackoff {
+			backoff = r.options.MaxBackoff
+			break
+		}
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// Limiter spaces requests evenly so that at most rate requests are made per second.
+// It is safe for concurrent use, so a single Limiter bounds the requests of every
+// reconciler which shares it.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter returns a Limiter allowing rate requests per second. Rates lower than or
+// equal to zero mean no limit, for which nil is returned.
+func NewLimiter(rate float64) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &Limiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+// Wait blocks until the next request is allowed. Waiting on a nil Limiter returns at once.
+func (l *Limiter) Wait() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if delay > 0 {
+		sleep(delay)
+	}
+}
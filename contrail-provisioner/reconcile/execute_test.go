@@ -0,0 +1,125 @@
+package reconcile
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/fake"
+)
+
+type testNode struct {
+	hostname    string
+	annotations map[string]string
+	failures    int32
+	calls       int32
+	inFlight    *int32
+	maxInFlight *int32
+}
+
+func (n *testNode) Create(contrailclient.ApiClient) error {
+	if n.inFlight != nil {
+		current := atomic.AddInt32(n.inFlight, 1)
+		defer atomic.AddInt32(n.inFlight, -1)
+		for {
+			max := atomic.LoadInt32(n.maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(n.maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if atomic.AddInt32(&n.calls, 1) <= n.failures {
+		return errors.New("500 Internal Server Error")
+	}
+	return nil
+}
+
+func (n *testNode) Update(contrailclient.ApiClient) error        { return nil }
+func (n *testNode) Delete(contrailclient.ApiClient) error        { return nil }
+func (n *testNode) GetHostname() string                          { return n.hostname }
+func (n *testNode) GetAnnotations() map[string]string            { return n.annotations }
+func (n *testNode) SetAnnotations(annotations map[string]string) { n.annotations = annotations }
+
+func TestReconcileNodesContinuesPastFailingNodes(t *testing.T) {
+	defer func(s func(time.Duration)) { sleep = s }(sleep)
+	var sleeps []time.Duration
+	sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	failing := &testNode{hostname: "failing", failures: 10}
+	flaky := &testNode{hostname: "flaky", failures: 1}
+	healthy := &testNode{hostname: "healthy"}
+	reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), []contrailnode.ContrailNode{failing, flaky, healthy}, map[string]string{}, Options{
+		Attempts:   3,
+		Backoff:    time.Second,
+		MaxBackoff: 10 * time.Second,
+	})
+
+	changes, err := reconciler.ReconcileNodes(nil)
+
+	var nodeErrors *NodeErrors
+	require.True(t, errors.As(err, &nodeErrors))
+	require.Len(t, nodeErrors.Failed, 1)
+	assert.Equal(t, "failing", nodeErrors.Failed[0].Hostname)
+	assert.Equal(t, "500 Internal Server Error", nodeErrors.Failed[0].Error)
+	require.Len(t, changes, 3)
+	assert.Empty(t, changes[1].Error)
+	assert.Empty(t, changes[2].Error)
+	assert.Equal(t, int32(3), failing.calls)
+	assert.Equal(t, int32(2), flaky.calls)
+	assert.Equal(t, int32(1), healthy.calls)
+	assert.Len(t, sleeps, 3)
+}
+
+func TestReconcileNodesLimitsParallelism(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var nodes []contrailnode.ContrailNode
+	for _, hostname := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		nodes = append(nodes, &testNode{hostname: hostname, inFlight: &inFlight, maxInFlight: &maxInFlight})
+	}
+	reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), nodes, map[string]string{}, Options{Workers: 3})
+
+	changes, err := reconciler.ReconcileNodes(nil)
+
+	require.NoError(t, err)
+	assert.Len(t, changes, 8)
+	assert.True(t, maxInFlight > 1, "changes should be made in parallel")
+	assert.True(t, maxInFlight <= 3, "at most 3 changes should be made in parallel, got %d", maxInFlight)
+}
+
+func TestBackoff(t *testing.T) {
+	reconciler := NewReconciler(nil, nil, nil, Options{Backoff: time.Second, MaxBackoff: 5 * time.Second})
+	for attempt, max := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		backoff := reconciler.backoff(attempt)
+		assert.True(t, backoff >= max/2 && backoff <= max, "backoff of attempt %d should be between %s and %s, got %s", attempt, max/2, max, backoff)
+	}
+}
+
+func TestLimiterIsSharedByReconcilers(t *testing.T) {
+	defer func(s func(time.Duration)) { sleep = s }(sleep)
+	var sleeps []time.Duration
+	sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	limiter := NewLimiter(10)
+	for _, hostname := range []string{"a", "b", "c"} {
+		reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), []contrailnode.ContrailNode{&testNode{hostname: hostname}}, map[string]string{}, Options{Limiter: limiter})
+		_, err := reconciler.ReconcileNodes(nil)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, sleeps, 2)
+	assert.True(t, sleeps[0] > 50*time.Millisecond && sleeps[0] <= 100*time.Millisecond, "second request should wait for about 100ms, got %s", sleeps[0])
+	assert.True(t, sleeps[1] > 150*time.Millisecond && sleeps[1] <= 200*time.Millisecond, "third request should wait for about 200ms, got %s", sleeps[1])
+}
+
+func TestNilLimiterDoesNotWait(t *testing.T) {
+	assert.Nil(t, NewLimiter(0))
+	var limiter *Limiter
+	limiter.Wait()
+}
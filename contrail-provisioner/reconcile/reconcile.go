@@ -1,6 +1,11 @@
 package reconcile
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
 )
@@ -13,6 +18,36 @@ const (
 	deleteAction
 )
 
+func (a Action) String() string {
+	switch a {
+	case updateAction:
+		return "update"
+	case createAction:
+		return "create"
+	case deleteAction:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// Change describes an action which the reconciler takes, or would take, on a single node.
+type Change struct {
+	Action   string        `json:"action"`
+	Hostname string        `json:"hostname"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	node     contrailnode.ContrailNode
+	action   Action
+}
+
+// FieldChange describes a field of a node whose value in the API server differs from the
+// required one.
+type FieldChange struct {
+	Field    string `json:"field"`
+	Current  string `json:"current"`
+	Required string `json:"required"`
+}
+
 type NodeWithAction struct {
 	node   contrailnode.ContrailNode
 	action Action
@@ -22,38 +57,145 @@ type Reconciler struct {
 	cl                  contrailclient.ApiClient
 	requiredNodes       []contrailnode.ContrailNode
 	requiredAnnotations map[string]string
+	options             Options
+}
+
+func NewReconciler(cl contrailclient.ApiClient, requiredNodes []contrailnode.ContrailNode, requiredAnnotations map[string]string, options Options) *Reconciler {
+	return &Reconciler{cl: cl, requiredNodes: requiredNodes, requiredAnnotations: requiredAnnotations, options: options}
 }
 
-func NewReconciler(cl contrailclient.ApiClient, requiredNodes []contrailnode.ContrailNode, requiredAnnotations map[string]string) *Reconciler {
-	return &Reconciler{cl: cl, requiredNodes: requiredNodes, requiredAnnotations: requiredAnnotations}
+// ReconcileNodes makes nodes in the API server match the required nodes and returns the
+// changes. Changes which failed have the Error set and are also returned as NodeErrors.
+func (r *Reconciler) ReconcileNodes(nodesInApiServer []contrailnode.ContrailNode) ([]Change, error) {
+	return r.execute(r.Plan(nodesInApiServer))
 }
 
-func (r *Reconciler) ReconcileNodes(nodesInApiServer []contrailnode.ContrailNode) error {
+// Plan returns the changes needed to make nodes in the API server match the required
+// nodes without executing them. Nodes which exist in the API server are only updated
+// when any of their fields differ from the required ones.
+func (r *Reconciler) Plan(nodesInApiServer []contrailnode.ContrailNode) []Change {
 	r.ensureRequiredAnnotationsOnRequiredNodes()
 	managedNodesInApiServer := r.getNodesWithRequiredAnnotations(nodesInApiServer)
 	actionMap := r.createContrailNodesActionMap(managedNodesInApiServer)
-	if err := r.executeActionMap(actionMap); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (r *Reconciler) executeActionMap(actionMap map[string]NodeWithAction) error {
-	for _, nodeWithAction := range actionMap {
-		var err error
-		switch nodeWithAction.action {
-		case updateAction:
-			err = nodeWithAction.node.Update(r.cl)
-		case createAction:
-			err = nodeWithAction.node.Create(r.cl)
-		case deleteAction:
-			err = nodeWithAction.node.Delete(r.cl)
+	currentNodes := make(map[string]contrailnode.ContrailNode, len(managedNodesInApiServer))
+	for _, node := range managedNodesInApiServer {
+		currentNodes[node.GetHostname()] = node
+	}
+	hostnames := make([]string, 0, len(actionMap))
+	for hostname := range actionMap {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	changes := []Change{}
+	for _, hostname := range hostnames {
+		nodeWithAction := actionMap[hostname]
+		change := Change{
+			Action:   nodeWithAction.action.String(),
+			Hostname: hostname,
+			node:     nodeWithAction.node,
+			action:   nodeWithAction.action,
+		}
+		if nodeWithAction.action == updateAction {
+			change.Fields = diffNodes(currentNodes[hostname], nodeWithAction.node)
+			if len(change.Fields) == 0 {
+				continue
+			}
 		}
-		if err != nil {
-			return err
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffNodes compares all fields of two nodes of the same type. Fields of embedded structs
+// are compared as if they were fields of the node itself. Values of fields tagged with
+// diff:"secret" are not revealed, the change only shows that they differ.
+func diffNodes(current, required contrailnode.ContrailNode) []FieldChange {
+	currentFields := nodeFields(reflect.ValueOf(current))
+	requiredFields := nodeFields(reflect.ValueOf(required))
+	var names []string
+	for name := range requiredFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var fieldChanges []FieldChange
+	for _, name := range names {
+		currentField, requiredField := currentFields[name], requiredFields[name]
+		if !reflect.DeepEqual(normalize(currentField.value), normalize(requiredField.value)) {
+			fieldChanges = append(fieldChanges, FieldChange{
+				Field:    name,
+				Current:  currentField.format(),
+				Required: requiredField.format(),
+			})
 		}
 	}
-	return nil
+	return fieldChanges
+}
+
+// redacted replaces values of secret fields in changes
+const redacted = "<redacted>"
+
+type nodeField struct {
+	value  interface{}
+	secret bool
+}
+
+func (f nodeField) format() string {
+	if normalize(f.value) == nil {
+		return ""
+	}
+	if f.secret {
+		if reflect.ValueOf(f.value).IsZero() {
+			return ""
+		}
+		return redacted
+	}
+	return fmt.Sprint(f.value)
+}
+
+func nodeFields(v reflect.Value) map[string]nodeField {
+	fields := map[string]nodeField{}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return fields
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fields
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Anonymous {
+			for name, value := range nodeFields(v.Field(i)) {
+				fields[name] = value
+			}
+			continue
+		}
+		fields[fieldName(f)] = nodeField{value: v.Field(i).Interface(), secret: f.Tag.Get("diff") == "secret"}
+	}
+	return fields
+}
+
+// fieldName returns the yaml name of the field, or the Go name with a lower case first
+// letter when the field has no yaml name or is not read from yaml at all.
+func fieldName(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("yaml"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(f.Name[:1]) + f.Name[1:]
+}
+
+// normalize treats empty maps and slices the same as missing ones.
+func normalize(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.Len() == 0 {
+		return nil
+	}
+	return value
 }
 
 func (r *Reconciler) createContrailNodesActionMap(nodesInApiServer []contrailnode.ContrailNode) map[string]NodeWithAction {
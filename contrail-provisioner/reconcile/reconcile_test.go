@@ -4,39 +4,42 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/Juniper/contrail-operator/contrail-provisioner/bgppeer"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/controlnode"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/fake"
 	"github.com/Juniper/contrail-operator/contrail-provisioner/vrouternode"
 )
 
 func TestCreateContrailNodesActionMap(t *testing.T) {
 	var requiredNodeOne contrailnode.ContrailNode = &vrouternode.VrouterNode{
-		contrailnode.Node{
+		Node: contrailnode.Node{
 			IPAddress: "1.1.1.1",
 			Hostname:  "first-node",
 		},
 	}
 	var requiredNodeTwo contrailnode.ContrailNode = &vrouternode.VrouterNode{
-		contrailnode.Node{
+		Node: contrailnode.Node{
 			IPAddress: "2.2.2.2",
 			Hostname:  "second-node",
 		},
 	}
 	var modifiedRequiredNodeTwo contrailnode.ContrailNode = &vrouternode.VrouterNode{
-		contrailnode.Node{
+		Node: contrailnode.Node{
 			IPAddress: "2.2.2.3",
 			Hostname:  "second-node",
 		},
 	}
 	var apiServerNodeOne contrailnode.ContrailNode = &vrouternode.VrouterNode{
-		contrailnode.Node{
+		Node: contrailnode.Node{
 			IPAddress: "1.1.1.1",
 			Hostname:  "first-node",
 		},
 	}
 	var apiServerNodeTwo contrailnode.ContrailNode = &vrouternode.VrouterNode{
-		contrailnode.Node{
+		Node: contrailnode.Node{
 			IPAddress: "2.2.2.2",
 			Hostname:  "second-node",
 		},
@@ -93,9 +96,92 @@ func TestCreateContrailNodesActionMap(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), testCase.requiredNodes, map[string]string{})
+			reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), testCase.requiredNodes, map[string]string{}, Options{})
 			actualActionMap := reconciler.createContrailNodesActionMap(testCase.nodesInApiServer)
 			assert.Equal(t, testCase.expectedActionMap, actualActionMap)
 		})
 	}
 }
+
+func TestPlan(t *testing.T) {
+	annotations := map[string]string{"managed_by": "test"}
+	newControlNode := func(hostname, ip string, asn int, annotations map[string]string) contrailnode.ContrailNode {
+		return &controlnode.ControlNode{
+			Node: contrailnode.Node{IPAddress: ip, Hostname: hostname, Annotations: annotations},
+			ASN:  asn,
+		}
+	}
+	nodesInApiServer := []contrailnode.ContrailNode{
+		newControlNode("in-sync", "1.1.1.1", 64512, annotations),
+		newControlNode("drifted", "2.2.2.2", 64513, annotations),
+		newControlNode("not-required", "3.3.3.3", 64512, annotations),
+		newControlNode("not-managed", "4.4.4.4", 64512, nil),
+	}
+	requiredNodes := []contrailnode.ContrailNode{
+		newControlNode("in-sync", "1.1.1.1", 64512, nil),
+		newControlNode("drifted", "2.2.2.3", 64512, nil),
+		newControlNode("missing", "5.5.5.5", 64512, nil),
+	}
+	reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), requiredNodes, annotations, Options{})
+
+	changes := reconciler.Plan(nodesInApiServer)
+
+	var summary []Change
+	for _, change := range changes {
+		summary = append(summary, Change{Action: change.Action, Hostname: change.Hostname, Fields: change.Fields})
+	}
+	assert.Equal(t, []Change{
+		{Action: "update", Hostname: "drifted", Fields: []FieldChange{
+			{Field: "asn", Current: "64513", Required: "64512"},
+			{Field: "ipAddress", Current: "2.2.2.2", Required: "2.2.2.3"},
+		}},
+		{Action: "create", Hostname: "missing"},
+		{Action: "delete", Hostname: "not-required"},
+	}, summary)
+}
+
+func TestPlanRepairsVrouterWithoutVhost0Interface(t *testing.T) {
+	annotations := map[string]string{"managed_by": "test"}
+	newVrouterNode := func(hostname string, vhost0Interface bool, annotations map[string]string) contrailnode.ContrailNode {
+		return &vrouternode.VrouterNode{
+			Node:            contrailnode.Node{IPAddress: "1.1.1.1", Hostname: hostname, Annotations: annotations},
+			Vhost0Interface: vhost0Interface,
+		}
+	}
+	nodesInApiServer := []contrailnode.ContrailNode{
+		newVrouterNode("in-sync", true, annotations),
+		newVrouterNode("without-vhost0", false, annotations),
+	}
+	requiredNodes := []contrailnode.ContrailNode{
+		newVrouterNode("in-sync", true, nil),
+		newVrouterNode("without-vhost0", true, nil),
+	}
+	reconciler := NewReconciler(fake.GetDefaultFakeContrailClient(), requiredNodes, annotations, Options{})
+
+	changes := reconciler.Plan(nodesInApiServer)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, "update", changes[0].Action)
+	assert.Equal(t, "without-vhost0", changes[0].Hostname)
+	assert.Equal(t, []FieldChange{{Field: "vhost0Interface", Current: "false", Required: "true"}}, changes[0].Fields)
+}
+
+func TestDiffNodesRedactsSecretFields(t *testing.T) {
+	current := &bgppeer.BgpPeer{
+		Node:    contrailnode.Node{IPAddress: "10.0.0.1", Hostname: "mx1"},
+		AuthKey: "old-key",
+	}
+	required := &bgppeer.BgpPeer{
+		Node:                   contrailnode.Node{IPAddress: "10.0.0.1", Hostname: "mx1"},
+		AuthKey:                "new-key",
+		PeeredWithControlNodes: true,
+	}
+
+	assert.Equal(t, []FieldChange{
+		{Field: "authKey", Current: redacted, Required: redacted},
+		{Field: "peeredWithControlNodes", Current: "false", Required: "true"},
+	}, diffNodes(current, required))
+
+	required.AuthKey = ""
+	assert.Contains(t, diffNodes(current, required), FieldChange{Field: "authKey", Current: redacted, Required: ""})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/nodemanager"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/reconcile"
+)
+
+/*
+Appends node manager reports to a file as JSON lines, one report per line.
+Reports without changes are skipped unless they come from a dry run.
+When path is empty reports are only logged.
+
+When statusPath is set, the file is overwritten after every run with nodes
+which failed to be provisioned in the last run for each node type.
+*/
+type ReportWriter struct {
+	path       string
+	statusPath string
+	mu         sync.Mutex
+	status     ProvisionStatus
+}
+
+// ProvisionStatus lists nodes which failed to be provisioned by node type.
+type ProvisionStatus struct {
+	NodeTypes map[contrailnode.ContrailNodeType]NodeTypeStatus `json:"nodeTypes"`
+}
+
+// NodeTypeStatus describes the last run of node manager for a single node type.
+type NodeTypeStatus struct {
+	Time   time.Time          `json:"time"`
+	Failed []reconcile.Change `json:"failed"`
+}
+
+func NewReportWriter(path, statusPath string) *ReportWriter {
+	return &ReportWriter{
+		path:       path,
+		statusPath: statusPath,
+		status:     ProvisionStatus{NodeTypes: map[contrailnode.ContrailNodeType]NodeTypeStatus{}},
+	}
+}
+
+func (w *ReportWriter) Write(report *nodemanager.Report) {
+	if !report.DryRun {
+		w.writeStatus(report)
+	}
+	if len(report.Changes) == 0 && !report.DryRun {
+		return
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		log.Printf("failed to marshal %s report: %v\n", report.NodeType, err)
+		return
+	}
+	log.Printf("%s report: %s\n", report.NodeType, data)
+	if w.path == "" {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("failed to open report file %s: %v\n", w.path, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.Printf("failed to write report file %s: %v\n", w.path, err)
+	}
+}
+
+func (w *ReportWriter) writeStatus(report *nodemanager.Report) {
+	if w.statusPath == "" {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	failed := report.Failed()
+	if failed == nil {
+		failed = []reconcile.Change{}
+	}
+	w.status.NodeTypes[report.NodeType] = NodeTypeStatus{Time: report.Time, Failed: failed}
+	data, err := json.MarshalIndent(w.status, "", "  ")
+	if err != nil {
+		log.Printf("failed to marshal provision status: %v\n", err)
+		return
+	}
+	// Readers must never see a partially written file
+	tmp, err := ioutil.TempFile(filepath.Dir(w.statusPath), filepath.Base(w.statusPath))
+	if err != nil {
+		log.Printf("failed to create status file %s: %v\n", w.statusPath, err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(append(data, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), w.statusPath)
+	}
+	if err != nil {
+		log.Printf("failed to write status file %s: %v\n", w.statusPath, err)
+	}
+}
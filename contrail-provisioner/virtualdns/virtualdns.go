@@ -0,0 +1,145 @@
+package virtualdns
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	contrailtypes "github.com/Juniper/contrail-operator/contrail-provisioner/contrail-go-types"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailclient"
+	"github.com/Juniper/contrail-operator/contrail-provisioner/contrailnode"
+)
+
+// VirtualDNS struct defines Contrail virtual DNS server
+type VirtualDNS struct {
+	Name                     string            `yaml:"name,omitempty"`
+	Annotations              map[string]string `yaml:"annotations,omitempty"`
+	DomainName               string            `yaml:"domainName,omitempty"`
+	DefaultTTLSeconds        int               `yaml:"defaultTTLSeconds,omitempty"`
+	RecordOrder              string            `yaml:"recordOrder,omitempty"`
+	NextVirtualDNS           string            `yaml:"nextVirtualDNS,omitempty"`
+	FloatingIPRecord         string            `yaml:"floatingIPRecord,omitempty"`
+	DynamicRecordsFromClient bool              `yaml:"dynamicRecordsFromClient,omitempty"`
+	ExternalVisible          bool              `yaml:"externalVisible,omitempty"`
+	ReverseResolution        bool              `yaml:"reverseResolution,omitempty"`
+}
+
+const (
+	nodeType           contrailnode.ContrailNodeType = contrailnode.VirtualDNS
+	defaultRecordOrder string                        = "random"
+	defaultTTLSeconds  int                           = 86400
+	defaultFIPRecord   string                        = "dashed-ip-tenant-name"
+)
+
+var defaultDomain = []string{"default-domain"}
+
+var virtualDNSInfoLog *log.Logger
+
+func init() {
+	prefix := fmt.Sprintf("%-15s ", nodeType+":")
+	virtualDNSInfoLog = log.New(os.Stdout, prefix, log.LstdFlags|log.Lmsgprefix)
+}
+
+// UnmarshalYAML sets defaults of fields missing in the server definition, so that the
+// server compares equal to its VirtualDns read from the API server.
+func (c *VirtualDNS) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawVirtualDNS VirtualDNS
+	raw := rawVirtualDNS{
+		DefaultTTLSeconds: defaultTTLSeconds,
+		RecordOrder:       defaultRecordOrder,
+		FloatingIPRecord:  defaultFIPRecord,
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*c = VirtualDNS(raw)
+	return nil
+}
+
+// Create creates a VirtualDns instance
+func (c *VirtualDNS) Create(contrailClient contrailclient.ApiClient) error {
+	virtualDNSInfoLog.Printf("Creating %s %s\n", c.Name, nodeType)
+	virtualDNS := &contrailtypes.VirtualDns{}
+	virtualDNS.SetFQName("domain", append(defaultDomain, c.Name))
+	c.setData(virtualDNS)
+	return contrailClient.Create(virtualDNS)
+}
+
+// Update updates a VirtualDns instance
+func (c *VirtualDNS) Update(contrailClient contrailclient.ApiClient) error {
+	virtualDNSInfoLog.Printf("Updating %s %s\n", c.Name, nodeType)
+	obj, err := contrailclient.GetContrailObjectByName(contrailClient, string(nodeType), c.Name)
+	if err != nil {
+		return err
+	}
+	virtualDNS := obj.(*contrailtypes.VirtualDns)
+	c.setData(virtualDNS)
+	return contrailClient.Update(virtualDNS)
+}
+
+// Delete deletes a VirtualDns instance
+func (c *VirtualDNS) Delete(contrailClient contrailclient.ApiClient) error {
+	virtualDNSInfoLog.Printf("Deleting %s %s\n", c.Name, nodeType)
+	obj, err := contrailclient.GetContrailObjectByName(contrailClient, string(nodeType), c.Name)
+	if err != nil {
+		return err
+	}
+	return contrailClient.Delete(obj)
+}
+
+func (c *VirtualDNS) GetHostname() string {
+	return c.Name
+}
+
+func (c *VirtualDNS) GetAnnotations() map[string]string {
+	return c.Annotations
+}
+
+func (c *VirtualDNS) SetAnnotations(annotations map[string]string) {
+	c.Annotations = annotations
+}
+
+func (c *VirtualDNS) setData(virtualDNS *contrailtypes.VirtualDns) {
+	annotations := contrailclient.ConvertMapToContrailKeyValuePairs(c.Annotations)
+	virtualDNS.SetAnnotations(&annotations)
+	virtualDNS.SetVirtualDnsData(&contrailtypes.VirtualDnsType{
+		DomainName:               c.DomainName,
+		DefaultTtlSeconds:        c.DefaultTTLSeconds,
+		RecordOrder:              c.RecordOrder,
+		NextVirtualDns:           c.NextVirtualDNS,
+		FloatingIpRecord:         c.FloatingIPRecord,
+		DynamicRecordsFromClient: c.DynamicRecordsFromClient,
+		ExternalVisible:          c.ExternalVisible,
+		ReverseResolution:        c.ReverseResolution,
+	})
+}
+
+// GetContrailNodesFromApiServer returns virtual DNS servers of the default domain
+func GetContrailNodesFromApiServer(contrailClient contrailclient.ApiClient) ([]contrailnode.ContrailNode, error) {
+	nodesInApiServer := []contrailnode.ContrailNode{}
+	listResults, err := contrailClient.List(string(nodeType))
+	if err != nil {
+		return nodesInApiServer, err
+	}
+	for _, listResult := range listResults {
+		obj, err := contrailClient.ReadListResult(string(nodeType), &listResult)
+		if err != nil {
+			return nodesInApiServer, err
+		}
+		virtualDNS := obj.(*contrailtypes.VirtualDns)
+		data := virtualDNS.GetVirtualDnsData()
+		nodesInApiServer = append(nodesInApiServer, &VirtualDNS{
+			Name:                     virtualDNS.GetName(),
+			Annotations:              contrailclient.ConvertContrailKeyValuePairsToMap(virtualDNS.GetAnnotations()),
+			DomainName:               data.DomainName,
+			DefaultTTLSeconds:        data.DefaultTtlSeconds,
+			RecordOrder:              data.RecordOrder,
+			NextVirtualDNS:           data.NextVirtualDns,
+			FloatingIPRecord:         data.FloatingIpRecord,
+			DynamicRecordsFromClient: data.DynamicRecordsFromClient,
+			ExternalVisible:          data.ExternalVisible,
+			ReverseResolution:        data.ReverseResolution,
+		})
+	}
+	return nodesInApiServer, nil
+}
@@ -15,6 +15,9 @@ import (
 // VrouterNode struct defines Contrail Vrouter node
 type VrouterNode struct {
 	contrailnode.Node `yaml:",inline"`
+	// Vhost0Interface is set when the VirtualRouter has the vhost0 virtual-machine-interface.
+	// It is not read from the input, as the interface is always required.
+	Vhost0Interface bool `yaml:"-"`
 }
 
 const (
@@ -101,6 +104,18 @@ func (c *VrouterNode) Delete(contrailClient contrailclient.ApiClient) error {
 	return nil
 }
 
+// UnmarshalYAML requires the vhost0 interface for all nodes read from the input
+func (c *VrouterNode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type rawVrouterNode VrouterNode
+	raw := rawVrouterNode{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	raw.Vhost0Interface = true
+	*c = VrouterNode(raw)
+	return nil
+}
+
 func (c *VrouterNode) GetHostname() string {
 	return c.Hostname
 }
@@ -125,12 +140,17 @@ func GetContrailNodesFromApiServer(contrailClient contrailclient.ApiClient) ([]c
 			return nodesInApiServer, err
 		}
 		typedNode := obj.(*contrailtypes.VirtualRouter)
+		vhost0Interface, err := vhost0VMIPresent(typedNode, contrailClient)
+		if err != nil {
+			return nodesInApiServer, err
+		}
 		node := &VrouterNode{
-			contrailnode.Node{
+			Node: contrailnode.Node{
 				IPAddress:   typedNode.GetVirtualRouterIpAddress(),
 				Hostname:    typedNode.GetName(),
 				Annotations: contrailclient.ConvertContrailKeyValuePairsToMap(typedNode.GetAnnotations()),
 			},
+			Vhost0Interface: vhost0Interface,
 		}
 		nodesInApiServer = append(nodesInApiServer, node)
 	}
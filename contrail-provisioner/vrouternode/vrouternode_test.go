@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
 	contrail "github.com/Juniper/contrail-go-api"
 
@@ -25,8 +27,8 @@ func TestGetVrouterNodesInApiServerCreatesVrouterNodeObjects(t *testing.T) {
 	}
 
 	expectedContrailNodes := []contrailnode.ContrailNode{
-		&VrouterNode{contrailnode.Node{IPAddress: "1.1.1.1", Hostname: "virtual-router-one", Annotations: map[string]string{}}},
-		&VrouterNode{contrailnode.Node{IPAddress: "1.1.1.1", Hostname: "virtual-router-one", Annotations: map[string]string{}}},
+		&VrouterNode{Node: contrailnode.Node{IPAddress: "1.1.1.1", Hostname: "virtual-router-one", Annotations: map[string]string{}}},
+		&VrouterNode{Node: contrailnode.Node{IPAddress: "1.1.1.1", Hostname: "virtual-router-one", Annotations: map[string]string{}}},
 	}
 	actualContrailNodes, err := GetContrailNodesFromApiServer(fakeContrailClient)
 
@@ -54,3 +56,13 @@ func TestGetVrouterNodesInApiServerReturnsEmptyListWhenNoNodesInApiServer(t *tes
 	assert.NoError(t, err)
 	assert.Len(t, actualVirtualRouterNodes, 0)
 }
+
+func TestVrouterNodesReadFromInputRequireVhost0Interface(t *testing.T) {
+	var nodes []*VrouterNode
+	require.NoError(t, yaml.Unmarshal([]byte("- ipAddress: 1.1.1.1\n  hostname: node1\n"), &nodes))
+
+	assert.Equal(t, []*VrouterNode{{
+		Node:            contrailnode.Node{IPAddress: "1.1.1.1", Hostname: "node1"},
+		Vhost0Interface: true,
+	}}, nodes)
+}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strconv"
 	"time"
@@ -45,6 +46,7 @@ type MonitorConfig struct {
 	NodeName       string            `yaml:"nodeName,omitempty"`
 	Namespace      string            `yaml:"namespace,omitempty"`
 	PodName        string            `yaml:"podName,omitempty"`
+	MetricsPort    int               `yaml:"metricsPort,omitempty"`
 }
 
 type MonitorEncryption struct {
@@ -62,6 +64,39 @@ type Container struct {
 	Command []string `json:"command,omitempty"`
 }
 
+// VirtualNetworkFQName is the fully qualified name of a Contrail virtual network.
+// +k8s:openapi-gen=true
+type VirtualNetworkFQName struct {
+	// Domain defaults to default-domain
+	Domain  string `json:"domain,omitempty"`
+	Project string `json:"project"`
+	Name    string `json:"name"`
+}
+
+// DefaultDomain is the Contrail domain used when a fully qualified name does not set one
+const DefaultDomain = "default-domain"
+
+// WithDefaults returns the name with the default domain filled in
+func (n VirtualNetworkFQName) WithDefaults() VirtualNetworkFQName {
+	if n.Domain == "" {
+		n.Domain = DefaultDomain
+	}
+	return n
+}
+
+// FQName returns the name as a list of its parts
+func (n VirtualNetworkFQName) FQName() []string {
+	n = n.WithDefaults()
+	return []string{n.Domain, n.Project, n.Name}
+}
+
+func (n VirtualNetworkFQName) validate() error {
+	if n.Project == "" || n.Name == "" {
+		return fmt.Errorf("virtual network project and name are required")
+	}
+	return nil
+}
+
 // ServiceStatus provides information on the current status of the service.
 // +k8s:openapi-gen=true
 type ServiceStatus struct {
@@ -231,13 +266,14 @@ func StatusMonitorConfig(hostname string, configNodeList []string, podIP, nodeTy
 			Key:      &key,
 			Insecure: true,
 		},
-		NodeType:  nodeType,
-		Hostname:  hostname,
-		Interval:  10,
-		InCluster: &inCluster,
-		NodeName:  nodeName,
-		Namespace: namespace,
-		PodName:   podName,
+		NodeType:    nodeType,
+		Hostname:    hostname,
+		Interval:    10,
+		InCluster:   &inCluster,
+		NodeName:    nodeName,
+		Namespace:   namespace,
+		PodName:     podName,
+		MetricsPort: StatusMonitorMetricsPort(nodeType),
 	}
 
 	monitorYaml, err := yaml.Marshal(monitorConfig)
@@ -247,6 +283,24 @@ func StatusMonitorConfig(hostname string, configNodeList []string, podIP, nodeTy
 	return string(monitorYaml), nil
 }
 
+// StatusMonitorMetricsPort returns port on which statusmonitor of the node type serves
+// Prometheus metrics. Ports differ per node type because the pods may share host network.
+func StatusMonitorMetricsPort(nodeType string) int {
+	switch nodeType {
+	case "control":
+		return ControlStatusMonitorMetricsPort
+	case "config":
+		return ConfigStatusMonitorMetricsPort
+	case "vrouter":
+		return VrouterStatusMonitorMetricsPort
+	case "webui":
+		return WebuiStatusMonitorMetricsPort
+	case "database":
+		return DatabaseStatusMonitorMetricsPort
+	}
+	return 0
+}
+
 // SetPodsToReady sets the status label of a POD to ready.
 func SetPodsToReady(podList *corev1.PodList, client client.Client) error {
 	for _, pod := range podList.Items {
@@ -60,10 +60,20 @@ type CassandraConfiguration struct {
 // CassandraStatus defines the status of the cassandra object.
 // +k8s:openapi-gen=true
 type CassandraStatus struct {
-	Active    *bool                `json:"active,omitempty"`
-	Nodes     map[string]string    `json:"nodes,omitempty"`
-	Ports     CassandraStatusPorts `json:"ports,omitempty"`
-	ClusterIP string               `json:"clusterIP,omitempty"`
+	Active        *bool                             `json:"active,omitempty"`
+	Nodes         map[string]string                 `json:"nodes,omitempty"`
+	Ports         CassandraStatusPorts              `json:"ports,omitempty"`
+	ClusterIP     string                            `json:"clusterIP,omitempty"`
+	ServiceStatus map[string]CassandraServiceStatus `json:"serviceStatus,omitempty"`
+}
+
+// CassandraServiceStatus is the database nodemanager status reported by the statusmonitor.
+// +k8s:openapi-gen=true
+type CassandraServiceStatus struct {
+	ModuleName  string       `json:"moduleName,omitempty"`
+	ModuleState string       `json:"state"`
+	Description string       `json:"description,omitempty"`
+	Connections []Connection `json:"connections,omitempty"`
 }
 
 // CassandraStatusPorts defines the status of the ports of the cassandra object.
@@ -139,12 +149,18 @@ func (c *Cassandra) InstanceConfiguration(request reconcile.Request,
 		})
 		cassandraConfigString := cassandraConfigBuffer.String()
 
+		cqlEndpoint := podList.Items[idx].Status.PodIP + ":" + strconv.Itoa(*cassandraConfig.CqlPort) + "::cassandra"
+		statusMonitorConfig, err := StatusMonitorConfig(podList.Items[idx].Annotations["hostname"], []string{cqlEndpoint},
+			podList.Items[idx].Status.PodIP, "database", request.Name, request.Namespace, podList.Items[idx].Name)
+		if err != nil {
+			return err
+		}
+
 		if configMapInstanceDynamicConfig.Data == nil {
-			data := map[string]string{podList.Items[idx].Status.PodIP + ".yaml": cassandraConfigString}
-			configMapInstanceDynamicConfig.Data = data
-		} else {
-			configMapInstanceDynamicConfig.Data[podList.Items[idx].Status.PodIP+".yaml"] = cassandraConfigString
+			configMapInstanceDynamicConfig.Data = map[string]string{}
 		}
+		configMapInstanceDynamicConfig.Data[podList.Items[idx].Status.PodIP+".yaml"] = cassandraConfigString
+		configMapInstanceDynamicConfig.Data["monitorconfig."+podList.Items[idx].Status.PodIP+".yaml"] = statusMonitorConfig
 		err = client.Update(context.TODO(), configMapInstanceDynamicConfig)
 		if err != nil {
 			return err
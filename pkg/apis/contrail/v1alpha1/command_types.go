@@ -46,6 +46,7 @@ type CommandConfiguration struct {
 	ContrailVersion    string            `json:"contrailVersion,omitempty"`
 	Containers         []*Container      `json:"containers,omitempty"`
 	Endpoints          []CommandEndpoint `json:"endpoints,omitempty"`
+	Resources          *CommandResources `json:"resources,omitempty"`
 }
 
 // CommandEndpoint is used to register extra endpoints in Command
@@ -56,14 +57,106 @@ type CommandEndpoint struct {
 	PrivateURL string `json:"privateURL,omitempty"`
 }
 
+// CommandResources are Contrail Command objects seeded at bootstrap and kept in sync afterwards.
+// Credentials, users and nodes referring to credentials are not seeded, as they would expose
+// secrets in the bootstrap config map. They are created by the first sync.
+// +k8s:openapi-gen=true
+type CommandResources struct {
+	Clusters    []CommandCluster    `json:"clusters,omitempty"`
+	Nodes       []CommandNode       `json:"nodes,omitempty"`
+	Credentials []CommandCredential `json:"credentials,omitempty"`
+	Users       []CommandUser       `json:"users,omitempty"`
+	Roles       []CommandRole       `json:"roles,omitempty"`
+	// ResyncPeriod is the time in seconds after which resources are synced again
+	// to revert changes made outside of the operator.
+	ResyncPeriod int `json:"resyncPeriod,omitempty"`
+}
+
+// CommandCluster is a Contrail cluster registered in Command
+// +k8s:openapi-gen=true
+type CommandCluster struct {
+	Name             string `json:"name"`
+	DisplayName      string `json:"displayName,omitempty"`
+	ContrailVersion  string `json:"contrailVersion,omitempty"`
+	Orchestrator     string `json:"orchestrator,omitempty"`
+	HighAvailability bool   `json:"highAvailability,omitempty"`
+}
+
+// CommandNode is a node registered in Command
+// +k8s:openapi-gen=true
+type CommandNode struct {
+	Name      string `json:"name"`
+	Hostname  string `json:"hostname,omitempty"`
+	IPAddress string `json:"ipAddress"`
+	// +kubebuilder:validation:Enum={"","private","public"}
+	Type string `json:"type,omitempty"`
+	// Credential is the name of the credential used to access the node.
+	Credential string `json:"credential,omitempty"`
+}
+
+// CommandCredential is an SSH credential used by Command to access nodes.
+// SecretName refers to a secret with user and password keys.
+// +k8s:openapi-gen=true
+type CommandCredential struct {
+	Name       string `json:"name"`
+	SecretName string `json:"secretName"`
+}
+
+// CommandUser is a Command user assigned to the Keystone project.
+// PasswordSecretName refers to a secret with the password key.
+// +k8s:openapi-gen=true
+type CommandUser struct {
+	Name               string `json:"name"`
+	Domain             string `json:"domain,omitempty"`
+	Project            string `json:"project"`
+	PasswordSecretName string `json:"passwordSecretName"`
+}
+
+// CommandRole is a set of RBAC rules (API access list) attached to the Keystone project.
+// +k8s:openapi-gen=true
+type CommandRole struct {
+	Name    string            `json:"name"`
+	Domain  string            `json:"domain,omitempty"`
+	Project string            `json:"project"`
+	Rules   []CommandRBACRule `json:"rules"`
+}
+
+// CommandRBACRule grants CRUD permissions on the object (and optionally its field) to Keystone roles
+// +k8s:openapi-gen=true
+type CommandRBACRule struct {
+	Object string                  `json:"object"`
+	Field  string                  `json:"field,omitempty"`
+	Perms  []CommandRBACPermission `json:"perms"`
+}
+
+// CommandRBACPermission maps the Keystone role to allowed operations, e.g. "CRUD" or "R"
+// +k8s:openapi-gen=true
+type CommandRBACPermission struct {
+	Role string `json:"role"`
+	CRUD string `json:"crud"`
+}
+
+// CommandResourceStatus shows whether the resource managed by the operator is in sync with Command.
+// Resources removed from the spec stay listed until they are deleted from Command.
+// +k8s:openapi-gen=true
+type CommandResourceStatus struct {
+	Kind         string       `json:"kind"`
+	Name         string       `json:"name"`
+	InSync       bool         `json:"inSync"`
+	Checksum     string       `json:"checksum,omitempty"`
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
 // CommandStatus defines the observed state of Command
 // +k8s:openapi-gen=true
 type CommandStatus struct {
 	Status               `json:",inline"`
-	Endpoint             string              `json:"endpoint,omitempty"`
-	UpgradeState         CommandUpgradeState `json:"upgradeState,omitempty"`
-	TargetContainerImage string              `json:"targetContainerImage,omitempty"`
-	ContainerImage       string              `json:"containerImage,omitempty"`
+	Endpoint             string                  `json:"endpoint,omitempty"`
+	UpgradeState         CommandUpgradeState     `json:"upgradeState,omitempty"`
+	TargetContainerImage string                  `json:"targetContainerImage,omitempty"`
+	ContainerImage       string                  `json:"containerImage,omitempty"`
+	Resources            []CommandResourceStatus `json:"resources,omitempty"`
 }
 
 // +kubebuilder:validation:Enum={"","upgrading","not upgrading","shutting down before upgrade","starting upgraded deployment", "upgrade failed"}
@@ -113,7 +113,10 @@ type ConfigStatus struct {
 	Ports         ConfigStatusPorts                 `json:"ports,omitempty"`
 	ConfigChanged *bool                             `json:"configChanged,omitempty"`
 	ServiceStatus map[string]ConfigServiceStatusMap `json:"serviceStatus,omitempty"`
-	Endpoint      string                            `json:"endpoint,omitempty"`
+	// AnalyticsServiceStatus is the status of analytics services running in config pods,
+	// keyed by hostname.
+	AnalyticsServiceStatus map[string]ConfigServiceStatusMap `json:"analyticsServiceStatus,omitempty"`
+	Endpoint               string                            `json:"endpoint,omitempty"`
 }
 
 type ConfigServiceStatusMap map[string]ConfigServiceStatus
@@ -245,6 +248,7 @@ func (c *Config) InstanceConfiguration(request reconcile.Request,
 			"contrail-svc-monitor":    *configConfig.SvcMonitorIntrospectPort,
 			"contrail-analytics-api":  *configConfig.AnalyticsApiIntrospectPort,
 			"contrail-collector":      *configConfig.CollectorIntrospectPort,
+			"contrail-query-engine":   QueryengineIntrospectPort,
 		}
 		for service, port := range introspectPorts {
 			nodesPortStr := pod.Status.PodIP + ":" + strconv.Itoa(port) + "::" + service
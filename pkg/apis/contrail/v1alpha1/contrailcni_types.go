@@ -2,9 +2,10 @@ package v1alpha1
 
 import (
 	"context"
-	"strconv"
+	"fmt"
+	"net"
 
-	batch "k8s.io/api/batch/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,6 +46,51 @@ type ContrailCNIConfiguration struct {
 	PollTimeout     *int32       `json:"pollTimeout,omitempty"`
 	PollRetries     *int32       `json:"pollRetries,omitempty"`
 	LogLevel        *int32       `json:"logLevel,omitempty"`
+	// NetworkRole decides whether the contrail CNI is the default network of pods or is only
+	// attached to pods as an additional network through Multus. Default is default.
+	NetworkRole CNINetworkRole `json:"networkRole,omitempty"`
+	// Networks are Contrail virtual networks published as Multus NetworkAttachmentDefinitions
+	Networks []ContrailCNINetwork `json:"networks,omitempty"`
+	// Namespaces are isolation settings applied to namespaces and handled by Kubemanager
+	Namespaces []ContrailCNINamespace `json:"namespaces,omitempty"`
+}
+
+// CNINetworkRole is the role of the contrail CNI among the networks of a pod
+// +kubebuilder:validation:Enum=default;secondary
+type CNINetworkRole string
+
+const (
+	// DefaultCNINetworkRole makes contrail the cluster network used for the primary interface of pods
+	DefaultCNINetworkRole CNINetworkRole = "default"
+	// SecondaryCNINetworkRole leaves the primary interface to another CNI, contrail networks are
+	// attached only to pods requesting them with the k8s.v1.cni.cncf.io/networks annotation
+	SecondaryCNINetworkRole CNINetworkRole = "secondary"
+)
+
+// ContrailCNINetwork is a Contrail virtual network which pods may attach to through Multus.
+// Either VirtualNetwork or CIDR has to be set.
+// +k8s:openapi-gen=true
+type ContrailCNINetwork struct {
+	// Name of the NetworkAttachmentDefinition
+	Name string `json:"name"`
+	// Namespace of the NetworkAttachmentDefinition, defaults to the namespace of the ContrailCNI
+	Namespace string `json:"namespace,omitempty"`
+	// VirtualNetwork refers to an existing virtual network
+	VirtualNetwork *VirtualNetworkFQName `json:"virtualNetwork,omitempty"`
+	// CIDR makes Kubemanager create a virtual network with this subnet
+	CIDR               string `json:"cidr,omitempty"`
+	IPFabricForwarding *bool  `json:"ipFabricForwarding,omitempty"`
+	IPFabricSnat       *bool  `json:"ipFabricSnat,omitempty"`
+}
+
+// ContrailCNINamespace are isolation settings of a namespace
+// +k8s:openapi-gen=true
+type ContrailCNINamespace struct {
+	Name string `json:"name"`
+	// Isolated namespaces get their own pod and service virtual networks
+	Isolated *bool `json:"isolated,omitempty"`
+	// VirtualNetwork is used for pods of the namespace instead of the cluster pod network
+	VirtualNetwork *VirtualNetworkFQName `json:"virtualNetwork,omitempty"`
 }
 
 //CNIPodConfiguration is the Common Configuration for ContrailCNI
@@ -71,6 +117,16 @@ type CNIPodConfiguration struct {
 // ContrailCNIStatus defines the observed state of ContrailCNI
 type ContrailCNIStatus struct {
 	Status `json:",inline"`
+	// Nodes is the state of the CNI plugin installation on each node, keyed by node name
+	Nodes map[string]ContrailCNINodeStatus `json:"nodes,omitempty"`
+}
+
+// ContrailCNINodeStatus is the CNI plugin installation state of a node
+// +k8s:openapi-gen=true
+type ContrailCNINodeStatus struct {
+	Pod string `json:"pod,omitempty"`
+	// Installed is true when binaries and configuration on the node match the desired ones
+	Installed bool `json:"installed"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -93,44 +149,103 @@ func init() {
 	SchemeBuilder.Register(&ContrailCNI{}, &ContrailCNIList{})
 }
 
-// PrepareJob prepares the intended podList.
-func (c *ContrailCNI) PrepareJob(job *batch.Job,
+// NetworkRole returns the role of the contrail CNI among the networks of a pod
+func (c *ContrailCNI) NetworkRole() CNINetworkRole {
+	if c.Spec.ServiceConfiguration.NetworkRole == "" {
+		return DefaultCNINetworkRole
+	}
+	return c.Spec.ServiceConfiguration.NetworkRole
+}
+
+// Validate checks the Multus networks and namespace isolation settings.
+func (c *ContrailCNI) Validate() error {
+	switch c.NetworkRole() {
+	case DefaultCNINetworkRole, SecondaryCNINetworkRole:
+	default:
+		return fmt.Errorf("unsupported network role %q", c.Spec.ServiceConfiguration.NetworkRole)
+	}
+	networks := map[string]bool{}
+	for _, network := range c.Spec.ServiceConfiguration.Networks {
+		if network.Name == "" {
+			return fmt.Errorf("network name is required")
+		}
+		key := network.NetworkNamespace(c.Namespace) + "/" + network.Name
+		if networks[key] {
+			return fmt.Errorf("network %s is defined more than once", key)
+		}
+		networks[key] = true
+		if (network.VirtualNetwork == nil) == (network.CIDR == "") {
+			return fmt.Errorf("network %s has to set either virtualNetwork or cidr", key)
+		}
+		if network.VirtualNetwork != nil {
+			if err := network.VirtualNetwork.validate(); err != nil {
+				return fmt.Errorf("network %s: %v", key, err)
+			}
+		}
+		if network.CIDR != "" {
+			if _, _, err := net.ParseCIDR(network.CIDR); err != nil {
+				return fmt.Errorf("network %s: %v", key, err)
+			}
+		}
+	}
+	namespaces := map[string]bool{}
+	for _, namespace := range c.Spec.ServiceConfiguration.Namespaces {
+		if namespace.Name == "" {
+			return fmt.Errorf("namespace name is required")
+		}
+		if namespaces[namespace.Name] {
+			return fmt.Errorf("namespace %s is defined more than once", namespace.Name)
+		}
+		namespaces[namespace.Name] = true
+		if namespace.VirtualNetwork != nil {
+			if err := namespace.VirtualNetwork.validate(); err != nil {
+				return fmt.Errorf("namespace %s: %v", namespace.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// NetworkNamespace returns the namespace of the NetworkAttachmentDefinition of the network
+func (n ContrailCNINetwork) NetworkNamespace(defaultNamespace string) string {
+	if n.Namespace == "" {
+		return defaultNamespace
+	}
+	return n.Namespace
+}
+
+// PrepareDaemonSet prepares the intended DaemonSet.
+func (c *ContrailCNI) PrepareDaemonSet(ds *appsv1.DaemonSet,
 	instance *ContrailCNI,
 	request reconcile.Request,
-	scheme *runtime.Scheme,
-	client client.Client) error {
+	scheme *runtime.Scheme) error {
 	instanceType := "contrailcni"
-	SetJobCommonConfiguration(job, &instance.Spec.CommonConfiguration)
-	job.SetName(request.Name + "-" + instanceType + "-job")
-	job.SetNamespace(request.Namespace)
-	job.SetLabels(map[string]string{"contrail_manager": instanceType,
-		instanceType:            request.Name,
-		"controller_generation": strconv.FormatInt(instance.Generation, 10)})
-	job.Spec.Selector.MatchLabels = map[string]string{"contrail_manager": instanceType,
+	SetCNIDaemonSetCommonConfiguration(ds, &instance.Spec.CommonConfiguration)
+	ds.SetName(request.Name + "-" + instanceType + "-daemonset")
+	ds.SetNamespace(request.Namespace)
+	ds.SetLabels(map[string]string{"contrail_manager": instanceType,
+		instanceType: request.Name})
+	ds.Spec.Selector.MatchLabels = map[string]string{"contrail_manager": instanceType,
 		instanceType: request.Name}
-	job.Spec.Template.SetLabels(map[string]string{"contrail_manager": instanceType,
+	ds.Spec.Template.SetLabels(map[string]string{"contrail_manager": instanceType,
 		instanceType: request.Name})
-	err := controllerutil.SetControllerReference(c, job, scheme)
-	if err != nil {
-		return err
-	}
-	return nil
+	return controllerutil.SetControllerReference(c, ds, scheme)
 }
 
-// SetJobCommonConfiguration takes common configuration parameters
+// SetCNIDaemonSetCommonConfiguration takes common configuration parameters
 // and applies it to the pod.
-func SetJobCommonConfiguration(job *batch.Job,
+func SetCNIDaemonSetCommonConfiguration(ds *appsv1.DaemonSet,
 	commonConfiguration *CNIPodConfiguration) {
 	if len(commonConfiguration.Tolerations) > 0 {
-		job.Spec.Template.Spec.Tolerations = commonConfiguration.Tolerations
+		ds.Spec.Template.Spec.Tolerations = commonConfiguration.Tolerations
 	}
 	if len(commonConfiguration.NodeSelector) > 0 {
-		job.Spec.Template.Spec.NodeSelector = commonConfiguration.NodeSelector
+		ds.Spec.Template.Spec.NodeSelector = commonConfiguration.NodeSelector
 	}
 	if commonConfiguration.HostNetwork != nil {
-		job.Spec.Template.Spec.HostNetwork = *commonConfiguration.HostNetwork
+		ds.Spec.Template.Spec.HostNetwork = *commonConfiguration.HostNetwork
 	} else {
-		job.Spec.Template.Spec.HostNetwork = false
+		ds.Spec.Template.Spec.HostNetwork = false
 	}
 	if len(commonConfiguration.ImagePullSecrets) > 0 {
 		imagePullSecretList := []corev1.LocalObjectReference{}
@@ -140,27 +255,61 @@ func SetJobCommonConfiguration(job *batch.Job,
 			}
 			imagePullSecretList = append(imagePullSecretList, imagePullSecret)
 		}
-		job.Spec.Template.Spec.ImagePullSecrets = imagePullSecretList
+		ds.Spec.Template.Spec.ImagePullSecrets = imagePullSecretList
 	}
 }
 
-// SetInstanceActive sets the instance to active.
-func (c *ContrailCNI) SetInstanceActive(client client.Client, activeStatus *bool, job *batch.Job, request reconcile.Request) error {
-	if err := client.Get(context.TODO(), types.NamespacedName{Name: job.Name, Namespace: request.Namespace},
-		job); err != nil {
+// SetInstanceActive records the installation state of every node running a pod of the DaemonSet
+// and sets the instance to active when the current plugin version is installed on all of them.
+func (c *ContrailCNI) SetInstanceActive(reconcileClient client.Client, ds *appsv1.DaemonSet, request reconcile.Request) error {
+	if err := reconcileClient.Get(context.TODO(), types.NamespacedName{Name: ds.Name, Namespace: request.Namespace},
+		ds); err != nil {
 		return err
 	}
-	active := false
-	if job.Status.Succeeded == *job.Spec.Completions {
-		active = true
+	pods := &corev1.PodList{}
+	if err := reconcileClient.List(context.TODO(), pods, client.InNamespace(request.Namespace),
+		client.MatchingLabels(ds.Spec.Selector.MatchLabels)); err != nil {
+		return err
+	}
+	nodes := map[string]ContrailCNINodeStatus{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		nodes[pod.Spec.NodeName] = ContrailCNINodeStatus{
+			Pod:       pod.Name,
+			Installed: podReady(pod),
+		}
 	}
+	c.Status.Nodes = nodes
+	c.Status.Replicas = ds.Status.DesiredNumberScheduled
+	c.Status.ReadyReplicas = ds.Status.NumberReady
+	c.Status.Active = daemonSetRolledOut(ds)
+	return reconcileClient.Status().Update(context.TODO(), c)
+}
+
+// daemonSetRolledOut returns true when the current spec of the DaemonSet is scheduled on at least one
+// node and its pods are updated and ready on all of them.
+func daemonSetRolledOut(ds *appsv1.DaemonSet) bool {
+	status := ds.Status
+	return status.ObservedGeneration == ds.Generation &&
+		status.DesiredNumberScheduled > 0 &&
+		status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberReady == status.DesiredNumberScheduled
+}
 
-	*activeStatus = active
-	return client.Status().Update(context.TODO(), c)
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
 }
 
 type CNIClusterInfo interface {
 	KubernetesClusterName() (string, error)
 	CNIBinariesDirectory() string
 	DeploymentType() string
+	NetworkMTU() (int, error)
 }
@@ -196,6 +196,12 @@ const (
 	VrouterDecryptInterface                     string = "decrypt0"
 	VrouterDecryptKey                           int    = 15
 	VrouterModuleOptions                        string = ""
+	VrouterAgentIntrospectPort                  int    = 8085
+	ControlStatusMonitorMetricsPort             int    = 9181
+	ConfigStatusMonitorMetricsPort              int    = 9182
+	VrouterStatusMonitorMetricsPort             int    = 9183
+	WebuiStatusMonitorMetricsPort               int    = 9184
+	DatabaseStatusMonitorMetricsPort            int    = 9185
 	FabricSnatHashTableSize                     int    = 4096
 	TsnEvpnMode                                 bool   = false
 	TsnNodes                                    string = "[]"
@@ -9,11 +9,31 @@ type FernetKeyManagerSpec struct {
 	TokenExpiration         int `json:"tokenExpiration"`
 	TokenAllowExpiredWindow int `json:"tokenAllowExpiredWindow"`
 	RotationInterval        int `json:"rotationFrequency"`
+	// Labels of keystone pods which use the key repositories. Staged key is
+	// promoted to primary only when every selected pod has loaded it.
+	KeystonePodSelector map[string]string `json:"keystonePodSelector,omitempty"`
+	// Name of the secret with keystone credential keys repository.
+	// Credential keys are not rotated when not defined.
+	CredentialKeysSecretName string `json:"credentialKeysSecretName,omitempty"`
+	// Time in seconds between credential keys rotations. Default is 30 days.
+	// +kubebuilder:validation:Minimum=0
+	CredentialKeysRotationInterval int `json:"credentialKeysRotationInterval,omitempty"`
 }
 
 // FernetKeyManagerStatus defines the observed state of FernetKeyManager
 type FernetKeyManagerStatus struct {
-	SecretName string `json:"secretName"`
+	SecretName     string            `json:"secretName"`
+	FernetKeys     KeyRotationStatus `json:"fernetKeys,omitempty"`
+	CredentialKeys KeyRotationStatus `json:"credentialKeys,omitempty"`
+	// Set after credential keys rotation until credentials stored
+	// in keystone are encrypted with the new primary key.
+	CredentialsMigrationPending bool `json:"credentialsMigrationPending,omitempty"`
+}
+
+// KeyRotationStatus describes state of the key repository rotation
+type KeyRotationStatus struct {
+	CurrentKeyIndex  int          `json:"currentKeyIndex,omitempty"`
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
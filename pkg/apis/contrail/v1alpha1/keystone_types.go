@@ -42,6 +42,147 @@ type KeystoneConfiguration struct {
 	// Default is 60 sec.
 	// +kubebuilder:validation:Minimum=1
 	ExternalAddressRetrySec int `json:"externalAddressRetrySec,omitempty"`
+	// Addresses of other instances of the external keystone. When ExternalAddress
+	// is not healthy, they are checked in order and the first healthy one is used.
+	ExternalAddresses []string `json:"externalAddresses,omitempty"`
+	// Name of the secret with "ca.crt" key containing CA bundle used
+	// to verify certificate of the external keystone. The manager adds it to
+	// the CA bundle mounted into contrail services.
+	ExternalCABundleSecretName string `json:"externalCABundleSecretName,omitempty"`
+	// Name of the kubernetes.io/tls secret with client certificate
+	// presented to the external keystone by the operator. Contrail services
+	// do not present it, so the external keystone must not require it from them.
+	ExternalClientCertificateSecretName string `json:"externalClientCertificateSecretName,omitempty"`
+	// Domains with their own identity backend. For every entry keystone domain
+	// is created and its users are authenticated by the configured backend
+	// instead of the SQL one.
+	Domains []KeystoneDomain `json:"domains,omitempty"`
+	// Identity federation with external SAML2 and OpenID Connect providers.
+	Federation *KeystoneFederation `json:"federation,omitempty"`
+}
+
+// KeystoneDomain defines keystone domain with domain specific identity backend.
+// +k8s:openapi-gen=true
+type KeystoneDomain struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// LDAP identity backend used to authenticate users of the domain.
+	LDAP KeystoneLDAPBackend `json:"ldap"`
+}
+
+// KeystoneLDAPBackend defines configuration of the keystone LDAP identity driver.
+// +k8s:openapi-gen=true
+type KeystoneLDAPBackend struct {
+	// LDAP server URL e.g. ldaps://ldap.example.com:636
+	URL    string `json:"url"`
+	Suffix string `json:"suffix"`
+	// Name of the secret with "user" and "password" keys used to bind
+	// to the LDAP server. Anonymous bind is used when not defined.
+	BindCredentialsSecretName string `json:"bindCredentialsSecretName,omitempty"`
+	// +kubebuilder:validation:Enum=one;sub
+	QueryScope string `json:"queryScope,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	PageSize int  `json:"pageSize,omitempty"`
+	UseTLS   bool `json:"useTLS,omitempty"`
+	// +kubebuilder:validation:Enum=demand;allow;never
+	TLSRequireCert       string `json:"tlsRequireCert,omitempty"`
+	UserTreeDN           string `json:"userTreeDN,omitempty"`
+	UserFilter           string `json:"userFilter,omitempty"`
+	UserObjectClass      string `json:"userObjectClass,omitempty"`
+	UserIDAttribute      string `json:"userIDAttribute,omitempty"`
+	UserNameAttribute    string `json:"userNameAttribute,omitempty"`
+	UserMailAttribute    string `json:"userMailAttribute,omitempty"`
+	GroupTreeDN          string `json:"groupTreeDN,omitempty"`
+	GroupFilter          string `json:"groupFilter,omitempty"`
+	GroupObjectClass     string `json:"groupObjectClass,omitempty"`
+	GroupIDAttribute     string `json:"groupIDAttribute,omitempty"`
+	GroupNameAttribute   string `json:"groupNameAttribute,omitempty"`
+	GroupMemberAttribute string `json:"groupMemberAttribute,omitempty"`
+}
+
+// KeystoneFederation defines identity providers trusted by keystone.
+// +k8s:openapi-gen=true
+type KeystoneFederation struct {
+	// Dashboards (e.g. WebUI or Command) to which keystone is allowed
+	// to redirect after successful web single sign-on.
+	TrustedDashboards []string                   `json:"trustedDashboards,omitempty"`
+	IdentityProviders []KeystoneIdentityProvider `json:"identityProviders,omitempty"`
+}
+
+// KeystoneIdentityProvider defines identity provider together with
+// its protocol and mapping of remote attributes to keystone users and groups.
+// +k8s:openapi-gen=true
+type KeystoneIdentityProvider struct {
+	Name string `json:"name"`
+	// Values of the remote id attribute (SAML2 entity ID or OpenID Connect issuer)
+	// identifying this provider.
+	RemoteIDs []string `json:"remoteIDs,omitempty"`
+	// Name of the domain in which federated users are created.
+	// Keystone creates dedicated domain when not defined.
+	Domain string `json:"domain,omitempty"`
+	// +kubebuilder:validation:Enum=saml2;openid
+	Protocol string                 `json:"protocol"`
+	Mapping  []KeystoneMappingRule  `json:"mapping"`
+	SAML2    *KeystoneSAML2Provider `json:"saml2,omitempty"`
+	OIDC     *KeystoneOIDCProvider  `json:"oidc,omitempty"`
+}
+
+// KeystoneMappingRule maps remote attributes matching Remote conditions to Local keystone objects.
+// +k8s:openapi-gen=true
+type KeystoneMappingRule struct {
+	Local  []KeystoneMappingLocal  `json:"local"`
+	Remote []KeystoneMappingRemote `json:"remote"`
+}
+
+// KeystoneMappingLocal defines keystone user, group or projects federated user is mapped to.
+// Remote attributes can be referenced with {0}, {1}... placeholders.
+// +k8s:openapi-gen=true
+type KeystoneMappingLocal struct {
+	User     *KeystoneMappingObject   `json:"user,omitempty"`
+	Group    *KeystoneMappingObject   `json:"group,omitempty"`
+	Projects []KeystoneMappingProject `json:"projects,omitempty"`
+}
+
+// KeystoneMappingObject identifies keystone user or group by name and domain name.
+// +k8s:openapi-gen=true
+type KeystoneMappingObject struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain,omitempty"`
+}
+
+// KeystoneMappingProject defines project created for federated user with roles assigned to user.
+// +k8s:openapi-gen=true
+type KeystoneMappingProject struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// KeystoneMappingRemote defines condition on remote attribute.
+// +k8s:openapi-gen=true
+type KeystoneMappingRemote struct {
+	Type     string   `json:"type"`
+	AnyOneOf []string `json:"anyOneOf,omitempty"`
+	NotAnyOf []string `json:"notAnyOf,omitempty"`
+	Regex    bool     `json:"regex,omitempty"`
+}
+
+// KeystoneSAML2Provider defines mod_auth_mellon service provider configuration.
+// +k8s:openapi-gen=true
+type KeystoneSAML2Provider struct {
+	// Name of the secret with "idp-metadata.xml", "sp-metadata.xml",
+	// "sp-key.pem" and "sp-cert.pem" keys.
+	MetadataSecretName string `json:"metadataSecretName"`
+}
+
+// KeystoneOIDCProvider defines mod_auth_openidc relying party configuration.
+// +k8s:openapi-gen=true
+type KeystoneOIDCProvider struct {
+	ProviderMetadataURL string `json:"providerMetadataURL"`
+	ClientID            string `json:"clientID"`
+	// Name of the secret with "client-secret" key.
+	ClientSecretName string `json:"clientSecretName"`
+	// Default is "openid email profile".
+	Scope string `json:"scope,omitempty"`
 }
 
 // KeystoneStatus defines the observed state of Keystone
@@ -57,6 +198,11 @@ type KeystoneStatus struct {
 	// Set to true when keystone service is not
 	// directly managed by controller.
 	External bool `json:"external,omitempty"`
+	// Errors returned by the last health check of external keystone addresses.
+	LastError string `json:"lastError,omitempty"`
+	// Names of domains with domain specific backend
+	// already created in keystone.
+	Domains []string `json:"domains,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"sort"
 	"strconv"
 
@@ -50,6 +51,16 @@ type KubemanagerStatus struct {
 	Active        *bool             `json:"active,omitempty"`
 	Nodes         map[string]string `json:"nodes,omitempty"`
 	ConfigChanged *bool             `json:"configChanged,omitempty"`
+	// Cluster is the cluster the kube-manager watches, used to detect conflicts between Kubemanagers
+	Cluster *KubemanagerClusterStatus `json:"cluster,omitempty"`
+}
+
+// KubemanagerClusterStatus is the name and subnets of the cluster watched by a Kubemanager.
+// +k8s:openapi-gen=true
+type KubemanagerClusterStatus struct {
+	Name           string `json:"name,omitempty"`
+	PodSubnets     string `json:"podSubnets,omitempty"`
+	ServiceSubnets string `json:"serviceSubnets,omitempty"`
 }
 
 // KubemanagerServiceConfiguration is the Spec for the kubemanagers API.
@@ -83,8 +94,80 @@ type KubemanagerConfiguration struct {
 	RabbitmqPassword      string             `json:"rabbitmqPassword,omitempty"`
 	RabbitmqVhost         string             `json:"rabbitmqVhost,omitempty"`
 	AuthMode              AuthenticationMode `json:"authMode,omitempty"`
+	// RemoteCluster makes the kube-manager watch a cluster other than the one running the operator.
+	// Cluster name and subnets have to be set or discovered with UseKubeadmConfig from that cluster.
+	RemoteCluster *KubemanagerRemoteCluster `json:"remoteCluster,omitempty"`
+	// IsolationMode decides how objects of the cluster are placed in Contrail projects. Default is namespace.
+	IsolationMode KubemanagerIsolationMode `json:"isolationMode,omitempty"`
+	// IsolatedNamespaces makes every namespace isolated in its own virtual network unless it is annotated otherwise
+	IsolatedNamespaces *bool `json:"isolatedNamespaces,omitempty"`
+	// ClusterProject is the project in default-domain for all namespaces of the cluster, it overrides IsolationMode
+	ClusterProject string `json:"clusterProject,omitempty"`
+	// PublicFIPPool is the floating IP pool used for services of type LoadBalancer and ingresses
+	PublicFIPPool *FloatingIPPoolFQName `json:"publicFIPPool,omitempty"`
+	// PodNetwork, ServiceNetwork and IPFabricNetwork replace virtual networks created by the kube-manager.
+	// They have to exist before the kube-manager is configured to use them.
+	PodNetwork      *VirtualNetworkFQName `json:"podNetwork,omitempty"`
+	ServiceNetwork  *VirtualNetworkFQName `json:"serviceNetwork,omitempty"`
+	IPFabricNetwork *VirtualNetworkFQName `json:"ipFabricNetwork,omitempty"`
+}
+
+// FloatingIPPoolFQName is the fully qualified name of a floating IP pool in a virtual network.
+// +k8s:openapi-gen=true
+type FloatingIPPoolFQName struct {
+	VirtualNetworkFQName `json:",inline"`
+	Pool                 string `json:"pool"`
+}
+
+// KubemanagerRemoteCluster is a Kubernetes cluster sharing the Contrail control plane.
+// +k8s:openapi-gen=true
+type KubemanagerRemoteCluster struct {
+	// KubeconfigSecret is a Secret in the namespace of the Kubemanager with the kubeconfig of the
+	// cluster under the kubeconfig key. Its current context has to authenticate with a token.
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+}
+
+// KubemanagerIsolationMode is the way objects of a cluster are separated from other clusters
+// +kubebuilder:validation:Enum=namespace;cluster
+type KubemanagerIsolationMode string
+
+const (
+	// NamespaceIsolationMode places every namespace in its own project prefixed with the cluster name
+	NamespaceIsolationMode KubemanagerIsolationMode = "namespace"
+	// ClusterIsolationMode places all namespaces of the cluster in a single project named after the cluster
+	ClusterIsolationMode KubemanagerIsolationMode = "cluster"
+)
+
+// VirtualNetworks returns the virtual networks referenced by the configuration
+func (c *KubemanagerConfiguration) VirtualNetworks() []VirtualNetworkFQName {
+	var networks []VirtualNetworkFQName
+	for _, network := range []*VirtualNetworkFQName{c.PodNetwork, c.ServiceNetwork, c.IPFabricNetwork} {
+		if network != nil {
+			networks = append(networks, *network)
+		}
+	}
+	if c.PublicFIPPool != nil {
+		networks = append(networks, c.PublicFIPPool.VirtualNetworkFQName)
+	}
+	return networks
 }
 
+// Validate checks the names of referenced virtual networks and floating IP pool
+func (c *KubemanagerConfiguration) Validate() error {
+	for _, network := range c.VirtualNetworks() {
+		if err := network.validate(); err != nil {
+			return err
+		}
+	}
+	if c.PublicFIPPool != nil && c.PublicFIPPool.Pool == "" {
+		return fmt.Errorf("public floating IP pool name is required")
+	}
+	return nil
+}
+
+// KubemanagerKubeconfigKey is the key of the kubeconfig in the Secret of a remote cluster
+const KubemanagerKubeconfigKey = "kubeconfig"
+
 // KubemanagerNodesConfiguration is the configuration for third party dependencies
 // +k8s:openapi-gen=true
 type KubemanagerNodesConfiguration struct {
@@ -150,7 +233,10 @@ func (c *Kubemanager) InstanceConfiguration(request reconcile.Request,
 		podIPList = append(podIPList, pod.Status.PodIP)
 	}
 
-	kubemanagerConfig := c.ConfigurationParameters()
+	kubemanagerConfig, err := c.ClusterConfiguration(cinfo)
+	if err != nil {
+		return err
+	}
 	if rabbitmqSecretUser == "" {
 		rabbitmqSecretUser = kubemanagerConfig.RabbitmqUser
 	}
@@ -161,32 +247,15 @@ func (c *Kubemanager) InstanceConfiguration(request reconcile.Request,
 		rabbitmqSecretVhost = kubemanagerConfig.RabbitmqVhost
 	}
 
-	if *kubemanagerConfig.UseKubeadmConfig {
-		apiSSLPort, err := cinfo.KubernetesAPISSLPort()
-		if err != nil {
-			return err
-		}
-		kubemanagerConfig.KubernetesAPISSLPort = &apiSSLPort
-		APIServer, err := cinfo.KubernetesAPIServer()
-		if err != nil {
-			return err
-		}
-		kubemanagerConfig.KubernetesAPIServer = APIServer
-		clusterName, err := cinfo.KubernetesClusterName()
-		if err != nil {
-			return err
-		}
-		kubemanagerConfig.KubernetesClusterName = clusterName
-		podSubnets, err := cinfo.PodSubnets()
-		if err != nil {
-			return err
-		}
-		kubemanagerConfig.PodSubnets = podSubnets
-		serviceSubnets, err := cinfo.ServiceSubnets()
-		if err != nil {
+	var token string
+	if remoteClusterInfo, ok := cinfo.(KubemanagerRemoteClusterInfo); ok {
+		token = remoteClusterInfo.KubernetesAPIToken()
+	} else {
+		secret := &corev1.Secret{}
+		if err = client.Get(context.TODO(), types.NamespacedName{Name: "kubemanagersecret", Namespace: request.Namespace}, secret); err != nil {
 			return err
 		}
-		kubemanagerConfig.ServiceSubnets = serviceSubnets
+		token = string(secret.Data["token"])
 	}
 	sort.SliceStable(podList.Items, func(i, j int) bool { return podList.Items[i].Status.PodIP < podList.Items[j].Status.PodIP })
 	var data = map[string]string{}
@@ -209,11 +278,6 @@ func (c *Kubemanager) InstanceConfiguration(request reconcile.Request,
 		cassandraEndpointList := configtemplates.EndpointList(cassandraNodesInformation.ServerIPList, cassandraNodesInformation.Port)
 		cassandraEndpointListSpaceSeparated := configtemplates.JoinListWithSeparator(cassandraEndpointList, " ")
 		var kubemanagerConfigBuffer bytes.Buffer
-		secret := &corev1.Secret{}
-		if err = client.Get(context.TODO(), types.NamespacedName{Name: "kubemanagersecret", Namespace: request.Namespace}, secret); err != nil {
-			return err
-		}
-		token := string(secret.Data["token"])
 		configtemplates.KubemanagerConfig.Execute(&kubemanagerConfigBuffer, struct {
 			Token                 string
 			ListenAddress         string
@@ -222,6 +286,12 @@ func (c *Kubemanager) InstanceConfiguration(request reconcile.Request,
 			KubernetesAPIPort     string
 			KubernetesAPISSLPort  string
 			KubernetesClusterName string
+			ClusterProject        string
+			IsolatedNamespaces    string
+			PodNetwork            string
+			ServiceNetwork        string
+			IPFabricNetwork       string
+			PublicFIPPool         string
 			PodSubnet             string
 			IPFabricSubnet        string
 			ServiceSubnet         string
@@ -247,6 +317,12 @@ func (c *Kubemanager) InstanceConfiguration(request reconcile.Request,
 			KubernetesAPIPort:     strconv.Itoa(*kubemanagerConfig.KubernetesAPIPort),
 			KubernetesAPISSLPort:  strconv.Itoa(*kubemanagerConfig.KubernetesAPISSLPort),
 			KubernetesClusterName: kubemanagerConfig.KubernetesClusterName,
+			ClusterProject:        clusterProject(kubemanagerConfig),
+			IsolatedNamespaces:    strconv.FormatBool(*kubemanagerConfig.IsolatedNamespaces),
+			PodNetwork:            networkConfig(kubemanagerConfig.PodNetwork),
+			ServiceNetwork:        networkConfig(kubemanagerConfig.ServiceNetwork),
+			IPFabricNetwork:       networkConfig(kubemanagerConfig.IPFabricNetwork),
+			PublicFIPPool:         publicFIPPoolConfig(kubemanagerConfig.PublicFIPPool),
 			PodSubnet:             kubemanagerConfig.PodSubnets,
 			IPFabricSubnet:        kubemanagerConfig.IPFabricSubnets,
 			ServiceSubnet:         kubemanagerConfig.ServiceSubnets,
@@ -478,10 +554,91 @@ func (c *Kubemanager) ConfigurationParameters() KubemanagerConfiguration {
 	kubemanagerConfiguration.HostNetworkService = &hostNetworkService
 	kubemanagerConfiguration.UseKubeadmConfig = &useKubeadmConfig
 	kubemanagerConfiguration.IPFabricSnat = &ipFabricSnat
+	isolatedNamespaces := false
+	if c.Spec.ServiceConfiguration.IsolatedNamespaces != nil {
+		isolatedNamespaces = *c.Spec.ServiceConfiguration.IsolatedNamespaces
+	}
+	kubemanagerConfiguration.IsolatedNamespaces = &isolatedNamespaces
+	kubemanagerConfiguration.IsolationMode = NamespaceIsolationMode
+	if c.Spec.ServiceConfiguration.IsolationMode != "" {
+		kubemanagerConfiguration.IsolationMode = c.Spec.ServiceConfiguration.IsolationMode
+	}
+	kubemanagerConfiguration.ClusterProject = c.Spec.ServiceConfiguration.ClusterProject
+	kubemanagerConfiguration.PublicFIPPool = c.Spec.ServiceConfiguration.PublicFIPPool
+	kubemanagerConfiguration.PodNetwork = c.Spec.ServiceConfiguration.PodNetwork
+	kubemanagerConfiguration.ServiceNetwork = c.Spec.ServiceConfiguration.ServiceNetwork
+	kubemanagerConfiguration.IPFabricNetwork = c.Spec.ServiceConfiguration.IPFabricNetwork
 
 	return kubemanagerConfiguration
 }
 
+// ClusterConfiguration returns configuration parameters with the API server, cluster name and
+// subnets of the cluster watched by the kube-manager filled in.
+func (c *Kubemanager) ClusterConfiguration(cinfo KubemanagerClusterInfo) (KubemanagerConfiguration, error) {
+	kubemanagerConfig := c.ConfigurationParameters()
+	// The API server of a remote cluster is always taken from its kubeconfig
+	if _, remote := cinfo.(KubemanagerRemoteClusterInfo); remote || *kubemanagerConfig.UseKubeadmConfig {
+		apiSSLPort, err := cinfo.KubernetesAPISSLPort()
+		if err != nil {
+			return kubemanagerConfig, err
+		}
+		kubemanagerConfig.KubernetesAPISSLPort = &apiSSLPort
+		apiServer, err := cinfo.KubernetesAPIServer()
+		if err != nil {
+			return kubemanagerConfig, err
+		}
+		kubemanagerConfig.KubernetesAPIServer = apiServer
+	}
+	if !*kubemanagerConfig.UseKubeadmConfig {
+		return kubemanagerConfig, nil
+	}
+	clusterName, err := cinfo.KubernetesClusterName()
+	if err != nil {
+		return kubemanagerConfig, err
+	}
+	kubemanagerConfig.KubernetesClusterName = clusterName
+	podSubnets, err := cinfo.PodSubnets()
+	if err != nil {
+		return kubemanagerConfig, err
+	}
+	kubemanagerConfig.PodSubnets = podSubnets
+	serviceSubnets, err := cinfo.ServiceSubnets()
+	if err != nil {
+		return kubemanagerConfig, err
+	}
+	kubemanagerConfig.ServiceSubnets = serviceSubnets
+	return kubemanagerConfig, nil
+}
+
+// clusterProject renders the project all namespaces of the cluster are placed in, {} is a project per namespace
+func clusterProject(kubemanagerConfig KubemanagerConfiguration) string {
+	project := kubemanagerConfig.ClusterProject
+	if project == "" && kubemanagerConfig.IsolationMode == ClusterIsolationMode {
+		project = kubemanagerConfig.KubernetesClusterName
+	}
+	if project == "" {
+		return "{}"
+	}
+	return fmt.Sprintf("{'domain': '%s', 'project': '%s'}", DefaultDomain, project)
+}
+
+// networkConfig renders a virtual network, {} lets the kube-manager create its own one
+func networkConfig(network *VirtualNetworkFQName) string {
+	if network == nil {
+		return "{}"
+	}
+	n := network.WithDefaults()
+	return fmt.Sprintf("{'domain': '%s', 'project': '%s', 'name': '%s'}", n.Domain, n.Project, n.Name)
+}
+
+func publicFIPPoolConfig(pool *FloatingIPPoolFQName) string {
+	if pool == nil {
+		return "{}"
+	}
+	n := pool.WithDefaults()
+	return fmt.Sprintf("{'domain': '%s', 'project': '%s', 'network': '%s', 'name': '%s'}", n.Domain, n.Project, n.Name, pool.Pool)
+}
+
 //KubemanagerClusterInfo is interface for gathering information about cluster
 type KubemanagerClusterInfo interface {
 	KubernetesAPISSLPort() (int, error)
@@ -490,3 +647,10 @@ type KubemanagerClusterInfo interface {
 	PodSubnets() (string, error)
 	ServiceSubnets() (string, error)
 }
+
+// KubemanagerRemoteClusterInfo is information about a remote cluster. The kube-manager reaches it with
+// the API server and token of a kubeconfig instead of its service account.
+type KubemanagerRemoteClusterInfo interface {
+	KubemanagerClusterInfo
+	KubernetesAPIToken() string
+}
@@ -205,3 +205,110 @@ func TestInstanceConfigurationWithStaticConfiguration(t *testing.T) {
 	assert.Equal(t, kubemanagerPod2.Section("VNC").Key("collectors").String(), "3.3.3.4:2223 4.4.4.5:2223")
 	assert.Equal(t, kubemanagerPod2.Section("VNC").Key("zk_server_ip").String(), "7.7.7.7:4444,8.8.8.8:4444")
 }
+
+func TestInstanceConfigurationWithRemoteCluster(t *testing.T) {
+	scheme, err := SchemeBuilder.Build()
+	require.NoError(t, err, "Failed to build scheme")
+	require.NoError(t, corev1.SchemeBuilder.AddToScheme(scheme), "Failed to add CoreV1 into scheme")
+
+	cl := fake.NewFakeClientWithScheme(scheme, kubemanagerCM, rabbitSecret)
+
+	var trueVal = true
+	kubemanager := Kubemanager{
+		Spec: KubemanagerSpec{
+			ServiceConfiguration: KubemanagerServiceConfiguration{
+				KubemanagerConfiguration: KubemanagerConfiguration{
+					UseKubeadmConfig: &trueVal,
+					RemoteCluster:    &KubemanagerRemoteCluster{KubeconfigSecret: "remote-kubeconfig"},
+					IsolationMode:    ClusterIsolationMode,
+				},
+				KubemanagerNodesConfiguration: KubemanagerNodesConfiguration{
+					CassandraNodesConfiguration: &CassandraClusterConfiguration{ServerIPList: []string{"5.5.5.5"}},
+					ConfigNodesConfiguration:    &ConfigClusterConfiguration{APIServerIPList: []string{"3.3.3.3"}},
+					RabbbitmqNodesConfiguration: &RabbitmqClusterConfiguration{ServerIPList: []string{"5.5.5.5"}, Secret: "rabbit-secret"},
+					ZookeeperNodesConfiguration: &ZookeeperClusterConfiguration{ServerIPList: []string{"7.7.7.7"}},
+					KeystoneNodesConfiguration:  &KeystoneClusterConfiguration{},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, kubemanager.InstanceConfiguration(request, &podList, cl, fakeCInfo.FakeRemoteClusterInfo{}), "Error while configuring instance")
+
+	var kubeConfigMap = &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "kubemanager1-kubemanager-configmap", Namespace: "test-ns"}, kubeConfigMap), "Error while gathering kubemanager config map")
+
+	kubemanagerPod1, err := ini.Load([]byte(kubeConfigMap.Data["kubemanager.1.1.1.1"]))
+	require.NoError(t, err)
+	assert.Equal(t, "remote_token", kubemanagerPod1.Section("DEFAULTS").Key("token").String())
+	assert.Equal(t, "2.2.2.2", kubemanagerPod1.Section("KUBERNETES").Key("kubernetes_api_server").String())
+	assert.Equal(t, "6443", kubemanagerPod1.Section("KUBERNETES").Key("kubernetes_api_secure_port").String())
+	assert.Equal(t, "remote_cluster", kubemanagerPod1.Section("KUBERNETES").Key("cluster_name").String())
+	assert.Equal(t, "{'domain': 'default-domain', 'project': 'remote_cluster'}", kubemanagerPod1.Section("KUBERNETES").Key("cluster_project").String())
+	assert.Equal(t, "192.168.1.0/24", kubemanagerPod1.Section("KUBERNETES").Key("pod_subnets").String())
+}
+
+func TestInstanceConfigurationWithNetworks(t *testing.T) {
+	scheme, err := SchemeBuilder.Build()
+	require.NoError(t, err, "Failed to build scheme")
+	require.NoError(t, corev1.SchemeBuilder.AddToScheme(scheme), "Failed to add CoreV1 into scheme")
+
+	cl := fake.NewFakeClientWithScheme(scheme, kubemanagerCM, rabbitSecret, kubemanagerSecret)
+
+	var trueVal = true
+	kubemanager := Kubemanager{
+		Spec: KubemanagerSpec{
+			ServiceConfiguration: KubemanagerServiceConfiguration{
+				KubemanagerConfiguration: KubemanagerConfiguration{
+					IsolationMode:      ClusterIsolationMode,
+					IsolatedNamespaces: &trueVal,
+					ClusterProject:     "k8s-project",
+					PublicFIPPool: &FloatingIPPoolFQName{
+						VirtualNetworkFQName: VirtualNetworkFQName{Project: "admin", Name: "public"},
+						Pool:                 "public-pool",
+					},
+					PodNetwork:      &VirtualNetworkFQName{Domain: "tenant-domain", Project: "k8s", Name: "pods"},
+					ServiceNetwork:  &VirtualNetworkFQName{Project: "k8s", Name: "services"},
+					IPFabricNetwork: &VirtualNetworkFQName{Project: "admin", Name: "fabric"},
+				},
+				KubemanagerNodesConfiguration: KubemanagerNodesConfiguration{
+					CassandraNodesConfiguration: &CassandraClusterConfiguration{ServerIPList: []string{"5.5.5.5"}},
+					ConfigNodesConfiguration:    &ConfigClusterConfiguration{APIServerIPList: []string{"3.3.3.3"}},
+					RabbbitmqNodesConfiguration: &RabbitmqClusterConfiguration{ServerIPList: []string{"5.5.5.5"}, Secret: "rabbit-secret"},
+					ZookeeperNodesConfiguration: &ZookeeperClusterConfiguration{ServerIPList: []string{"7.7.7.7"}},
+					KeystoneNodesConfiguration:  &KeystoneClusterConfiguration{},
+				},
+			},
+		},
+	}
+	require.NoError(t, kubemanager.Spec.ServiceConfiguration.Validate())
+
+	require.NoError(t, kubemanager.InstanceConfiguration(request, &podList, cl, fakeCInfo.FakeClusterInfo{}), "Error while configuring instance")
+
+	var kubeConfigMap = &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "kubemanager1-kubemanager-configmap", Namespace: "test-ns"}, kubeConfigMap), "Error while gathering kubemanager config map")
+
+	kubemanagerPod1, err := ini.Load([]byte(kubeConfigMap.Data["kubemanager.1.1.1.1"]))
+	require.NoError(t, err)
+	kubernetes := kubemanagerPod1.Section("KUBERNETES")
+	assert.Equal(t, "{'domain': 'default-domain', 'project': 'k8s-project'}", kubernetes.Key("cluster_project").String())
+	assert.Equal(t, "{'domain': 'tenant-domain', 'project': 'k8s', 'name': 'pods'}", kubernetes.Key("cluster_pod_network").String())
+	assert.Equal(t, "{'domain': 'default-domain', 'project': 'k8s', 'name': 'services'}", kubernetes.Key("cluster_service_network").String())
+	assert.Equal(t, "{'domain': 'default-domain', 'project': 'admin', 'name': 'fabric'}", kubernetes.Key("cluster_ip_fabric_network").String())
+	assert.Equal(t, "true", kubernetes.Key("isolated_namespaces").String())
+	assert.Equal(t, "{'domain': 'default-domain', 'project': 'admin', 'network': 'public', 'name': 'public-pool'}", kubemanagerPod1.Section("VNC").Key("public_fip_pool").String())
+}
+
+func TestKubemanagerConfigurationValidate(t *testing.T) {
+	t.Run("should require virtual network project and name", func(t *testing.T) {
+		configuration := KubemanagerConfiguration{PodNetwork: &VirtualNetworkFQName{Name: "pods"}}
+		assert.Error(t, configuration.Validate())
+	})
+
+	t.Run("should require floating IP pool name", func(t *testing.T) {
+		configuration := KubemanagerConfiguration{PublicFIPPool: &FloatingIPPoolFQName{
+			VirtualNetworkFQName: VirtualNetworkFQName{Project: "admin", Name: "public"},
+		}}
+		assert.Error(t, configuration.Validate())
+	})
+}
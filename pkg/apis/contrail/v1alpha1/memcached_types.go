@@ -1,9 +1,16 @@
 package v1alpha1
 
 import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// MemcachedServersAnnotation is set on pod templates of memcached clients, so that
+// they are restarted when the list of memcached servers changes.
+const MemcachedServersAnnotation = "contrail.juniper.net/memcached-servers"
+
 // MemcachedSpec defines the desired state of Memcached
 type MemcachedSpec struct {
 	CommonConfiguration  PodConfiguration       `json:"commonConfiguration,omitempty"`
@@ -14,6 +21,8 @@ type MemcachedSpec struct {
 type MemcachedStatus struct {
 	Status   `json:",inline"`
 	Endpoint string `json:"endpoint,omitempty"`
+	// Addresses of all memcached replicas when memcached is clustered.
+	Endpoints []string `json:"endpoints,omitempty"`
 }
 
 type MemcachedConfiguration struct {
@@ -24,6 +33,11 @@ type MemcachedConfiguration struct {
 	ConnectionLimit int32 `json:"connectionLimit,omitempty"`
 	// +optional
 	MaxMemory int32 `json:"maxMemory,omitempty"`
+	// When set memcached listens on all pod addresses instead of localhost
+	// and addresses of all replicas are published in status, so that clients
+	// distribute cached items across every replica.
+	// +optional
+	Clustered bool `json:"clustered,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -77,3 +91,24 @@ func (m *MemcachedConfiguration) GetMaxMemory() int32 {
 	}
 	return m.MaxMemory
 }
+
+// GetServers returns comma separated list of memcached servers which should be used by clients.
+func (m *MemcachedStatus) GetServers() string {
+	if len(m.Endpoints) > 0 {
+		return strings.Join(m.Endpoints, ",")
+	}
+	return m.Endpoint
+}
+
+// SetServersAnnotation sets MemcachedServersAnnotation on the pod template of memcached client.
+// Annotation is removed when memcached is not clustered.
+func (m *MemcachedStatus) SetServersAnnotation(template *corev1.PodTemplateSpec) {
+	if len(m.Endpoints) == 0 {
+		delete(template.Annotations, MemcachedServersAnnotation)
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[MemcachedServersAnnotation] = m.GetServers()
+}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -40,7 +41,77 @@ type ProvisionManagerConfiguration struct {
 	KeystoneSecretName         string                     `json:"keystoneSecretName,omitempty"`
 	KeystoneInstance           string                     `json:"keystoneInstance,omitempty"`
 	GlobalVrouterConfiguration GlobalVrouterConfiguration `json:"globalVrouterConfiguration,omitempty"`
-}
+	ProvisionMode              ProvisionMode              `json:"provisionMode,omitempty"`
+	GlobalSystemConfiguration  GlobalSystemConfiguration  `json:"globalSystemConfiguration,omitempty"`
+	BGPPeers                   []ExternalBGPPeer          `json:"bgpPeers,omitempty"`
+	VirtualDNSServers          []VirtualDNSServer         `json:"virtualDNSServers,omitempty"`
+
+	// KeystoneApplicationCredentialSecretName is the name of a secret with the keystone
+	// application credential used by the provisioner instead of the admin password. The
+	// secret holds the credential secret under the secret key and either id or name. A
+	// credential given by name must belong to the admin user.
+	KeystoneApplicationCredentialSecretName string `json:"keystoneApplicationCredentialSecretName,omitempty"`
+}
+
+// GlobalSystemConfiguration defines fields of the default global-system-config set by
+// the provisioner. Fields which are not set are left unchanged.
+type GlobalSystemConfiguration struct {
+	AutonomousSystem *int                       `json:"autonomousSystem,omitempty" yaml:"autonomousSystem,omitempty"`
+	IBGPAutoMesh     *bool                      `json:"ibgpAutoMesh,omitempty" yaml:"ibgpAutoMesh,omitempty"`
+	FastConvergence  *FastConvergenceParameters `json:"fastConvergence,omitempty" yaml:"fastConvergence,omitempty"`
+}
+
+// FastConvergenceParameters configure detection of unreachable next hops by control nodes
+// and vRouter agents.
+type FastConvergenceParameters struct {
+	Enable              bool `json:"enable" yaml:"enable"`
+	NHReachabilityCheck bool `json:"nhReachabilityCheck,omitempty" yaml:"nhReachabilityCheck,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=90
+	XMPPHoldTime int `json:"xmppHoldTime,omitempty" yaml:"xmppHoldTime,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	BGPHoldTime int `json:"bgpHoldTime,omitempty" yaml:"bgpHoldTime,omitempty"`
+}
+
+// ExternalBGPPeer defines an external BGP router which is peered with all control nodes.
+type ExternalBGPPeer struct {
+	Name            string   `json:"name"`
+	Address         string   `json:"address"`
+	ASN             int      `json:"asn"`
+	Vendor          string   `json:"vendor,omitempty"`
+	Port            int      `json:"port,omitempty"`
+	HoldTime        int      `json:"holdTime,omitempty"`
+	AddressFamilies []string `json:"addressFamilies,omitempty"`
+	// AuthKeySecretName is the name of a secret with the MD5 authentication key of the
+	// BGP session stored under the authKey key.
+	AuthKeySecretName string `json:"authKeySecretName,omitempty"`
+}
+
+// VirtualDNSServer defines a virtual DNS server of the default domain.
+type VirtualDNSServer struct {
+	Name                     string `json:"name" yaml:"name"`
+	DomainName               string `json:"domainName" yaml:"domainName"`
+	DefaultTTLSeconds        int    `json:"defaultTTLSeconds,omitempty" yaml:"defaultTTLSeconds,omitempty"`
+	RecordOrder              string `json:"recordOrder,omitempty" yaml:"recordOrder,omitempty"`
+	NextVirtualDNS           string `json:"nextVirtualDNS,omitempty" yaml:"nextVirtualDNS,omitempty"`
+	FloatingIPRecord         string `json:"floatingIPRecord,omitempty" yaml:"floatingIPRecord,omitempty"`
+	DynamicRecordsFromClient bool   `json:"dynamicRecordsFromClient,omitempty" yaml:"dynamicRecordsFromClient,omitempty"`
+	ExternalVisible          bool   `json:"externalVisible,omitempty" yaml:"externalVisible,omitempty"`
+	ReverseResolution        bool   `json:"reverseResolution,omitempty" yaml:"reverseResolution,omitempty"`
+}
+
+// ProvisionMode selects how the provisioner discovers nodes. In configmap mode (the
+// default) node lists are rendered by the operator into ConfigMaps watched by the
+// provisioner. In kubernetes mode the provisioner watches Config, Control, Vrouter and
+// Cassandra resources itself.
+// +kubebuilder:validation:Enum=configmap;kubernetes
+type ProvisionMode string
+
+const (
+	ProvisionModeConfigMap  ProvisionMode = "configmap"
+	ProvisionModeKubernetes ProvisionMode = "kubernetes"
+)
 
 type EcmpHashingIncludeFields struct {
 	HashingConfigured bool `json:"hashingConfigured,omitempty"`
@@ -51,10 +122,50 @@ type EcmpHashingIncludeFields struct {
 	DestinationPort   bool `json:"destinationPort,omitempty"`
 }
 
+// GlobalVrouterConfiguration defines fields of the default global-vrouter-config set by
+// the provisioner. Lists and fields without defaults which are not set are left
+// unchanged.
 type GlobalVrouterConfiguration struct {
 	EcmpHashingIncludeFields   EcmpHashingIncludeFields `json:"ecmpHashingIncludeFields,omitempty"`
 	EncapsulationPriorities    string                   `json:"encapPriority,omitempty"`
 	VxlanNetworkIdentifierMode string                   `json:"vxlanNetworkIdentifierMode,omitempty"`
+	LinklocalServices          []LinklocalService       `json:"linklocalServices,omitempty"`
+	// FlowExportRate is the number of flows per second exported by each vRouter agent.
+	// +kubebuilder:validation:Minimum=0
+	FlowExportRate    *int               `json:"flowExportRate,omitempty"`
+	FlowAgingTimeouts []FlowAgingTimeout `json:"flowAgingTimeouts,omitempty"`
+	// +kubebuilder:validation:Enum=l2_l3;l2;l3
+	ForwardingMode       string                `json:"forwardingMode,omitempty"`
+	PortTranslationPools []PortTranslationPool `json:"portTranslationPools,omitempty"`
+}
+
+// LinklocalService maps a link-local address, e.g. 169.254.169.254:80 of the metadata
+// service, to a service in the IP fabric given by DNS name or IP addresses.
+type LinklocalService struct {
+	Name                 string   `json:"name"`
+	IP                   string   `json:"ip"`
+	Port                 int      `json:"port"`
+	FabricDNSServiceName string   `json:"fabricDNSServiceName,omitempty"`
+	FabricIPs            []string `json:"fabricIPs,omitempty"`
+	FabricPort           int      `json:"fabricPort"`
+}
+
+// FlowAgingTimeout sets the timeout of idle flows of a protocol and, optionally, port.
+type FlowAgingTimeout struct {
+	// +kubebuilder:validation:Enum=tcp;udp;icmp
+	Protocol       string `json:"protocol"`
+	Port           int    `json:"port,omitempty"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+}
+
+// PortTranslationPool reserves fabric ports used for source NAT of the protocol, either
+// as a range or as a number of ports.
+type PortTranslationPool struct {
+	// +kubebuilder:validation:Enum=tcp;udp
+	Protocol  string `json:"protocol"`
+	StartPort int    `json:"startPort,omitempty"`
+	EndPort   int    `json:"endPort,omitempty"`
+	PortCount int    `json:"portCount,omitempty"`
 }
 
 // ProvisionManagerNodesConfiguration is the configuration for third party dependencies
@@ -69,8 +180,9 @@ type ProvisionManagerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
-	Active              *bool             `json:"active,omitempty"`
-	Nodes               map[string]string `json:"nodes,omitempty"`
+	Active *bool             `json:"active,omitempty"`
+	Nodes  map[string]string `json:"nodes,omitempty"`
+	// GlobalConfiguration lists global configuration rendered for the provisioner.
 	GlobalConfiguration map[string]string `json:"globalConfiguration,omitempty"`
 }
 
@@ -137,12 +249,26 @@ type DatabaseNode struct {
 	Node `yaml:",inline"`
 }
 
+type BGPPeerNode struct {
+	Node            `yaml:",inline"`
+	ASN             int      `yaml:"asn,omitempty"`
+	Vendor          string   `yaml:"vendor,omitempty"`
+	Port            int      `yaml:"port,omitempty"`
+	HoldTime        int      `yaml:"holdTime,omitempty"`
+	AddressFamilies []string `yaml:"addressFamilies,omitempty"`
+	AuthKey         string   `yaml:"authKey,omitempty"`
+}
+
 type KeystoneAuthParameters struct {
 	AdminUsername string     `yaml:"admin_user,omitempty"`
 	AdminPassword string     `yaml:"admin_password,omitempty"`
 	AuthUrl       string     `yaml:"auth_url,omitempty"`
 	TenantName    string     `yaml:"tenant_name,omitempty"`
 	Encryption    Encryption `yaml:"encryption,omitempty"`
+	// Application credentials are used instead of the admin password when the secret is set
+	ApplicationCredentialID     string `yaml:"application_credential_id,omitempty"`
+	ApplicationCredentialName   string `yaml:"application_credential_name,omitempty"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret,omitempty"`
 }
 
 func init() {
@@ -216,6 +342,11 @@ func (c *ProvisionManager) GetGlobalVrouterConfig() (*GlobalVrouterConfiguration
 	g.EncapsulationPriorities = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.EncapsulationPriorities
 	g.VxlanNetworkIdentifierMode = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.VxlanNetworkIdentifierMode
 	g.EcmpHashingIncludeFields = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.EcmpHashingIncludeFields
+	g.LinklocalServices = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.LinklocalServices
+	g.FlowExportRate = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.FlowExportRate
+	g.FlowAgingTimeouts = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.FlowAgingTimeouts
+	g.ForwardingMode = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.ForwardingMode
+	g.PortTranslationPools = c.Spec.ServiceConfiguration.GlobalVrouterConfiguration.PortTranslationPools
 	if g.EncapsulationPriorities == "" {
 		g.EncapsulationPriorities = "VXLAN,MPLSoGRE,MPLSoUDP"
 	}
@@ -228,6 +359,60 @@ func (c *ProvisionManager) GetGlobalVrouterConfig() (*GlobalVrouterConfiguration
 	return g, nil
 }
 
+// GetGlobalConfiguration flattens the global vRouter and global system configuration
+// rendered for the provisioner into JSON encoded values keyed by object and field name,
+// e.g. "globalVrouterConfig.forwardingMode".
+func (c *ProvisionManager) GetGlobalConfiguration(globalVrouter *GlobalVrouterConfiguration) (map[string]string, error) {
+	globalConfiguration := map[string]string{}
+	objects := map[string]interface{}{
+		"globalVrouterConfig": globalVrouter,
+		"globalSystemConfig":  c.Spec.ServiceConfiguration.GlobalSystemConfiguration,
+	}
+	for objectName, object := range objects {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		for field, value := range fields {
+			globalConfiguration[objectName+"."+field] = string(value)
+		}
+	}
+	return globalConfiguration, nil
+}
+
+// GetBGPPeerNodes returns external BGP peers with authentication keys read from their
+// secrets.
+func (c *ProvisionManager) GetBGPPeerNodes(client client.Client) ([]*BGPPeerNode, error) {
+	nodes := []*BGPPeerNode{}
+	for _, peer := range c.Spec.ServiceConfiguration.BGPPeers {
+		node := &BGPPeerNode{
+			Node:            Node{IPAddress: peer.Address, Hostname: peer.Name},
+			ASN:             peer.ASN,
+			Vendor:          peer.Vendor,
+			Port:            peer.Port,
+			HoldTime:        peer.HoldTime,
+			AddressFamilies: peer.AddressFamilies,
+		}
+		if peer.AuthKeySecretName != "" {
+			authKeySecret := &corev1.Secret{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: peer.AuthKeySecretName, Namespace: c.Namespace}, authKeySecret); err != nil {
+				return nil, err
+			}
+			authKey, ok := authKeySecret.Data["authKey"]
+			if !ok {
+				return nil, fmt.Errorf("secret %q has no authKey", peer.AuthKeySecretName)
+			}
+			node.AuthKey = string(authKey)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
 func (c *ProvisionManager) GetAuthParameters(client client.Client, podIP string) (*KeystoneAuthParameters, error) {
 	k := &KeystoneAuthParameters{
 		AdminUsername: "admin",
@@ -239,12 +424,25 @@ func (c *ProvisionManager) GetAuthParameters(client client.Client, podIP string)
 			Insecure: false,
 		},
 	}
-	adminPasswordSecretName := c.Spec.ServiceConfiguration.KeystoneSecretName
-	adminPasswordSecret := &corev1.Secret{}
-	if err := client.Get(context.TODO(), types.NamespacedName{Name: adminPasswordSecretName, Namespace: c.Namespace}, adminPasswordSecret); err != nil {
-		return nil, err
+	if credentialSecretName := c.Spec.ServiceConfiguration.KeystoneApplicationCredentialSecretName; credentialSecretName != "" {
+		credentialSecret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: credentialSecretName, Namespace: c.Namespace}, credentialSecret); err != nil {
+			return nil, err
+		}
+		k.ApplicationCredentialSecret = string(credentialSecret.Data["secret"])
+		k.ApplicationCredentialID = string(credentialSecret.Data["id"])
+		k.ApplicationCredentialName = string(credentialSecret.Data["name"])
+		if k.ApplicationCredentialSecret == "" || (k.ApplicationCredentialID == "" && k.ApplicationCredentialName == "") {
+			return nil, fmt.Errorf("secret %q must have secret and either id or name", credentialSecretName)
+		}
+	} else {
+		adminPasswordSecretName := c.Spec.ServiceConfiguration.KeystoneSecretName
+		adminPasswordSecret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: adminPasswordSecretName, Namespace: c.Namespace}, adminPasswordSecret); err != nil {
+			return nil, err
+		}
+		k.AdminPassword = string(adminPasswordSecret.Data["password"])
 	}
-	k.AdminPassword = string(adminPasswordSecret.Data["password"])
 
 	keystoneInstanceName := c.Spec.ServiceConfiguration.KeystoneInstance
 	keystone := &Keystone{}
@@ -0,0 +1,80 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProvisionManagerGetAuthParameters(t *testing.T) {
+	scheme, err := SchemeBuilder.Build()
+	require.NoError(t, err, "Failed to build scheme")
+	require.NoError(t, corev1.SchemeBuilder.AddToScheme(scheme), "Failed to add CoreV1 into scheme")
+	keystone := &Keystone{
+		ObjectMeta: metav1.ObjectMeta{Name: "keystone", Namespace: "test-ns"},
+		Spec: KeystoneSpec{ServiceConfiguration: KeystoneConfiguration{
+			AuthProtocol: "https",
+			ListenPort:   5555,
+		}},
+		Status: KeystoneStatus{Endpoint: "10.0.0.1"},
+	}
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keystone-password", Namespace: "test-ns"},
+		Data:       map[string][]byte{"password": []byte("admin-password")},
+	}
+	newProvisionManager := func(credentialSecretName string) *ProvisionManager {
+		return &ProvisionManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "provmanager", Namespace: "test-ns"},
+			Spec: ProvisionManagerSpec{ServiceConfiguration: ProvisionManagerServiceConfiguration{
+				ProvisionManagerConfiguration: ProvisionManagerConfiguration{
+					KeystoneInstance:                        "keystone",
+					KeystoneSecretName:                      "keystone-password",
+					KeystoneApplicationCredentialSecretName: credentialSecretName,
+				},
+			}},
+		}
+	}
+
+	t.Run("should use the admin password when no application credential is set", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, keystone, passwordSecret)
+
+		k, err := newProvisionManager("").GetAuthParameters(cl, "1.1.1.1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "admin-password", k.AdminPassword)
+		assert.Empty(t, k.ApplicationCredentialSecret)
+		assert.Equal(t, "https://10.0.0.1:5555/v3/auth", k.AuthUrl)
+	})
+
+	t.Run("should use the application credential instead of the admin password", func(t *testing.T) {
+		credentialSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "provisioner-credential", Namespace: "test-ns"},
+			Data:       map[string][]byte{"id": []byte("credential-id"), "secret": []byte("credential-secret")},
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, keystone, credentialSecret)
+
+		k, err := newProvisionManager("provisioner-credential").GetAuthParameters(cl, "1.1.1.1")
+
+		require.NoError(t, err)
+		assert.Empty(t, k.AdminPassword)
+		assert.Equal(t, "credential-id", k.ApplicationCredentialID)
+		assert.Empty(t, k.ApplicationCredentialName)
+		assert.Equal(t, "credential-secret", k.ApplicationCredentialSecret)
+	})
+
+	t.Run("should fail when the application credential has neither id nor name", func(t *testing.T) {
+		credentialSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "provisioner-credential", Namespace: "test-ns"},
+			Data:       map[string][]byte{"secret": []byte("credential-secret")},
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, keystone, credentialSecret)
+
+		_, err := newProvisionManager("provisioner-credential").GetAuthParameters(cl, "1.1.1.1")
+
+		assert.Error(t, err)
+	})
+}
@@ -3,8 +3,14 @@ package v1alpha1
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/Juniper/contrail-operator/pkg/certificates"
 	configtemplates "github.com/Juniper/contrail-operator/pkg/configuration"
@@ -12,6 +18,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -42,9 +49,76 @@ type VrouterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html
-	Ports  ConfigStatusPorts `json:"ports,omitempty"`
-	Nodes  map[string]string `json:"nodes,omitempty"`
-	Active *bool             `json:"active,omitempty"`
+	Ports         ConfigStatusPorts               `json:"ports,omitempty"`
+	Nodes         map[string]string               `json:"nodes,omitempty"`
+	Active        *bool                           `json:"active,omitempty"`
+	ServiceStatus map[string]VrouterServiceStatus `json:"serviceStatus,omitempty"`
+	// NodeConfigurations is the configuration rendered for each pod listed in Nodes
+	NodeConfigurations map[string]VrouterNodeStatus `json:"nodeConfigurations,omitempty"`
+	// KernelModules is the state of the vRouter kernel module on each node, keyed by node name
+	KernelModules map[string]VrouterKernelModuleStatus `json:"kernelModules,omitempty"`
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []VrouterCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// VrouterKernelModuleStatus is the vRouter kernel module state of a node
+// +k8s:openapi-gen=true
+type VrouterKernelModuleStatus struct {
+	KernelVersion string                    `json:"kernelVersion,omitempty"`
+	OSImage       string                    `json:"osImage,omitempty"`
+	Source        VrouterKernelModuleSource `json:"source,omitempty"`
+	// Compatible is false when no module matches the kernel of the node. The vRouter is
+	// not scheduled on such nodes.
+	Compatible bool `json:"compatible"`
+}
+
+// VrouterKernelModuleSource tells how the vRouter kernel module is provided on a node.
+type VrouterKernelModuleSource string
+
+const (
+	// PrebuiltKernelModule is installed from the vrouterkernelinit image.
+	PrebuiltKernelModule VrouterKernelModuleSource = "prebuilt"
+	// BuiltKernelModule is compiled on the node by the vrouterkernelbuildinit image.
+	BuiltKernelModule VrouterKernelModuleSource = "build"
+)
+
+// VrouterConditionType is used to represent condition of vRouter.
+type VrouterConditionType string
+
+// These are valid conditions of vRouter.
+const (
+	VrouterKernelModuleCompatible VrouterConditionType = "KernelModuleCompatible"
+	VrouterControlConnected       VrouterConditionType = "ControlConnected"
+)
+
+// VrouterCondition is used to represent vRouter condition
+// +k8s:openapi-gen=true
+type VrouterCondition struct {
+	// Type of vRouter condition.
+	Type VrouterConditionType `json:"type"`
+	// Status of the condition, one of True or False.
+	Status  ConditionStatus `json:"status"`
+	Reason  string          `json:"reason,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// VrouterServiceStatus is the vRouter agent status reported by the statusmonitor
+// +k8s:openapi-gen=true
+type VrouterServiceStatus struct {
+	Connections            []Connection      `json:"connections,omitempty"`
+	XMPPPeers              []VrouterXMPPPeer `json:"xmppPeers,omitempty"`
+	NumberOfInterfaces     string            `json:"numberOfInterfaces,omitempty"`
+	NumberOfDownInterfaces string            `json:"numberOfDownInterfaces,omitempty"`
+	State                  string            `json:"state,omitempty"`
+}
+
+// VrouterXMPPPeer is the XMPP session of the vRouter agent with the control node
+// +k8s:openapi-gen=true
+type VrouterXMPPPeer struct {
+	IP      string `json:"ip,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
 }
 
 // VrouterSpec is the Spec for the vrouter API.
@@ -76,6 +150,109 @@ type VrouterConfiguration struct {
 	VrouterEncryption   bool              `json:"vrouterEncryption,omitempty"`
 	ContrailStatusImage string            `json:"contrailStatusImage,omitempty"`
 	EnvVariablesConfig  map[string]string `json:"envVariablesConfig,omitempty"`
+	// Datapath selects the vRouter forwarding plane, kernel module by default.
+	// +kubebuilder:validation:Enum=kernel;dpdk;sriov
+	Datapath VrouterDatapath            `json:"datapath,omitempty"`
+	Dpdk     *VrouterDpdkConfiguration  `json:"dpdk,omitempty"`
+	Sriov    *VrouterSriovConfiguration `json:"sriov,omitempty"`
+	// Agent holds tunables of the vRouter agent. Agents are restarted when they change.
+	Agent *VrouterAgentConfiguration `json:"agent,omitempty"`
+	// KernelModule configures how the vRouter kernel module is provided on nodes.
+	KernelModule *VrouterKernelModuleConfiguration `json:"kernelModule,omitempty"`
+}
+
+// VrouterKernelModuleConfiguration selects between the prebuilt vRouter kernel module and
+// building it on each node, and lists kernels for which prebuilt modules exist.
+// +k8s:openapi-gen=true
+type VrouterKernelModuleConfiguration struct {
+	// Build compiles the module against kernel headers of each node with the
+	// vrouterkernelbuildinit container. It defaults to true on Ubuntu when the container
+	// is given.
+	Build *bool `json:"build,omitempty"`
+	// SupportedKernels lists kernel versions for which the vrouterkernelinit image ships a
+	// prebuilt module. A trailing "*" matches versions by prefix. When empty all kernels
+	// are assumed to be supported.
+	SupportedKernels []string `json:"supportedKernels,omitempty"`
+}
+
+// VrouterAgentConfiguration is the configuration of the vRouter agent and its forwarding
+// tables. LogLevel, MaxVMFlows and FabricSNATHashTableSize are defaults which may be
+// overridden per node by VrouterNodeProfiles.
+// +k8s:openapi-gen=true
+type VrouterAgentConfiguration struct {
+	// +kubebuilder:validation:Enum=SYS_EMERG;SYS_ALERT;SYS_CRIT;SYS_ERR;SYS_WARN;SYS_NOTICE;SYS_INFO;SYS_DEBUG
+	LogLevel string `json:"logLevel,omitempty"`
+	// MaxVMFlows limits flows of a single VM to the percentage of the flow table.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxVMFlows *int `json:"maxVMFlows,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	FabricSNATHashTableSize *int `json:"fabricSNATHashTableSize,omitempty"`
+	// FlowTableSize is the number of entries of the vRouter flow table.
+	// +kubebuilder:validation:Minimum=1
+	FlowTableSize *int `json:"flowTableSize,omitempty"`
+	// LabelTableSize is the number of entries of the vRouter MPLS label table.
+	// +kubebuilder:validation:Minimum=1
+	LabelTableSize *int `json:"labelTableSize,omitempty"`
+	// FlowThreadCount is the number of agent threads processing flows.
+	// +kubebuilder:validation:Minimum=1
+	FlowThreadCount *int `json:"flowThreadCount,omitempty"`
+	// SloDestination lists where session logs of security logging objects are sent.
+	SloDestination []SessionDestination `json:"sloDestination,omitempty"`
+	// SampleDestination lists where sampled session logs are sent.
+	SampleDestination []SessionDestination `json:"sampleDestination,omitempty"`
+	// TSNServers are addresses of TOR service nodes.
+	TSNServers []string `json:"tsnServers,omitempty"`
+	// IntrospectSSLInsecure disables verification of client certificates of the
+	// introspect server. It is enabled by default.
+	IntrospectSSLInsecure *bool `json:"introspectSSLInsecure,omitempty"`
+}
+
+// SessionDestination is the destination of session logs of the vRouter agent.
+// +kubebuilder:validation:Enum=collector;file;syslog
+type SessionDestination string
+
+const (
+	CollectorSessionDestination SessionDestination = "collector"
+	FileSessionDestination      SessionDestination = "file"
+	SyslogSessionDestination    SessionDestination = "syslog"
+)
+
+// VrouterDpdkConfiguration is the configuration of the DPDK datapath. The vrouterdpdk
+// container runs the forwarding plane in user space and vrouterkernelinitdpdk replaces
+// the kernel module init container.
+// +k8s:openapi-gen=true
+type VrouterDpdkConfiguration struct {
+	// HugePages is the amount of huge pages requested by the vrouterdpdk container.
+	// +kubebuilder:validation:Pattern=^([0-9]+)([KMGTPE]i)?$
+	HugePages string `json:"hugePages,omitempty"`
+	// +kubebuilder:validation:Enum=2Mi;1Gi
+	HugePageSize string `json:"hugePageSize,omitempty"`
+	// CPUCoreMask lists cores used by forwarding threads, e.g. "0x3" or "2,3".
+	CPUCoreMask string `json:"cpuCoreMask,omitempty"`
+	// ServiceCoreMask lists cores used by service threads of the DPDK process and the agent.
+	ServiceCoreMask string `json:"serviceCoreMask,omitempty"`
+	// ControlThreadMask lists cores used by DPDK control threads.
+	ControlThreadMask string `json:"controlThreadMask,omitempty"`
+	// Driver is the poll mode driver to which the physical interface is bound.
+	// +kubebuilder:validation:Enum=uio_pci_generic;igb_uio;vfio-pci
+	Driver string `json:"driver,omitempty"`
+	// PhysicalInterfaceAddress is the PCI address of the physical interface. When empty
+	// it is discovered from the physical interface.
+	PhysicalInterfaceAddress string `json:"physicalInterfaceAddress,omitempty"`
+	// VhostUserSocketDir is the host directory with vhost-user sockets of VM interfaces.
+	VhostUserSocketDir string `json:"vhostUserSocketDir,omitempty"`
+}
+
+// VrouterSriovConfiguration is the configuration of SR-IOV virtual functions created on
+// the physical function before the vRouter starts.
+// +k8s:openapi-gen=true
+type VrouterSriovConfiguration struct {
+	PhysicalFunction string `json:"physicalFunction"`
+	// +kubebuilder:validation:Minimum=1
+	NumVFs int `json:"numVFs"`
+	// PhysicalNetwork is the name of the provider network the virtual functions attach to.
+	PhysicalNetwork string `json:"physicalNetwork,omitempty"`
 }
 
 // VrouterNodesConfiguration is the static configuration for vrouter.
@@ -102,6 +279,45 @@ const (
 	UBUNTU Distribution = "ubuntu"
 )
 
+type VrouterDatapath string
+
+const (
+	KernelDatapath VrouterDatapath = "kernel"
+	DpdkDatapath   VrouterDatapath = "dpdk"
+	SriovDatapath  VrouterDatapath = "sriov"
+)
+
+// Defaults of the DPDK datapath
+const (
+	DefaultDpdkHugePages          = "2Gi"
+	DefaultDpdkHugePageSize       = "1Gi"
+	DefaultDpdkDriver             = "uio_pci_generic"
+	DefaultDpdkVhostUserSocketDir = "/var/run/vrouter"
+)
+
+// Defaults of the vRouter agent
+const (
+	DefaultVrouterAgentLogLevel                = "SYS_NOTICE"
+	DefaultVrouterAgentFabricSNATHashTableSize = 4096
+)
+
+// VrouterAgentConfigHashAnnotation is the annotation of vRouter pods with the hash of the
+// agent configuration. Changing it rolls the agents.
+const VrouterAgentConfigHashAnnotation = "vrouter.contrail.juniper.net/agent-config-hash"
+
+// Annotations of vRouter pods with the physical interface and gateway rendered for the node.
+// The physicalInterface and gateway annotations hold the values discovered on the node and
+// are not overwritten, so that removing an override restores the discovered value.
+const (
+	VrouterPhysicalInterfaceAnnotation = "vrouter.contrail.juniper.net/physical-interface"
+	VrouterGatewayAnnotation           = "vrouter.contrail.juniper.net/gateway"
+)
+
+var vrouterLogLevels = map[string]bool{
+	"SYS_EMERG": true, "SYS_ALERT": true, "SYS_CRIT": true, "SYS_ERR": true,
+	"SYS_WARN": true, "SYS_NOTICE": true, "SYS_INFO": true, "SYS_DEBUG": true,
+}
+
 func init() {
 	SchemeBuilder.Register(&Vrouter{}, &VrouterList{})
 }
@@ -256,6 +472,27 @@ func (c *Vrouter) UpdateDS(ds *appsv1.DaemonSet,
 		return err
 	}
 	imagesChanged := false
+	// Kernel module init containers are upgraded with pods, the agent unloads the module when stopped
+	for _, intendedContainer := range ds.Spec.Template.Spec.InitContainers {
+		for _, currentContainer := range currentDS.Spec.Template.Spec.InitContainers {
+			if intendedContainer.Name == currentContainer.Name && intendedContainer.Image != currentContainer.Image {
+				imagesChanged = true
+			}
+		}
+	}
+	// Nodes with incompatible kernels are excluded with node affinity
+	if !reflect.DeepEqual(ds.Spec.Template.Spec.Affinity, currentDS.Spec.Template.Spec.Affinity) {
+		imagesChanged = true
+	}
+	// Containers are added and removed when the datapath changes
+	if len(ds.Spec.Template.Spec.Containers) != len(currentDS.Spec.Template.Spec.Containers) ||
+		len(ds.Spec.Template.Spec.InitContainers) != len(currentDS.Spec.Template.Spec.InitContainers) {
+		imagesChanged = true
+	}
+	// Agents read their configuration on start, so they are rolled when it changes
+	if ds.Spec.Template.Annotations[VrouterAgentConfigHashAnnotation] != currentDS.Spec.Template.Annotations[VrouterAgentConfigHashAnnotation] {
+		imagesChanged = true
+	}
 	for _, intendedContainer := range ds.Spec.Template.Spec.Containers {
 		for _, currentContainer := range currentDS.Spec.Template.Spec.Containers {
 			if intendedContainer.Name == currentContainer.Name {
@@ -316,7 +553,19 @@ func (c *Vrouter) InstanceConfiguration(request reconcile.Request,
 	if err := client.Get(context.TODO(), types.NamespacedName{Name: instanceConfigMapName, Namespace: request.Namespace}, configMapInstanceDynamicConfig); err != nil {
 		return err
 	}
-	configMapInstanceDynamicConfig.Data = c.createVrouterDynamicConfig(podList, controlNodesInformation, configNodesInformation)
+	nodeStatuses, err := getVrouterNodeStatuses(podList, c.nodeConfigurationDefaults(), request.Namespace, client)
+	if err != nil {
+		return err
+	}
+	if err := syncVrouterPodAnnotations(podList, nodeStatuses, client); err != nil {
+		return err
+	}
+	c.Status.NodeConfigurations = nodeStatuses
+	data, err := c.createVrouterDynamicConfig(podList, nodeStatuses, controlNodesInformation, configNodesInformation)
+	if err != nil {
+		return err
+	}
+	configMapInstanceDynamicConfig.Data = data
 	if err := client.Update(context.TODO(), configMapInstanceDynamicConfig); err != nil {
 		return err
 	}
@@ -379,19 +628,366 @@ func (c *Vrouter) ConfigurationParameters() VrouterConfiguration {
 	vrouterConfiguration.MetaDataSecret = metaDataSecret
 	vrouterConfiguration.EnvVariablesConfig = c.Spec.ServiceConfiguration.EnvVariablesConfig
 
+	vrouterConfiguration.Datapath = c.Spec.ServiceConfiguration.Datapath
+	if vrouterConfiguration.Datapath == "" {
+		vrouterConfiguration.Datapath = KernelDatapath
+	}
+	if vrouterConfiguration.Datapath == DpdkDatapath {
+		dpdk := VrouterDpdkConfiguration{}
+		if c.Spec.ServiceConfiguration.Dpdk != nil {
+			dpdk = *c.Spec.ServiceConfiguration.Dpdk
+		}
+		if dpdk.HugePages == "" {
+			dpdk.HugePages = DefaultDpdkHugePages
+		}
+		if dpdk.HugePageSize == "" {
+			dpdk.HugePageSize = DefaultDpdkHugePageSize
+		}
+		if dpdk.Driver == "" {
+			dpdk.Driver = DefaultDpdkDriver
+		}
+		if dpdk.VhostUserSocketDir == "" {
+			dpdk.VhostUserSocketDir = DefaultDpdkVhostUserSocketDir
+		}
+		vrouterConfiguration.Dpdk = &dpdk
+	}
+	if vrouterConfiguration.Datapath == SriovDatapath {
+		vrouterConfiguration.Sriov = c.Spec.ServiceConfiguration.Sriov
+	}
+
+	agent := VrouterAgentConfiguration{}
+	if c.Spec.ServiceConfiguration.Agent != nil {
+		agent = *c.Spec.ServiceConfiguration.Agent
+	}
+	if agent.LogLevel == "" {
+		agent.LogLevel = DefaultVrouterAgentLogLevel
+	}
+	if agent.FabricSNATHashTableSize == nil {
+		fabricSNATHashTableSize := DefaultVrouterAgentFabricSNATHashTableSize
+		agent.FabricSNATHashTableSize = &fabricSNATHashTableSize
+	}
+	if len(agent.SloDestination) == 0 {
+		agent.SloDestination = []SessionDestination{CollectorSessionDestination}
+	}
+	if len(agent.SampleDestination) == 0 {
+		agent.SampleDestination = []SessionDestination{CollectorSessionDestination}
+	}
+	if agent.IntrospectSSLInsecure == nil {
+		introspectSSLInsecure := true
+		agent.IntrospectSSLInsecure = &introspectSSLInsecure
+	}
+	vrouterConfiguration.Agent = &agent
+
 	return vrouterConfiguration
 }
 
+// ValidateDatapath checks that the selected datapath can be deployed.
+func (c *Vrouter) ValidateDatapath() error {
+	if sriov := c.Spec.ServiceConfiguration.Sriov; sriov != nil && (sriov.PhysicalFunction == "" || sriov.NumVFs < 1) {
+		return fmt.Errorf("sriov configuration requires physicalFunction and numVFs")
+	}
+	switch c.Spec.ServiceConfiguration.Datapath {
+	case DpdkDatapath:
+		containers := map[string]bool{}
+		for _, container := range c.Spec.ServiceConfiguration.Containers {
+			containers[container.Name] = true
+		}
+		for _, name := range []string{"vrouterdpdk", "vrouterkernelinitdpdk"} {
+			if !containers[name] {
+				return fmt.Errorf("container %s is required by the dpdk datapath", name)
+			}
+		}
+		if dpdk := c.Spec.ServiceConfiguration.Dpdk; dpdk != nil && dpdk.HugePages != "" {
+			if _, err := resource.ParseQuantity(dpdk.HugePages); err != nil {
+				return fmt.Errorf("invalid dpdk hugePages %q: %v", dpdk.HugePages, err)
+			}
+		}
+	case SriovDatapath:
+		if c.Spec.ServiceConfiguration.Sriov == nil {
+			return fmt.Errorf("sriov datapath requires physicalFunction and numVFs")
+		}
+	}
+	return nil
+}
+
+// ValidateAgent checks the vRouter agent tunables.
+func (c *Vrouter) ValidateAgent() error {
+	agent := c.Spec.ServiceConfiguration.Agent
+	if agent == nil {
+		return nil
+	}
+	if agent.LogLevel != "" && !vrouterLogLevels[agent.LogLevel] {
+		return fmt.Errorf("invalid agent logLevel %q", agent.LogLevel)
+	}
+	if agent.MaxVMFlows != nil && (*agent.MaxVMFlows < 1 || *agent.MaxVMFlows > 100) {
+		return fmt.Errorf("agent maxVMFlows must be between 1 and 100, got %d", *agent.MaxVMFlows)
+	}
+	for name, size := range map[string]*int{
+		"fabricSNATHashTableSize": agent.FabricSNATHashTableSize,
+		"flowTableSize":           agent.FlowTableSize,
+		"labelTableSize":          agent.LabelTableSize,
+		"flowThreadCount":         agent.FlowThreadCount,
+	} {
+		if size != nil && *size < 1 {
+			return fmt.Errorf("agent %s must be positive, got %d", name, *size)
+		}
+	}
+	for _, destination := range append(append([]SessionDestination{}, agent.SloDestination...), agent.SampleDestination...) {
+		switch destination {
+		case CollectorSessionDestination, FileSessionDestination, SyslogSessionDestination:
+		default:
+			return fmt.Errorf("invalid agent session destination %q", destination)
+		}
+	}
+	for _, server := range agent.TSNServers {
+		if net.ParseIP(server) == nil {
+			return fmt.Errorf("invalid agent TSN server address %q", server)
+		}
+	}
+	return nil
+}
+
+// ValidateKernelModule checks that the vRouter kernel module can be provided.
+func (c *Vrouter) ValidateKernelModule() error {
+	kernelModule := c.Spec.ServiceConfiguration.KernelModule
+	if kernelModule == nil || kernelModule.Build == nil || !*kernelModule.Build {
+		return nil
+	}
+	for _, container := range c.Spec.ServiceConfiguration.Containers {
+		if container.Name == "vrouterkernelbuildinit" {
+			return nil
+		}
+	}
+	return fmt.Errorf("container vrouterkernelbuildinit is required to build the kernel module")
+}
+
+// KernelModuleSource returns how the vRouter kernel module is provided on nodes, or an
+// empty source when the datapath does not use the kernel module.
+func (c *Vrouter) KernelModuleSource() VrouterKernelModuleSource {
+	if c.Spec.ServiceConfiguration.Datapath == DpdkDatapath {
+		return ""
+	}
+	kernelModule := c.Spec.ServiceConfiguration.KernelModule
+	if kernelModule != nil && kernelModule.Build != nil {
+		if *kernelModule.Build {
+			return BuiltKernelModule
+		}
+		return PrebuiltKernelModule
+	}
+	if distribution := c.Spec.ServiceConfiguration.Distribution; distribution != nil && *distribution == UBUNTU {
+		for _, container := range c.Spec.ServiceConfiguration.Containers {
+			if container.Name == "vrouterkernelbuildinit" {
+				return BuiltKernelModule
+			}
+		}
+	}
+	return PrebuiltKernelModule
+}
+
+// ManageKernelModules records the kernel of each node selected for the vRouter and whether
+// the kernel module is available for it. It returns names of nodes with incompatible kernels.
+func (c *Vrouter) ManageKernelModules(reconcileClient client.Client) ([]string, error) {
+	source := c.KernelModuleSource()
+	if source == "" {
+		c.Status.KernelModules = nil
+		c.Status.Conditions = removeVrouterCondition(c.Status.Conditions, VrouterKernelModuleCompatible)
+		return nil, nil
+	}
+	nodeList := &corev1.NodeList{}
+	if err := reconcileClient.List(context.TODO(), nodeList, client.MatchingLabels(c.Spec.CommonConfiguration.NodeSelector)); err != nil {
+		return nil, err
+	}
+	var supportedKernels []string
+	if c.Spec.ServiceConfiguration.KernelModule != nil {
+		supportedKernels = c.Spec.ServiceConfiguration.KernelModule.SupportedKernels
+	}
+	kernelModules := map[string]VrouterKernelModuleStatus{}
+	var incompatibleNodes, incompatibleKernels []string
+	for _, node := range nodeList.Items {
+		kernelVersion := node.Status.NodeInfo.KernelVersion
+		compatible := source == BuiltKernelModule || kernelSupported(kernelVersion, supportedKernels)
+		kernelModules[node.Name] = VrouterKernelModuleStatus{
+			KernelVersion: kernelVersion,
+			OSImage:       node.Status.NodeInfo.OSImage,
+			Source:        source,
+			Compatible:    compatible,
+		}
+		if !compatible {
+			incompatibleNodes = append(incompatibleNodes, node.Name)
+			incompatibleKernels = append(incompatibleKernels, node.Name+" ("+kernelVersion+")")
+		}
+	}
+	sort.Strings(incompatibleNodes)
+	sort.Strings(incompatibleKernels)
+	c.Status.KernelModules = kernelModules
+	condition := VrouterCondition{Type: VrouterKernelModuleCompatible, Status: ConditionTrue}
+	if len(incompatibleNodes) != 0 {
+		condition.Status = ConditionFalse
+		condition.Reason = "IncompatibleKernel"
+		condition.Message = "no prebuilt vRouter kernel module for nodes " + strings.Join(incompatibleKernels, ", ")
+	}
+	c.Status.Conditions = setVrouterCondition(c.Status.Conditions, condition)
+	return incompatibleNodes, nil
+}
+
+// SetControlConnected records whether the vRouter agents of all pods have an established
+// XMPP session with a control node. disconnectedPods lists pods whose agents have none.
+func (c *Vrouter) SetControlConnected(disconnectedPods []string) {
+	condition := VrouterCondition{Type: VrouterControlConnected, Status: ConditionTrue}
+	if len(disconnectedPods) != 0 {
+		sort.Strings(disconnectedPods)
+		condition.Status = ConditionFalse
+		condition.Reason = "NoXMPPSession"
+		condition.Message = "vRouter agents of pods " + strings.Join(disconnectedPods, ", ") + " are not connected to a control node"
+	}
+	c.Status.Conditions = setVrouterCondition(c.Status.Conditions, condition)
+}
+
+func kernelSupported(kernelVersion string, supportedKernels []string) bool {
+	if len(supportedKernels) == 0 {
+		return true
+	}
+	for _, supported := range supportedKernels {
+		if strings.HasSuffix(supported, "*") && strings.HasPrefix(kernelVersion, strings.TrimSuffix(supported, "*")) {
+			return true
+		}
+		if supported == kernelVersion {
+			return true
+		}
+	}
+	return false
+}
+
+func setVrouterCondition(conditions []VrouterCondition, condition VrouterCondition) []VrouterCondition {
+	for idx := range conditions {
+		if conditions[idx].Type == condition.Type {
+			conditions[idx] = condition
+			return conditions
+		}
+	}
+	return append(conditions, condition)
+}
+
+func removeVrouterCondition(conditions []VrouterCondition, conditionType VrouterConditionType) []VrouterCondition {
+	var result []VrouterCondition
+	for _, condition := range conditions {
+		if condition.Type != conditionType {
+			result = append(result, condition)
+		}
+	}
+	return result
+}
+
+// AgentConfigHash returns the hash of the vRouter agent tunables, environment variables
+// and configuration rendered for the selected nodes.
+func (c *Vrouter) AgentConfigHash(reconcileClient client.Client) (string, error) {
+	nodeList := &corev1.NodeList{}
+	if err := reconcileClient.List(context.TODO(), nodeList, client.MatchingLabels(c.Spec.CommonConfiguration.NodeSelector)); err != nil {
+		return "", err
+	}
+	nodeConfigurations, err := getVrouterNodeConfigurations(nodeList.Items, c.nodeConfigurationDefaults(), c.Namespace, reconcileClient)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(struct {
+		Agent       *VrouterAgentConfiguration
+		Environment map[string]string
+		Nodes       map[string]VrouterNodeStatus
+	}{
+		Agent:       c.ConfigurationParameters().Agent,
+		Environment: c.getVrouterEnvironmentData(),
+		Nodes:       nodeConfigurations,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// nodeConfigurationDefaults returns configuration of vRouters on nodes which are not
+// selected by any VrouterNodeProfile.
+func (c *Vrouter) nodeConfigurationDefaults() VrouterNodeConfiguration {
+	vrouterConfig := c.ConfigurationParameters()
+	return VrouterNodeConfiguration{
+		PhysicalInterface:       vrouterConfig.PhysicalInterface,
+		Gateway:                 vrouterConfig.Gateway,
+		LogLevel:                vrouterConfig.Agent.LogLevel,
+		MaxVMFlows:              vrouterConfig.Agent.MaxVMFlows,
+		FabricSNATHashTableSize: vrouterConfig.Agent.FabricSNATHashTableSize,
+	}
+}
+
+// syncVrouterPodAnnotations sets the annotations of pods with the rendered physical interface
+// and gateway, as containers of the vRouter pod read them from annotations.
+func syncVrouterPodAnnotations(podList *corev1.PodList, nodeStatuses map[string]VrouterNodeStatus, client client.Client) error {
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		status := nodeStatuses[pod.Name]
+		annotations := map[string]string{}
+		for key, value := range pod.Annotations {
+			annotations[key] = value
+		}
+		changed := false
+		for key, value := range map[string]string{VrouterPhysicalInterfaceAnnotation: status.PhysicalInterface, VrouterGatewayAnnotation: status.Gateway} {
+			current, ok := annotations[key]
+			switch {
+			case value == "" && ok:
+				delete(annotations, key)
+			case value != "" && current != value:
+				annotations[key] = value
+			default:
+				continue
+			}
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		pod.SetAnnotations(annotations)
+		if err := client.Update(context.TODO(), pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Vrouter) getVrouterEnvironmentData() map[string]string {
 	vrouterConfig := c.ConfigurationParameters()
 	envVariables := make(map[string]string)
 	envVariables["CLOUD_ORCHESTRATOR"] = "kubernetes"
 	envVariables["VROUTER_ENCRYPTION"] = strconv.FormatBool(vrouterConfig.VrouterEncryption)
-	// If PhysicalInterface is set, environment variable from the config map will
-	// override the value from the annotations.
+	// Containers reading the physical interface from the pod annotation use the value
+	// rendered for their node instead.
 	if vrouterConfig.PhysicalInterface != "" {
 		envVariables["PHYSICAL_INTERFACE"] = vrouterConfig.PhysicalInterface
 	}
+	if dpdk := vrouterConfig.Dpdk; dpdk != nil {
+		envVariables["AGENT_MODE"] = string(DpdkDatapath)
+		envVariables["DPDK_UIO_DRIVER"] = dpdk.Driver
+		if dpdk.CPUCoreMask != "" {
+			envVariables["CPU_CORE_MASK"] = dpdk.CPUCoreMask
+		}
+		if dpdk.ServiceCoreMask != "" {
+			envVariables["SERVICE_CORE_MASK"] = dpdk.ServiceCoreMask
+		}
+		if dpdk.ControlThreadMask != "" {
+			envVariables["DPDK_CTRL_THREAD_MASK"] = dpdk.ControlThreadMask
+		}
+	}
+	if tableOptions := vrouterTableOptions(vrouterConfig.Agent); len(tableOptions) != 0 {
+		if vrouterConfig.Dpdk != nil {
+			envVariables["DPDK_COMMAND_ADDITIONAL_ARGS"] = "--" + strings.Join(tableOptions, " --")
+		} else {
+			envVariables["VROUTER_MODULE_OPTIONS"] = strings.Join(tableOptions, " ")
+		}
+	}
+	if sriov := vrouterConfig.Sriov; sriov != nil {
+		envVariables["SRIOV_PHYSICAL_INTERFACE"] = sriov.PhysicalFunction
+		envVariables["SRIOV_VF"] = strconv.Itoa(sriov.NumVFs)
+		if sriov.PhysicalNetwork != "" {
+			envVariables["SRIOV_PHYSICAL_NETWORK"] = sriov.PhysicalNetwork
+		}
+	}
 	if len(vrouterConfig.EnvVariablesConfig) != 0 {
 		for key, value := range vrouterConfig.EnvVariablesConfig {
 			envVariables[key] = value
@@ -400,29 +996,57 @@ func (c *Vrouter) getVrouterEnvironmentData() map[string]string {
 	return envVariables
 }
 
+// vrouterTableOptions returns options of the vRouter forwarding plane sizing its tables.
+func vrouterTableOptions(agent *VrouterAgentConfiguration) []string {
+	var options []string
+	if agent.FlowTableSize != nil {
+		options = append(options, "vr_flow_entries="+strconv.Itoa(*agent.FlowTableSize))
+	}
+	if agent.LabelTableSize != nil {
+		options = append(options, "vr_mpls_labels="+strconv.Itoa(*agent.LabelTableSize))
+	}
+	return options
+}
+
+func joinSessionDestinations(destinations []SessionDestination) string {
+	var names []string
+	for _, destination := range destinations {
+		names = append(names, string(destination))
+	}
+	return strings.Join(names, " ")
+}
+
 func (c *Vrouter) createVrouterDynamicConfig(podList *corev1.PodList,
+	nodeStatuses map[string]VrouterNodeStatus,
 	controlNodesInformation *ControlClusterConfiguration,
-	configNodesInformation *ConfigClusterConfiguration) map[string]string {
+	configNodesInformation *ConfigClusterConfiguration) (map[string]string, error) {
 	vrouterConfig := c.ConfigurationParameters()
 	sort.SliceStable(podList.Items, func(i, j int) bool { return podList.Items[i].Status.PodIP < podList.Items[j].Status.PodIP })
 	data := map[string]string{}
 	for _, vrouterPod := range podList.Items {
-		data["vrouter."+vrouterPod.Status.PodIP] = createVrouterConfigForPod(&vrouterPod, vrouterConfig, controlNodesInformation, configNodesInformation)
+		data["vrouter."+vrouterPod.Status.PodIP] = createVrouterConfigForPod(&vrouterPod, vrouterConfig, nodeStatuses[vrouterPod.Name].VrouterNodeConfiguration, controlNodesInformation, configNodesInformation)
+		agentIntrospectEndpoint := vrouterPod.Status.PodIP + ":" + strconv.Itoa(VrouterAgentIntrospectPort)
+		statusMonitorConfig, err := StatusMonitorConfig(vrouterPod.Annotations["hostname"], []string{agentIntrospectEndpoint},
+			vrouterPod.Status.PodIP, "vrouter", c.Name, c.Namespace, vrouterPod.Name)
+		if err != nil {
+			return nil, err
+		}
+		data["monitorconfig."+vrouterPod.Status.PodIP+".yaml"] = statusMonitorConfig
 	}
-	return data
+	return data, nil
 }
 
-func createVrouterConfigForPod(vrouterPod *corev1.Pod, vrouterConfig VrouterConfiguration, controlNodesInformation *ControlClusterConfiguration, configNodesInformation *ConfigClusterConfiguration) string {
+func createVrouterConfigForPod(vrouterPod *corev1.Pod, vrouterConfig VrouterConfiguration, nodeConfig VrouterNodeConfiguration, controlNodesInformation *ControlClusterConfiguration, configNodesInformation *ConfigClusterConfiguration) string {
 	hostname := vrouterPod.Annotations["hostname"]
 	physicalInterfaceMac := vrouterPod.Annotations["physicalInterfaceMac"]
 	prefixLength := vrouterPod.Annotations["prefixLength"]
-	physicalInterface := vrouterPod.Annotations["physicalInterface"]
-	gateway := vrouterPod.Annotations["gateway"]
-	if vrouterConfig.PhysicalInterface != "" {
-		physicalInterface = vrouterConfig.PhysicalInterface
+	logLevel := DefaultVrouterAgentLogLevel
+	if nodeConfig.LogLevel != "" {
+		logLevel = nodeConfig.LogLevel
 	}
-	if vrouterConfig.Gateway != "" {
-		gateway = vrouterConfig.Gateway
+	fabricSNATHashTableSize := DefaultVrouterAgentFabricSNATHashTableSize
+	if nodeConfig.FabricSNATHashTableSize != nil {
+		fabricSNATHashTableSize = *nodeConfig.FabricSNATHashTableSize
 	}
 	controlXMPPEndpointList := configtemplates.EndpointList(controlNodesInformation.ControlServerIPList, controlNodesInformation.XMPPPort)
 	controlXMPPEndpointListSpaceSeparated := configtemplates.JoinListWithSeparator(controlXMPPEndpointList, " ")
@@ -430,31 +1054,66 @@ func createVrouterConfigForPod(vrouterPod *corev1.Pod, vrouterConfig VrouterConf
 	controlDNSEndpointListSpaceSeparated := configtemplates.JoinListWithSeparator(controlDNSEndpointList, " ")
 	configCollectorEndpointList := configtemplates.EndpointList(configNodesInformation.CollectorServerIPList, configNodesInformation.CollectorPort)
 	configCollectorEndpointListSpaceSeparated := configtemplates.JoinListWithSeparator(configCollectorEndpointList, " ")
+	dpdk := VrouterDpdkConfiguration{}
+	if vrouterConfig.Dpdk != nil {
+		dpdk = *vrouterConfig.Dpdk
+	}
+	agent := vrouterConfig.Agent
+	introspectSSLInsecure := "False"
+	if *agent.IntrospectSSLInsecure {
+		introspectSSLInsecure = "True"
+	}
 	var vrouterConfigBuffer bytes.Buffer
 	configtemplates.VRouterConfig.Execute(&vrouterConfigBuffer, struct {
-		Hostname             string
-		ListenAddress        string
-		ControlServerList    string
-		DNSServerList        string
-		CollectorServerList  string
-		PrefixLength         string
-		PhysicalInterface    string
-		PhysicalInterfaceMac string
-		Gateway              string
-		MetaDataSecret       string
-		CAFilePath           string
+		Hostname              string
+		ListenAddress         string
+		ControlServerList     string
+		DNSServerList         string
+		CollectorServerList   string
+		PrefixLength          string
+		PhysicalInterface     string
+		PhysicalInterfaceMac  string
+		Gateway               string
+		MetaDataSecret        string
+		CAFilePath            string
+		Dpdk                  bool
+		DpdkDriver            string
+		PCIAddress            string
+		ServiceCoreMask       string
+		ControlThreadMask     string
+		LogLevel              string
+		MaxVMFlows            *int
+		FabricSNATHashTable   int
+		FlowThreadCount       *int
+		SloDestination        string
+		SampleDestination     string
+		TSNServers            string
+		IntrospectSSLInsecure string
 	}{
-		Hostname:             hostname,
-		ListenAddress:        vrouterPod.Status.PodIP,
-		ControlServerList:    controlXMPPEndpointListSpaceSeparated,
-		DNSServerList:        controlDNSEndpointListSpaceSeparated,
-		CollectorServerList:  configCollectorEndpointListSpaceSeparated,
-		PrefixLength:         prefixLength,
-		PhysicalInterface:    physicalInterface,
-		PhysicalInterfaceMac: physicalInterfaceMac,
-		Gateway:              gateway,
-		MetaDataSecret:       vrouterConfig.MetaDataSecret,
-		CAFilePath:           certificates.SignerCAFilepath,
+		Hostname:              hostname,
+		ListenAddress:         vrouterPod.Status.PodIP,
+		ControlServerList:     controlXMPPEndpointListSpaceSeparated,
+		DNSServerList:         controlDNSEndpointListSpaceSeparated,
+		CollectorServerList:   configCollectorEndpointListSpaceSeparated,
+		PrefixLength:          prefixLength,
+		PhysicalInterface:     nodeConfig.PhysicalInterface,
+		PhysicalInterfaceMac:  physicalInterfaceMac,
+		Gateway:               nodeConfig.Gateway,
+		MetaDataSecret:        vrouterConfig.MetaDataSecret,
+		CAFilePath:            certificates.SignerCAFilepath,
+		Dpdk:                  vrouterConfig.Datapath == DpdkDatapath,
+		DpdkDriver:            dpdk.Driver,
+		PCIAddress:            dpdk.PhysicalInterfaceAddress,
+		ServiceCoreMask:       dpdk.ServiceCoreMask,
+		ControlThreadMask:     dpdk.ControlThreadMask,
+		LogLevel:              logLevel,
+		MaxVMFlows:            nodeConfig.MaxVMFlows,
+		FabricSNATHashTable:   fabricSNATHashTableSize,
+		FlowThreadCount:       agent.FlowThreadCount,
+		SloDestination:        joinSessionDestinations(agent.SloDestination),
+		SampleDestination:     joinSessionDestinations(agent.SampleDestination),
+		TSNServers:            strings.Join(agent.TSNServers, " "),
+		IntrospectSSLInsecure: introspectSSLInsecure,
 	})
 	return vrouterConfigBuffer.String()
 }
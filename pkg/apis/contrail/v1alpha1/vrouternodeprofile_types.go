@@ -0,0 +1,167 @@
+package v1alpha1
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VrouterNodeProfile overrides configuration of vRouters running on nodes selected by
+// labels. It applies to all Vrouters in its namespace. When several profiles select a
+// node they are applied in the order of their names, so fields set by a later profile
+// take precedence.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=vrouternodeprofiles,scope=Namespaced
+type VrouterNodeProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VrouterNodeProfileSpec `json:"spec,omitempty"`
+}
+
+// VrouterNodeProfileSpec is the Spec for the VrouterNodeProfile API.
+// +k8s:openapi-gen=true
+type VrouterNodeProfileSpec struct {
+	// NodeSelector selects nodes by labels. An empty selector selects all nodes.
+	NodeSelector             map[string]string `json:"nodeSelector,omitempty"`
+	VrouterNodeConfiguration `json:",inline"`
+}
+
+// VrouterNodeConfiguration is the part of vRouter configuration which may differ
+// between nodes.
+// +k8s:openapi-gen=true
+type VrouterNodeConfiguration struct {
+	PhysicalInterface string `json:"physicalInterface,omitempty"`
+	Gateway           string `json:"gateway,omitempty"`
+	// +kubebuilder:validation:Enum=SYS_EMERG;SYS_ALERT;SYS_CRIT;SYS_ERR;SYS_WARN;SYS_NOTICE;SYS_INFO;SYS_DEBUG
+	LogLevel string `json:"logLevel,omitempty"`
+	// MaxVMFlows limits flows of a single VM to the percentage of the flow table.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxVMFlows *int `json:"maxVMFlows,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	FabricSNATHashTableSize *int `json:"fabricSNATHashTableSize,omitempty"`
+}
+
+// VrouterNodeStatus is the configuration rendered for the vRouter on a node.
+// +k8s:openapi-gen=true
+type VrouterNodeStatus struct {
+	Node                     string   `json:"node,omitempty"`
+	Profiles                 []string `json:"profiles,omitempty"`
+	VrouterNodeConfiguration `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VrouterNodeProfileList contains a list of VrouterNodeProfile.
+type VrouterNodeProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VrouterNodeProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VrouterNodeProfile{}, &VrouterNodeProfileList{})
+}
+
+// merge overrides fields which are set in the other configuration.
+func (n *VrouterNodeConfiguration) merge(other VrouterNodeConfiguration) {
+	if other.PhysicalInterface != "" {
+		n.PhysicalInterface = other.PhysicalInterface
+	}
+	if other.Gateway != "" {
+		n.Gateway = other.Gateway
+	}
+	if other.LogLevel != "" {
+		n.LogLevel = other.LogLevel
+	}
+	if other.MaxVMFlows != nil {
+		n.MaxVMFlows = other.MaxVMFlows
+	}
+	if other.FabricSNATHashTableSize != nil {
+		n.FabricSNATHashTableSize = other.FabricSNATHashTableSize
+	}
+}
+
+// getVrouterNodeStatuses returns configuration of vRouter pods, keyed by pod name, built
+// from the physical interface and gateway discovered on the node, the defaults and
+// VrouterNodeProfiles selecting the node of the pod, in this order of precedence.
+func getVrouterNodeStatuses(podList *corev1.PodList, defaults VrouterNodeConfiguration, namespace string, cl client.Client) (map[string]VrouterNodeStatus, error) {
+	profiles, err := listVrouterNodeProfiles(namespace, cl)
+	if err != nil {
+		return nil, err
+	}
+	statuses := map[string]VrouterNodeStatus{}
+	for _, pod := range podList.Items {
+		nodeLabels, err := getNodeLabels(pod.Spec.NodeName, cl)
+		if err != nil {
+			return nil, err
+		}
+		discovered := VrouterNodeConfiguration{
+			PhysicalInterface: pod.Annotations["physicalInterface"],
+			Gateway:           pod.Annotations["gateway"],
+		}
+		statuses[pod.Name] = resolveVrouterNodeStatus(pod.Spec.NodeName, nodeLabels, discovered, defaults, profiles)
+	}
+	return statuses, nil
+}
+
+// getVrouterNodeConfigurations returns configuration of vRouters, keyed by node name, set
+// by the defaults and VrouterNodeProfiles on the nodes. Values discovered on the nodes are
+// not included.
+func getVrouterNodeConfigurations(nodes []corev1.Node, defaults VrouterNodeConfiguration, namespace string, cl client.Client) (map[string]VrouterNodeStatus, error) {
+	profiles, err := listVrouterNodeProfiles(namespace, cl)
+	if err != nil {
+		return nil, err
+	}
+	configurations := map[string]VrouterNodeStatus{}
+	for _, node := range nodes {
+		configurations[node.Name] = resolveVrouterNodeStatus(node.Name, labels.Set(node.Labels), VrouterNodeConfiguration{}, defaults, profiles)
+	}
+	return configurations, nil
+}
+
+func resolveVrouterNodeStatus(nodeName string, nodeLabels labels.Set, discovered, defaults VrouterNodeConfiguration, profiles []VrouterNodeProfile) VrouterNodeStatus {
+	status := VrouterNodeStatus{Node: nodeName}
+	status.merge(discovered)
+	status.merge(defaults)
+	for _, profile := range profiles {
+		if labels.SelectorFromSet(profile.Spec.NodeSelector).Matches(nodeLabels) {
+			status.Profiles = append(status.Profiles, profile.Name)
+			status.merge(profile.Spec.VrouterNodeConfiguration)
+		}
+	}
+	return status
+}
+
+func listVrouterNodeProfiles(namespace string, cl client.Client) ([]VrouterNodeProfile, error) {
+	profileList := &VrouterNodeProfileList{}
+	if err := cl.List(context.TODO(), profileList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	profiles := profileList.Items
+	sort.SliceStable(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+func getNodeLabels(nodeName string, cl client.Client) (labels.Set, error) {
+	if nodeName == "" {
+		return labels.Set{}, nil
+	}
+	node := &corev1.Node{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Name: nodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return labels.Set{}, nil
+		}
+		return nil, err
+	}
+	return labels.Set(node.Labels), nil
+}
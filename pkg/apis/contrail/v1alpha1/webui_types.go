@@ -217,6 +217,13 @@ func (c *Webui) InstanceConfiguration(request reconcile.Request,
 			KeystoneProjectDomainName: keystoneData.projectDomainName,
 		})
 		data["contrail-webui-userauth.js"] = webuiAuthConfigBuffer.String()
+		webuiEndpoint := podList.Items[idx].Status.PodIP + ":" + strconv.Itoa(WebuiHttpsListenPort) + "::contrail-webui"
+		statusMonitorConfig, err := StatusMonitorConfig(podList.Items[idx].Name, []string{webuiEndpoint},
+			podList.Items[idx].Status.PodIP, "webui", request.Name, request.Namespace, podList.Items[idx].Name)
+		if err != nil {
+			return err
+		}
+		data["monitorconfig."+podList.Items[idx].Status.PodIP+".yaml"] = statusMonitorConfig
 	}
 	configMapInstanceDynamicConfig.Data = data
 	err = client.Update(context.TODO(), configMapInstanceDynamicConfig)
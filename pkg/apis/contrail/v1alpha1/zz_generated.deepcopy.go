@@ -92,6 +92,28 @@ func (in *BGPPeer) DeepCopy() *BGPPeer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeerNode) DeepCopyInto(out *BGPPeerNode) {
+	*out = *in
+	in.Node.DeepCopyInto(&out.Node)
+	if in.AddressFamilies != nil {
+		in, out := &in.AddressFamilies, &out.AddressFamilies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeerNode.
+func (in *BGPPeerNode) DeepCopy() *BGPPeerNode {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeerNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CNIPodConfiguration) DeepCopyInto(out *CNIPodConfiguration) {
 	*out = *in
@@ -290,6 +312,29 @@ func (in *CassandraService) DeepCopy() *CassandraService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CassandraServiceStatus) DeepCopyInto(out *CassandraServiceStatus) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]Connection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraServiceStatus.
+func (in *CassandraServiceStatus) DeepCopy() *CassandraServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CassandraServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CassandraSpec) DeepCopyInto(out *CassandraSpec) {
 	*out = *in
@@ -324,6 +369,13 @@ func (in *CassandraStatus) DeepCopyInto(out *CassandraStatus) {
 		}
 	}
 	out.Ports = in.Ports
+	if in.ServiceStatus != nil {
+		in, out := &in.ServiceStatus, &out.ServiceStatus
+		*out = make(map[string]CassandraServiceStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -359,7 +411,7 @@ func (in *Command) DeepCopyInto(out *Command) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -381,6 +433,22 @@ func (in *Command) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandCluster) DeepCopyInto(out *CommandCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandCluster.
+func (in *CommandCluster) DeepCopy() *CommandCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandClusterConfiguration) DeepCopyInto(out *CommandClusterConfiguration) {
 	*out = *in
@@ -416,6 +484,11 @@ func (in *CommandConfiguration) DeepCopyInto(out *CommandConfiguration) {
 		*out = make([]CommandEndpoint, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(CommandResources)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -429,6 +502,22 @@ func (in *CommandConfiguration) DeepCopy() *CommandConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandCredential) DeepCopyInto(out *CommandCredential) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandCredential.
+func (in *CommandCredential) DeepCopy() *CommandCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandEndpoint) DeepCopyInto(out *CommandEndpoint) {
 	*out = *in
@@ -478,6 +567,145 @@ func (in *CommandList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandNode) DeepCopyInto(out *CommandNode) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandNode.
+func (in *CommandNode) DeepCopy() *CommandNode {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandRBACPermission) DeepCopyInto(out *CommandRBACPermission) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandRBACPermission.
+func (in *CommandRBACPermission) DeepCopy() *CommandRBACPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandRBACPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandRBACRule) DeepCopyInto(out *CommandRBACRule) {
+	*out = *in
+	if in.Perms != nil {
+		in, out := &in.Perms, &out.Perms
+		*out = make([]CommandRBACPermission, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandRBACRule.
+func (in *CommandRBACRule) DeepCopy() *CommandRBACRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandRBACRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandResourceStatus) DeepCopyInto(out *CommandResourceStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandResourceStatus.
+func (in *CommandResourceStatus) DeepCopy() *CommandResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandResources) DeepCopyInto(out *CommandResources) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]CommandCluster, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]CommandNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]CommandCredential, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]CommandUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]CommandRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandResources.
+func (in *CommandResources) DeepCopy() *CommandResources {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandRole) DeepCopyInto(out *CommandRole) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CommandRBACRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandRole.
+func (in *CommandRole) DeepCopy() *CommandRole {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandService) DeepCopyInto(out *CommandService) {
 	*out = *in
@@ -518,6 +746,13 @@ func (in *CommandSpec) DeepCopy() *CommandSpec {
 func (in *CommandStatus) DeepCopyInto(out *CommandStatus) {
 	*out = *in
 	out.Status = in.Status
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]CommandResourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -531,6 +766,22 @@ func (in *CommandStatus) DeepCopy() *CommandStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandUser) DeepCopyInto(out *CommandUser) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandUser.
+func (in *CommandUser) DeepCopy() *CommandUser {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -892,6 +1143,23 @@ func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.AnalyticsServiceStatus != nil {
+		in, out := &in.AnalyticsServiceStatus, &out.AnalyticsServiceStatus
+		*out = make(map[string]ConfigServiceStatusMap, len(*in))
+		for key, val := range *in {
+			var outVal map[string]ConfigServiceStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(ConfigServiceStatusMap, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 	return
 }
 
@@ -969,7 +1237,7 @@ func (in *ContrailCNI) DeepCopyInto(out *ContrailCNI) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1025,6 +1293,20 @@ func (in *ContrailCNIConfiguration) DeepCopyInto(out *ContrailCNIConfiguration)
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]ContrailCNINetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]ContrailCNINamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1072,85 +1354,165 @@ func (in *ContrailCNIList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContrailCNIService) DeepCopyInto(out *ContrailCNIService) {
+func (in *ContrailCNINamespace) DeepCopyInto(out *ContrailCNINamespace) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Isolated != nil {
+		in, out := &in.Isolated, &out.Isolated
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VirtualNetwork != nil {
+		in, out := &in.VirtualNetwork, &out.VirtualNetwork
+		*out = new(VirtualNetworkFQName)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNIService.
-func (in *ContrailCNIService) DeepCopy() *ContrailCNIService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNINamespace.
+func (in *ContrailCNINamespace) DeepCopy() *ContrailCNINamespace {
 	if in == nil {
 		return nil
 	}
-	out := new(ContrailCNIService)
+	out := new(ContrailCNINamespace)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContrailCNISpec) DeepCopyInto(out *ContrailCNISpec) {
+func (in *ContrailCNINetwork) DeepCopyInto(out *ContrailCNINetwork) {
 	*out = *in
-	in.CommonConfiguration.DeepCopyInto(&out.CommonConfiguration)
-	in.ServiceConfiguration.DeepCopyInto(&out.ServiceConfiguration)
+	if in.VirtualNetwork != nil {
+		in, out := &in.VirtualNetwork, &out.VirtualNetwork
+		*out = new(VirtualNetworkFQName)
+		**out = **in
+	}
+	if in.IPFabricForwarding != nil {
+		in, out := &in.IPFabricForwarding, &out.IPFabricForwarding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPFabricSnat != nil {
+		in, out := &in.IPFabricSnat, &out.IPFabricSnat
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNISpec.
-func (in *ContrailCNISpec) DeepCopy() *ContrailCNISpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNINetwork.
+func (in *ContrailCNINetwork) DeepCopy() *ContrailCNINetwork {
 	if in == nil {
 		return nil
 	}
-	out := new(ContrailCNISpec)
+	out := new(ContrailCNINetwork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContrailCNIStatus) DeepCopyInto(out *ContrailCNIStatus) {
+func (in *ContrailCNINodeStatus) DeepCopyInto(out *ContrailCNINodeStatus) {
 	*out = *in
-	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNIStatus.
-func (in *ContrailCNIStatus) DeepCopy() *ContrailCNIStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNINodeStatus.
+func (in *ContrailCNINodeStatus) DeepCopy() *ContrailCNINodeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContrailCNIStatus)
+	out := new(ContrailCNINodeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Contrailmonitor) DeepCopyInto(out *Contrailmonitor) {
+func (in *ContrailCNIService) DeepCopyInto(out *ContrailCNIService) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Contrailmonitor.
-func (in *Contrailmonitor) DeepCopy() *Contrailmonitor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNIService.
+func (in *ContrailCNIService) DeepCopy() *ContrailCNIService {
 	if in == nil {
 		return nil
 	}
-	out := new(Contrailmonitor)
+	out := new(ContrailCNIService)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Contrailmonitor) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContrailCNISpec) DeepCopyInto(out *ContrailCNISpec) {
+	*out = *in
+	in.CommonConfiguration.DeepCopyInto(&out.CommonConfiguration)
+	in.ServiceConfiguration.DeepCopyInto(&out.ServiceConfiguration)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNISpec.
+func (in *ContrailCNISpec) DeepCopy() *ContrailCNISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContrailCNISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContrailCNIStatus) DeepCopyInto(out *ContrailCNIStatus) {
+	*out = *in
+	out.Status = in.Status
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make(map[string]ContrailCNINodeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContrailCNIStatus.
+func (in *ContrailCNIStatus) DeepCopy() *ContrailCNIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContrailCNIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Contrailmonitor) DeepCopyInto(out *Contrailmonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Contrailmonitor.
+func (in *Contrailmonitor) DeepCopy() *Contrailmonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(Contrailmonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Contrailmonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContrailmonitorConfiguration) DeepCopyInto(out *ContrailmonitorConfiguration) {
@@ -1650,13 +2012,50 @@ func (in *Encryption) DeepCopy() *Encryption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalBGPPeer) DeepCopyInto(out *ExternalBGPPeer) {
+	*out = *in
+	if in.AddressFamilies != nil {
+		in, out := &in.AddressFamilies, &out.AddressFamilies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalBGPPeer.
+func (in *ExternalBGPPeer) DeepCopy() *ExternalBGPPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalBGPPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FastConvergenceParameters) DeepCopyInto(out *FastConvergenceParameters) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FastConvergenceParameters.
+func (in *FastConvergenceParameters) DeepCopy() *FastConvergenceParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(FastConvergenceParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FernetKeyManager) DeepCopyInto(out *FernetKeyManager) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1714,6 +2113,13 @@ func (in *FernetKeyManagerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FernetKeyManagerSpec) DeepCopyInto(out *FernetKeyManagerSpec) {
 	*out = *in
+	if in.KeystonePodSelector != nil {
+		in, out := &in.KeystonePodSelector, &out.KeystonePodSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -1730,6 +2136,8 @@ func (in *FernetKeyManagerSpec) DeepCopy() *FernetKeyManagerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FernetKeyManagerStatus) DeepCopyInto(out *FernetKeyManagerStatus) {
 	*out = *in
+	in.FernetKeys.DeepCopyInto(&out.FernetKeys)
+	in.CredentialKeys.DeepCopyInto(&out.CredentialKeys)
 	return
 }
 
@@ -1743,10 +2151,96 @@ func (in *FernetKeyManagerStatus) DeepCopy() *FernetKeyManagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FloatingIPPoolFQName) DeepCopyInto(out *FloatingIPPoolFQName) {
+	*out = *in
+	out.VirtualNetworkFQName = in.VirtualNetworkFQName
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FloatingIPPoolFQName.
+func (in *FloatingIPPoolFQName) DeepCopy() *FloatingIPPoolFQName {
+	if in == nil {
+		return nil
+	}
+	out := new(FloatingIPPoolFQName)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowAgingTimeout) DeepCopyInto(out *FlowAgingTimeout) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowAgingTimeout.
+func (in *FlowAgingTimeout) DeepCopy() *FlowAgingTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowAgingTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalSystemConfiguration) DeepCopyInto(out *GlobalSystemConfiguration) {
+	*out = *in
+	if in.AutonomousSystem != nil {
+		in, out := &in.AutonomousSystem, &out.AutonomousSystem
+		*out = new(int)
+		**out = **in
+	}
+	if in.IBGPAutoMesh != nil {
+		in, out := &in.IBGPAutoMesh, &out.IBGPAutoMesh
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FastConvergence != nil {
+		in, out := &in.FastConvergence, &out.FastConvergence
+		*out = new(FastConvergenceParameters)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSystemConfiguration.
+func (in *GlobalSystemConfiguration) DeepCopy() *GlobalSystemConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalSystemConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalVrouterConfiguration) DeepCopyInto(out *GlobalVrouterConfiguration) {
 	*out = *in
 	out.EcmpHashingIncludeFields = in.EcmpHashingIncludeFields
+	if in.LinklocalServices != nil {
+		in, out := &in.LinklocalServices, &out.LinklocalServices
+		*out = make([]LinklocalService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FlowExportRate != nil {
+		in, out := &in.FlowExportRate, &out.FlowExportRate
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowAgingTimeouts != nil {
+		in, out := &in.FlowAgingTimeouts, &out.FlowAgingTimeouts
+		*out = make([]FlowAgingTimeout, len(*in))
+		copy(*out, *in)
+	}
+	if in.PortTranslationPools != nil {
+		in, out := &in.PortTranslationPools, &out.PortTranslationPools
+		*out = make([]PortTranslationPool, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1760,13 +2254,33 @@ func (in *GlobalVrouterConfiguration) DeepCopy() *GlobalVrouterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotationStatus) DeepCopyInto(out *KeyRotationStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotationStatus.
+func (in *KeyRotationStatus) DeepCopy() *KeyRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Keystone) DeepCopyInto(out *Keystone) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1835,6 +2349,21 @@ func (in *KeystoneConfiguration) DeepCopyInto(out *KeystoneConfiguration) {
 			}
 		}
 	}
+	if in.ExternalAddresses != nil {
+		in, out := &in.ExternalAddresses, &out.ExternalAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]KeystoneDomain, len(*in))
+		copy(*out, *in)
+	}
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(KeystoneFederation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1849,118 +2378,396 @@ func (in *KeystoneConfiguration) DeepCopy() *KeystoneConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeystoneList) DeepCopyInto(out *KeystoneList) {
+func (in *KeystoneDomain) DeepCopyInto(out *KeystoneDomain) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Keystone, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.LDAP = in.LDAP
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneList.
-func (in *KeystoneList) DeepCopy() *KeystoneList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneDomain.
+func (in *KeystoneDomain) DeepCopy() *KeystoneDomain {
 	if in == nil {
 		return nil
 	}
-	out := new(KeystoneList)
+	out := new(KeystoneDomain)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeystoneList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeystoneService) DeepCopyInto(out *KeystoneService) {
+func (in *KeystoneFederation) DeepCopyInto(out *KeystoneFederation) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.TrustedDashboards != nil {
+		in, out := &in.TrustedDashboards, &out.TrustedDashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdentityProviders != nil {
+		in, out := &in.IdentityProviders, &out.IdentityProviders
+		*out = make([]KeystoneIdentityProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneService.
-func (in *KeystoneService) DeepCopy() *KeystoneService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneFederation.
+func (in *KeystoneFederation) DeepCopy() *KeystoneFederation {
 	if in == nil {
 		return nil
 	}
-	out := new(KeystoneService)
+	out := new(KeystoneFederation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeystoneSpec) DeepCopyInto(out *KeystoneSpec) {
+func (in *KeystoneIdentityProvider) DeepCopyInto(out *KeystoneIdentityProvider) {
 	*out = *in
-	in.CommonConfiguration.DeepCopyInto(&out.CommonConfiguration)
-	in.ServiceConfiguration.DeepCopyInto(&out.ServiceConfiguration)
+	if in.RemoteIDs != nil {
+		in, out := &in.RemoteIDs, &out.RemoteIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Mapping != nil {
+		in, out := &in.Mapping, &out.Mapping
+		*out = make([]KeystoneMappingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SAML2 != nil {
+		in, out := &in.SAML2, &out.SAML2
+		*out = new(KeystoneSAML2Provider)
+		**out = **in
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(KeystoneOIDCProvider)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneSpec.
-func (in *KeystoneSpec) DeepCopy() *KeystoneSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneIdentityProvider.
+func (in *KeystoneIdentityProvider) DeepCopy() *KeystoneIdentityProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(KeystoneSpec)
+	out := new(KeystoneIdentityProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeystoneStatus) DeepCopyInto(out *KeystoneStatus) {
+func (in *KeystoneLDAPBackend) DeepCopyInto(out *KeystoneLDAPBackend) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneStatus.
-func (in *KeystoneStatus) DeepCopy() *KeystoneStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneLDAPBackend.
+func (in *KeystoneLDAPBackend) DeepCopy() *KeystoneLDAPBackend {
 	if in == nil {
 		return nil
 	}
-	out := new(KeystoneStatus)
+	out := new(KeystoneLDAPBackend)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kubemanager) DeepCopyInto(out *Kubemanager) {
+func (in *KeystoneList) DeepCopyInto(out *KeystoneList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Keystone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubemanager.
-func (in *Kubemanager) DeepCopy() *Kubemanager {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneList.
+func (in *KeystoneList) DeepCopy() *KeystoneList {
 	if in == nil {
 		return nil
 	}
-	out := new(Kubemanager)
+	out := new(KeystoneList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Kubemanager) DeepCopyObject() runtime.Object {
+func (in *KeystoneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneMappingLocal) DeepCopyInto(out *KeystoneMappingLocal) {
+	*out = *in
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(KeystoneMappingObject)
+		**out = **in
+	}
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(KeystoneMappingObject)
+		**out = **in
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]KeystoneMappingProject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneMappingLocal.
+func (in *KeystoneMappingLocal) DeepCopy() *KeystoneMappingLocal {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneMappingLocal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneMappingObject) DeepCopyInto(out *KeystoneMappingObject) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneMappingObject.
+func (in *KeystoneMappingObject) DeepCopy() *KeystoneMappingObject {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneMappingObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneMappingProject) DeepCopyInto(out *KeystoneMappingProject) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneMappingProject.
+func (in *KeystoneMappingProject) DeepCopy() *KeystoneMappingProject {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneMappingProject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneMappingRemote) DeepCopyInto(out *KeystoneMappingRemote) {
+	*out = *in
+	if in.AnyOneOf != nil {
+		in, out := &in.AnyOneOf, &out.AnyOneOf
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotAnyOf != nil {
+		in, out := &in.NotAnyOf, &out.NotAnyOf
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneMappingRemote.
+func (in *KeystoneMappingRemote) DeepCopy() *KeystoneMappingRemote {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneMappingRemote)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneMappingRule) DeepCopyInto(out *KeystoneMappingRule) {
+	*out = *in
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = make([]KeystoneMappingLocal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = make([]KeystoneMappingRemote, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneMappingRule.
+func (in *KeystoneMappingRule) DeepCopy() *KeystoneMappingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneMappingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneOIDCProvider) DeepCopyInto(out *KeystoneOIDCProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneOIDCProvider.
+func (in *KeystoneOIDCProvider) DeepCopy() *KeystoneOIDCProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneOIDCProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneSAML2Provider) DeepCopyInto(out *KeystoneSAML2Provider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneSAML2Provider.
+func (in *KeystoneSAML2Provider) DeepCopy() *KeystoneSAML2Provider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneSAML2Provider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneService) DeepCopyInto(out *KeystoneService) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneService.
+func (in *KeystoneService) DeepCopy() *KeystoneService {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneSpec) DeepCopyInto(out *KeystoneSpec) {
+	*out = *in
+	in.CommonConfiguration.DeepCopyInto(&out.CommonConfiguration)
+	in.ServiceConfiguration.DeepCopyInto(&out.ServiceConfiguration)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneSpec.
+func (in *KeystoneSpec) DeepCopy() *KeystoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneStatus) DeepCopyInto(out *KeystoneStatus) {
+	*out = *in
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneStatus.
+func (in *KeystoneStatus) DeepCopy() *KeystoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kubemanager) DeepCopyInto(out *Kubemanager) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubemanager.
+func (in *Kubemanager) DeepCopy() *Kubemanager {
+	if in == nil {
+		return nil
+	}
+	out := new(Kubemanager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Kubemanager) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubemanagerClusterStatus) DeepCopyInto(out *KubemanagerClusterStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubemanagerClusterStatus.
+func (in *KubemanagerClusterStatus) DeepCopy() *KubemanagerClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubemanagerClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubemanagerConfiguration) DeepCopyInto(out *KubemanagerConfiguration) {
 	*out = *in
@@ -2005,6 +2812,36 @@ func (in *KubemanagerConfiguration) DeepCopyInto(out *KubemanagerConfiguration)
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(KubemanagerRemoteCluster)
+		**out = **in
+	}
+	if in.IsolatedNamespaces != nil {
+		in, out := &in.IsolatedNamespaces, &out.IsolatedNamespaces
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PublicFIPPool != nil {
+		in, out := &in.PublicFIPPool, &out.PublicFIPPool
+		*out = new(FloatingIPPoolFQName)
+		**out = **in
+	}
+	if in.PodNetwork != nil {
+		in, out := &in.PodNetwork, &out.PodNetwork
+		*out = new(VirtualNetworkFQName)
+		**out = **in
+	}
+	if in.ServiceNetwork != nil {
+		in, out := &in.ServiceNetwork, &out.ServiceNetwork
+		*out = new(VirtualNetworkFQName)
+		**out = **in
+	}
+	if in.IPFabricNetwork != nil {
+		in, out := &in.IPFabricNetwork, &out.IPFabricNetwork
+		*out = new(VirtualNetworkFQName)
+		**out = **in
+	}
 	return
 }
 
@@ -2109,6 +2946,22 @@ func (in *KubemanagerNodesConfiguration) DeepCopy() *KubemanagerNodesConfigurati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubemanagerRemoteCluster) DeepCopyInto(out *KubemanagerRemoteCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubemanagerRemoteCluster.
+func (in *KubemanagerRemoteCluster) DeepCopy() *KubemanagerRemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KubemanagerRemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubemanagerService) DeepCopyInto(out *KubemanagerService) {
 	*out = *in
@@ -2201,6 +3054,11 @@ func (in *KubemanagerStatus) DeepCopyInto(out *KubemanagerStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(KubemanagerClusterStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -2214,6 +3072,27 @@ func (in *KubemanagerStatus) DeepCopy() *KubemanagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinklocalService) DeepCopyInto(out *LinklocalService) {
+	*out = *in
+	if in.FabricIPs != nil {
+		in, out := &in.FabricIPs, &out.FabricIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinklocalService.
+func (in *LinklocalService) DeepCopy() *LinklocalService {
+	if in == nil {
+		return nil
+	}
+	out := new(LinklocalService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manager) DeepCopyInto(out *Manager) {
 	*out = *in
@@ -2499,7 +3378,7 @@ func (in *Memcached) DeepCopyInto(out *Memcached) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -2621,6 +3500,11 @@ func (in *MemcachedSpec) DeepCopy() *MemcachedSpec {
 func (in *MemcachedStatus) DeepCopyInto(out *MemcachedStatus) {
 	*out = *in
 	out.Status = in.Status
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2791,9 +3675,25 @@ func (in *PodConfiguration) DeepCopy() *PodConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Postgres) DeepCopyInto(out *Postgres) {
+func (in *PortTranslationPool) DeepCopyInto(out *PortTranslationPool) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortTranslationPool.
+func (in *PortTranslationPool) DeepCopy() *PortTranslationPool {
+	if in == nil {
+		return nil
+	}
+	out := new(PortTranslationPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Postgres) DeepCopyInto(out *Postgres) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
@@ -2974,7 +3874,20 @@ func (in *ProvisionManagerConfiguration) DeepCopyInto(out *ProvisionManagerConfi
 			}
 		}
 	}
-	out.GlobalVrouterConfiguration = in.GlobalVrouterConfiguration
+	in.GlobalVrouterConfiguration.DeepCopyInto(&out.GlobalVrouterConfiguration)
+	in.GlobalSystemConfiguration.DeepCopyInto(&out.GlobalSystemConfiguration)
+	if in.BGPPeers != nil {
+		in, out := &in.BGPPeers, &out.BGPPeers
+		*out = make([]ExternalBGPPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VirtualDNSServers != nil {
+		in, out := &in.VirtualDNSServers, &out.VirtualDNSServers
+		*out = make([]VirtualDNSServer, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -3978,6 +4891,38 @@ func (in *SwiftStorageStatus) DeepCopy() *SwiftStorageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualDNSServer) DeepCopyInto(out *VirtualDNSServer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualDNSServer.
+func (in *VirtualDNSServer) DeepCopy() *VirtualDNSServer {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualDNSServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNetworkFQName) DeepCopyInto(out *VirtualNetworkFQName) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkFQName.
+func (in *VirtualNetworkFQName) DeepCopy() *VirtualNetworkFQName {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkFQName)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Vrouter) DeepCopyInto(out *Vrouter) {
 	*out = *in
@@ -4006,6 +4951,67 @@ func (in *Vrouter) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterAgentConfiguration) DeepCopyInto(out *VrouterAgentConfiguration) {
+	*out = *in
+	if in.MaxVMFlows != nil {
+		in, out := &in.MaxVMFlows, &out.MaxVMFlows
+		*out = new(int)
+		**out = **in
+	}
+	if in.FabricSNATHashTableSize != nil {
+		in, out := &in.FabricSNATHashTableSize, &out.FabricSNATHashTableSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowTableSize != nil {
+		in, out := &in.FlowTableSize, &out.FlowTableSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.LabelTableSize != nil {
+		in, out := &in.LabelTableSize, &out.LabelTableSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowThreadCount != nil {
+		in, out := &in.FlowThreadCount, &out.FlowThreadCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.SloDestination != nil {
+		in, out := &in.SloDestination, &out.SloDestination
+		*out = make([]SessionDestination, len(*in))
+		copy(*out, *in)
+	}
+	if in.SampleDestination != nil {
+		in, out := &in.SampleDestination, &out.SampleDestination
+		*out = make([]SessionDestination, len(*in))
+		copy(*out, *in)
+	}
+	if in.TSNServers != nil {
+		in, out := &in.TSNServers, &out.TSNServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IntrospectSSLInsecure != nil {
+		in, out := &in.IntrospectSSLInsecure, &out.IntrospectSSLInsecure
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterAgentConfiguration.
+func (in *VrouterAgentConfiguration) DeepCopy() *VrouterAgentConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterAgentConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VrouterClusterConfiguration) DeepCopyInto(out *VrouterClusterConfiguration) {
 	*out = *in
@@ -4022,6 +5028,22 @@ func (in *VrouterClusterConfiguration) DeepCopy() *VrouterClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterCondition) DeepCopyInto(out *VrouterCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterCondition.
+func (in *VrouterCondition) DeepCopy() *VrouterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VrouterConfiguration) DeepCopyInto(out *VrouterConfiguration) {
 	*out = *in
@@ -4053,6 +5075,26 @@ func (in *VrouterConfiguration) DeepCopyInto(out *VrouterConfiguration) {
 			(*out)[key] = val
 		}
 	}
+	if in.Dpdk != nil {
+		in, out := &in.Dpdk, &out.Dpdk
+		*out = new(VrouterDpdkConfiguration)
+		**out = **in
+	}
+	if in.Sriov != nil {
+		in, out := &in.Sriov, &out.Sriov
+		*out = new(VrouterSriovConfiguration)
+		**out = **in
+	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(VrouterAgentConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KernelModule != nil {
+		in, out := &in.KernelModule, &out.KernelModule
+		*out = new(VrouterKernelModuleConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4066,6 +5108,64 @@ func (in *VrouterConfiguration) DeepCopy() *VrouterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterDpdkConfiguration) DeepCopyInto(out *VrouterDpdkConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterDpdkConfiguration.
+func (in *VrouterDpdkConfiguration) DeepCopy() *VrouterDpdkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterDpdkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterKernelModuleConfiguration) DeepCopyInto(out *VrouterKernelModuleConfiguration) {
+	*out = *in
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SupportedKernels != nil {
+		in, out := &in.SupportedKernels, &out.SupportedKernels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterKernelModuleConfiguration.
+func (in *VrouterKernelModuleConfiguration) DeepCopy() *VrouterKernelModuleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterKernelModuleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterKernelModuleStatus) DeepCopyInto(out *VrouterKernelModuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterKernelModuleStatus.
+func (in *VrouterKernelModuleStatus) DeepCopy() *VrouterKernelModuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterKernelModuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VrouterList) DeepCopyInto(out *VrouterList) {
 	*out = *in
@@ -4133,6 +5233,138 @@ func (in *VrouterNode) DeepCopy() *VrouterNode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterNodeConfiguration) DeepCopyInto(out *VrouterNodeConfiguration) {
+	*out = *in
+	if in.MaxVMFlows != nil {
+		in, out := &in.MaxVMFlows, &out.MaxVMFlows
+		*out = new(int)
+		**out = **in
+	}
+	if in.FabricSNATHashTableSize != nil {
+		in, out := &in.FabricSNATHashTableSize, &out.FabricSNATHashTableSize
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterNodeConfiguration.
+func (in *VrouterNodeConfiguration) DeepCopy() *VrouterNodeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterNodeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterNodeProfile) DeepCopyInto(out *VrouterNodeProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterNodeProfile.
+func (in *VrouterNodeProfile) DeepCopy() *VrouterNodeProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterNodeProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VrouterNodeProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterNodeProfileList) DeepCopyInto(out *VrouterNodeProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VrouterNodeProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterNodeProfileList.
+func (in *VrouterNodeProfileList) DeepCopy() *VrouterNodeProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterNodeProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VrouterNodeProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterNodeProfileSpec) DeepCopyInto(out *VrouterNodeProfileSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.VrouterNodeConfiguration.DeepCopyInto(&out.VrouterNodeConfiguration)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterNodeProfileSpec.
+func (in *VrouterNodeProfileSpec) DeepCopy() *VrouterNodeProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterNodeProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterNodeStatus) DeepCopyInto(out *VrouterNodeStatus) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.VrouterNodeConfiguration.DeepCopyInto(&out.VrouterNodeConfiguration)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterNodeStatus.
+func (in *VrouterNodeStatus) DeepCopy() *VrouterNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VrouterNodesConfiguration) DeepCopyInto(out *VrouterNodesConfiguration) {
 	*out = *in
@@ -4213,6 +5445,34 @@ func (in *VrouterServiceSpec) DeepCopy() *VrouterServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterServiceStatus) DeepCopyInto(out *VrouterServiceStatus) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]Connection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.XMPPPeers != nil {
+		in, out := &in.XMPPPeers, &out.XMPPPeers
+		*out = make([]VrouterXMPPPeer, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterServiceStatus.
+func (in *VrouterServiceStatus) DeepCopy() *VrouterServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VrouterSpec) DeepCopyInto(out *VrouterSpec) {
 	*out = *in
@@ -4231,6 +5491,22 @@ func (in *VrouterSpec) DeepCopy() *VrouterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterSriovConfiguration) DeepCopyInto(out *VrouterSriovConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterSriovConfiguration.
+func (in *VrouterSriovConfiguration) DeepCopy() *VrouterSriovConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterSriovConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VrouterStatus) DeepCopyInto(out *VrouterStatus) {
 	*out = *in
@@ -4247,6 +5523,32 @@ func (in *VrouterStatus) DeepCopyInto(out *VrouterStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ServiceStatus != nil {
+		in, out := &in.ServiceStatus, &out.ServiceStatus
+		*out = make(map[string]VrouterServiceStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.NodeConfigurations != nil {
+		in, out := &in.NodeConfigurations, &out.NodeConfigurations
+		*out = make(map[string]VrouterNodeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make(map[string]VrouterKernelModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VrouterCondition, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -4260,6 +5562,22 @@ func (in *VrouterStatus) DeepCopy() *VrouterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VrouterXMPPPeer) DeepCopyInto(out *VrouterXMPPPeer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VrouterXMPPPeer.
+func (in *VrouterXMPPPeer) DeepCopy() *VrouterXMPPPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(VrouterXMPPPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebUIClusterConfiguration) DeepCopyInto(out *WebUIClusterConfiguration) {
 	*out = *in
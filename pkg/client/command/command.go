@@ -0,0 +1,105 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// NewClient creates a client of the Contrail Command API server which authenticates
+// requests with the keystone token.
+func NewClient(connector Connector, token string) *Client {
+	return &Client{connector: connector, token: token}
+}
+
+// Connector specifies backend mechanism used to communicate with Contrail Command.
+// When Command is deployed as part of the cluster this should be a kubeproxy client.
+type Connector interface {
+	NewRequest(method, path string, body io.Reader) (*http.Request, error)
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is an interface to the Contrail Command API server.
+type Client struct {
+	connector Connector
+	token     string
+}
+
+// Resource is a single Contrail Command object in the format used by init_cluster.yml
+// and by the sync endpoint.
+type Resource struct {
+	Kind string                 `json:"kind"`
+	Data map[string]interface{} `json:"data"`
+}
+
+type syncEvent struct {
+	Kind      string                 `json:"kind"`
+	Data      map[string]interface{} `json:"data"`
+	Operation string                 `json:"operation"`
+}
+
+type syncRequest struct {
+	Resources []syncEvent `json:"resources"`
+}
+
+// Sync creates the resource or updates it when it already exists. It works the same
+// way as `contrailcli sync`.
+func (c *Client) Sync(resource Resource) error {
+	status, body, err := c.sync(resource, "CREATE")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict {
+		status, body, err = c.sync(resource, "UPDATE")
+		if err != nil {
+			return err
+		}
+	}
+	if status >= 300 {
+		return fmt.Errorf("failed to sync %s: invalid status code returned: %d, response: %s", resource.Kind, status, body)
+	}
+	return nil
+}
+
+// Delete removes the resource identified by the uuid in its data. Resources which
+// do not exist are treated as deleted.
+func (c *Client) Delete(resource Resource) error {
+	status, body, err := c.sync(resource, "DELETE")
+	if err != nil {
+		return err
+	}
+	if status >= 300 && status != http.StatusNotFound {
+		return fmt.Errorf("failed to delete %s: invalid status code returned: %d, response: %s", resource.Kind, status, body)
+	}
+	return nil
+}
+
+func (c *Client) sync(resource Resource, operation string) (int, string, error) {
+	data, err := json.Marshal(syncRequest{
+		Resources: []syncEvent{{Kind: resource.Kind, Data: resource.Data, Operation: operation}},
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	request, err := c.connector.NewRequest(http.MethodPost, "/sync", bytes.NewReader(data))
+	if err != nil {
+		return 0, "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		request.Header.Set("X-Auth-Token", c.token)
+	}
+	response, err := c.connector.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return 0, "", err
+	}
+	return response.StatusCode, string(body), nil
+}
@@ -0,0 +1,128 @@
+package command_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Juniper/contrail-operator/pkg/client/command"
+)
+
+type httpConnector struct {
+	url string
+}
+
+func (c httpConnector) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, c.url+path, body)
+}
+
+func (c httpConnector) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+type syncRequest struct {
+	Resources []struct {
+		Kind      string                 `json:"kind"`
+		Data      map[string]interface{} `json:"data"`
+		Operation string                 `json:"operation"`
+	} `json:"resources"`
+}
+
+func TestSync(t *testing.T) {
+	node := command.Resource{Kind: "node", Data: map[string]interface{}{"uuid": "n1", "hostname": "node1"}}
+
+	t.Run("should create resource", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/sync", r.URL.Path)
+			assert.Equal(t, "token", r.Header.Get("X-Auth-Token"))
+			body := syncRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Len(t, body.Resources, 1)
+			assert.Equal(t, "node", body.Resources[0].Kind)
+			assert.Equal(t, "CREATE", body.Resources[0].Operation)
+			assert.Equal(t, "node1", body.Resources[0].Data["hostname"])
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+		client := command.NewClient(httpConnector{url: server.URL}, "token")
+
+		assert.NoError(t, client.Sync(node))
+	})
+
+	t.Run("should update resource which already exists", func(t *testing.T) {
+		var operations []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := syncRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			operations = append(operations, body.Resources[0].Operation)
+			if body.Resources[0].Operation == "CREATE" {
+				w.WriteHeader(http.StatusConflict)
+			}
+		}))
+		defer server.Close()
+		client := command.NewClient(httpConnector{url: server.URL}, "token")
+
+		assert.NoError(t, client.Sync(node))
+		assert.Equal(t, []string{"CREATE", "UPDATE"}, operations)
+	})
+
+	t.Run("should fail when server rejects resource", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad reference"))
+		}))
+		defer server.Close()
+		client := command.NewClient(httpConnector{url: server.URL}, "token")
+
+		err := client.Sync(node)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad reference")
+	})
+}
+
+func TestDelete(t *testing.T) {
+	node := command.Resource{Kind: "node", Data: map[string]interface{}{"uuid": "n1"}}
+
+	t.Run("should delete resource", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := syncRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Len(t, body.Resources, 1)
+			assert.Equal(t, "DELETE", body.Resources[0].Operation)
+			assert.Equal(t, "n1", body.Resources[0].Data["uuid"])
+		}))
+		defer server.Close()
+		client := command.NewClient(httpConnector{url: server.URL}, "token")
+
+		assert.NoError(t, client.Delete(node))
+	})
+
+	t.Run("should treat missing resource as deleted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		client := command.NewClient(httpConnector{url: server.URL}, "token")
+
+		assert.NoError(t, client.Delete(node))
+	})
+
+	t.Run("should fail when server rejects deletion", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte("node is referenced"))
+		}))
+		defer server.Close()
+		client := command.NewClient(httpConnector{url: server.URL}, "token")
+
+		err := client.Delete(node)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "node is referenced")
+	})
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -79,3 +82,47 @@ func (c *Client) GetResource(resourceName string) ([]byte, error) {
 	}
 	return ioutil.ReadAll(response.Body)
 }
+
+// ErrNotFound is returned when a resource does not exist in the Config API
+var ErrNotFound = errors.New("resource not found")
+
+type fqNameToIDRequest struct {
+	Type   string   `json:"type"`
+	FqName []string `json:"fq_name"`
+}
+
+type fqNameToIDResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// FQNameToID returns the UUID of a resource of given type and fully qualified name
+func (c *Client) FQNameToID(resourceType string, fqName []string) (string, error) {
+	body, err := json.Marshal(fqNameToIDRequest{Type: resourceType, FqName: fqName})
+	if err != nil {
+		return "", err
+	}
+	request, err := c.proxy.NewRequest(http.MethodPost, "/fqname-to-id", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		request.Header.Set("X-Auth-Token", c.token)
+	}
+	response, err := c.proxy.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+	if response.StatusCode != 200 {
+		return "", fmt.Errorf("invalid status code returned: %d, response: %s", response.StatusCode, c.response(response))
+	}
+	var id fqNameToIDResponse
+	if err := json.NewDecoder(response.Body).Decode(&id); err != nil {
+		return "", err
+	}
+	return id.UUID, nil
+}
@@ -0,0 +1,129 @@
+package introspect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// NewClient creates a client of the Sandesh introspect server of a contrail process.
+func NewClient(connector Connector) *Client {
+	return &Client{connector: connector}
+}
+
+// Connector specifies backend mechanism used to communicate with the introspect server.
+// Controllers should use a kubeproxy client, statusmonitor connects directly with NewConnector.
+type Connector interface {
+	NewRequest(method, path string, body io.Reader) (*http.Request, error)
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewConnector creates Connector which sends requests over https to the introspect
+// server listening on address (host:port).
+func NewConnector(address string, client *http.Client) Connector {
+	return &httpConnector{url: "https://" + address, client: client}
+}
+
+type httpConnector struct {
+	url    string
+	client *http.Client
+}
+
+func (c *httpConnector) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, c.url+path, body)
+}
+
+func (c *httpConnector) Do(req *http.Request) (*http.Response, error) {
+	return c.client.Do(req)
+}
+
+// TLSConfig returns TLS configuration which trusts the operator CA and presents the
+// certificate of the pod. Introspect servers require client certificates.
+func TLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	caCert, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{cert}}, nil
+}
+
+// Client is an interface to the Sandesh introspect server.
+type Client struct {
+	connector Connector
+}
+
+// NodeStatus returns status of processes running on the node.
+func (c *Client) NodeStatus() (*NodeStatus, error) {
+	body, err := c.uveCache("NodeStatus")
+	if err != nil {
+		return nil, err
+	}
+	return ParseNodeStatus(body)
+}
+
+// BgpRouterState returns state of the control node BGP router.
+func (c *Client) BgpRouterState() (*BgpRouterState, error) {
+	body, err := c.uveCache("BgpRouterState")
+	if err != nil {
+		return nil, err
+	}
+	return ParseBgpRouterState(body)
+}
+
+// XmppPeers returns XMPP peers of the control node.
+func (c *Client) XmppPeers() ([]XmppPeerInfo, error) {
+	body, err := c.uveCache("XmppPeerInfoData")
+	if err != nil {
+		return nil, err
+	}
+	return ParseXmppPeers(body)
+}
+
+// VrouterAgent returns state of the vRouter agent.
+func (c *Client) VrouterAgent() (*VrouterAgent, error) {
+	body, err := c.uveCache("VrouterAgent")
+	if err != nil {
+		return nil, err
+	}
+	return ParseVrouterAgent(body)
+}
+
+// VrouterStats returns statistics of the vRouter agent.
+func (c *Client) VrouterStats() (*VrouterStats, error) {
+	body, err := c.uveCache("VrouterStatsAgent")
+	if err != nil {
+		return nil, err
+	}
+	return ParseVrouterStats(body)
+}
+
+func (c *Client) uveCache(name string) ([]byte, error) {
+	request, err := c.connector.NewRequest(http.MethodGet, "/Snh_SandeshUVECacheReq?x="+name, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.connector.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: invalid status code returned: %d, response: %s", name, response.StatusCode, body)
+	}
+	return body, nil
+}
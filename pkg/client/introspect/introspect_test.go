@@ -0,0 +1,160 @@
+package introspect_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
+	"github.com/Juniper/contrail-operator/pkg/client/kubeproxy"
+)
+
+var _ introspect.Connector = &kubeproxy.Client{}
+
+func readGolden(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("test_files", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseNodeStatus(t *testing.T) {
+	t.Run("should parse control node status", func(t *testing.T) {
+		status, err := introspect.ParseNodeStatus(readGolden(t, "control_node_status.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, "cs1-bm-ctrl-1", status.Name)
+		require.Len(t, status.ProcessStatus, 1)
+		process := status.ProcessStatus[0]
+		assert.Equal(t, "contrail-control", process.ModuleID)
+		assert.Equal(t, "Functional", process.State)
+		require.Len(t, process.ConnectionInfos, 3)
+		assert.Equal(t, "Cassandra", process.ConnectionInfos[1].Name)
+		assert.Equal(t, []string{"10.87.141.162:9041", "10.87.141.163:9041", "10.87.141.164:9041"}, process.ConnectionInfos[1].ServerAddrs)
+		assert.Equal(t, "Up", process.ConnectionInfos[1].Status)
+	})
+
+	t.Run("should parse config node status", func(t *testing.T) {
+		status, err := introspect.ParseNodeStatus(readGolden(t, "config_node_status.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, "kind-control-plane", status.Name)
+		require.Len(t, status.ProcessStatus, 1)
+		assert.Equal(t, "contrail-api", status.ProcessStatus[0].ModuleID)
+		assert.Len(t, status.ProcessStatus[0].ConnectionInfos, 5)
+	})
+
+	t.Run("should return error when status is missing", func(t *testing.T) {
+		_, err := introspect.ParseNodeStatus([]byte("<__NodeStatusUVE_list></__NodeStatusUVE_list>"))
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error when response is not xml", func(t *testing.T) {
+		_, err := introspect.ParseNodeStatus([]byte("not xml"))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseBgpRouterState(t *testing.T) {
+	state, err := introspect.ParseBgpRouterState(readGolden(t, "bgp_router_state.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, introspect.BgpRouterState{
+		Name:                "cs1-bm-ctrl-1",
+		RouterID:            "13.1.0.22",
+		LocalASN:            64512,
+		NumBgpPeer:          3,
+		NumUpBgpPeer:        3,
+		NumXmppPeer:         1,
+		NumUpXmppPeer:       1,
+		NumRoutingInstance:  387,
+		NumStaticRoutes:     1,
+		NumDownStaticRoutes: 0,
+	}, *state)
+}
+
+func TestParseXmppPeers(t *testing.T) {
+	peers, err := introspect.ParseXmppPeers(readGolden(t, "xmpp_peer_info.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, []introspect.XmppPeerInfo{
+		{Name: "cs1-bm-ctrl-1:10.87.141.170", Identifier: "10.87.141.170", State: "Established", LastState: "OpenConfirm"},
+		{Name: "cs1-bm-ctrl-1:10.87.141.171", Identifier: "10.87.141.171", State: "Idle", LastState: "Established"},
+	}, peers)
+}
+
+func TestParseVrouterAgent(t *testing.T) {
+	agent, err := introspect.ParseVrouterAgent(readGolden(t, "vrouter_agent.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, introspect.VrouterAgent{
+		Name: "compute-1",
+		XmppPeers: []introspect.AgentXmppPeer{
+			{IP: "10.0.0.1", Status: true, Primary: true},
+			{IP: "10.0.0.2", Status: false, Primary: false},
+		},
+		TotalInterfaceCount: 12,
+		DownInterfaceCount:  1,
+	}, *agent)
+}
+
+func TestParseVrouterStats(t *testing.T) {
+	stats, err := introspect.ParseVrouterStats(readGolden(t, "vrouter_stats_agent.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, introspect.VrouterStats{Name: "compute-1", AddedFlows: 3, DeletedFlows: 1, ActiveFlows: 42}, *stats)
+}
+
+func TestClient(t *testing.T) {
+	responses := map[string]string{
+		"NodeStatus":        "control_node_status.xml",
+		"BgpRouterState":    "bgp_router_state.xml",
+		"XmppPeerInfoData":  "xmpp_peer_info.xml",
+		"VrouterAgent":      "vrouter_agent.xml",
+		"VrouterStatsAgent": "vrouter_stats_agent.xml",
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		golden, ok := responses[r.URL.Query().Get("x")]
+		if r.URL.Path != "/Snh_SandeshUVECacheReq" || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(readGolden(t, golden))
+	}))
+	defer server.Close()
+	client := introspect.NewClient(introspect.NewConnector(server.Listener.Addr().String(), server.Client()))
+
+	t.Run("should get node status", func(t *testing.T) {
+		status, err := client.NodeStatus()
+		require.NoError(t, err)
+		assert.Equal(t, "cs1-bm-ctrl-1", status.Name)
+	})
+
+	t.Run("should get bgp router state", func(t *testing.T) {
+		state, err := client.BgpRouterState()
+		require.NoError(t, err)
+		assert.Equal(t, 387, state.NumRoutingInstance)
+	})
+
+	t.Run("should get xmpp peers", func(t *testing.T) {
+		peers, err := client.XmppPeers()
+		require.NoError(t, err)
+		assert.Len(t, peers, 2)
+	})
+
+	t.Run("should get vrouter agent", func(t *testing.T) {
+		agent, err := client.VrouterAgent()
+		require.NoError(t, err)
+		assert.Equal(t, 12, agent.TotalInterfaceCount)
+	})
+
+	t.Run("should get vrouter stats", func(t *testing.T) {
+		stats, err := client.VrouterStats()
+		require.NoError(t, err)
+		assert.Equal(t, 42, stats.ActiveFlows)
+	})
+
+	t.Run("should return error on unexpected status code", func(t *testing.T) {
+		delete(responses, "NodeStatus")
+		_, err := client.NodeStatus()
+		assert.Error(t, err)
+	})
+}
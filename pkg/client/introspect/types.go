@@ -0,0 +1,158 @@
+package introspect
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// NodeStatus is the NodeStatus UVE of a contrail node.
+type NodeStatus struct {
+	Name          string          `xml:"name"`
+	ProcessStatus []ProcessStatus `xml:"process_status>list>ProcessStatus"`
+}
+
+// ProcessStatus is the state of a single contrail process.
+type ProcessStatus struct {
+	ModuleID        string           `xml:"module_id"`
+	InstanceID      string           `xml:"instance_id"`
+	State           string           `xml:"state"`
+	Description     string           `xml:"description"`
+	ConnectionInfos []ConnectionInfo `xml:"connection_infos>list>ConnectionInfo"`
+}
+
+// ConnectionInfo is the state of a connection of the process to other services.
+type ConnectionInfo struct {
+	Type        string   `xml:"type"`
+	Name        string   `xml:"name"`
+	ServerAddrs []string `xml:"server_addrs>list>element"`
+	Status      string   `xml:"status"`
+	Description string   `xml:"description"`
+}
+
+// BgpRouterState is the BgpRouterState UVE of a control node.
+type BgpRouterState struct {
+	Name                string `xml:"name"`
+	RouterID            string `xml:"router_id"`
+	LocalASN            int    `xml:"local_asn"`
+	NumBgpPeer          int    `xml:"num_bgp_peer"`
+	NumUpBgpPeer        int    `xml:"num_up_bgp_peer"`
+	NumXmppPeer         int    `xml:"num_xmpp_peer"`
+	NumUpXmppPeer       int    `xml:"num_up_xmpp_peer"`
+	NumRoutingInstance  int    `xml:"num_routing_instance"`
+	NumStaticRoutes     int    `xml:"num_static_routes"`
+	NumDownStaticRoutes int    `xml:"num_down_static_routes"`
+}
+
+// XmppPeerInfo is the XmppPeerInfoData UVE of a control node XMPP peer.
+type XmppPeerInfo struct {
+	Name       string `xml:"name"`
+	Identifier string `xml:"identifier"`
+	State      string `xml:"state_info>PeerStateInfo>state"`
+	LastState  string `xml:"state_info>PeerStateInfo>last_state"`
+}
+
+// VrouterAgent is the VrouterAgent UVE of a vRouter.
+type VrouterAgent struct {
+	Name                string          `xml:"name"`
+	XmppPeers           []AgentXmppPeer `xml:"xmpp_peer_list>list>AgentXmppPeer"`
+	TotalInterfaceCount int             `xml:"total_interface_count"`
+	DownInterfaceCount  int             `xml:"down_interface_count"`
+}
+
+// AgentXmppPeer is the XMPP session of the vRouter agent with a control node.
+type AgentXmppPeer struct {
+	IP      string `xml:"ip"`
+	Status  bool   `xml:"status"`
+	Primary bool   `xml:"primary"`
+}
+
+// VrouterStats is the VrouterStatsAgent UVE of a vRouter.
+type VrouterStats struct {
+	Name         string `xml:"name"`
+	AddedFlows   int    `xml:"flow_rate>FlowRateInfo>added_flows"`
+	DeletedFlows int    `xml:"flow_rate>FlowRateInfo>deleted_flows"`
+	ActiveFlows  int    `xml:"flow_rate>FlowRateInfo>active_flows"`
+}
+
+// ParseNodeStatus parses response of the NodeStatus UVE cache request.
+func ParseNodeStatus(data []byte) (*NodeStatus, error) {
+	list := struct {
+		XMLName xml.Name     `xml:"__NodeStatusUVE_list"`
+		Items   []NodeStatus `xml:"NodeStatusUVE>data>NodeStatus"`
+	}{}
+	if err := parse(data, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, notFound("NodeStatus")
+	}
+	return &list.Items[0], nil
+}
+
+// ParseBgpRouterState parses response of the BgpRouterState UVE cache request.
+func ParseBgpRouterState(data []byte) (*BgpRouterState, error) {
+	list := struct {
+		XMLName xml.Name         `xml:"__BGPRouterInfo_list"`
+		Items   []BgpRouterState `xml:"BGPRouterInfo>data>BgpRouterState"`
+	}{}
+	if err := parse(data, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, notFound("BgpRouterState")
+	}
+	return &list.Items[0], nil
+}
+
+// ParseXmppPeers parses response of the XmppPeerInfoData UVE cache request.
+func ParseXmppPeers(data []byte) ([]XmppPeerInfo, error) {
+	list := struct {
+		XMLName xml.Name       `xml:"__XMPPPeerInfo_list"`
+		Items   []XmppPeerInfo `xml:"XMPPPeerInfo>data>XmppPeerInfoData"`
+	}{}
+	if err := parse(data, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ParseVrouterAgent parses response of the VrouterAgent UVE cache request.
+func ParseVrouterAgent(data []byte) (*VrouterAgent, error) {
+	list := struct {
+		XMLName xml.Name       `xml:"__UveVrouterAgent_list"`
+		Items   []VrouterAgent `xml:"UveVrouterAgent>data>VrouterAgent"`
+	}{}
+	if err := parse(data, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, notFound("VrouterAgent")
+	}
+	return &list.Items[0], nil
+}
+
+// ParseVrouterStats parses response of the VrouterStatsAgent UVE cache request.
+func ParseVrouterStats(data []byte) (*VrouterStats, error) {
+	list := struct {
+		XMLName xml.Name       `xml:"__UveVrouterStatsAgent_list"`
+		Items   []VrouterStats `xml:"UveVrouterStatsAgent>data>VrouterStatsAgent"`
+	}{}
+	if err := parse(data, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, notFound("VrouterStatsAgent")
+	}
+	return &list.Items[0], nil
+}
+
+func parse(data []byte, v interface{}) error {
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unmarshalling introspect response failed: %v", err)
+	}
+	return nil
+}
+
+func notFound(uve string) error {
+	return fmt.Errorf("%s not found in introspect response", uve)
+}
@@ -0,0 +1,173 @@
+package keystone
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// Domain is a keystone domain.
+type Domain struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// IdentityProvider is a keystone OS-FEDERATION identity provider.
+type IdentityProvider struct {
+	DomainID  string   `json:"domain_id,omitempty"`
+	RemoteIDs []string `json:"remote_ids"`
+	Enabled   bool     `json:"enabled"`
+}
+
+// Mapping is a set of rules translating federated user attributes into
+// keystone users, groups and projects.
+type Mapping struct {
+	Rules []MappingRule `json:"rules"`
+}
+
+// MappingRule is a single rule of the Mapping.
+type MappingRule struct {
+	Local  []MappingLocal  `json:"local"`
+	Remote []MappingRemote `json:"remote"`
+}
+
+// MappingLocal defines keystone objects federated user is mapped to.
+type MappingLocal struct {
+	User     *MappingObject   `json:"user,omitempty"`
+	Group    *MappingObject   `json:"group,omitempty"`
+	Projects []MappingProject `json:"projects,omitempty"`
+}
+
+// MappingObject identifies keystone user or group.
+type MappingObject struct {
+	Name   string         `json:"name"`
+	Domain *MappingDomain `json:"domain,omitempty"`
+}
+
+// MappingDomain identifies keystone domain by name.
+type MappingDomain struct {
+	Name string `json:"name"`
+}
+
+// MappingProject defines project with roles granted to federated user.
+type MappingProject struct {
+	Name  string        `json:"name"`
+	Roles []MappingRole `json:"roles"`
+}
+
+// MappingRole identifies keystone role by name.
+type MappingRole struct {
+	Name string `json:"name"`
+}
+
+// MappingRemote is a condition on the federated user attribute.
+type MappingRemote struct {
+	Type     string   `json:"type"`
+	AnyOneOf []string `json:"any_one_of,omitempty"`
+	NotAnyOf []string `json:"not_any_of,omitempty"`
+	Regex    bool     `json:"regex,omitempty"`
+}
+
+// EnsureDomain returns ID of the domain with given name. The domain is created when it does not exist.
+func (c *Client) EnsureDomain(token, name, description string) (string, error) {
+	response, err := c.doWithToken(token, http.MethodGet, "/v3/domains?name="+url.QueryEscape(name), nil)
+	if err != nil {
+		return "", err
+	}
+	domains := struct {
+		Domains []Domain `json:"domains"`
+	}{}
+	if err = decodeResponse(response, &domains, http.StatusOK); err != nil {
+		return "", err
+	}
+	if len(domains.Domains) > 0 {
+		return domains.Domains[0].ID, nil
+	}
+	body := map[string]Domain{"domain": {Name: name, Description: description, Enabled: true}}
+	response, err = c.doWithToken(token, http.MethodPost, "/v3/domains", body)
+	if err != nil {
+		return "", err
+	}
+	created := struct {
+		Domain Domain `json:"domain"`
+	}{}
+	if err = decodeResponse(response, &created, http.StatusCreated); err != nil {
+		return "", err
+	}
+	return created.Domain.ID, nil
+}
+
+// PutIdentityProvider creates or updates the identity provider with given ID.
+func (c *Client) PutIdentityProvider(token, id string, idp IdentityProvider) error {
+	return c.putOrPatch(token, "/v3/OS-FEDERATION/identity_providers/"+id, "identity_provider", idp)
+}
+
+// PutMapping creates or updates the mapping with given ID.
+func (c *Client) PutMapping(token, id string, mapping Mapping) error {
+	return c.putOrPatch(token, "/v3/OS-FEDERATION/mappings/"+id, "mapping", mapping)
+}
+
+// PutProtocol creates or updates the protocol of the identity provider and
+// associates it with the mapping.
+func (c *Client) PutProtocol(token, idpID, protocolID, mappingID string) error {
+	body := struct {
+		MappingID string `json:"mapping_id"`
+	}{MappingID: mappingID}
+	return c.putOrPatch(token, "/v3/OS-FEDERATION/identity_providers/"+idpID+"/protocols/"+protocolID, "protocol", body)
+}
+
+func (c *Client) putOrPatch(token, path, key string, object interface{}) error {
+	body := map[string]interface{}{key: object}
+	response, err := c.doWithToken(token, http.MethodPut, path, body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode == http.StatusConflict {
+		response.Body.Close()
+		if response, err = c.doWithToken(token, http.MethodPatch, path, body); err != nil {
+			return err
+		}
+		return decodeResponse(response, nil, http.StatusOK)
+	}
+	return decodeResponse(response, nil, http.StatusCreated)
+}
+
+func (c *Client) doWithToken(token, method, path string, body interface{}) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	request, err := c.Connector.NewRequest(method, path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Auth-Token", token)
+	return c.Connector.Do(request)
+}
+
+func decodeResponse(response *http.Response, into interface{}, expectedStatus int) error {
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusUnauthorized {
+		return newUnauthorized()
+	}
+	if response.StatusCode != expectedStatus {
+		return fmt.Errorf("invalid status code returned: %d", response.StatusCode)
+	}
+	if into == nil {
+		return nil
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
@@ -0,0 +1,116 @@
+package keystone_test
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Juniper/contrail-operator/pkg/client/keystone"
+)
+
+type httpConnector struct {
+	url string
+}
+
+func (c httpConnector) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, c.url+path, body)
+}
+
+func (c httpConnector) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+func TestEnsureDomain(t *testing.T) {
+	t.Run("should return ID of existing domain", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "token", r.Header.Get("X-Auth-Token"))
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "corporate", r.URL.Query().Get("name"))
+			_, _ = w.Write([]byte(`{"domains": [{"id": "d1", "name": "corporate", "enabled": true}]}`))
+		}))
+		defer server.Close()
+		client := &keystone.Client{Connector: httpConnector{url: server.URL}}
+
+		id, err := client.EnsureDomain("token", "corporate", "")
+		require.NoError(t, err)
+		assert.Equal(t, "d1", id)
+	})
+
+	t.Run("should create missing domain", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"domains": []}`))
+				return
+			}
+			assert.Equal(t, http.MethodPost, r.Method)
+			body := map[string]keystone.Domain{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, keystone.Domain{Name: "corporate", Description: "LDAP users", Enabled: true}, body["domain"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"domain": {"id": "d2", "name": "corporate", "enabled": true}}`))
+		}))
+		defer server.Close()
+		client := &keystone.Client{Connector: httpConnector{url: server.URL}}
+
+		id, err := client.EnsureDomain("token", "corporate", "LDAP users")
+		require.NoError(t, err)
+		assert.Equal(t, "d2", id)
+	})
+}
+
+func TestPutMapping(t *testing.T) {
+	mapping := keystone.Mapping{Rules: []keystone.MappingRule{{
+		Local:  []keystone.MappingLocal{{User: &keystone.MappingObject{Name: "{0}"}}},
+		Remote: []keystone.MappingRemote{{Type: "OIDC-email"}},
+	}}}
+	expectedBody := `{"mapping":{"rules":[{"local":[{"user":{"name":"{0}"}}],"remote":[{"type":"OIDC-email"}]}]}}`
+
+	t.Run("should create mapping", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/v3/OS-FEDERATION/mappings/okta_mapping", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, expectedBody, string(body))
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+		client := &keystone.Client{Connector: httpConnector{url: server.URL}}
+
+		assert.NoError(t, client.PutMapping("token", "okta_mapping", mapping))
+	})
+
+	t.Run("should update existing mapping", func(t *testing.T) {
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			if r.Method == http.MethodPut {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, expectedBody, string(body))
+		}))
+		defer server.Close()
+		client := &keystone.Client{Connector: httpConnector{url: server.URL}}
+
+		assert.NoError(t, client.PutMapping("token", "okta_mapping", mapping))
+		assert.Equal(t, []string{http.MethodPut, http.MethodPatch}, methods)
+	})
+
+	t.Run("should fail when not authorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		client := &keystone.Client{Connector: httpConnector{url: server.URL}}
+
+		err := client.PutMapping("token", "okta_mapping", mapping)
+		assert.True(t, keystone.IsUnauthorized(err))
+	})
+}
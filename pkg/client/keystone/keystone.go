@@ -2,6 +2,7 @@ package keystone
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -10,7 +11,9 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -31,11 +34,29 @@ func NewClient(kubClient client.Client, scheme *runtime.Scheme, config *rest.Con
 	}, nil
 }
 
+// NewExternalClient prepares client of the external keystone instance available at the address.
+func NewExternalClient(kubClient client.Client, scheme *runtime.Scheme, k *contrail.Keystone, address string) (*Client, error) {
+	tlsConfig, err := externalTLSConfig(kubClient, scheme, k)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		Connector:    newExtKeystoneClient(k.Spec.ServiceConfiguration.AuthProtocol, address, k.Spec.ServiceConfiguration.ListenPort, tlsConfig),
+		KeystoneConf: &k.Spec.ServiceConfiguration,
+	}, nil
+}
+
 func newConnector(kubClient client.Client, scheme *runtime.Scheme, config *rest.Config, k *contrail.Keystone) (keystoneClient, error) {
 	if k.Spec.ServiceConfiguration.ExternalAddress != "" {
-		caCertificate := certificates.NewCACertificate(kubClient, scheme, k, k.GetName())
-		caBundle, _ := caCertificate.GetCaCert()
-		return newExtKeystoneClient(k.Spec.ServiceConfiguration.AuthProtocol, k.Spec.ServiceConfiguration.ExternalAddress, k.Spec.ServiceConfiguration.ListenPort, caBundle), nil
+		address := k.Spec.ServiceConfiguration.ExternalAddress
+		if k.Status.External && k.Status.Endpoint != "" {
+			address = k.Status.Endpoint
+		}
+		tlsConfig, err := externalTLSConfig(kubClient, scheme, k)
+		if err != nil {
+			return nil, err
+		}
+		return newExtKeystoneClient(k.Spec.ServiceConfiguration.AuthProtocol, address, k.Spec.ServiceConfiguration.ListenPort, tlsConfig), nil
 	}
 	proxy, err := kubeproxy.New(config)
 	if err != nil {
@@ -69,19 +90,55 @@ type extKeystoneClient struct {
 	client http.Client
 }
 
-func newExtKeystoneClient(protocol string, address string, port int, ca []byte) *extKeystoneClient {
-	var httpClient http.Client
-
-	if protocol == "https" && ca != nil {
-		rootCAs, _ := x509.SystemCertPool()
-		if rootCAs == nil {
-			rootCAs = x509.NewCertPool()
+func externalTLSConfig(kubClient client.Client, scheme *runtime.Scheme, k *contrail.Keystone) (*tls.Config, error) {
+	if k.Spec.ServiceConfiguration.AuthProtocol != "https" {
+		return nil, nil
+	}
+	rootCAs, _ := x509.SystemCertPool()
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	caCertificate := certificates.NewCACertificate(kubClient, scheme, k, k.GetName())
+	if caBundle, err := caCertificate.GetCaCert(); err == nil {
+		rootCAs.AppendCertsFromPEM(caBundle)
+	}
+	config := &tls.Config{
+		RootCAs: rootCAs,
+	}
+	if secretName := k.Spec.ServiceConfiguration.ExternalCABundleSecretName; secretName != "" {
+		secret, err := getSecret(kubClient, secretName, k.Namespace)
+		if err != nil {
+			return nil, err
 		}
-		rootCAs.AppendCertsFromPEM(ca)
-		config := &tls.Config{
-			RootCAs: rootCAs,
+		if !rootCAs.AppendCertsFromPEM(secret.Data["ca.crt"]) {
+			return nil, fmt.Errorf("secret %s does not contain valid CA bundle under ca.crt key", secretName)
 		}
-		tr := &http.Transport{TLSClientConfig: config}
+	}
+	if secretName := k.Spec.ServiceConfiguration.ExternalClientCertificateSecretName; secretName != "" {
+		secret, err := getSecret(kubClient, secretName, k.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		clientCertificate, err := tls.X509KeyPair(secret.Data[core.TLSCertKey], secret.Data[core.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("secret %s does not contain valid client certificate: %v", secretName, err)
+		}
+		config.Certificates = []tls.Certificate{clientCertificate}
+	}
+	return config, nil
+}
+
+func getSecret(kubClient client.Client, name, namespace string) (*core.Secret, error) {
+	secret := &core.Secret{}
+	err := kubClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	return secret, err
+}
+
+func newExtKeystoneClient(protocol string, address string, port int, tlsConfig *tls.Config) *extKeystoneClient {
+	var httpClient http.Client
+
+	if protocol == "https" && tlsConfig != nil {
+		tr := &http.Transport{TLSClientConfig: tlsConfig}
 		httpClient = http.Client{Transport: tr}
 	} else {
 		httpClient = http.Client{}
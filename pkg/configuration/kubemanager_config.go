@@ -17,16 +17,20 @@ kubernetes_api_server={{ .KubernetesAPIServer }}
 kubernetes_api_port={{ .KubernetesAPIPort }}
 kubernetes_api_secure_port={{ .KubernetesAPISSLPort }}
 cluster_name={{ .KubernetesClusterName }}
-cluster_project={}
+cluster_project={{ .ClusterProject }}
 cluster_network={}
+cluster_pod_network={{ .PodNetwork }}
+cluster_service_network={{ .ServiceNetwork }}
+cluster_ip_fabric_network={{ .IPFabricNetwork }}
 pod_subnets={{ .PodSubnet }}
 ip_fabric_subnets={{ .IPFabricSubnet }}
 service_subnets={{ .ServiceSubnet }}
 ip_fabric_forwarding={{ .IPFabricForwarding }}
 ip_fabric_snat={{ .IPFabricSnat }}
 host_network_service={{ .HostNetworkService }}
+isolated_namespaces={{ .IsolatedNamespaces }}
 [VNC]
-public_fip_pool={}
+public_fip_pool={{ .PublicFIPPool }}
 vnc_endpoint_ip={{ .APIServerList }}
 vnc_endpoint_port={{ .APIServerPort }}
 rabbit_server={{ .RabbitmqServerList }}
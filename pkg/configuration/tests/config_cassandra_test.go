@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/kylelemons/godebug/diff"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -36,6 +37,10 @@ func TestCassandraConfig(t *testing.T) {
 		configDiff := diff.Diff(environment.cassandraConfigMap.Data["1.1.2.1.yaml"], cassandraConfig)
 		t.Fatalf("get cassandra config: \n%v\n", configDiff)
 	}
+
+	monitorConfig := environment.cassandraConfigMap.Data["monitorconfig.1.1.2.1.yaml"]
+	assert.Contains(t, monitorConfig, "nodeType: database")
+	assert.Contains(t, monitorConfig, "- 1.1.2.1:9042::cassandra")
 }
 
 var cassandraConfig = `cluster_name: ContrailConfigDB
@@ -78,12 +78,16 @@ kubernetes_api_secure_port=6443
 cluster_name=kubernetes
 cluster_project={}
 cluster_network={}
+cluster_pod_network={}
+cluster_service_network={}
+cluster_ip_fabric_network={}
 pod_subnets=10.32.0.0/12
 ip_fabric_subnets=10.64.0.0/12
 service_subnets=10.96.0.0/12
 ip_fabric_forwarding=false
 ip_fabric_snat=true
 host_network_service=false
+isolated_namespaces=false
 [VNC]
 public_fip_pool={}
 vnc_endpoint_ip=1.1.1.1,1.1.1.2,1.1.1.3
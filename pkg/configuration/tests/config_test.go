@@ -1,6 +1,8 @@
 package contrailtest
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -211,6 +213,7 @@ var controlList = &v1alpha1.ControlList{}
 var kubemanagerList = &v1alpha1.KubemanagerList{}
 var webuiList = &v1alpha1.WebuiList{}
 var vrouterList = &v1alpha1.VrouterList{}
+var vrouterNodeProfileList = &v1alpha1.VrouterNodeProfileList{}
 
 var configMap = &corev1.ConfigMap{}
 var secret = &corev1.Secret{}
@@ -348,7 +351,9 @@ func SetupEnv() Environment {
 		controlList,
 		kubemanagerList,
 		webuiList,
-		vrouterList)
+		vrouterList,
+		&v1alpha1.VrouterNodeProfile{},
+		vrouterNodeProfileList)
 
 	objs := []runtime.Object{config,
 		cassandra,
@@ -510,6 +515,12 @@ func SetupEnv() Environment {
 		podTemplate.SetAnnotations(map[string]string{"hostname": "host1", "physicalInterface": "eth0", "physicalInterfaceMac": "de:ad:be:ef:ba:be", "prefixLength": "24"})
 		vrouterPodItems = append(vrouterPodItems, podTemplate)
 	}
+	// vRouter pods are annotated with the rendered configuration
+	for idx := range vrouterPodItems {
+		if err := cl.Create(context.TODO(), &vrouterPodItems[idx]); err != nil {
+			panic(err)
+		}
+	}
 	vrouterPodList := corev1.PodList{
 		Items: vrouterPodItems,
 	}
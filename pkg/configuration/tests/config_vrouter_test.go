@@ -6,7 +6,11 @@ import (
 
 	"github.com/kylelemons/godebug/diff"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/ini.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -19,6 +23,7 @@ type vrouterClusterInfoFake struct {
 	clusterName          string
 	cniBinariesDirectory string
 	deploymentType       string
+	networkMTU           int
 }
 
 func (c vrouterClusterInfoFake) KubernetesClusterName() (string, error) {
@@ -32,6 +37,10 @@ func (c vrouterClusterInfoFake) DeploymentType() string {
 	return c.deploymentType
 }
 
+func (c vrouterClusterInfoFake) NetworkMTU() (int, error) {
+	return c.networkMTU, nil
+}
+
 func TestVrouterConfig(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	request := reconcile.Request{
@@ -234,6 +243,322 @@ func TestVrouterCustomEnvVariablesConfigMap(t *testing.T) {
 	})
 }
 
+func TestVrouterDatapathConfiguration(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "vrouter1",
+			Namespace: "default",
+		},
+	}
+
+	t.Run("DPDK datapath", func(t *testing.T) {
+		environment := SetupEnv()
+		cl := *environment.client
+		environment.vrouterResource.Spec.ServiceConfiguration.Datapath = v1alpha1.DpdkDatapath
+		environment.vrouterResource.Spec.ServiceConfiguration.Dpdk = &v1alpha1.VrouterDpdkConfiguration{
+			CPUCoreMask:              "2,3",
+			ServiceCoreMask:          "0x1",
+			Driver:                   "vfio-pci",
+			PhysicalInterfaceAddress: "0000:00:05.0",
+		}
+
+		if err := environment.vrouterResource.InstanceConfiguration(request,
+			&environment.vrouterPodList, cl); err != nil {
+			t.Fatalf("get configmap: (%v)", err)
+		}
+		if err := cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap", Namespace: "default"}, &environment.vrouterConfigMap); err != nil {
+			t.Fatalf("get configmap: (%v)", err)
+		}
+		if err := cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap-1", Namespace: "default"}, &environment.vrouterConfigMap2); err != nil {
+			t.Fatalf("get configmap: (%v)", err)
+		}
+		vrouterConfig, err := ini.Load([]byte(environment.vrouterConfigMap.Data["vrouter.1.1.8.1"]))
+		if err != nil {
+			t.Fatalf("failed to read vrouter config as ini file: (%v)", err)
+		}
+		assert.Equal(t, "dpdk", vrouterConfig.Section("DEFAULT").Key("platform").String())
+		assert.Equal(t, "vfio-pci", vrouterConfig.Section("DEFAULT").Key("physical_uio_driver").String())
+		assert.Equal(t, "0000:00:05.0", vrouterConfig.Section("DEFAULT").Key("physical_interface_address").String())
+		assert.Equal(t, "0x1", vrouterConfig.Section("SERVICE-CORE").Key("cpu_core_mask").String())
+		_, err = vrouterConfig.GetSection("DPDK")
+		assert.Error(t, err, "DPDK section should be rendered only with the control thread mask")
+
+		expectedVrouterEnvVariables := map[string]string{
+			"CLOUD_ORCHESTRATOR": "kubernetes",
+			"VROUTER_ENCRYPTION": "false",
+			"AGENT_MODE":         "dpdk",
+			"DPDK_UIO_DRIVER":    "vfio-pci",
+			"CPU_CORE_MASK":      "2,3",
+			"SERVICE_CORE_MASK":  "0x1",
+		}
+		assert.Equal(t, expectedVrouterEnvVariables, environment.vrouterConfigMap2.Data)
+	})
+
+	t.Run("SR-IOV datapath", func(t *testing.T) {
+		environment := SetupEnv()
+		cl := *environment.client
+		environment.vrouterResource.Spec.ServiceConfiguration.Datapath = v1alpha1.SriovDatapath
+		environment.vrouterResource.Spec.ServiceConfiguration.Sriov = &v1alpha1.VrouterSriovConfiguration{
+			PhysicalFunction: "ens1f0",
+			NumVFs:           8,
+			PhysicalNetwork:  "physnet1",
+		}
+
+		if err := environment.vrouterResource.InstanceConfiguration(request,
+			&environment.vrouterPodList, cl); err != nil {
+			t.Fatalf("get configmap: (%v)", err)
+		}
+		if err := cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap", Namespace: "default"}, &environment.vrouterConfigMap); err != nil {
+			t.Fatalf("get configmap: (%v)", err)
+		}
+		if err := cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap-1", Namespace: "default"}, &environment.vrouterConfigMap2); err != nil {
+			t.Fatalf("get configmap: (%v)", err)
+		}
+		assert.Equal(t, vrouterConfig, environment.vrouterConfigMap.Data["vrouter.1.1.8.1"], "SR-IOV should use the kernel agent configuration")
+		expectedVrouterEnvVariables := map[string]string{
+			"CLOUD_ORCHESTRATOR":       "kubernetes",
+			"VROUTER_ENCRYPTION":       "false",
+			"SRIOV_PHYSICAL_INTERFACE": "ens1f0",
+			"SRIOV_VF":                 "8",
+			"SRIOV_PHYSICAL_NETWORK":   "physnet1",
+		}
+		assert.Equal(t, expectedVrouterEnvVariables, environment.vrouterConfigMap2.Data)
+	})
+}
+
+func TestVrouterAgentConfiguration(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "vrouter1",
+			Namespace: "default",
+		},
+	}
+	flowTableSize := 1048576
+	labelTableSize := 15000
+	flowThreadCount := 4
+	introspectSSLInsecure := false
+	agent := &v1alpha1.VrouterAgentConfiguration{
+		LogLevel:              "SYS_DEBUG",
+		FlowTableSize:         &flowTableSize,
+		LabelTableSize:        &labelTableSize,
+		FlowThreadCount:       &flowThreadCount,
+		SloDestination:        []v1alpha1.SessionDestination{"collector", "syslog"},
+		SampleDestination:     []v1alpha1.SessionDestination{"file"},
+		TSNServers:            []string{"10.0.0.1", "10.0.0.2"},
+		IntrospectSSLInsecure: &introspectSSLInsecure,
+	}
+
+	t.Run("agent tunables are rendered in the agent configuration", func(t *testing.T) {
+		environment := SetupEnv()
+		cl := *environment.client
+		environment.vrouterResource.Spec.ServiceConfiguration.Agent = agent
+
+		require.NoError(t, environment.vrouterResource.ValidateAgent())
+		require.NoError(t, environment.vrouterResource.InstanceConfiguration(request, &environment.vrouterPodList, cl))
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap", Namespace: "default"}, &environment.vrouterConfigMap))
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap-1", Namespace: "default"}, &environment.vrouterConfigMap2))
+		vrouterConfig, err := ini.Load([]byte(environment.vrouterConfigMap.Data["vrouter.1.1.8.1"]))
+		require.NoError(t, err)
+		assert.Equal(t, "SYS_DEBUG", vrouterConfig.Section("DEFAULT").Key("log_level").String())
+		assert.Equal(t, "[10.0.0.1 10.0.0.2]", vrouterConfig.Section("DEFAULT").Key("tsn_servers").String())
+		assert.Equal(t, "False", vrouterConfig.Section("SANDESH").Key("introspect_ssl_insecure").String())
+		assert.Equal(t, "4", vrouterConfig.Section("FLOWS").Key("thread_count").String())
+		assert.Equal(t, "4096", vrouterConfig.Section("FLOWS").Key("fabric_snat_hash_table_size").String())
+		assert.Equal(t, "collector syslog", vrouterConfig.Section("SESSION").Key("slo_destination").String())
+		assert.Equal(t, "file", vrouterConfig.Section("SESSION").Key("sample_destination").String())
+		assert.Equal(t, "vr_flow_entries=1048576 vr_mpls_labels=15000", environment.vrouterConfigMap2.Data["VROUTER_MODULE_OPTIONS"])
+	})
+
+	t.Run("table sizes are passed to the DPDK process", func(t *testing.T) {
+		environment := SetupEnv()
+		cl := *environment.client
+		environment.vrouterResource.Spec.ServiceConfiguration.Agent = agent
+		environment.vrouterResource.Spec.ServiceConfiguration.Datapath = v1alpha1.DpdkDatapath
+
+		require.NoError(t, environment.vrouterResource.InstanceConfiguration(request, &environment.vrouterPodList, cl))
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap-1", Namespace: "default"}, &environment.vrouterConfigMap2))
+		assert.Equal(t, "--vr_flow_entries=1048576 --vr_mpls_labels=15000", environment.vrouterConfigMap2.Data["DPDK_COMMAND_ADDITIONAL_ARGS"])
+		assert.NotContains(t, environment.vrouterConfigMap2.Data, "VROUTER_MODULE_OPTIONS")
+	})
+
+	t.Run("invalid tunables are rejected", func(t *testing.T) {
+		zero := 0
+		tests := map[string]*v1alpha1.VrouterAgentConfiguration{
+			"log level":           {LogLevel: "DEBUG"},
+			"flow table size":     {FlowTableSize: &zero},
+			"session destination": {SloDestination: []v1alpha1.SessionDestination{"kafka"}},
+			"TSN server":          {TSNServers: []string{"tsn1"}},
+		}
+		for name, agent := range tests {
+			vrouter := v1alpha1.Vrouter{}
+			vrouter.Spec.ServiceConfiguration.Agent = agent
+			assert.Error(t, vrouter.ValidateAgent(), name)
+		}
+	})
+
+	t.Run("agent configuration hash changes with tunables", func(t *testing.T) {
+		environment := SetupEnv()
+		cl := *environment.client
+		vrouter := v1alpha1.Vrouter{}
+		defaultHash, err := vrouter.AgentConfigHash(cl)
+		require.NoError(t, err)
+		vrouter.Spec.ServiceConfiguration.Agent = &v1alpha1.VrouterAgentConfiguration{LogLevel: v1alpha1.DefaultVrouterAgentLogLevel}
+		sameHash, err := vrouter.AgentConfigHash(cl)
+		require.NoError(t, err)
+		assert.Equal(t, defaultHash, sameHash, "explicit defaults should not roll the agents")
+		vrouter.Spec.ServiceConfiguration.Agent = agent
+		changedHash, err := vrouter.AgentConfigHash(cl)
+		require.NoError(t, err)
+		assert.NotEqual(t, defaultHash, changedHash)
+	})
+}
+
+func TestVrouterNodeProfiles(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	environment := SetupEnv()
+	cl := *environment.client
+	for idx, pod := range environment.vrouterPodList.Items {
+		nodeName := "rack2-node"
+		if pod.Status.PodIP == "1.1.8.1" {
+			nodeName = "rack1-node"
+		}
+		environment.vrouterPodList.Items[idx].Spec.NodeName = nodeName
+	}
+	maxVMFlows := 50
+	fabricSNATHashTableSize := 8192
+	for _, obj := range []runtime.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rack1-node", Labels: map[string]string{"rack": "r1"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rack2-node", Labels: map[string]string{"rack": "r2"}}},
+		&v1alpha1.VrouterNodeProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "all", Namespace: "default"},
+			Spec: v1alpha1.VrouterNodeProfileSpec{
+				VrouterNodeConfiguration: v1alpha1.VrouterNodeConfiguration{FabricSNATHashTableSize: &fabricSNATHashTableSize},
+			},
+		},
+		&v1alpha1.VrouterNodeProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "rack1", Namespace: "default"},
+			Spec: v1alpha1.VrouterNodeProfileSpec{
+				NodeSelector: map[string]string{"rack": "r1"},
+				VrouterNodeConfiguration: v1alpha1.VrouterNodeConfiguration{
+					PhysicalInterface: "ens3f0",
+					Gateway:           "10.1.0.1",
+					LogLevel:          "SYS_DEBUG",
+					MaxVMFlows:        &maxVMFlows,
+				},
+			},
+		},
+		&v1alpha1.VrouterNodeProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "rack1-debug", Namespace: "default"},
+			Spec: v1alpha1.VrouterNodeProfileSpec{
+				NodeSelector:             map[string]string{"rack": "r1"},
+				VrouterNodeConfiguration: v1alpha1.VrouterNodeConfiguration{LogLevel: "SYS_INFO"},
+			},
+		},
+	} {
+		if err := cl.Create(context.TODO(), obj); err != nil {
+			t.Fatalf("create object: (%v)", err)
+		}
+	}
+
+	if err := environment.vrouterResource.InstanceConfiguration(reconcile.Request{NamespacedName: types.NamespacedName{Name: "vrouter1", Namespace: "default"}},
+		&environment.vrouterPodList, cl); err != nil {
+		t.Fatalf("get configmap: (%v)", err)
+	}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap", Namespace: "default"}, &environment.vrouterConfigMap); err != nil {
+		t.Fatalf("get configmap: (%v)", err)
+	}
+
+	t.Run("profiles selecting the node override vRouter configuration", func(t *testing.T) {
+		vrouterConfig, err := ini.Load([]byte(environment.vrouterConfigMap.Data["vrouter.1.1.8.1"]))
+		if err != nil {
+			t.Fatalf("failed to read vrouter config as ini file: (%v)", err)
+		}
+		assert.Equal(t, "ens3f0", vrouterConfig.Section("VIRTUAL-HOST-INTERFACE").Key("physical_interface").String())
+		assert.Equal(t, "10.1.0.1", vrouterConfig.Section("VIRTUAL-HOST-INTERFACE").Key("gateway").String())
+		assert.Equal(t, "SYS_INFO", vrouterConfig.Section("DEFAULT").Key("log_level").String(), "rack1-debug profile should be applied after rack1")
+		assert.Equal(t, "50", vrouterConfig.Section("FLOWS").Key("max_vm_flows").String())
+		assert.Equal(t, "8192", vrouterConfig.Section("FLOWS").Key("fabric_snat_hash_table_size").String())
+	})
+
+	t.Run("nodes not selected by profiles use the Vrouter configuration", func(t *testing.T) {
+		vrouterConfig, err := ini.Load([]byte(environment.vrouterConfigMap.Data["vrouter.1.1.8.2"]))
+		if err != nil {
+			t.Fatalf("failed to read vrouter config as ini file: (%v)", err)
+		}
+		assert.Equal(t, "eth0", vrouterConfig.Section("VIRTUAL-HOST-INTERFACE").Key("physical_interface").String())
+		assert.Equal(t, "1.1.8.254", vrouterConfig.Section("VIRTUAL-HOST-INTERFACE").Key("gateway").String())
+		assert.Equal(t, "SYS_NOTICE", vrouterConfig.Section("DEFAULT").Key("log_level").String())
+		assert.False(t, vrouterConfig.Section("FLOWS").HasKey("max_vm_flows"))
+		assert.Equal(t, "8192", vrouterConfig.Section("FLOWS").Key("fabric_snat_hash_table_size").String())
+	})
+
+	t.Run("rendered configuration is reported in status and pod annotations", func(t *testing.T) {
+		var podName string
+		for _, pod := range environment.vrouterPodList.Items {
+			if pod.Status.PodIP == "1.1.8.1" {
+				podName = pod.Name
+			}
+		}
+		expectedStatus := v1alpha1.VrouterNodeStatus{
+			Node:     "rack1-node",
+			Profiles: []string{"all", "rack1", "rack1-debug"},
+			VrouterNodeConfiguration: v1alpha1.VrouterNodeConfiguration{
+				PhysicalInterface:       "ens3f0",
+				Gateway:                 "10.1.0.1",
+				LogLevel:                "SYS_INFO",
+				MaxVMFlows:              &maxVMFlows,
+				FabricSNATHashTableSize: &fabricSNATHashTableSize,
+			},
+		}
+		assert.Equal(t, expectedStatus, environment.vrouterResource.Status.NodeConfigurations[podName])
+		pod := &corev1.Pod{}
+		if err := cl.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: "default"}, pod); err != nil {
+			t.Fatalf("get pod: (%v)", err)
+		}
+		assert.Equal(t, "ens3f0", pod.Annotations[v1alpha1.VrouterPhysicalInterfaceAnnotation])
+		assert.Equal(t, "10.1.0.1", pod.Annotations[v1alpha1.VrouterGatewayAnnotation])
+		assert.Equal(t, "eth0", pod.Annotations["physicalInterface"], "discovered value should be kept")
+	})
+
+	t.Run("removing the profile restores the discovered values", func(t *testing.T) {
+		profile := &v1alpha1.VrouterNodeProfile{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "rack1", Namespace: "default"}, profile))
+		require.NoError(t, cl.Delete(context.TODO(), profile))
+		podList := &corev1.PodList{}
+		for _, item := range environment.vrouterPodList.Items {
+			pod := corev1.Pod{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: item.Name, Namespace: "default"}, &pod))
+			pod.Spec.NodeName = item.Spec.NodeName
+			podList.Items = append(podList.Items, pod)
+		}
+		require.NoError(t, environment.vrouterResource.InstanceConfiguration(reconcile.Request{NamespacedName: types.NamespacedName{Name: "vrouter1", Namespace: "default"}},
+			podList, cl))
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "vrouter1-vrouter-configmap", Namespace: "default"}, &environment.vrouterConfigMap))
+		vrouterConfig, err := ini.Load([]byte(environment.vrouterConfigMap.Data["vrouter.1.1.8.1"]))
+		require.NoError(t, err)
+		assert.Equal(t, "eth0", vrouterConfig.Section("VIRTUAL-HOST-INTERFACE").Key("physical_interface").String())
+		for _, item := range podList.Items {
+			pod := &corev1.Pod{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: item.Name, Namespace: "default"}, pod))
+			assert.Equal(t, "eth0", pod.Annotations[v1alpha1.VrouterPhysicalInterfaceAnnotation])
+		}
+	})
+
+	t.Run("agent configuration hash changes with profiles selecting nodes", func(t *testing.T) {
+		hash, err := environment.vrouterResource.AgentConfigHash(cl)
+		require.NoError(t, err)
+		debugProfile := &v1alpha1.VrouterNodeProfile{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "rack1-debug", Namespace: "default"}, debugProfile))
+		debugProfile.Spec.LogLevel = "SYS_DEBUG"
+		require.NoError(t, cl.Update(context.TODO(), debugProfile))
+		changedHash, err := environment.vrouterResource.AgentConfigHash(cl)
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, changedHash)
+	})
+}
+
 func TestVrouterConfigStaticConfiguration(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 	t.Run("given mock environment", func(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/kylelemons/godebug/diff"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -41,6 +42,10 @@ func TestWebuiConfig(t *testing.T) {
 		configDiff := diff.Diff(environment.webuiConfigMap.Data["contrail-webui-userauth.js"], webuiAuthConfig)
 		t.Fatalf("get webui auth config: \n%v\n", configDiff)
 	}
+
+	monitorConfig := environment.webuiConfigMap.Data["monitorconfig.1.1.7.1.yaml"]
+	assert.Contains(t, monitorConfig, "nodeType: webui")
+	assert.Contains(t, monitorConfig, "- 1.1.7.1:8143::contrail-webui")
 }
 
 var webuiConfigHa = `/*
@@ -9,7 +9,7 @@ servers={{ .ControlServerList }}
 http_server_ip=0.0.0.0
 collectors={{ .CollectorServerList }}
 log_file=/var/log/contrail/contrail-vrouter-agent.log
-log_level=SYS_NOTICE
+log_level={{ .LogLevel }}
 log_local=1
 hostname={{ .Hostname }}
 agent_name={{ .Hostname }}
@@ -19,10 +19,17 @@ xmpp_server_cert=/etc/certificates/server-{{ .ListenAddress }}.crt
 xmpp_server_key=/etc/certificates/server-key-{{ .ListenAddress }}.pem
 xmpp_ca_cert={{ .CAFilePath }}
 physical_interface_mac = {{ .PhysicalInterfaceMac }}
-tsn_servers = []
+tsn_servers = [{{ .TSNServers }}]
+{{- if .Dpdk }}
+platform=dpdk
+physical_uio_driver={{ .DpdkDriver }}
+{{- if .PCIAddress }}
+physical_interface_address={{ .PCIAddress }}
+{{- end }}
+{{- end }}
 [SANDESH]
 introspect_ssl_enable=True
-introspect_ssl_insecure=True
+introspect_ssl_insecure={{ .IntrospectSSLInsecure }}
 sandesh_ssl_enable=True
 sandesh_keyfile=/etc/certificates/server-key-{{ .ListenAddress }}.pem
 sandesh_certfile=/etc/certificates/server-{{ .ListenAddress }}.crt
@@ -45,10 +52,24 @@ docker_command=/usr/bin/opencontrail-vrouter-docker
 [HYPERVISOR]
 type = kvm
 [FLOWS]
-fabric_snat_hash_table_size = 4096
+{{- if .FlowThreadCount }}
+thread_count = {{ .FlowThreadCount }}
+{{- end }}
+fabric_snat_hash_table_size = {{ .FabricSNATHashTable }}
+{{- if .MaxVMFlows }}
+max_vm_flows = {{ .MaxVMFlows }}
+{{- end }}
 [SESSION]
-slo_destination = collector
-sample_destination = collector`))
+slo_destination = {{ .SloDestination }}
+sample_destination = {{ .SampleDestination }}
+{{- if and .Dpdk .ServiceCoreMask }}
+[SERVICE-CORE]
+cpu_core_mask={{ .ServiceCoreMask }}
+{{- end }}
+{{- if and .Dpdk .ControlThreadMask }}
+[DPDK]
+dpdk_ctrl_thread_mask={{ .ControlThreadMask }}
+{{- end }}`))
 
 //VrouterNodemanagerConfig is the template of the Vrouter Nodemanager service configuration
 var VrouterNodemanagerConfig = template.Must(template.New("").Parse(`[DEFAULTS]
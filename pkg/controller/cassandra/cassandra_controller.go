@@ -208,6 +208,9 @@ func (r *ReconcileCassandra) Reconcile(request reconcile.Request) (reconcile.Res
 	if err := svc.EnsureExists(); err != nil {
 		return reconcile.Result{}, err
 	}
+	if err := utils.StatusMonitorMetricsService(r.Kubernetes, instanceType, "database", instance).EnsureExists(); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	clusterIP := svc.ClusterIP()
 	if clusterIP == "" {
@@ -264,6 +267,14 @@ func (r *ReconcileCassandra) Reconcile(request reconcile.Request) (reconcile.Res
 	}
 	statefulSet.Spec.Template.Spec.Volumes = append(statefulSet.Spec.Template.Spec.Volumes, emptyVolume)
 
+	serviceAccountName := request.Name + "-" + instanceType + "-service-account"
+	if err = r.Kubernetes.ServiceAccount(serviceAccountName, request.Name+"-"+instanceType+"-role", request.Name+"-"+instanceType+"-role-binding", instance).
+		EnsureExists(nil, namespaceRules); err != nil {
+		return reconcile.Result{}, err
+	}
+	statefulSet.Spec.Template.Spec.ServiceAccountName = serviceAccountName
+
+	statusMonitor := utils.GetContainerFromList("statusmonitor", instance.Spec.ServiceConfiguration.Containers) != nil
 	for idx, container := range statefulSet.Spec.Template.Spec.Containers {
 
 		if container.Name == "cassandra" {
@@ -322,8 +333,42 @@ func (r *ReconcileCassandra) Reconcile(request reconcile.Request) (reconcile.Res
 			}
 
 		}
+		if container.Name == "statusmonitor" && statusMonitor {
+			command := []string{"sh", "-c",
+				"/app/statusmonitor/contrail-statusmonitor-image.binary -config /mydata/monitorconfig.${POD_IP}.yaml"}
+			instanceContainer := utils.GetContainerFromList(container.Name, instance.Spec.ServiceConfiguration.Containers)
+			if instanceContainer.Command == nil {
+				(&statefulSet.Spec.Template.Spec.Containers[idx]).Command = command
+			} else {
+				(&statefulSet.Spec.Template.Spec.Containers[idx]).Command = instanceContainer.Command
+			}
+			(&statefulSet.Spec.Template.Spec.Containers[idx]).VolumeMounts = []corev1.VolumeMount{
+				{
+					Name:      request.Name + "-" + instanceType + "-volume",
+					MountPath: "/mydata",
+				},
+				{
+					Name:      request.Name + "-secret-certificates",
+					MountPath: "/etc/certificates",
+				},
+				{
+					Name:      csrSignerCaVolumeName,
+					MountPath: certificates.SignerCAMountPath,
+				},
+			}
+			(&statefulSet.Spec.Template.Spec.Containers[idx]).Image = instanceContainer.Image
+		}
 
 	}
+	// The statusmonitor sidecar is optional, it is deployed only when its image is specified.
+	if !statusMonitor {
+		for idx, container := range statefulSet.Spec.Template.Spec.Containers {
+			if container.Name == "statusmonitor" {
+				statefulSet.Spec.Template.Spec.Containers = utils.RemoveIndex(statefulSet.Spec.Template.Spec.Containers, idx)
+				break
+			}
+		}
+	}
 	initHostPathType := corev1.HostPathType("DirectoryOrCreate")
 	initHostPathSource := &corev1.HostPathVolumeSource{
 		Path: cassandraDefaultConfiguration.Storage.Path,
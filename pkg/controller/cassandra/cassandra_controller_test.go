@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
 	storage "k8s.io/api/storage/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -149,6 +150,7 @@ func TestCassandra(t *testing.T) {
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme), "Failed core.SchemeBuilder.AddToScheme()")
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme), "Failed apps.SchemeBuilder.AddToScheme()")
 	require.NoError(t, storage.SchemeBuilder.AddToScheme(scheme), "Failed storage.SchemeBuilder.AddToScheme()")
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme), "Failed rbac.SchemeBuilder.AddToScheme()")
 	cas := newCassandra()
 	svc := newCassandraService()
 
@@ -169,6 +171,18 @@ func TestCassandra(t *testing.T) {
 	err = r.Client.Get(context.TODO(), req.NamespacedName, &res)
 	require.NoError(t, err)
 	assert.Equal(t, "10.0.0.1", res.Status.ClusterIP)
+	sts := &apps.StatefulSet{}
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "cassandra-cassandra-statefulset", Namespace: "default"}, sts))
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		assert.NotEqual(t, "statusmonitor", container.Name, "statusmonitor should be deployed only when its image is specified")
+	}
+	metricsService := &core.Service{}
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "cassandra-database-statusmonitor", Namespace: "default"}, metricsService))
+	assert.Equal(t, "database", metricsService.Labels["contrail_statusmonitor"])
+	assert.Equal(t, map[string]string{"contrail_manager": "cassandra", "cassandra": "cassandra"}, metricsService.Spec.Selector)
+	require.Len(t, metricsService.Spec.Ports, 1)
+	assert.Equal(t, "metrics", metricsService.Spec.Ports[0].Name)
+	assert.Equal(t, int32(contrail.DatabaseStatusMonitorMetricsPort), metricsService.Spec.Ports[0].Port)
 }
 
 func TestCassandraControllerStatefulSetCreate(t *testing.T) {
@@ -194,6 +208,7 @@ func TestCassandraControllerStatefulSetCreate(t *testing.T) {
 					{Name: "cassandra", Image: "cassandra:3.5"},
 					{Name: "init", Image: "busybox"},
 					{Name: "init2", Image: "cassandra:3.5"},
+					{Name: "statusmonitor", Image: "statusmonitor"},
 				},
 				MinHeapSize: "100M",
 				MaxHeapSize: "1G",
@@ -245,4 +260,19 @@ func TestCassandraControllerStatefulSetCreate(t *testing.T) {
 
 	// Check if the pod management policy is set to ordered ready.
 	assert.Equal(t, apps.OrderedReadyPodManagement, sts.Spec.PodManagementPolicy)
+
+	// Check if the statusmonitor sidecar reads its config from the configmap.
+	var statusMonitor *core.Container
+	for idx, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == "statusmonitor" {
+			statusMonitor = &sts.Spec.Template.Spec.Containers[idx]
+		}
+	}
+	require.NotNil(t, statusMonitor)
+	assert.Equal(t, "statusmonitor", statusMonitor.Image)
+	assert.Contains(t, statusMonitor.Command[2], "/mydata/monitorconfig.${POD_IP}.yaml")
+	assert.Equal(t, "cassandra-cassandra-service-account", sts.Spec.Template.Spec.ServiceAccountName)
+	role := &rbac.Role{}
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "cassandra-cassandra-role", Namespace: namespace}, role))
+	assert.Equal(t, namespaceRules, role.Rules)
 }
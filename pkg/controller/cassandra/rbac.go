@@ -0,0 +1,19 @@
+package cassandra
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// namespaceRules are permissions of Cassandra pods in the namespace of the Cassandra. Only the
+// status monitor uses the Kubernetes API, so no cluster wide permissions are needed.
+var namespaceRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"cassandras"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"cassandras/status"},
+	Verbs:     []string{"get", "update"},
+}}
@@ -62,6 +62,14 @@ spec:
         #  name: cassandra-logs
         #- mountPath: /var/lib/cassandra
         #  name: cassandra-data
+      - name: statusmonitor
+        image: docker.io/kaweue/contrail-statusmonitor:debug
+        env:
+        - name: POD_IP
+          valueFrom:
+            fieldRef:
+              fieldPath: status.podIP
+        imagePullPolicy: IfNotPresent
       dnsPolicy: ClusterFirst
       hostNetwork: true
       initContainers:
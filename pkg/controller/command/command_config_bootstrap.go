@@ -30,6 +30,7 @@ type commandBootstrapConf struct {
 	WebUIAddress         string
 	WebUIPort            int
 	Endpoints            []bootstrapEndpoint
+	Resources            string
 }
 
 type bootstrapEndpoint struct {
@@ -232,7 +233,7 @@ resources:
       public_url: {{ .PublicURL }}
     kind: endpoint
 {{end -}}
-`))
+{{ .Resources }}`))
 
 var migrationConfig = template.Must(template.New("").Parse(`
 database:
@@ -55,7 +55,7 @@ func (c *configMaps) ensureCommandConfigExist(postgresAddress, ConfigEndpoint st
 	return c.cm.EnsureExists(cc)
 }
 
-func (c *configMaps) ensureCommandInitConfigExist(webUIPort, swiftProxyPort, keystonePort int, webUIAddress, swiftProxyAddress, keystoneAddress, keystoneAuthProtocol, postgresAddress, ConfigEndpoint string, podIP string) error {
+func (c *configMaps) ensureCommandInitConfigExist(webUIPort, swiftProxyPort, keystonePort int, webUIAddress, swiftProxyAddress, keystoneAddress, keystoneAuthProtocol, postgresAddress, ConfigEndpoint string, podIP string, resources []commandResource) error {
 	configAPIURL := fmt.Sprintf("https://%v:%v", ConfigEndpoint, contrail.ConfigApiPort)
 	telemetryURL := fmt.Sprintf("https://%v:%v", ConfigEndpoint, contrail.AnalyticsApiPort)
 	webUIURL := fmt.Sprintf("https://%v:%v", webUIAddress, webUIPort)
@@ -79,6 +79,10 @@ func (c *configMaps) ensureCommandInitConfigExist(webUIPort, swiftProxyPort, key
 		fqname := []string{"default-global-system-config", clusterName, e.Name}
 		bes = append(bes, makeBootstrapEndpoint(e, fqname))
 	}
+	bootstrapResources, err := bootstrapResources(resources)
+	if err != nil {
+		return err
+	}
 	cc := &commandBootstrapConf{
 		ClusterName:          clusterName,
 		AdminUsername:        "admin",
@@ -96,6 +100,7 @@ func (c *configMaps) ensureCommandInitConfigExist(webUIPort, swiftProxyPort, key
 		KeystoneAuthProtocol: keystoneAuthProtocol,
 		ContrailVersion:      c.ccSpec.ServiceConfiguration.ContrailVersion,
 		Endpoints:            bes,
+		Resources:            bootstrapResources,
 	}
 	return c.cm.EnsureExists(cc)
 }
@@ -287,8 +287,12 @@ func (r *ReconcileCommand) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
+	resources, err := r.commandResources(command)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	commandBootStrapConfigName := command.Name + "-bootstrap-configmap"
-	if err = r.configMap(commandBootStrapConfigName, "command", command, adminPasswordSecret, swiftSecret).ensureCommandInitConfigExist(webUIPort, swiftProxyPort, keystonePort, webUIAddress, swiftProxyAddress, keystoneAddress, keystoneAuthProtocol, psql.Status.Endpoint, config.Status.Endpoint, commandClusterIP); err != nil {
+	if err = r.configMap(commandBootStrapConfigName, "command", command, adminPasswordSecret, swiftSecret).ensureCommandInitConfigExist(webUIPort, swiftProxyPort, keystonePort, webUIAddress, swiftProxyAddress, keystoneAddress, keystoneAuthProtocol, psql.Status.Endpoint, config.Status.Endpoint, commandClusterIP, resources); err != nil {
 		return reconcile.Result{}, err
 	}
 	if err = r.reconcileBootstrapJob(command, commandBootStrapConfigName); err != nil {
@@ -338,7 +342,11 @@ func (r *ReconcileCommand) Reconcile(request reconcile.Request) (reconcile.Resul
 	if err := r.ensureContrailSwiftContainerExists(command, keystone, sPort, adminPasswordSecret, swiftServiceName); err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	requeueAfter, err := r.reconcileResources(command, resources, keystone, adminPasswordSecret)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ReconcileCommand) getPostgres(command *contrail.Command) (*contrail.Postgres, error) {
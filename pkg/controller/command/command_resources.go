@@ -0,0 +1,318 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/uuid"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	commandclient "github.com/Juniper/contrail-operator/pkg/client/command"
+	"github.com/Juniper/contrail-operator/pkg/client/keystone"
+	"github.com/Juniper/contrail-operator/pkg/client/kubeproxy"
+)
+
+const (
+	// Default time in seconds after which resources are synced again.
+	defaultResourcesResyncPeriod = 600
+	// Time after which resources which failed to sync are retried.
+	resourcesSyncRetryInterval = 30 * time.Second
+)
+
+type commandResource struct {
+	commandclient.Resource
+	name string
+	// secretFields are keys of Data holding values read from the secret of the given
+	// resource version. They are left out of the checksum, which covers the version instead.
+	secretFields  []string
+	secretVersion string
+	// syncOnly resources carry secrets or refer to resources which do. They are not seeded
+	// in the bootstrap config map and are only synced with the running Command.
+	syncOnly bool
+}
+
+func newCommandResource(kind string, fqName []string, parentType string, data map[string]interface{}) commandResource {
+	data["fq_name"] = fqName
+	data["name"] = fqName[len(fqName)-1]
+	data["uuid"] = resourceUUID(kind, strings.Join(fqName, ":"))
+	data["parent_type"] = parentType
+	return commandResource{
+		Resource: commandclient.Resource{Kind: kind, Data: data},
+		name:     strings.Join(fqName, ":"),
+	}
+}
+
+// resourceUUID returns uuid of the Command object of the kind with the colon separated fq name.
+// It is stable, so objects which left the spec can be deleted knowing only their status.
+func resourceUUID(kind, name string) string {
+	return uuid.NewSHA1(NameSpaceCommand, []byte(kind+":"+name)).String()
+}
+
+func (c commandResource) uuid() string {
+	return c.Data["uuid"].(string)
+}
+
+// withSecret marks fields of the resource which are read from the secret.
+func (c commandResource) withSecret(secret *core.Secret, fields ...string) commandResource {
+	c.secretFields = fields
+	c.secretVersion = secret.ResourceVersion
+	c.syncOnly = true
+	return c
+}
+
+// checksum is reported in status, so values read from secrets are replaced with the
+// resource version of the secret. A change of the secret still changes the checksum.
+func (c commandResource) checksum() string {
+	data := make(map[string]interface{}, len(c.Data))
+	for key, value := range c.Data {
+		data[key] = value
+	}
+	for _, field := range c.secretFields {
+		delete(data, field)
+	}
+	checksumData, _ := json.Marshal(struct {
+		Data          map[string]interface{} `json:"data"`
+		SecretVersion string                 `json:"secretVersion,omitempty"`
+	}{data, c.secretVersion})
+	return fmt.Sprintf("%x", sha256.Sum256(checksumData))
+}
+
+// commandResources builds Command objects declared in the spec. Parents are listed before
+// the objects which refer to them.
+func (r *ReconcileCommand) commandResources(command *contrail.Command) ([]commandResource, error) {
+	spec := command.Spec.ServiceConfiguration.Resources
+	if spec == nil {
+		return nil, nil
+	}
+	var resources []commandResource
+	credentials := map[string]commandResource{}
+	for _, c := range spec.Credentials {
+		secret, err := r.getSecret(c.SecretName, command.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		credential := newCommandResource("credential", []string{"default-global-system-config", c.Name}, "global-system-config", map[string]interface{}{
+			"ssh_user":     string(secret.Data["user"]),
+			"ssh_password": string(secret.Data["password"]),
+		}).withSecret(secret, "ssh_user", "ssh_password")
+		credentials[c.Name] = credential
+		resources = append(resources, credential)
+	}
+	for _, c := range spec.Clusters {
+		data := map[string]interface{}{
+			"display_name":      c.DisplayName,
+			"contrail_version":  c.ContrailVersion,
+			"orchestrator":      c.Orchestrator,
+			"high_availability": c.HighAvailability,
+		}
+		if c.DisplayName == "" {
+			data["display_name"] = c.Name
+		}
+		resources = append(resources, newCommandResource("contrail_cluster", []string{"default-global-system-config", strings.ToLower(c.Name)}, "global-system-config", data))
+	}
+	for _, n := range spec.Nodes {
+		data := map[string]interface{}{
+			"hostname":   n.Hostname,
+			"ip_address": n.IPAddress,
+			"type":       n.Type,
+		}
+		if n.Hostname == "" {
+			data["hostname"] = n.Name
+		}
+		if n.Type == "" {
+			data["type"] = "private"
+		}
+		node := newCommandResource("node", []string{"default-global-system-config", n.Name}, "global-system-config", data)
+		if n.Credential != "" {
+			credential, ok := credentials[n.Credential]
+			if !ok {
+				return nil, fmt.Errorf("node %q refers to undefined credential %q", n.Name, n.Credential)
+			}
+			data["credential_refs"] = []map[string]string{{"uuid": credential.uuid()}}
+			node.syncOnly = true
+		}
+		resources = append(resources, node)
+	}
+	for _, u := range spec.Users {
+		secret, err := r.getSecret(u.PasswordSecretName, command.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, newCommandResource("user", []string{domainOrDefault(u.Domain), u.Project, u.Name}, "project", map[string]interface{}{
+			"password": string(secret.Data["password"]),
+		}).withSecret(secret, "password"))
+	}
+	for _, role := range spec.Roles {
+		var rules []map[string]interface{}
+		for _, rule := range role.Rules {
+			var perms []map[string]string
+			for _, perm := range rule.Perms {
+				perms = append(perms, map[string]string{"role_name": perm.Role, "role_crud": perm.CRUD})
+			}
+			rules = append(rules, map[string]interface{}{
+				"rule_object": rule.Object,
+				"rule_field":  rule.Field,
+				"rule_perms":  perms,
+			})
+		}
+		resources = append(resources, newCommandResource("api_access_list", []string{domainOrDefault(role.Domain), role.Project, role.Name}, "project", map[string]interface{}{
+			"api_access_list_entries": map[string]interface{}{"rbac_rule": rules},
+		}))
+	}
+	return resources, nil
+}
+
+func domainOrDefault(domain string) string {
+	if domain == "" {
+		return "default-domain"
+	}
+	return domain
+}
+
+// bootstrapResources renders resources as items of the init_cluster.yml resources list.
+// The config map is readable by everyone who can read the namespace, so resources carrying
+// secrets are left out and created by the first sync instead.
+func bootstrapResources(resources []commandResource) (string, error) {
+	items := make([]commandclient.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if !resource.syncOnly {
+			items = append(items, resource.Resource)
+		}
+	}
+	if len(items) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	var rendered strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if line != "" {
+			rendered.WriteString("  " + line)
+		}
+	}
+	return rendered.String(), nil
+}
+
+// reconcileResources syncs resources declared in the spec with Command. Resources are synced when
+// they change, when the previous sync failed or when the resync period passes. Resources reported
+// in status which are no longer declared are deleted from Command. It returns time after which it
+// should be called again.
+func (r *ReconcileCommand) reconcileResources(command *contrail.Command, resources []commandResource, k *contrail.Keystone, adminPass *core.Secret) (time.Duration, error) {
+	if len(resources) == 0 && len(command.Status.Resources) == 0 {
+		return 0, nil
+	}
+	if !command.Status.Active {
+		return 0, nil
+	}
+	resyncPeriod := defaultResourcesResyncPeriod * time.Second
+	if spec := command.Spec.ServiceConfiguration.Resources; spec != nil && spec.ResyncPeriod != 0 {
+		resyncPeriod = time.Duration(spec.ResyncPeriod) * time.Second
+	}
+	observed := map[string]contrail.CommandResourceStatus{}
+	for _, s := range command.Status.Resources {
+		observed[s.Kind+"/"+s.Name] = s
+	}
+
+	var syncClient *commandclient.Client
+	getSyncClient := func() (*commandclient.Client, error) {
+		if syncClient != nil {
+			return syncClient, nil
+		}
+		var err error
+		syncClient, err = r.commandClient(command, k, adminPass)
+		return syncClient, err
+	}
+	now := time.Now()
+	requeueAfter := resyncPeriod
+	statuses := make([]contrail.CommandResourceStatus, 0, len(resources))
+	declared := map[string]bool{}
+	for _, resource := range resources {
+		declared[resource.Kind+"/"+resource.name] = true
+		checksum := resource.checksum()
+		status, found := observed[resource.Kind+"/"+resource.name]
+		if found && status.InSync && status.Checksum == checksum && status.LastSyncTime != nil {
+			if nextSync := status.LastSyncTime.Add(resyncPeriod); now.Before(nextSync) {
+				statuses = append(statuses, status)
+				if nextSync.Sub(now) < requeueAfter {
+					requeueAfter = nextSync.Sub(now)
+				}
+				continue
+			}
+		}
+		client, err := getSyncClient()
+		if err != nil {
+			return 0, err
+		}
+		status = contrail.CommandResourceStatus{Kind: resource.Kind, Name: resource.name, Checksum: checksum, LastSyncTime: &meta.Time{Time: now}}
+		if err := client.Sync(resource.Resource); err != nil {
+			log.Info(fmt.Sprintf("Failed to sync %s %s: %v", resource.Kind, resource.name, err))
+			status.Error = err.Error()
+			requeueAfter = resourcesSyncRetryInterval
+		} else {
+			status.InSync = true
+		}
+		statuses = append(statuses, status)
+	}
+
+	// Removed resources are deleted in reverse order, so objects go before the parents they refer to.
+	// The ones which failed to be deleted stay in status and are retried.
+	var undeleted []contrail.CommandResourceStatus
+	for idx := len(command.Status.Resources) - 1; idx >= 0; idx-- {
+		status := command.Status.Resources[idx]
+		if declared[status.Kind+"/"+status.Name] {
+			continue
+		}
+		client, err := getSyncClient()
+		if err != nil {
+			return 0, err
+		}
+		resource := commandclient.Resource{Kind: status.Kind, Data: map[string]interface{}{"uuid": resourceUUID(status.Kind, status.Name)}}
+		if err := client.Delete(resource); err != nil {
+			log.Info(fmt.Sprintf("Failed to delete %s %s: %v", status.Kind, status.Name, err))
+			status.InSync = false
+			status.Error = err.Error()
+			status.LastSyncTime = &meta.Time{Time: now}
+			undeleted = append([]contrail.CommandResourceStatus{status}, undeleted...)
+			requeueAfter = resourcesSyncRetryInterval
+		}
+	}
+	command.Status.Resources = append(statuses, undeleted...)
+	if len(command.Status.Resources) == 0 {
+		command.Status.Resources = nil
+		requeueAfter = 0
+	}
+	return requeueAfter, r.client.Status().Update(context.Background(), command)
+}
+
+func (r *ReconcileCommand) commandClient(command *contrail.Command, k *contrail.Keystone, adminPass *core.Secret) (*commandclient.Client, error) {
+	keystoneClient, err := keystone.NewClient(r.client, r.scheme, r.config, k)
+	if err != nil {
+		return nil, err
+	}
+	token, err := keystoneClient.PostAuthTokens("admin", string(adminPass.Data["password"]), "admin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keystone token: %v", err)
+	}
+	proxy, err := kubeproxy.New(r.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubeproxy: %v", err)
+	}
+	commandProxy := proxy.NewSecureClientForService(command.Namespace, command.Name+"-command", 9091)
+	return commandclient.NewClient(commandProxy, token.XAuthTokenHeader), nil
+}
+
+func (r *ReconcileCommand) getSecret(name, namespace string) (*core.Secret, error) {
+	secret := &core.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	return secret, err
+}
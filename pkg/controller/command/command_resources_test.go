@@ -0,0 +1,268 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/keystone"
+	"github.com/Juniper/contrail-operator/pkg/controller/command"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
+)
+
+type fakeCommandServer struct {
+	syncStatus   int
+	deleteStatus int
+	synced       []string
+	deleted      []string
+}
+
+func (s *fakeCommandServer) config() *rest.Config {
+	return &rest.Config{
+		Host:    "localhost",
+		APIPath: "/",
+		Transport: mockRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if strings.Contains(r.URL.Path, "keystone") {
+				jsonBytes, _ := json.Marshal(keystone.AuthTokens{})
+				return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(jsonBytes))}, nil
+			}
+			if strings.HasSuffix(r.URL.Path, "/sync") {
+				body := struct {
+					Resources []struct {
+						Kind      string                 `json:"kind"`
+						Data      map[string]interface{} `json:"data"`
+						Operation string                 `json:"operation"`
+					} `json:"resources"`
+				}{}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				status := s.syncStatus
+				for _, resource := range body.Resources {
+					if resource.Operation == "DELETE" {
+						s.deleted = append(s.deleted, resource.Kind+"/"+resource.Data["uuid"].(string))
+						status = s.deleteStatus
+						continue
+					}
+					s.synced = append(s.synced, resource.Kind+"/"+resource.Data["name"].(string))
+				}
+				return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("everything fine"))}, nil
+		}),
+	}
+}
+
+func TestCommandResources(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "command", Namespace: "default"}}
+
+	newCommandWithResources := func() *contrail.Command {
+		cmd := newCommand()
+		cmd.Spec.ServiceConfiguration.Resources = &contrail.CommandResources{
+			Credentials: []contrail.CommandCredential{{Name: "root-ssh", SecretName: "root-ssh"}},
+			Nodes:       []contrail.CommandNode{{Name: "node1", IPAddress: "10.0.0.1", Credential: "root-ssh"}},
+			Roles: []contrail.CommandRole{{
+				Name:    "readers",
+				Project: "admin",
+				Rules: []contrail.CommandRBACRule{{
+					Object: "virtual-network",
+					Perms:  []contrail.CommandRBACPermission{{Role: "reader", CRUD: "R"}},
+				}},
+			}},
+		}
+		return cmd
+	}
+	initObjsWithSSHPassword := func(password string) []runtime.Object {
+		return []runtime.Object{
+			newCommandWithResources(),
+			newCommandService(),
+			newDeployment(apps.DeploymentStatus{ReadyReplicas: 1}),
+			newConfig(true),
+			newPostgres(true),
+			newAdminSecret(),
+			newSwiftSecret(),
+			newSwift(true),
+			newKeystone(contrail.KeystoneStatus{Active: true, Endpoint: "10.0.2.16"}, nil),
+			newWebUI(true),
+			&core.Secret{
+				ObjectMeta: meta.ObjectMeta{Name: "root-ssh", Namespace: "default"},
+				Data:       map[string][]byte{"user": []byte("root"), "password": []byte(password)},
+			},
+		}
+	}
+	initObjs := func() []runtime.Object {
+		return initObjsWithSSHPassword("c0ntrail123")
+	}
+	expectedSynced := []string{"credential/root-ssh", "node/node1", "api_access_list/readers"}
+
+	t.Run("should seed declared resources at bootstrap", func(t *testing.T) {
+		server := &fakeCommandServer{syncStatus: http.StatusOK}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs()...)
+		r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+
+		configMap := &core.ConfigMap{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "command-bootstrap-configmap", Namespace: "default"}, configMap))
+		initCluster := configMap.Data["init_cluster.yml"]
+		assert.Contains(t, initCluster, "    kind: endpoint\n  - data:\n")
+		assert.Contains(t, initCluster, "    kind: api_access_list\n")
+		assert.NotContains(t, initCluster, "c0ntrail123", "secrets should not be seeded in the config map")
+		assert.NotContains(t, initCluster, "kind: credential")
+		assert.NotContains(t, initCluster, "ip_address: 10.0.0.1", "nodes referring to credentials should only be synced")
+	})
+
+	t.Run("should not report checksums of secret values in status", func(t *testing.T) {
+		checksums := map[string]string{}
+		for _, password := range []string{"c0ntrail123", "other-password"} {
+			server := &fakeCommandServer{syncStatus: http.StatusOK}
+			cl := fake.NewFakeClientWithScheme(scheme, initObjsWithSSHPassword(password)...)
+			r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+
+			_, err := r.Reconcile(req)
+			require.NoError(t, err)
+
+			cmd := &contrail.Command{}
+			require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cmd))
+			require.Equal(t, "credential", cmd.Status.Resources[0].Kind)
+			checksums[password] = cmd.Status.Resources[0].Checksum
+		}
+		assert.Equal(t, checksums["c0ntrail123"], checksums["other-password"])
+	})
+
+	t.Run("should sync credentials again when their secret changes", func(t *testing.T) {
+		server := &fakeCommandServer{syncStatus: http.StatusOK}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs()...)
+		r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		secret := &core.Secret{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "root-ssh", Namespace: "default"}, secret))
+		secret.Data["password"] = []byte("new-password")
+		require.NoError(t, cl.Update(context.Background(), secret))
+
+		_, err = r.Reconcile(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, append(expectedSynced, "credential/root-ssh"), server.synced)
+	})
+
+	t.Run("should sync resources and report them in status", func(t *testing.T) {
+		server := &fakeCommandServer{syncStatus: http.StatusOK}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs()...)
+		r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+
+		res, err := r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, expectedSynced, server.synced)
+		assert.True(t, res.RequeueAfter > 9*time.Minute)
+
+		cmd := &contrail.Command{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cmd))
+		require.Len(t, cmd.Status.Resources, 3)
+		assert.Equal(t, "node", cmd.Status.Resources[1].Kind)
+		assert.Equal(t, "default-global-system-config:node1", cmd.Status.Resources[1].Name)
+		for _, s := range cmd.Status.Resources {
+			assert.True(t, s.InSync)
+			assert.Empty(t, s.Error)
+		}
+
+		_, err = r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, expectedSynced, server.synced, "unchanged resources should not be synced before resync period")
+	})
+
+	t.Run("should report resources which failed to sync", func(t *testing.T) {
+		server := &fakeCommandServer{syncStatus: http.StatusBadRequest}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs()...)
+		r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+
+		res, err := r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, res.RequeueAfter)
+
+		cmd := &contrail.Command{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cmd))
+		require.Len(t, cmd.Status.Resources, 3)
+		for _, s := range cmd.Status.Resources {
+			assert.False(t, s.InSync)
+			assert.NotEmpty(t, s.Error)
+		}
+	})
+	removeNodeAndRole := func(t *testing.T, cl client.Client) {
+		cmd := &contrail.Command{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cmd))
+		cmd.Spec.ServiceConfiguration.Resources.Nodes = nil
+		cmd.Spec.ServiceConfiguration.Resources.Roles = nil
+		require.NoError(t, cl.Update(context.Background(), cmd))
+	}
+
+	t.Run("should delete resources removed from the spec", func(t *testing.T) {
+		server := &fakeCommandServer{syncStatus: http.StatusOK, deleteStatus: http.StatusOK}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs()...)
+		r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		removeNodeAndRole(t, cl)
+
+		_, err = r.Reconcile(req)
+		require.NoError(t, err)
+
+		cmd := &contrail.Command{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cmd))
+		assert.Equal(t, []string{
+			"api_access_list/" + uuid.NewSHA1(command.NameSpaceCommand, []byte("api_access_list:default-domain:admin:readers")).String(),
+			"node/" + uuid.NewSHA1(command.NameSpaceCommand, []byte("node:default-global-system-config:node1")).String(),
+		}, server.deleted)
+		require.Len(t, cmd.Status.Resources, 1)
+		assert.Equal(t, "credential", cmd.Status.Resources[0].Kind)
+	})
+
+	t.Run("should keep resources which failed to be deleted in status", func(t *testing.T) {
+		server := &fakeCommandServer{syncStatus: http.StatusOK, deleteStatus: http.StatusConflict}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs()...)
+		r := command.NewReconciler(cl, scheme, k8s.New(cl, scheme), server.config())
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		removeNodeAndRole(t, cl)
+
+		res, err := r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, res.RequeueAfter)
+
+		cmd := &contrail.Command{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, cmd))
+		require.Len(t, cmd.Status.Resources, 3)
+		assert.True(t, cmd.Status.Resources[0].InSync)
+		for _, s := range cmd.Status.Resources[1:] {
+			assert.False(t, s.InSync)
+			assert.NotEmpty(t, s.Error)
+		}
+		assert.Equal(t, "node", cmd.Status.Resources[1].Kind)
+		assert.Equal(t, "api_access_list", cmd.Status.Resources[2].Kind)
+	})
+}
@@ -217,6 +217,9 @@ func (r *ReconcileConfig) Reconcile(request reconcile.Request) (reconcile.Result
 	if err := configService.EnsureExists(); err != nil {
 		return reconcile.Result{}, err
 	}
+	if err := utils.StatusMonitorMetricsService(r.Kubernetes, instanceType, instanceType, config).EnsureExists(); err != nil {
+		return reconcile.Result{}, err
+	}
 	currentConfigMap, currentConfigExists := config.CurrentConfigMapExists(request.Name+"-"+instanceType+"-configmap", r.Client, r.Scheme, request)
 
 	configMap, err := config.CreateConfigMap(request.Name+"-"+instanceType+"-configmap", r.Client, r.Scheme, request)
@@ -8,6 +8,7 @@ import (
 )
 
 type contrailCNIConf struct {
+	Name                  string
 	KubernetesClusterName string
 	CniMetaPlugin         string
 	VrouterIP             string
@@ -15,12 +16,18 @@ type contrailCNIConf struct {
 	PollTimeout           *int32
 	PollRetries           *int32
 	LogLevel              *int32
+	MTU                   int
 }
 
 func (c *contrailCNIConf) FillConfigMap(cm *core.ConfigMap) {
 	cm.Data["10-contrail.conf"] = c.executeTemplate(contrailCNIConfig)
 }
 
+// String returns the CNI configuration, NetworkAttachmentDefinitions pass it to the plugin through Multus
+func (c *contrailCNIConf) String() string {
+	return c.executeTemplate(contrailCNIConfig)
+}
+
 func (c *contrailCNIConf) executeTemplate(t *template.Template) string {
 	var buffer bytes.Buffer
 	if err := t.Execute(&buffer, c); err != nil {
@@ -40,8 +47,9 @@ var contrailCNIConfig = template.Must(template.New("").Parse(`{
 		"poll-timeout"  : {{ .PollTimeout }},
 		"poll-retries"  : {{ .PollRetries }},
 		"log-file"      : "/var/log/contrail/cni/opencontrail.log",
-		"log-level"     : "{{ .LogLevel }}"
+		"log-level"     : "{{ .LogLevel }}"{{ if .MTU }},
+		"mtu"           : {{ .MTU }}{{ end }}
 	},
-	"name": "contrail-k8s-cni",
+	"name": "{{ .Name }}",
 	"type": "contrail-k8s-cni"
   }`))
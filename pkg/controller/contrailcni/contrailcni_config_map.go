@@ -19,21 +19,31 @@ func (r *ReconcileContrailCNI) configMap(
 }
 
 func (c *configMaps) ensureContrailCNIConfigExists(clusterInfo contrail.CNIClusterInfo) error {
-	ccni := &contrailCNIConf{}
+	ccni, err := newContrailCNIConf(c.ccniSpec, clusterInfo)
+	if err != nil {
+		return err
+	}
+	return c.cm.EnsureExists(ccni)
+}
+
+func newContrailCNIConf(ccniSpec contrail.ContrailCNISpec, clusterInfo contrail.CNIClusterInfo) (*contrailCNIConf, error) {
+	ccni := &contrailCNIConf{Name: "contrail-k8s-cni"}
 
 	clusterName, err := clusterInfo.KubernetesClusterName()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ccni.KubernetesClusterName = clusterName
-	ccni.CniMetaPlugin = configStringWithDefault(c.ccniSpec.ServiceConfiguration.CniMetaPlugin, contrail.DefaultCniMetaPlugin)
-	ccni.VrouterIP = configStringWithDefault(c.ccniSpec.ServiceConfiguration.VrouterIP, contrail.DefaultVrouterIP)
-	ccni.VrouterPort = configIntWithDefault(c.ccniSpec.ServiceConfiguration.VrouterPort, contrail.DefaultVrouterPort)
-	ccni.PollTimeout = configIntWithDefault(c.ccniSpec.ServiceConfiguration.PollTimeout, contrail.DefaultPollTimeout)
-	ccni.PollRetries = configIntWithDefault(c.ccniSpec.ServiceConfiguration.PollRetries, contrail.DefaultPollRetries)
-	ccni.LogLevel = configIntWithDefault(c.ccniSpec.ServiceConfiguration.LogLevel, contrail.DefaultLogLevel)
-
-	return c.cm.EnsureExists(ccni)
+	if ccni.MTU, err = clusterInfo.NetworkMTU(); err != nil {
+		return nil, err
+	}
+	ccni.CniMetaPlugin = configStringWithDefault(ccniSpec.ServiceConfiguration.CniMetaPlugin, contrail.DefaultCniMetaPlugin)
+	ccni.VrouterIP = configStringWithDefault(ccniSpec.ServiceConfiguration.VrouterIP, contrail.DefaultVrouterIP)
+	ccni.VrouterPort = configIntWithDefault(ccniSpec.ServiceConfiguration.VrouterPort, contrail.DefaultVrouterPort)
+	ccni.PollTimeout = configIntWithDefault(ccniSpec.ServiceConfiguration.PollTimeout, contrail.DefaultPollTimeout)
+	ccni.PollRetries = configIntWithDefault(ccniSpec.ServiceConfiguration.PollRetries, contrail.DefaultPollRetries)
+	ccni.LogLevel = configIntWithDefault(ccniSpec.ServiceConfiguration.LogLevel, contrail.DefaultLogLevel)
+	return ccni, nil
 }
 
 func configStringWithDefault(specValue, defaultValue string) string {
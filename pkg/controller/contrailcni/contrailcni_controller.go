@@ -3,14 +3,17 @@ package contrailcni
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -24,10 +27,15 @@ import (
 
 var log = logf.Log.WithName("controller_contrailcni")
 
+const instanceType = "contrailcni"
+
+// cleanupFinalizer keeps the ContrailCNI until its networks and namespace settings are removed
+const cleanupFinalizer = "contrailcni.contrail.juniper.net/cleanup"
+
 // Add creates a new ContrailCNI Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, clusterInfo v1alpha1.CNIClusterInfo) error {
-	return add(mgr, newReconciler(mgr, clusterInfo))
+	return add(mgr, newReconciler(mgr, clusterInfo), clusterInfo)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -42,7 +50,7 @@ func NewReconciler(client client.Client, scheme *runtime.Scheme, kubernetes *k8s
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, clusterInfo v1alpha1.CNIClusterInfo) error {
 	// Create a new controller
 	c, err := controller.New("contrailcni-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -55,11 +63,39 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch for changes to Nodes
-	err = c.Watch(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestsFromMapFunc{
-		ToRequests: handler.ToRequestsFunc(func(nodeObject handler.MapObject) []reconcile.Request {
+	// Watch for changes to the DaemonSet installing the plugin, its status follows the pods on the nodes
+	err = c.Watch(&source.Kind{Type: &appsv1.DaemonSet{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &v1alpha1.ContrailCNI{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Namespaces, isolation settings are applied to namespaces once they are created
+	if err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, enqueueAllContrailCNIs(mgr.GetClient())); err != nil {
+		return err
+	}
+
+	// Watch for changes to NetworkAttachmentDefinitions, so that modified or deleted ones are restored.
+	// Without Multus installed when the operator starts there is nothing to watch.
+	if multusInstalled(mgr.GetRESTMapper()) {
+		nad := &unstructured.Unstructured{}
+		nad.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+		if err = c.Watch(&source.Kind{Type: nad}, enqueueLabeledContrailCNI(mgr.GetClient())); err != nil {
+			return err
+		}
+	}
+
+	// Watch for changes to resources the cluster information is gathered from
+	return utils.WatchClusterInfo(c, clusterInfo, enqueueAllContrailCNIs(mgr.GetClient()))
+}
+
+func enqueueAllContrailCNIs(cl client.Client) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(handler.MapObject) []reconcile.Request {
 			var cniObjects v1alpha1.ContrailCNIList
-			_ = mgr.GetClient().List(context.TODO(), &cniObjects)
+			_ = cl.List(context.TODO(), &cniObjects)
 			var requests = []reconcile.Request{}
 			for _, cniObject := range cniObjects.Items {
 				requests = append(requests, reconcile.Request{
@@ -71,8 +107,35 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 			}
 			return requests
 		}),
-	})
-	return err
+	}
+}
+
+// enqueueLabeledContrailCNI enqueues the ContrailCNI named by the label of the object. Objects created in
+// other namespaces cannot be owned by the ContrailCNI, so it is looked up in all of them.
+func enqueueLabeledContrailCNI(cl client.Client) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(object handler.MapObject) []reconcile.Request {
+			name, ok := object.Meta.GetLabels()[instanceType]
+			if !ok {
+				return nil
+			}
+			var cniObjects v1alpha1.ContrailCNIList
+			_ = cl.List(context.TODO(), &cniObjects)
+			var requests = []reconcile.Request{}
+			for _, cniObject := range cniObjects.Items {
+				if cniObject.Name != name {
+					continue
+				}
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      cniObject.Name,
+						Namespace: cniObject.Namespace,
+					},
+				})
+			}
+			return requests
+		}),
+	}
 }
 
 // blank assignment to verify that ReconcileContrailCNI implements reconcile.Reconciler
@@ -93,7 +156,6 @@ type ReconcileContrailCNI struct {
 func (r *ReconcileContrailCNI) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling ContrailCNI")
-	instanceType := "contrailcni"
 	instance := &v1alpha1.ContrailCNI{}
 	ctx := context.TODO()
 
@@ -105,7 +167,25 @@ func (r *ReconcileContrailCNI) Reconcile(request reconcile.Request) (reconcile.R
 	}
 
 	if !instance.GetDeletionTimestamp().IsZero() {
-		return reconcile.Result{}, nil
+		if !hasFinalizer(instance, cleanupFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		if err := r.cleanupNetworks(instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(instance, cleanupFinalizer)
+		return reconcile.Result{}, r.Client.Update(ctx, instance)
+	}
+
+	if !hasFinalizer(instance, cleanupFinalizer) {
+		controllerutil.AddFinalizer(instance, cleanupFinalizer)
+		if err := r.Client.Update(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := instance.Validate(); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	if instance.Spec.ServiceConfiguration.ControlInstance != "" {
@@ -127,43 +207,72 @@ func (r *ReconcileContrailCNI) Reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, err
 	}
 
+	if err := r.ensureNetworkAttachmentDefinitions(instance, instance.Spec.ServiceConfiguration.Networks); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.ensureNamespaceIsolation(instance, instance.Spec.ServiceConfiguration.Namespaces); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	cniDirs := CniDirs{
 		BinariesDirectory: r.ClusterInfo.CNIBinariesDirectory(),
 		DeploymentType:    r.ClusterInfo.DeploymentType(),
+		SecondaryNetwork:  instance.NetworkRole() == v1alpha1.SecondaryCNINetworkRole,
 	}
 
-	var nodesListOptions client.MatchingLabels = instance.Spec.CommonConfiguration.NodeSelector
-	var nodes corev1.NodeList
-	if err := r.Client.List(ctx, &nodes, nodesListOptions); err != nil && errors.IsNotFound(err) {
-		return reconcile.Result{}, nil
-	}
-	jobReplicas := int32(len(nodes.Items))
-	job := GetJob(cniDirs, request.Name, instanceType, &jobReplicas)
-	for idx, container := range job.Spec.Template.Spec.Containers {
+	daemonSet := GetDaemonSet(cniDirs, request.Name, instanceType)
+	for idx, container := range daemonSet.Spec.Template.Spec.Containers {
 		instanceContainer := utils.GetContainerFromList(container.Name, instance.Spec.ServiceConfiguration.Containers)
 		if instanceContainer != nil {
-			(&job.Spec.Template.Spec.Containers[idx]).Image = instanceContainer.Image
+			(&daemonSet.Spec.Template.Spec.Containers[idx]).Image = instanceContainer.Image
 		}
 	}
 
-	if err := instance.PrepareJob(job, instance, request, r.Scheme, r.Client); err != nil {
+	if err := instance.PrepareDaemonSet(daemonSet, instance, request, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	var clusterJob batchv1.Job
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, &clusterJob); err == nil {
-		if *clusterJob.Spec.Completions != jobReplicas || clusterJob.Labels["controller_generation"] != job.Labels["controller_generation"] {
-			_ = r.Client.Delete(ctx, &batchv1.Job{ObjectMeta: v1.ObjectMeta{Name: job.Name, Namespace: job.Namespace}}, client.PropagationPolicy("Background"))
-			return reconcile.Result{RequeueAfter: 5}, nil
-		}
-	} else if errors.IsNotFound(err) {
-		if err := r.Client.Create(ctx, job); err != nil {
-			return reconcile.Result{}, err
-		}
-	} else {
+	if err := r.deleteLegacyJob(request); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	clusterDaemonSet := &appsv1.DaemonSet{ObjectMeta: v1.ObjectMeta{Name: daemonSet.Name, Namespace: daemonSet.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, clusterDaemonSet, func() error {
+		clusterDaemonSet.Labels = daemonSet.Labels
+		clusterDaemonSet.Spec.Selector = daemonSet.Spec.Selector
+		clusterDaemonSet.Spec.Template = daemonSet.Spec.Template
+		return controllerutil.SetControllerReference(instance, clusterDaemonSet, r.Scheme)
+	}); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	err := instance.SetInstanceActive(r.Client, &instance.Status.Active, job, request)
+	err := instance.SetInstanceActive(r.Client, clusterDaemonSet, request)
 	return reconcile.Result{}, err
 }
+
+// deleteLegacyJob removes the one-shot Job which installed the plugin before it was done by a DaemonSet.
+// The Job is looked up first, so that clusters without it are not sent a delete on every reconcile.
+func (r *ReconcileContrailCNI) deleteLegacyJob(request reconcile.Request) error {
+	job := &batchv1.Job{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: request.Name + "-contrailcni-job", Namespace: request.Namespace}, job); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	err := r.Client.Delete(context.TODO(), job, client.PropagationPolicy(v1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func hasFinalizer(object v1.Object, finalizer string) bool {
+	for _, f := range object.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
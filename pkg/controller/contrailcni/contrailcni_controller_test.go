@@ -2,6 +2,7 @@ package contrailcni
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -12,8 +13,10 @@ import (
 	batch "k8s.io/api/batch/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -22,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -30,12 +34,14 @@ import (
 
 	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 	"github.com/Juniper/contrail-operator/pkg/k8s"
+	"github.com/Juniper/contrail-operator/pkg/openshift"
 )
 
 type contrailcniClusterInfoFake struct {
 	clusterName          string
 	cniBinariesDirectory string
 	deploymentType       string
+	networkMTU           int
 }
 
 func (c contrailcniClusterInfoFake) KubernetesClusterName() (string, error) {
@@ -49,6 +55,10 @@ func (c contrailcniClusterInfoFake) DeploymentType() string {
 	return c.deploymentType
 }
 
+func (c contrailcniClusterInfoFake) NetworkMTU() (int, error) {
+	return c.networkMTU, nil
+}
+
 var trueVal = true
 var falseVal = false
 
@@ -106,9 +116,10 @@ func TestContrailCNIController(t *testing.T) {
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+	addNetworkAttachmentDefinitionToScheme(scheme)
 
 	fakeClient := fake.NewFakeClientWithScheme(scheme, contrailcniCR)
-	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s"}
+	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0}
 	reconciler := NewReconciler(fakeClient, scheme, k8s.New(fakeClient, scheme), fakeClusterInfo)
 	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
 	assert.NoError(t, err)
@@ -128,30 +139,30 @@ func TestContrailCNIController(t *testing.T) {
 		assert.Equal(t, expectedOwnerRefs, cm.OwnerReferences)
 	})
 
-	t.Run("should create job for contrailcni", func(t *testing.T) {
-		job := &batchv1.Job{}
+	t.Run("should create daemonset for contrailcni", func(t *testing.T) {
+		ds := &apps.DaemonSet{}
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
-			Name:      "test-contrailcni-contrailcni-job",
+			Name:      "test-contrailcni-contrailcni-daemonset",
 			Namespace: "default",
-		}, job)
+		}, ds)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, job)
+		assert.NotEmpty(t, ds)
 		expectedOwnerRefs := []v1.OwnerReference{{
 			APIVersion: "contrail.juniper.net/v1alpha1", Kind: "ContrailCNI", Name: "test-contrailcni",
 			Controller: &trueVal, BlockOwnerDeletion: &trueVal,
 		}}
-		assert.Equal(t, expectedOwnerRefs, job.OwnerReferences)
-		var expectedCompletions int32 = 0
-		assert.Equal(t, expectedCompletions, *job.Spec.Completions)
+		assert.Equal(t, expectedOwnerRefs, ds.OwnerReferences)
+		assert.Equal(t, map[string]string{"node-role.opencontrail.org": "contrailcni"}, ds.Spec.Template.Spec.NodeSelector)
+		assert.Equal(t, "image1", ds.Spec.Template.Spec.Containers[0].Image)
 	})
 
-	t.Run("Should set ContrailCNI to Active state", func(t *testing.T) {
+	t.Run("Should not set ContrailCNI to Active state before daemonset is scheduled", func(t *testing.T) {
 		cni := &contrail.ContrailCNI{}
 		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{
 			Name:      "test-contrailcni",
 			Namespace: "default",
 		}, cni))
-		assert.Equal(t, cni.Status.Active, true)
+		assert.False(t, cni.Status.Active)
 	})
 }
 
@@ -161,57 +172,69 @@ func TestContrailCNIControllerUpdate(t *testing.T) {
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+	addNetworkAttachmentDefinitionToScheme(scheme)
 
-	var mockCompletions int32 = 6
-	jobCR := &batchv1.Job{
+	legacyJob := &batchv1.Job{
 		ObjectMeta: v1.ObjectMeta{
 			Namespace: "default",
 			Name:      "test-contrailcni-contrailcni-job",
 		},
-		Spec: batchv1.JobSpec{
-			Completions: &mockCompletions,
+	}
+	driftedDaemonSet := &apps.DaemonSet{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-contrailcni-contrailcni-daemonset",
+		},
+		Spec: apps.DaemonSetSpec{
+			Template: core.PodTemplateSpec{Spec: core.PodSpec{
+				Containers: []core.Container{{Name: "vroutercni", Image: "old-image"}},
+			}},
 		},
+		Status: apps.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 1},
+	}
+	podLabels := map[string]string{"contrail_manager": "contrailcni", "contrailcni": "test-contrailcni"}
+	newPod := func(name, nodeName string, ready core.ConditionStatus) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: v1.ObjectMeta{Namespace: "default", Name: name, Labels: podLabels},
+			Spec:       core.PodSpec{NodeName: nodeName},
+			Status:     core.PodStatus{Conditions: []core.PodCondition{{Type: core.PodReady, Status: ready}}},
+		}
 	}
 
-	fakeClient := fake.NewFakeClientWithScheme(scheme, contrailcniCR, jobCR)
-	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s"}
+	fakeClient := fake.NewFakeClientWithScheme(scheme, contrailcniCR, legacyJob, driftedDaemonSet,
+		newPod("cni-pod-1", "node1", core.ConditionTrue), newPod("cni-pod-2", "node2", core.ConditionFalse))
+	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0}
 	reconciler := NewReconciler(fakeClient, scheme, k8s.New(fakeClient, scheme), fakeClusterInfo)
-	t.Run("should have contrailcni job with 6 completions", func(t *testing.T) {
-		job := &batchv1.Job{}
+	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
+	assert.NoError(t, err)
+
+	t.Run("should remove legacy job for contrailcni", func(t *testing.T) {
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
 			Name:      "test-contrailcni-contrailcni-job",
 			Namespace: "default",
-		}, job)
-		assert.NoError(t, err)
-		assert.NotEmpty(t, job)
-		var expectedCompletions int32 = 6
-		assert.Equal(t, &expectedCompletions, job.Spec.Completions)
+		}, &batchv1.Job{})
+		assert.True(t, errors.IsNotFound(err))
 	})
 
-	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
-	assert.NoError(t, err)
-	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
-	assert.NoError(t, err)
-
-	t.Run("should update job for contrailcni", func(t *testing.T) {
-		job := &batchv1.Job{}
+	t.Run("should update daemonset for contrailcni", func(t *testing.T) {
+		ds := &apps.DaemonSet{}
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
-			Name:      "test-contrailcni-contrailcni-job",
+			Name:      "test-contrailcni-contrailcni-daemonset",
 			Namespace: "default",
-		}, job)
+		}, ds)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, job)
-		var expectedCompletions int32 = 0
-		assert.Equal(t, &expectedCompletions, job.Spec.Completions)
+		assert.Equal(t, "image1", ds.Spec.Template.Spec.Containers[0].Image)
+		assert.NotNil(t, ds.Spec.Template.Spec.Containers[0].ReadinessProbe)
 	})
 
-	t.Run("Should set ContrailCNI to Active state", func(t *testing.T) {
+	t.Run("should report installation state of each node", func(t *testing.T) {
 		cni := &contrail.ContrailCNI{}
-		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{
-			Name:      "test-contrailcni",
-			Namespace: "default",
-		}, cni))
-		assert.Equal(t, cni.Status.Active, true)
+		require.NoError(t, fakeClient.Get(context.Background(), contrailcniName, cni))
+		assert.Equal(t, map[string]contrail.ContrailCNINodeStatus{
+			"node1": {Pod: "cni-pod-1", Installed: true},
+			"node2": {Pod: "cni-pod-2", Installed: false},
+		}, cni.Status.Nodes)
+		assert.False(t, cni.Status.Active)
 	})
 }
 
@@ -222,34 +245,121 @@ func TestAdd(t *testing.T) {
 	assert.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
 	assert.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
 	addCases := map[string]struct {
-		manager    *mockManager
-		reconciler *mockReconciler
+		manager     *mockManager
+		reconciler  *mockReconciler
+		clusterInfo contrail.CNIClusterInfo
 	}{
 		"add process suceeds": {
 			manager: &mockManager{
 				scheme: scheme,
 			},
-			reconciler: &mockReconciler{},
+			reconciler:  &mockReconciler{},
+			clusterInfo: contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0},
+		},
+		"add process watching openshift cluster configuration suceeds": {
+			manager: &mockManager{
+				scheme: scheme,
+			},
+			reconciler:  &mockReconciler{},
+			clusterInfo: openshift.ClusterConfig{},
 		},
 	}
 	for name, addCase := range addCases {
 		t.Run(name, func(t *testing.T) {
-			err := add(addCase.manager, addCase.reconciler)
+			err := add(addCase.manager, addCase.reconciler, addCase.clusterInfo)
 			assert.NoError(t, err)
 		})
 	}
 }
 
+func TestContrailCNIActive(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+
+	tests := map[string]struct {
+		generation int64
+		status     apps.DaemonSetStatus
+		active     bool
+	}{
+		"rolled out on all nodes": {
+			generation: 2,
+			status:     apps.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2},
+			active:     true,
+		},
+		"new generation not observed yet": {
+			generation: 3,
+			status:     apps.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2},
+		},
+		"old pods still running": {
+			generation: 2,
+			status:     apps.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 2, UpdatedNumberScheduled: 1, NumberReady: 2},
+		},
+		"not scheduled on any node": {
+			generation: 2,
+			status:     apps.DaemonSetStatus{ObservedGeneration: 2},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cni := contrailcniCR.DeepCopy()
+			ds := &apps.DaemonSet{
+				ObjectMeta: v1.ObjectMeta{Namespace: "default", Name: "test-contrailcni-contrailcni-daemonset", Generation: test.generation},
+				Spec:       apps.DaemonSetSpec{Selector: &v1.LabelSelector{MatchLabels: map[string]string{"contrailcni": "test-contrailcni"}}},
+				Status:     test.status,
+			}
+			fakeClient := fake.NewFakeClientWithScheme(scheme, cni, ds)
+
+			require.NoError(t, cni.SetInstanceActive(fakeClient, ds, reconcile.Request{NamespacedName: contrailcniName}))
+
+			assert.Equal(t, test.active, cni.Status.Active)
+		})
+	}
+}
+
+// deleteRecordingClient records objects deleted through it
+type deleteRecordingClient struct {
+	client.Client
+	deleted []runtime.Object
+}
+
+func (c *deleteRecordingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	c.deleted = append(c.deleted, obj)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestContrailCNIControllerWithoutLegacyJob(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+	addNetworkAttachmentDefinitionToScheme(scheme)
+
+	recordingClient := &deleteRecordingClient{Client: fake.NewFakeClientWithScheme(scheme, contrailcniCR)}
+	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0}
+	reconciler := NewReconciler(recordingClient, scheme, k8s.New(recordingClient, scheme), fakeClusterInfo)
+	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
+	require.NoError(t, err)
+
+	for _, obj := range recordingClient.deleted {
+		_, isJob := obj.(*batchv1.Job)
+		assert.False(t, isJob, "missing legacy job should not be deleted")
+	}
+}
+
 func TestContrailCNIControllerWithControl(t *testing.T) {
 	scheme, err := contrail.SchemeBuilder.Build()
 	require.NoError(t, err)
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+	addNetworkAttachmentDefinitionToScheme(scheme)
 
 	(&contrailcniCR.Spec.ServiceConfiguration).ControlInstance = "control1"
 	fakeClient := fake.NewFakeClientWithScheme(scheme, contrailcniCR, configCR, controlCR)
-	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s"}
+	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0}
 	reconciler := NewReconciler(fakeClient, scheme, k8s.New(fakeClient, scheme), fakeClusterInfo)
 	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
 	assert.NoError(t, err)
@@ -263,12 +373,12 @@ func TestContrailCNIControllerWithControl(t *testing.T) {
 		assert.Error(t, err)
 	})
 
-	t.Run("should not create job for contrailcni", func(t *testing.T) {
-		job := &batchv1.Job{}
+	t.Run("should not create daemonset for contrailcni", func(t *testing.T) {
+		ds := &apps.DaemonSet{}
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
-			Name:      "test-contrailcni-contrailcni-job",
+			Name:      "test-contrailcni-contrailcni-daemonset",
 			Namespace: "default",
-		}, job)
+		}, ds)
 		assert.Error(t, err)
 	})
 
@@ -289,12 +399,12 @@ func TestContrailCNIControllerWithControl(t *testing.T) {
 		assert.Error(t, err)
 	})
 
-	t.Run("should not create job for contrailcni", func(t *testing.T) {
-		job := &batchv1.Job{}
+	t.Run("should not create daemonset for contrailcni", func(t *testing.T) {
+		ds := &apps.DaemonSet{}
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
-			Name:      "test-contrailcni-contrailcni-job",
+			Name:      "test-contrailcni-contrailcni-daemonset",
 			Namespace: "default",
-		}, job)
+		}, ds)
 		assert.Error(t, err)
 	})
 
@@ -321,31 +431,201 @@ func TestContrailCNIControllerWithControl(t *testing.T) {
 		assert.Equal(t, expectedOwnerRefs, cm.OwnerReferences)
 	})
 
-	t.Run("should create job for contrailcni", func(t *testing.T) {
-		job := &batchv1.Job{}
+	t.Run("should create daemonset for contrailcni", func(t *testing.T) {
+		ds := &apps.DaemonSet{}
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
-			Name:      "test-contrailcni-contrailcni-job",
+			Name:      "test-contrailcni-contrailcni-daemonset",
 			Namespace: "default",
-		}, job)
+		}, ds)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, job)
+		assert.NotEmpty(t, ds)
 		expectedOwnerRefs := []v1.OwnerReference{{
 			APIVersion: "contrail.juniper.net/v1alpha1", Kind: "ContrailCNI", Name: "test-contrailcni",
 			Controller: &trueVal, BlockOwnerDeletion: &trueVal,
 		}}
-		assert.Equal(t, expectedOwnerRefs, job.OwnerReferences)
-		var expectedCompletions int32 = 0
-		assert.Equal(t, &expectedCompletions, job.Spec.Completions)
+		assert.Equal(t, expectedOwnerRefs, ds.OwnerReferences)
+		assert.Equal(t, map[string]string{"node-role.opencontrail.org": "contrailcni"}, ds.Spec.Template.Spec.NodeSelector)
+		assert.Equal(t, "image1", ds.Spec.Template.Spec.Containers[0].Image)
 	})
 
-	t.Run("Should set ContrailCNI to Active state", func(t *testing.T) {
+	t.Run("Should not set ContrailCNI to Active state before daemonset is scheduled", func(t *testing.T) {
 		cni := &contrail.ContrailCNI{}
 		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{
 			Name:      "test-contrailcni",
 			Namespace: "default",
 		}, cni))
-		assert.Equal(t, cni.Status.Active, true)
+		assert.False(t, cni.Status.Active)
+	})
+}
+
+func TestContrailCNIControllerNetworks(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+	addNetworkAttachmentDefinitionToScheme(scheme)
+
+	managedLabels := map[string]string{"contrail_manager": "contrailcni", "contrailcni": "test-contrailcni"}
+	newCNI := func(configuration contrail.ContrailCNIConfiguration) *contrail.ContrailCNI {
+		return &contrail.ContrailCNI{
+			ObjectMeta: v1.ObjectMeta{Namespace: contrailcniName.Namespace, Name: contrailcniName.Name},
+			Spec:       contrail.ContrailCNISpec{ServiceConfiguration: configuration},
+		}
+	}
+	staleNAD := &unstructured.Unstructured{}
+	staleNAD.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+	staleNAD.SetNamespace("default")
+	staleNAD.SetName("stale")
+	staleNAD.SetLabels(managedLabels)
+	cni := newCNI(contrail.ContrailCNIConfiguration{
+		NetworkRole: contrail.SecondaryCNINetworkRole,
+		Networks: []contrail.ContrailCNINetwork{
+			{Name: "blue", VirtualNetwork: &contrail.VirtualNetworkFQName{Project: "k8s-default", Name: "blue-vn"}, IPFabricSnat: &trueVal},
+			{Name: "red", Namespace: "other", CIDR: "10.10.0.0/24"},
+		},
+		Namespaces: []contrail.ContrailCNINamespace{
+			{Name: "isolated", Isolated: &trueVal},
+			{Name: "custom", VirtualNetwork: &contrail.VirtualNetworkFQName{Domain: "domain", Project: "project", Name: "vn"}},
+		},
+	})
+	fakeClient := fake.NewFakeClientWithScheme(scheme, cni, staleNAD,
+		&core.Namespace{ObjectMeta: v1.ObjectMeta{Name: "isolated"}},
+		&core.Namespace{ObjectMeta: v1.ObjectMeta{Name: "custom"}},
+		&core.Namespace{ObjectMeta: v1.ObjectMeta{
+			Name:        "previously-isolated",
+			Labels:      map[string]string{"contrail_manager": "contrailcni", "contrailcni": "test-contrailcni", "team": "a"},
+			Annotations: map[string]string{"opencontrail.org/isolation": "true"},
+		}},
+	)
+	fakeClusterInfo := contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0}
+	reconciler := NewReconciler(fakeClient, scheme, k8s.New(fakeClient, scheme), fakeClusterInfo)
+	_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
+	require.NoError(t, err)
+
+	getNAD := func(namespace, name string) (*unstructured.Unstructured, error) {
+		nad := &unstructured.Unstructured{}
+		nad.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, nad)
+		return nad, err
+	}
+
+	t.Run("should create network attachment definition of existing virtual network", func(t *testing.T) {
+		nad, err := getNAD("default", "blue")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"opencontrail.org/network":        `{"domain":"default-domain","project":"k8s-default","name":"blue-vn"}`,
+			"opencontrail.org/ip_fabric_snat": "true",
+		}, nad.GetAnnotations())
+		assert.Equal(t, managedLabels, nad.GetLabels())
+		require.Len(t, nad.GetOwnerReferences(), 1)
+		config, _, _ := unstructured.NestedString(nad.Object, "spec", "config")
+		assert.Contains(t, config, `"name": "blue"`)
+		assert.Contains(t, config, `"type": "contrail-k8s-cni"`)
+		assert.Contains(t, config, `"cluster-name"  : "test-cluster"`)
+	})
+
+	t.Run("should create network attachment definition with subnet in another namespace", func(t *testing.T) {
+		nad, err := getNAD("other", "red")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"opencontrail.org/cidr": "10.10.0.0/24"}, nad.GetAnnotations())
+		assert.Empty(t, nad.GetOwnerReferences())
+	})
+
+	t.Run("should remove network attachment definitions of networks no longer listed", func(t *testing.T) {
+		_, err := getNAD("default", "stale")
+		assert.True(t, errors.IsNotFound(err))
 	})
+
+	t.Run("should annotate namespaces with isolation settings", func(t *testing.T) {
+		namespace := &core.Namespace{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "isolated"}, namespace))
+		assert.Equal(t, map[string]string{"opencontrail.org/isolation": "true"}, namespace.Annotations)
+		assert.Equal(t, managedLabels, namespace.Labels)
+		customNamespace := &core.Namespace{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "custom"}, customNamespace))
+		assert.Equal(t, map[string]string{"opencontrail.org/network": `{"domain":"domain","project":"project","name":"vn"}`}, customNamespace.Annotations)
+	})
+
+	t.Run("should remove isolation settings of namespaces no longer listed", func(t *testing.T) {
+		namespace := &core.Namespace{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "previously-isolated"}, namespace))
+		assert.Empty(t, namespace.Annotations)
+		assert.Equal(t, map[string]string{"team": "a"}, namespace.Labels)
+	})
+
+	t.Run("should not install network configuration on nodes in secondary role", func(t *testing.T) {
+		ds := &apps.DaemonSet{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{
+			Name:      "test-contrailcni-contrailcni-daemonset",
+			Namespace: "default",
+		}, ds))
+		assert.Contains(t, ds.Spec.Template.Spec.Containers[0].Command[2], "rm -f /host/etc_cni/net.d/10-contrail.conf")
+		assert.NotContains(t, ds.Spec.Template.Spec.Containers[0].Command[2], "cp -f /etc/contrailconfigmaps/10-contrail.conf")
+	})
+
+	t.Run("should add cleanup finalizer", func(t *testing.T) {
+		instance := &contrail.ContrailCNI{}
+		require.NoError(t, fakeClient.Get(context.Background(), contrailcniName, instance))
+		assert.Equal(t, []string{cleanupFinalizer}, instance.Finalizers)
+	})
+
+	t.Run("should enqueue contrailcni of labeled network attachment definition", func(t *testing.T) {
+		nad, err := getNAD("other", "red")
+		require.NoError(t, err)
+		handler := enqueueLabeledContrailCNI(fakeClient).(*crhandler.EnqueueRequestsFromMapFunc)
+		assert.Equal(t, []reconcile.Request{{NamespacedName: contrailcniName}}, handler.ToRequests.Map(crhandler.MapObject{Meta: nad, Object: nad}))
+		unlabeled := &core.Namespace{ObjectMeta: v1.ObjectMeta{Name: "unlabeled"}}
+		assert.Empty(t, handler.ToRequests.Map(crhandler.MapObject{Meta: unlabeled, Object: unlabeled}))
+	})
+
+	t.Run("should remove networks and namespace settings when deleted", func(t *testing.T) {
+		instance := &contrail.ContrailCNI{}
+		require.NoError(t, fakeClient.Get(context.Background(), contrailcniName, instance))
+		now := v1.Now()
+		instance.DeletionTimestamp = &now
+		require.NoError(t, fakeClient.Update(context.Background(), instance))
+
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
+		require.NoError(t, err)
+
+		_, err = getNAD("default", "blue")
+		assert.True(t, errors.IsNotFound(err))
+		_, err = getNAD("other", "red")
+		assert.True(t, errors.IsNotFound(err))
+		namespace := &core.Namespace{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "isolated"}, namespace))
+		assert.Empty(t, namespace.Annotations)
+		assert.Empty(t, namespace.Labels)
+		deleted := &contrail.ContrailCNI{}
+		require.NoError(t, fakeClient.Get(context.Background(), contrailcniName, deleted))
+		assert.Empty(t, deleted.Finalizers)
+	})
+
+	t.Run("should reject network with both virtual network and subnet", func(t *testing.T) {
+		invalidCNI := newCNI(contrail.ContrailCNIConfiguration{
+			Networks: []contrail.ContrailCNINetwork{
+				{Name: "blue", VirtualNetwork: &contrail.VirtualNetworkFQName{Project: "k8s-default", Name: "blue-vn"}, CIDR: "10.10.0.0/24"},
+			},
+		})
+		fakeClient := fake.NewFakeClientWithScheme(scheme, invalidCNI)
+		reconciler := NewReconciler(fakeClient, scheme, k8s.New(fakeClient, scheme), fakeClusterInfo)
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: contrailcniName})
+		assert.Error(t, err)
+	})
+}
+
+func TestMultusInstalled(t *testing.T) {
+	assert.False(t, multusInstalled(nil))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	assert.False(t, multusInstalled(mapper))
+	mapper.Add(networkAttachmentDefinitionGVK, meta.RESTScopeNamespace)
+	assert.True(t, multusInstalled(mapper))
+}
+
+func addNetworkAttachmentDefinitionToScheme(scheme *runtime.Scheme) {
+	scheme.AddKnownTypeWithName(networkAttachmentDefinitionGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(networkAttachmentDefinitionGVK.GroupVersion().WithKind("NetworkAttachmentDefinitionList"), &unstructured.UnstructuredList{})
 }
 
 type mockManager struct {
@@ -432,3 +712,21 @@ type mockReconciler struct{}
 func (m *mockReconciler) Reconcile(reconcile.Request) (reconcile.Result, error) {
 	return reconcile.Result{}, nil
 }
+
+func TestContrailCNIConfMTU(t *testing.T) {
+	t.Run("should set MTU of the cluster network", func(t *testing.T) {
+		conf, err := newContrailCNIConf(contrail.ContrailCNISpec{}, contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "openshift", 1400})
+		require.NoError(t, err)
+		var parsed struct{ Contrail map[string]interface{} }
+		require.NoError(t, json.Unmarshal([]byte(conf.String()), &parsed))
+		assert.Equal(t, float64(1400), parsed.Contrail["mtu"])
+	})
+
+	t.Run("should leave plugin default MTU when cluster does not set one", func(t *testing.T) {
+		conf, err := newContrailCNIConf(contrail.ContrailCNISpec{}, contrailcniClusterInfoFake{"test-cluster", "/cni/bin", "k8s", 0})
+		require.NoError(t, err)
+		var parsed struct{ Contrail map[string]interface{} }
+		require.NoError(t, json.Unmarshal([]byte(conf.String()), &parsed))
+		assert.NotContains(t, parsed.Contrail, "mtu")
+	})
+}
@@ -0,0 +1,230 @@
+package contrailcni
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// syncPeriodSeconds is how often the installed plugin is compared with the desired one
+const syncPeriodSeconds = 10
+
+// CniDirs is a struct with data deciding which directories to cover with cni configuration
+type CniDirs struct {
+	BinariesDirectory string
+	DeploymentType    string
+	// SecondaryNetwork leaves the default network configuration of the node to another CNI
+	SecondaryNetwork bool
+}
+
+// GetDaemonSet is a method that returns k8s DaemonSet object filled with containers configuration contrail CNI plugin.
+// The container installs the plugin on start and then periodically reinstalls it whenever binaries or
+// configuration on the node drift from the ones in the image and the config map.
+func GetDaemonSet(cniDir CniDirs, requestName, instanceType string) *apps.DaemonSet {
+	confDestinations := []string{"/host/etc_cni/net.d/10-contrail.conf"}
+	if cniDir.DeploymentType == "openshift" {
+		confDestinations = append(confDestinations, "/etc/kubernetes/cni/net.d/10-contrail.conf")
+	}
+	installSteps := []string{
+		"mkdir -p /var/lib/contrail/ports/vm",
+		"cp -f /usr/bin/contrail-k8s-cni /host/opt_cni_bin",
+		"chmod 0755 /host/opt_cni_bin/contrail-k8s-cni",
+		"tar -C /host/opt_cni_bin -xzf /opt/cni-v0.3.0.tgz",
+	}
+	installedCheck := sameContentCommand("/usr/bin/contrail-k8s-cni", "/host/opt_cni_bin/contrail-k8s-cni")
+	if cniDir.SecondaryNetwork {
+		// Pods get contrail interfaces only through Multus NetworkAttachmentDefinitions carrying the configuration
+		for _, destination := range confDestinations {
+			installSteps = append(installSteps, "rm -f "+destination)
+			installedCheck += " && [ ! -e " + destination + " ]"
+		}
+	} else {
+		if cniDir.DeploymentType == "openshift" {
+			confDestinations = append(confDestinations, "/var/run/multus/cni/net.d/80-openshift-network.conf")
+		}
+		for _, destination := range confDestinations {
+			installSteps = append(installSteps,
+				"mkdir -p "+path.Dir(destination),
+				"cp -f /etc/contrailconfigmaps/10-contrail.conf "+destination)
+			installedCheck += " && " + sameContentCommand("/etc/contrailconfigmaps/10-contrail.conf", destination)
+		}
+	}
+	installCommand := strings.Join(installSteps, " && ")
+
+	var cniContainer = core.Container{
+		Name:  "vroutercni",
+		Image: "hub.juniper.net/contrail-nightly/contrail-kubernetes-cni-init:master.latest",
+		Command: []string{"sh", "-c",
+			"install_cni() { " + installCommand + "; } && " +
+				"install_cni && " +
+				"while true; do " +
+				"sleep " + strconv.Itoa(syncPeriodSeconds) + "; " +
+				"(" + installedCheck + ") || install_cni; " +
+				"done"},
+		ReadinessProbe: &core.Probe{
+			Handler: core.Handler{
+				Exec: &core.ExecAction{Command: []string{"sh", "-c", installedCheck}},
+			},
+			PeriodSeconds: syncPeriodSeconds,
+		},
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      "var-lib-contrail",
+				MountPath: "/var/lib/contrail",
+			},
+			{
+				Name:      "cni-config-files",
+				MountPath: "/host/etc_cni",
+			},
+			{
+				Name:      "cni-bin",
+				MountPath: "/host/opt_cni_bin",
+			},
+			{
+				Name:      "var-log-contrail-cni",
+				MountPath: "/host/log_cni",
+			},
+			{
+				Name:      "vrouter-logs",
+				MountPath: "/var/log/contrail",
+			},
+			{
+				Name:      "configmap-volume",
+				MountPath: "/etc/contrailconfigmaps",
+			},
+			{
+				Name:      "etc-kubernetes-cni",
+				MountPath: "/etc/kubernetes/cni",
+			},
+			{
+				Name:      "multus-cni",
+				MountPath: "/var/run/multus",
+			},
+		},
+		ImagePullPolicy: "IfNotPresent",
+	}
+
+	var podVolumes = []core.Volume{
+		{
+			Name: "vrouter-logs",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: "/var/log/contrail/vrouter",
+				},
+			},
+		},
+		{
+			Name: "var-log-contrail-cni",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: "/var/log/contrail/cni",
+				},
+			},
+		},
+		{
+			Name: "cni-config-files",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: "/etc/cni",
+				},
+			},
+		},
+		{
+			Name: "var-lib-contrail",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: "/var/lib/contrail",
+				},
+			},
+		},
+		{
+			Name: "cni-bin",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: cniDir.BinariesDirectory,
+				},
+			},
+		},
+		{
+			Name: "configmap-volume",
+			VolumeSource: core.VolumeSource{
+				ConfigMap: &core.ConfigMapVolumeSource{
+					LocalObjectReference: core.LocalObjectReference{
+						Name: requestName + "-" + instanceType + "-configuration",
+					},
+				},
+			},
+		},
+		{
+			Name: "etc-kubernetes-cni",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: "/etc/kubernetes/cni",
+				},
+			},
+		},
+		{
+			Name: "multus-cni",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: "/var/run/multus",
+				},
+			},
+		},
+	}
+
+	var podTolerations = []core.Toleration{
+		{
+			Operator: "Exists",
+			Effect:   "NoSchedule",
+		},
+		{
+			Operator: "Exists",
+			Effect:   "NoExecute",
+		},
+	}
+
+	var podSpec = core.PodSpec{
+		Volumes:     podVolumes,
+		Containers:  []core.Container{cniContainer},
+		DNSPolicy:   "ClusterFirst",
+		HostNetwork: true,
+		Tolerations: podTolerations,
+	}
+
+	var daemonSetSelector = meta.LabelSelector{
+		MatchLabels: map[string]string{"app": "contrailcni"},
+	}
+
+	var daemonSetTemplate = core.PodTemplateSpec{
+		ObjectMeta: meta.ObjectMeta{},
+		Spec:       podSpec,
+	}
+
+	var daemonSet = apps.DaemonSet{
+		TypeMeta: meta.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "contrailcni",
+			Namespace: "default",
+		},
+		Spec: apps.DaemonSetSpec{
+			Selector: &daemonSetSelector,
+			Template: daemonSetTemplate,
+		},
+	}
+
+	return &daemonSet
+}
+
+// sameContentCommand returns shell test succeeding when both files exist and have the same content
+func sameContentCommand(source, destination string) string {
+	return fmt.Sprintf(`[ "$(md5sum 2>/dev/null < %s)" = "$(md5sum 2>/dev/null < %s)" ]`, source, destination)
+}
@@ -0,0 +1,93 @@
+package contrailcni_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/controller/contrailcni"
+)
+
+var requestName = "test-request"
+var instanceType = "contrailcni"
+
+var testBinariesPath = "/test/cni/bin"
+
+var expectedCniBinVolume = core.Volume{
+	Name: "cni-bin",
+	VolumeSource: core.VolumeSource{
+		HostPath: &core.HostPathVolumeSource{
+			Path: testBinariesPath,
+		},
+	},
+}
+
+var binariesInstallCommand = "mkdir -p /var/lib/contrail/ports/vm && " +
+	"cp -f /usr/bin/contrail-k8s-cni /host/opt_cni_bin && " +
+	"chmod 0755 /host/opt_cni_bin/contrail-k8s-cni && " +
+	"tar -C /host/opt_cni_bin -xzf /opt/cni-v0.3.0.tgz"
+
+var k8sInstallCommand = binariesInstallCommand +
+	" && mkdir -p /host/etc_cni/net.d && " +
+	"cp -f /etc/contrailconfigmaps/10-contrail.conf /host/etc_cni/net.d/10-contrail.conf"
+
+var openshiftInstallCommand = k8sInstallCommand +
+	" && mkdir -p /etc/kubernetes/cni/net.d && " +
+	"cp -f /etc/contrailconfigmaps/10-contrail.conf /etc/kubernetes/cni/net.d/10-contrail.conf && " +
+	"mkdir -p /var/run/multus/cni/net.d && " +
+	"cp -f /etc/contrailconfigmaps/10-contrail.conf /var/run/multus/cni/net.d/80-openshift-network.conf"
+
+var secondaryInstallCommand = binariesInstallCommand +
+	" && rm -f /host/etc_cni/net.d/10-contrail.conf"
+
+var binariesInstalledCheck = `[ "$(md5sum 2>/dev/null < /usr/bin/contrail-k8s-cni)" = "$(md5sum 2>/dev/null < /host/opt_cni_bin/contrail-k8s-cni)" ]`
+
+var k8sInstalledCheck = binariesInstalledCheck +
+	` && [ "$(md5sum 2>/dev/null < /etc/contrailconfigmaps/10-contrail.conf)" = "$(md5sum 2>/dev/null < /host/etc_cni/net.d/10-contrail.conf)" ]`
+
+var openshiftInstalledCheck = k8sInstalledCheck +
+	` && [ "$(md5sum 2>/dev/null < /etc/contrailconfigmaps/10-contrail.conf)" = "$(md5sum 2>/dev/null < /etc/kubernetes/cni/net.d/10-contrail.conf)" ]` +
+	` && [ "$(md5sum 2>/dev/null < /etc/contrailconfigmaps/10-contrail.conf)" = "$(md5sum 2>/dev/null < /var/run/multus/cni/net.d/80-openshift-network.conf)" ]`
+
+var secondaryInstalledCheck = binariesInstalledCheck +
+	" && [ ! -e /host/etc_cni/net.d/10-contrail.conf ]"
+
+func syncCommand(installCommand, installedCheck string) []string {
+	return []string{"sh", "-c",
+		"install_cni() { " + installCommand + "; } && install_cni && " +
+			"while true; do sleep 10; (" + installedCheck + ") || install_cni; done"}
+}
+
+func TestGetDaemonSetK8s(t *testing.T) {
+	testCNIDirs := contrailcni.CniDirs{
+		BinariesDirectory: testBinariesPath,
+		DeploymentType:    "k8s",
+	}
+	ds := contrailcni.GetDaemonSet(testCNIDirs, requestName, instanceType)
+	assert.Contains(t, ds.Spec.Template.Spec.Volumes, expectedCniBinVolume)
+	assert.Equal(t, syncCommand(k8sInstallCommand, k8sInstalledCheck), ds.Spec.Template.Spec.Containers[0].Command)
+	assert.Equal(t, []string{"sh", "-c", k8sInstalledCheck}, ds.Spec.Template.Spec.Containers[0].ReadinessProbe.Exec.Command)
+}
+
+func TestGetDaemonSetOpenshift(t *testing.T) {
+	testCNIDirs := contrailcni.CniDirs{
+		BinariesDirectory: testBinariesPath,
+		DeploymentType:    "openshift",
+	}
+	ds := contrailcni.GetDaemonSet(testCNIDirs, requestName, instanceType)
+	assert.Contains(t, ds.Spec.Template.Spec.Volumes, expectedCniBinVolume)
+	assert.Equal(t, syncCommand(openshiftInstallCommand, openshiftInstalledCheck), ds.Spec.Template.Spec.Containers[0].Command)
+	assert.Equal(t, []string{"sh", "-c", openshiftInstalledCheck}, ds.Spec.Template.Spec.Containers[0].ReadinessProbe.Exec.Command)
+}
+
+func TestGetDaemonSetSecondaryNetwork(t *testing.T) {
+	testCNIDirs := contrailcni.CniDirs{
+		BinariesDirectory: testBinariesPath,
+		DeploymentType:    "k8s",
+		SecondaryNetwork:  true,
+	}
+	ds := contrailcni.GetDaemonSet(testCNIDirs, requestName, instanceType)
+	assert.Equal(t, syncCommand(secondaryInstallCommand, secondaryInstalledCheck), ds.Spec.Template.Spec.Containers[0].Command)
+	assert.Equal(t, []string{"sh", "-c", secondaryInstalledCheck}, ds.Spec.Template.Spec.Containers[0].ReadinessProbe.Exec.Command)
+}
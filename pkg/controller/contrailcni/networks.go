@@ -0,0 +1,185 @@
+package contrailcni
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/label"
+)
+
+// Annotations read by Kubemanager from namespaces and NetworkAttachmentDefinitions
+const (
+	networkAnnotation            = "opencontrail.org/network"
+	cidrAnnotation               = "opencontrail.org/cidr"
+	ipFabricForwardingAnnotation = "opencontrail.org/ip_fabric_forwarding"
+	ipFabricSnatAnnotation       = "opencontrail.org/ip_fabric_snat"
+	isolationAnnotation          = "opencontrail.org/isolation"
+)
+
+var networkAttachmentDefinitionGVK = schema.GroupVersionKind{
+	Group:   "k8s.cni.cncf.io",
+	Version: "v1",
+	Kind:    "NetworkAttachmentDefinition",
+}
+
+// ensureNetworkAttachmentDefinitions creates a Multus NetworkAttachmentDefinition for every given network
+// and removes the ones created for networks which are no longer listed.
+func (r *ReconcileContrailCNI) ensureNetworkAttachmentDefinitions(instance *v1alpha1.ContrailCNI, networks []v1alpha1.ContrailCNINetwork) error {
+	ctx := context.TODO()
+	labels := label.New(instanceType, instance.Name)
+	desired := map[string]bool{}
+	for _, network := range networks {
+		namespace := network.NetworkNamespace(instance.Namespace)
+		desired[namespace+"/"+network.Name] = true
+		conf, err := newContrailCNIConf(instance.Spec, r.ClusterInfo)
+		if err != nil {
+			return err
+		}
+		conf.Name = network.Name
+		nad := &unstructured.Unstructured{}
+		nad.SetGroupVersionKind(networkAttachmentDefinitionGVK)
+		nad.SetName(network.Name)
+		nad.SetNamespace(namespace)
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, nad, func() error {
+			nad.SetLabels(labels)
+			nad.SetAnnotations(networkAnnotations(network))
+			if err := unstructured.SetNestedField(nad.Object, conf.String(), "spec", "config"); err != nil {
+				return err
+			}
+			// Owner references cannot point to another namespace, such definitions are removed by label
+			if namespace != instance.Namespace {
+				return nil
+			}
+			return controllerutil.SetControllerReference(instance, nad, r.Scheme)
+		}); err != nil {
+			return err
+		}
+	}
+
+	nads := &unstructured.UnstructuredList{}
+	nads.SetGroupVersionKind(networkAttachmentDefinitionGVK.GroupVersion().WithKind("NetworkAttachmentDefinitionList"))
+	// Without Multus there are no definitions to remove
+	if err := r.Client.List(ctx, nads, client.MatchingLabels(labels)); meta.IsNoMatchError(err) && len(desired) == 0 {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for idx := range nads.Items {
+		nad := &nads.Items[idx]
+		if desired[nad.GetNamespace()+"/"+nad.GetName()] {
+			continue
+		}
+		if err := r.Client.Delete(ctx, nad); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func networkAnnotations(network v1alpha1.ContrailCNINetwork) map[string]string {
+	annotations := map[string]string{}
+	if network.VirtualNetwork != nil {
+		annotations[networkAnnotation] = fqNameAnnotation(*network.VirtualNetwork)
+	}
+	if network.CIDR != "" {
+		annotations[cidrAnnotation] = network.CIDR
+	}
+	if network.IPFabricForwarding != nil {
+		annotations[ipFabricForwardingAnnotation] = strconv.FormatBool(*network.IPFabricForwarding)
+	}
+	if network.IPFabricSnat != nil {
+		annotations[ipFabricSnatAnnotation] = strconv.FormatBool(*network.IPFabricSnat)
+	}
+	return annotations
+}
+
+// ensureNamespaceIsolation annotates namespaces with the given isolation settings, so that Kubemanager
+// places their pods in the requested virtual networks. Namespaces which are not created yet are
+// annotated once they appear. Settings of namespaces which are no longer listed are removed.
+func (r *ReconcileContrailCNI) ensureNamespaceIsolation(instance *v1alpha1.ContrailCNI, settings []v1alpha1.ContrailCNINamespace) error {
+	ctx := context.TODO()
+	labels := label.New(instanceType, instance.Name)
+	desired := map[string]v1alpha1.ContrailCNINamespace{}
+	for _, namespace := range settings {
+		desired[namespace.Name] = namespace
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.Client.List(ctx, namespaces); err != nil {
+		return err
+	}
+	for idx := range namespaces.Items {
+		namespace := &namespaces.Items[idx]
+		namespaceSettings, isDesired := desired[namespace.Name]
+		managed := namespace.Labels[instanceType] == instance.Name
+		if !isDesired && !managed {
+			continue
+		}
+		current := namespace.DeepCopy()
+		if namespace.Labels == nil {
+			namespace.Labels = map[string]string{}
+		}
+		if namespace.Annotations == nil {
+			namespace.Annotations = map[string]string{}
+		}
+		delete(namespace.Annotations, isolationAnnotation)
+		delete(namespace.Annotations, networkAnnotation)
+		if isDesired {
+			for key, value := range labels {
+				namespace.Labels[key] = value
+			}
+			if namespaceSettings.Isolated != nil {
+				namespace.Annotations[isolationAnnotation] = strconv.FormatBool(*namespaceSettings.Isolated)
+			}
+			if namespaceSettings.VirtualNetwork != nil {
+				namespace.Annotations[networkAnnotation] = fqNameAnnotation(*namespaceSettings.VirtualNetwork)
+			}
+		} else {
+			for key := range labels {
+				delete(namespace.Labels, key)
+			}
+		}
+		if reflect.DeepEqual(current.Labels, namespace.Labels) && reflect.DeepEqual(current.Annotations, namespace.Annotations) {
+			continue
+		}
+		if err := r.Client.Update(ctx, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupNetworks removes the NetworkAttachmentDefinitions and namespace isolation settings of the
+// instance. Definitions in other namespaces and namespace settings are not garbage collected with the
+// instance, so they are removed before its finalizer is.
+func (r *ReconcileContrailCNI) cleanupNetworks(instance *v1alpha1.ContrailCNI) error {
+	if err := r.ensureNetworkAttachmentDefinitions(instance, nil); err != nil {
+		return err
+	}
+	return r.ensureNamespaceIsolation(instance, nil)
+}
+
+// multusInstalled returns true when the API server serves NetworkAttachmentDefinitions.
+func multusInstalled(mapper meta.RESTMapper) bool {
+	if mapper == nil {
+		return false
+	}
+	_, err := mapper.RESTMapping(networkAttachmentDefinitionGVK.GroupKind(), networkAttachmentDefinitionGVK.Version)
+	return err == nil
+}
+
+func fqNameAnnotation(fqName v1alpha1.VirtualNetworkFQName) string {
+	value, _ := json.Marshal(fqName.WithDefaults())
+	return string(value)
+}
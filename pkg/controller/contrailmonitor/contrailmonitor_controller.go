@@ -2,7 +2,6 @@ package contrailmonitor
 
 import (
 	"context"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,22 +28,23 @@ var log = logf.Log.WithName("controller_contrailmonitor")
 
 // Add creates a new Contrailmonitor Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+// On OpenShift clusters Postgres, Memcached and Keystone are not monitored.
+func Add(mgr manager.Manager, isOpenShift bool) error {
+	return add(mgr, newReconciler(mgr, isOpenShift), isOpenShift)
 }
 
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, isOpenShift bool) reconcile.Reconciler {
 	return NewReconciler(
-		mgr.GetClient(), mgr.GetScheme(), k8s.New(mgr.GetClient(), mgr.GetScheme()))
+		mgr.GetClient(), mgr.GetScheme(), k8s.New(mgr.GetClient(), mgr.GetScheme()), isOpenShift)
 }
 
 // NewReconciler returns a new reconcile.Reconciler
-func NewReconciler(client client.Client, scheme *runtime.Scheme, kubernetes *k8s.Kubernetes) *ReconcileContrailmonitor {
-	return &ReconcileContrailmonitor{client: client, scheme: scheme, kubernetes: kubernetes}
+func NewReconciler(client client.Client, scheme *runtime.Scheme, kubernetes *k8s.Kubernetes, isOpenShift bool) *ReconcileContrailmonitor {
+	return &ReconcileContrailmonitor{client: client, scheme: scheme, kubernetes: kubernetes, isOpenShift: isOpenShift}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, isOpenShift bool) error {
 	// Create a new controller
 	c, err := controller.New("contrailmonitor-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -57,9 +57,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	_, ok := os.LookupEnv("CLUSTER_TYPE")
-	if !ok {
-
+	if !isOpenShift {
 		err = c.Watch(&source.Kind{Type: &contrailv1alpha1.Postgres{}}, &handler.EnqueueRequestForOwner{
 			OwnerType: &contrailv1alpha1.Contrailmonitor{},
 		})
@@ -135,9 +133,10 @@ var _ reconcile.Reconciler = &ReconcileContrailmonitor{}
 type ReconcileContrailmonitor struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	scheme     *runtime.Scheme
-	kubernetes *k8s.Kubernetes
+	client      client.Client
+	scheme      *runtime.Scheme
+	kubernetes  *k8s.Kubernetes
+	isOpenShift bool
 }
 
 // Reconcile reads that state of the cluster for a Contrailmonitor object and makes changes based on the state read
@@ -168,10 +167,7 @@ func (r *ReconcileContrailmonitor) Reconcile(request reconcile.Request) (reconci
 		return reconcile.Result{}, nil
 	}
 
-	_, ok := os.LookupEnv("CLUSTER_TYPE")
-
-	if !ok {
-
+	if !r.isOpenShift {
 		psql, err := r.getPostgres(instance)
 		if err != nil {
 			return reconcile.Result{}, err
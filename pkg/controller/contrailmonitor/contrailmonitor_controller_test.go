@@ -17,6 +17,7 @@ import (
 
 	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 	mocking "github.com/Juniper/contrail-operator/pkg/controller/mock"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
 )
 
 type TestCase struct {
@@ -41,9 +42,42 @@ func TestContrailmonitorControllertwo(t *testing.T) {
 		}
 		cl := fake.NewFakeClientWithScheme(scheme, initObjs...)
 		mgr := &mocking.MockManager{Client: &cl, Scheme: scheme}
-		err := Add(mgr)
+		err := Add(mgr, false)
 		assert.NoError(t, err)
 	})
+
+	t.Run("Add method/watchers Verification on OpenShift", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, contrailmonitorCR)
+		mgr := &mocking.MockManager{Client: &cl, Scheme: scheme}
+		err := Add(mgr, true)
+		assert.NoError(t, err)
+	})
+}
+
+func TestContrailmonitorControllerOpenShift(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	monitor := contrailmonitorCR.DeepCopy()
+	monitor.DeletionTimestamp = nil
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: monitor.Name, Namespace: monitor.Namespace}}
+
+	t.Run("should require postgres on kubernetes", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, monitor)
+		r := NewReconciler(cl, scheme, k8s.New(cl, scheme), false)
+		_, err := r.Reconcile(req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "postgres")
+	})
+
+	t.Run("should not look up postgres, memcached and keystone on OpenShift", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, monitor)
+		r := NewReconciler(cl, scheme, k8s.New(cl, scheme), true)
+		_, err := r.Reconcile(req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rabbitmq")
+	})
 }
 
 func TestContrailmonitorOne(t *testing.T) {
@@ -33,7 +33,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return NewReconciler(mgr.GetClient(), mgr.GetScheme(), k8s.New(mgr.GetClient(), mgr.GetScheme()))
+	return NewReconciler(mgr.GetClient(), mgr.GetScheme(), k8s.New(mgr.GetClient(), mgr.GetScheme()), execToPod)
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -61,12 +61,24 @@ type ReconcileFernetKeyManager struct {
 	client     client.Client
 	scheme     *runtime.Scheme
 	kubernetes *k8s.Kubernetes
+	executor   PodExecutor
 }
 
+// PodExecutor runs command in the container of the pod and returns its standard output.
+type PodExecutor func(command []string, containerName, podName, namespace string) (string, error)
+
 // NewReconciler is used to create a new ReconcileKeystone
 func NewReconciler(
-	client client.Client, scheme *runtime.Scheme, kubernetes *k8s.Kubernetes) *ReconcileFernetKeyManager {
-	return &ReconcileFernetKeyManager{client: client, scheme: scheme, kubernetes: kubernetes}
+	client client.Client, scheme *runtime.Scheme, kubernetes *k8s.Kubernetes, executor PodExecutor) *ReconcileFernetKeyManager {
+	return &ReconcileFernetKeyManager{client: client, scheme: scheme, kubernetes: kubernetes, executor: executor}
+}
+
+func execToPod(command []string, containerName, podName, namespace string) (string, error) {
+	stdout, stderr, err := k8s.ExecToPodThroughAPI(command, containerName, podName, namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, stderr)
+	}
+	return stdout, nil
 }
 
 // Reconcile reads that state of the cluster for a FernetKeyManager object and makes changes based on the state read
@@ -111,10 +123,28 @@ func (r *ReconcileFernetKeyManager) Reconcile(request reconcile.Request) (reconc
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	if err := r.rotateKeys(keySecret, maxActiveKeys); err != nil {
+	fernetKeys := keyRepository{
+		secret:           keySecret,
+		mountPath:        "/etc/keystone/fernet-keys",
+		maxActiveKeys:    maxActiveKeys,
+		rotationInterval: time.Second * time.Duration(rotationInterval),
+		status:           &fernetKeyManager.Status.FernetKeys,
+	}
+	requeueAfter, err := r.reconcileKeyRepository(fernetKeyManager, fernetKeys)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if fernetKeyManager.Spec.CredentialKeysSecretName != "" {
+		credentialKeysRequeueAfter, err := r.reconcileCredentialKeys(fernetKeyManager)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if credentialKeysRequeueAfter < requeueAfter {
+			requeueAfter = credentialKeysRequeueAfter
+		}
+	}
+
 	fernetKeyManager.Status.SecretName = keySecretName
 	if err := r.client.Status().Update(context.TODO(), fernetKeyManager); err != nil {
 		return reconcile.Result{}, err
@@ -122,7 +152,7 @@ func (r *ReconcileFernetKeyManager) Reconcile(request reconcile.Request) (reconc
 
 	return reconcile.Result{
 		Requeue:      true,
-		RequeueAfter: time.Second * time.Duration(rotationInterval),
+		RequeueAfter: requeueAfter,
 	}, nil
 }
 
@@ -135,20 +165,20 @@ func (r *ReconcileFernetKeyManager) getSecret(secretName, secretNamespace string
 	return secret, nil
 }
 
-func (r *ReconcileFernetKeyManager) rotateKeys(sc *core.Secret, maxActiveKeys int) error {
+func (r *ReconcileFernetKeyManager) rotateKeys(sc *core.Secret, maxActiveKeys int) (int, error) {
 	keys := sc.Data
 	existingKeysIndices := make([]int, 0, len(keys))
 	for k := range keys {
 		key, err := strconv.Atoi(k)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		existingKeysIndices = append(existingKeysIndices, key)
 	}
 
 	activeKeysNumber := len(existingKeysIndices)
 	if activeKeysNumber == 0 {
-		return fmt.Errorf("key repository not initialized, secret is empty")
+		return 0, fmt.Errorf("key repository not initialized, secret is empty")
 	}
 	log.Info(fmt.Sprintf("Starting rotation with %d keys", activeKeysNumber))
 
@@ -164,7 +194,7 @@ func (r *ReconcileFernetKeyManager) rotateKeys(sc *core.Secret, maxActiveKeys in
 
 	newKey, err := generateKey()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	keys[stagedKeyIndex] = newKey
 	log.Info("Promoted key 0 to be primary key")
@@ -176,5 +206,5 @@ func (r *ReconcileFernetKeyManager) rotateKeys(sc *core.Secret, maxActiveKeys in
 	}
 
 	sc.Data = keys
-	return r.client.Update(context.TODO(), sc)
+	return maxKeyIndex + 1, r.client.Update(context.TODO(), sc)
 }
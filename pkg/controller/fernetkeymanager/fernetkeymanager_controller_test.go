@@ -55,7 +55,7 @@ func TestFernetKeyManager(t *testing.T) {
 		}
 		cl := fake.NewFakeClientWithScheme(scheme, fernetKeyManager, initSecret)
 		r := fernetkeymanager.NewReconciler(
-			cl, scheme, k8s.New(cl, scheme),
+			cl, scheme, k8s.New(cl, scheme), failingExecutor,
 		)
 		_, err := r.Reconcile(req)
 		assert.NoError(t, err)
@@ -104,7 +104,7 @@ func TestFernetKeyManager(t *testing.T) {
 		}
 		cl := fake.NewFakeClientWithScheme(scheme, fernetKeyManager, initSecret)
 		r := fernetkeymanager.NewReconciler(
-			cl, scheme, k8s.New(cl, scheme),
+			cl, scheme, k8s.New(cl, scheme), failingExecutor,
 		)
 		res, err := r.Reconcile(req)
 		assert.NoError(t, err)
@@ -127,14 +127,13 @@ func TestFernetKeyManager(t *testing.T) {
 			})
 		})
 
-		t.Run("then status should contain information about secret name", func(t *testing.T) {
-			expectedStatus := contrail.FernetKeyManagerStatus{
-				SecretName: "fernet-keys-repository",
-			}
+		t.Run("then status should contain information about secret name and rotation", func(t *testing.T) {
 			k := &contrail.FernetKeyManager{}
 			err = cl.Get(context.Background(), req.NamespacedName, k)
 			assert.NoError(t, err)
-			assert.Equal(t, expectedStatus, k.Status)
+			assert.Equal(t, "fernet-keys-repository", k.Status.SecretName)
+			assert.Equal(t, maxIndexFromKeyRepository(initSecret)+1, k.Status.FernetKeys.CurrentKeyIndex)
+			assert.NotNil(t, k.Status.FernetKeys.LastRotationTime)
 		})
 
 	})
@@ -157,7 +156,7 @@ func TestFernetKeyManager(t *testing.T) {
 		}
 		cl := fake.NewFakeClientWithScheme(scheme, fernetKeyManager, initSecret)
 		r := fernetkeymanager.NewReconciler(
-			cl, scheme, k8s.New(cl, scheme),
+			cl, scheme, k8s.New(cl, scheme), failingExecutor,
 		)
 		res, err := r.Reconcile(req)
 		assert.NoError(t, err)
@@ -181,14 +180,13 @@ func TestFernetKeyManager(t *testing.T) {
 			})
 		})
 
-		t.Run("then status should contain information about secret name", func(t *testing.T) {
-			expectedStatus := contrail.FernetKeyManagerStatus{
-				SecretName: "fernet-keys-repository",
-			}
+		t.Run("then status should contain information about secret name and rotation", func(t *testing.T) {
 			k := &contrail.FernetKeyManager{}
 			err = cl.Get(context.Background(), req.NamespacedName, k)
 			assert.NoError(t, err)
-			assert.Equal(t, expectedStatus, k.Status)
+			assert.Equal(t, "fernet-keys-repository", k.Status.SecretName)
+			assert.Equal(t, maxIndexFromKeyRepository(initSecret)+1, k.Status.FernetKeys.CurrentKeyIndex)
+			assert.NotNil(t, k.Status.FernetKeys.LastRotationTime)
 		})
 	})
 }
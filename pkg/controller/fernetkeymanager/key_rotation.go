@@ -0,0 +1,192 @@
+package fernetkeymanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contrailv1alpha1 "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+const (
+	// Time after which pods are checked again when they have not loaded the keys yet.
+	podsSyncRetryInterval = 30 * time.Second
+	// Default time between credential keys rotations (30 days).
+	defaultCredentialKeysRotationInterval = 2592000
+	// Keystone default value of credential max_active_keys.
+	credentialMaxActiveKeys = 3
+)
+
+type keyRepository struct {
+	secret           *core.Secret
+	mountPath        string
+	maxActiveKeys    int
+	rotationInterval time.Duration
+	status           *contrailv1alpha1.KeyRotationStatus
+}
+
+// reconcileKeyRepository promotes staged key to primary when rotation interval has passed and
+// every keystone pod has loaded the staged key. It returns time after which it should be called again.
+func (r *ReconcileFernetKeyManager) reconcileKeyRepository(fkm *contrailv1alpha1.FernetKeyManager, repo keyRepository) (time.Duration, error) {
+	now := time.Now()
+	if repo.status.LastRotationTime != nil {
+		nextRotation := repo.status.LastRotationTime.Add(repo.rotationInterval)
+		if now.Before(nextRotation) {
+			return nextRotation.Sub(now), nil
+		}
+	}
+	_, loaded, err := r.keysLoadedByPods(fkm, repo)
+	if err != nil {
+		return 0, err
+	}
+	if !loaded {
+		log.Info(fmt.Sprintf("Postponing rotation of %s, staged key not loaded by all keystone pods", repo.secret.Name))
+		return podsSyncRetryInterval, nil
+	}
+	primaryKeyIndex, err := r.rotateKeys(repo.secret, repo.maxActiveKeys)
+	if err != nil {
+		return 0, err
+	}
+	repo.status.CurrentKeyIndex = primaryKeyIndex
+	repo.status.LastRotationTime = &meta.Time{Time: now}
+	return repo.rotationInterval, nil
+}
+
+// reconcileCredentialKeys rotates credential keys. After every rotation credentials stored in
+// keystone are re-encrypted with the new primary key, so that keys purged by the next rotation
+// are no longer needed.
+func (r *ReconcileFernetKeyManager) reconcileCredentialKeys(fkm *contrailv1alpha1.FernetKeyManager) (time.Duration, error) {
+	if len(fkm.Spec.KeystonePodSelector) == 0 {
+		log.Info("Credential keys are not rotated, keystone pod selector is not defined")
+		return podsSyncRetryInterval, nil
+	}
+	keySecret, err := r.getSecret(fkm.Spec.CredentialKeysSecretName, fkm.Namespace)
+	if errors.IsNotFound(err) {
+		return podsSyncRetryInterval, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	rotationInterval := fkm.Spec.CredentialKeysRotationInterval
+	if rotationInterval == 0 {
+		rotationInterval = defaultCredentialKeysRotationInterval
+	}
+	repo := keyRepository{
+		secret:           keySecret,
+		mountPath:        "/etc/keystone/credential-keys",
+		maxActiveKeys:    credentialMaxActiveKeys,
+		rotationInterval: time.Second * time.Duration(rotationInterval),
+		status:           &fkm.Status.CredentialKeys,
+	}
+
+	if repo.status.LastRotationTime == nil {
+		// Credential keys are created together with keystone, first rotation is due after full interval.
+		primaryKeyIndex, err := maxKeyIndex(keySecret)
+		if err != nil {
+			return 0, err
+		}
+		repo.status.CurrentKeyIndex = primaryKeyIndex
+		repo.status.LastRotationTime = &meta.Time{Time: time.Now()}
+		return repo.rotationInterval, nil
+	}
+
+	if fkm.Status.CredentialsMigrationPending {
+		podName, loaded, err := r.keysLoadedByPods(fkm, repo)
+		if err != nil {
+			return 0, err
+		}
+		if !loaded || podName == "" {
+			log.Info("Postponing credentials migration, new primary key not loaded by all keystone pods")
+			return podsSyncRetryInterval, nil
+		}
+		command := []string{"keystone-manage", "credential_migrate"}
+		if _, err = r.executor(command, "keystone", podName, fkm.Namespace); err != nil {
+			return 0, fmt.Errorf("failed to migrate credentials: %v", err)
+		}
+		fkm.Status.CredentialsMigrationPending = false
+	}
+
+	lastRotationTime := repo.status.LastRotationTime
+	requeueAfter, err := r.reconcileKeyRepository(fkm, repo)
+	if err != nil {
+		return 0, err
+	}
+	if repo.status.LastRotationTime != lastRotationTime {
+		fkm.Status.CredentialsMigrationPending = true
+		return podsSyncRetryInterval, nil
+	}
+	return requeueAfter, nil
+}
+
+// keysLoadedByPods checks whether every running keystone pod sees the current content of the key
+// repository. It returns name of one of the checked pods.
+func (r *ReconcileFernetKeyManager) keysLoadedByPods(fkm *contrailv1alpha1.FernetKeyManager, repo keyRepository) (string, bool, error) {
+	if len(fkm.Spec.KeystonePodSelector) == 0 {
+		return "", true, nil
+	}
+	pods := &core.PodList{}
+	listOpts := client.ListOptions{
+		Namespace:     fkm.Namespace,
+		LabelSelector: labels.SelectorFromSet(fkm.Spec.KeystonePodSelector),
+	}
+	if err := r.client.List(context.TODO(), pods, &listOpts); err != nil {
+		return "", false, err
+	}
+	expectedChecksum := keysChecksum(repo.secret)
+	podName := ""
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != core.PodRunning {
+			continue
+		}
+		command := []string{"sh", "-c", fmt.Sprintf(
+			`cd %s && for k in $(ls | LC_ALL=C sort); do printf '%%s=%%s\n' "$k" "$(cat "$k")"; done | sha256sum`, repo.mountPath,
+		)}
+		stdout, err := r.executor(command, "keystone", pod.Name, pod.Namespace)
+		if err != nil {
+			log.Info(fmt.Sprintf("Failed to check keys loaded by pod %s: %v", pod.Name, err))
+			return "", false, nil
+		}
+		if fields := strings.Fields(stdout); len(fields) == 0 || fields[0] != expectedChecksum {
+			return "", false, nil
+		}
+		podName = pod.Name
+	}
+	return podName, true, nil
+}
+
+func keysChecksum(sc *core.Secret) string {
+	keyNames := make([]string, 0, len(sc.Data))
+	for k := range sc.Data {
+		keyNames = append(keyNames, k)
+	}
+	sort.Strings(keyNames)
+	var content strings.Builder
+	for _, k := range keyNames {
+		content.WriteString(k + "=" + strings.TrimRight(string(sc.Data[k]), "\n") + "\n")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content.String())))
+}
+
+func maxKeyIndex(sc *core.Secret) (int, error) {
+	max := 0
+	for k := range sc.Data {
+		index, err := strconv.Atoi(k)
+		if err != nil {
+			return 0, err
+		}
+		if index > max {
+			max = index
+		}
+	}
+	return max, nil
+}
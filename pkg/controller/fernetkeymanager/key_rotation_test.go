@@ -0,0 +1,193 @@
+package fernetkeymanager_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/controller/fernetkeymanager"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
+)
+
+func TestKeyRotation(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "keystone-fernet-key-manager", Namespace: "default"},
+	}
+	newFernetKeyManager := func(status contrail.FernetKeyManagerStatus) *contrail.FernetKeyManager {
+		return &contrail.FernetKeyManager{
+			ObjectMeta: v1.ObjectMeta{Name: "keystone-fernet-key-manager", Namespace: "default"},
+			Spec: contrail.FernetKeyManagerSpec{
+				TokenExpiration:          86400,
+				TokenAllowExpiredWindow:  172800,
+				RotationInterval:         259200,
+				KeystonePodSelector:      map[string]string{"keystone": "keystone"},
+				CredentialKeysSecretName: "keystone-credential-keys-repository",
+			},
+			Status: status,
+		}
+	}
+	fernetKeys := func() *core.Secret {
+		return &core.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "fernet-keys-repository", Namespace: "default"},
+			Data:       map[string][]byte{"0": []byte("staged"), "1": []byte("primary")},
+		}
+	}
+	credentialKeys := func() *core.Secret {
+		return &core.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "keystone-credential-keys-repository", Namespace: "default"},
+			Data:       map[string][]byte{"0": []byte("staged"), "1": []byte("primary")},
+		}
+	}
+	keystonePod := &core.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "keystone-keystone-statefulset-0", Namespace: "default", Labels: map[string]string{"keystone": "keystone"}},
+		Status:     core.PodStatus{Phase: core.PodRunning},
+	}
+	longAgo := v1.NewTime(time.Now().Add(-90 * 24 * time.Hour))
+	recently := v1.NewTime(time.Now().Add(-time.Hour))
+
+	t.Run("should postpone fernet key promotion until keystone pods load staged key", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, newFernetKeyManager(contrail.FernetKeyManagerStatus{}), fernetKeys(), credentialKeys(), keystonePod)
+		executor := &fakeExecutor{outputs: map[string]string{"/etc/keystone/fernet-keys": "outdated  -\n"}}
+		r := fernetkeymanager.NewReconciler(cl, scheme, k8s.New(cl, scheme), executor.exec)
+
+		res, err := r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, res.RequeueAfter)
+		assert.Equal(t, fernetKeys().Data, getSecret(t, cl, "fernet-keys-repository").Data)
+		assert.Nil(t, getFernetKeyManager(t, cl, req).Status.FernetKeys.LastRotationTime)
+	})
+
+	t.Run("should promote fernet staged key when every keystone pod loaded it", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, newFernetKeyManager(contrail.FernetKeyManagerStatus{}), fernetKeys(), credentialKeys(), keystonePod)
+		executor := &fakeExecutor{outputs: map[string]string{"/etc/keystone/fernet-keys": checksum(fernetKeys()) + "  -\n"}}
+		r := fernetkeymanager.NewReconciler(cl, scheme, k8s.New(cl, scheme), executor.exec)
+
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		keys := getSecret(t, cl, "fernet-keys-repository")
+		assert.Equal(t, []byte("staged"), keys.Data["2"])
+		status := getFernetKeyManager(t, cl, req).Status
+		assert.Equal(t, 2, status.FernetKeys.CurrentKeyIndex)
+		assert.NotNil(t, status.FernetKeys.LastRotationTime)
+	})
+
+	t.Run("should not rotate keys before rotation interval passes", func(t *testing.T) {
+		status := contrail.FernetKeyManagerStatus{
+			FernetKeys:     contrail.KeyRotationStatus{CurrentKeyIndex: 1, LastRotationTime: &recently},
+			CredentialKeys: contrail.KeyRotationStatus{CurrentKeyIndex: 1, LastRotationTime: &recently},
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, newFernetKeyManager(status), fernetKeys(), credentialKeys(), keystonePod)
+		r := fernetkeymanager.NewReconciler(cl, scheme, k8s.New(cl, scheme), failingExecutor)
+
+		res, err := r.Reconcile(req)
+		require.NoError(t, err)
+		assert.True(t, res.RequeueAfter > 70*time.Hour)
+		assert.Equal(t, fernetKeys().Data, getSecret(t, cl, "fernet-keys-repository").Data)
+		assert.Equal(t, credentialKeys().Data, getSecret(t, cl, "keystone-credential-keys-repository").Data)
+	})
+
+	t.Run("should initialize credential keys status without rotation", func(t *testing.T) {
+		status := contrail.FernetKeyManagerStatus{
+			FernetKeys: contrail.KeyRotationStatus{CurrentKeyIndex: 1, LastRotationTime: &recently},
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, newFernetKeyManager(status), fernetKeys(), credentialKeys(), keystonePod)
+		r := fernetkeymanager.NewReconciler(cl, scheme, k8s.New(cl, scheme), failingExecutor)
+
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, credentialKeys().Data, getSecret(t, cl, "keystone-credential-keys-repository").Data)
+		credentialStatus := getFernetKeyManager(t, cl, req).Status.CredentialKeys
+		assert.Equal(t, 1, credentialStatus.CurrentKeyIndex)
+		assert.NotNil(t, credentialStatus.LastRotationTime)
+	})
+
+	t.Run("should rotate credential keys and migrate credentials once pods load new keys", func(t *testing.T) {
+		status := contrail.FernetKeyManagerStatus{
+			FernetKeys:     contrail.KeyRotationStatus{CurrentKeyIndex: 1, LastRotationTime: &recently},
+			CredentialKeys: contrail.KeyRotationStatus{CurrentKeyIndex: 1, LastRotationTime: &longAgo},
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, newFernetKeyManager(status), fernetKeys(), credentialKeys(), keystonePod)
+		executor := &fakeExecutor{outputs: map[string]string{"/etc/keystone/credential-keys": checksum(credentialKeys()) + "  -\n"}}
+		r := fernetkeymanager.NewReconciler(cl, scheme, k8s.New(cl, scheme), executor.exec)
+
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		rotatedKeys := getSecret(t, cl, "keystone-credential-keys-repository")
+		assert.Equal(t, []byte("staged"), rotatedKeys.Data["2"])
+		credentialStatus := getFernetKeyManager(t, cl, req).Status
+		assert.Equal(t, 2, credentialStatus.CredentialKeys.CurrentKeyIndex)
+		assert.True(t, credentialStatus.CredentialsMigrationPending)
+		assert.Empty(t, executor.migrations)
+
+		executor.outputs["/etc/keystone/credential-keys"] = checksum(rotatedKeys) + "  -\n"
+		_, err = r.Reconcile(req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"keystone-keystone-statefulset-0"}, executor.migrations)
+		assert.False(t, getFernetKeyManager(t, cl, req).Status.CredentialsMigrationPending)
+	})
+}
+
+type fakeExecutor struct {
+	outputs    map[string]string
+	migrations []string
+}
+
+func (e *fakeExecutor) exec(command []string, containerName, podName, namespace string) (string, error) {
+	if command[0] == "keystone-manage" {
+		e.migrations = append(e.migrations, podName)
+		return "", nil
+	}
+	for path, output := range e.outputs {
+		if strings.Contains(command[len(command)-1], "cd "+path+" ") {
+			return output, nil
+		}
+	}
+	return "", errors.New("unexpected command")
+}
+
+func failingExecutor(command []string, containerName, podName, namespace string) (string, error) {
+	return "", errors.New("exec not available")
+}
+
+func checksum(sc *core.Secret) string {
+	keyNames := make([]string, 0, len(sc.Data))
+	for k := range sc.Data {
+		keyNames = append(keyNames, k)
+	}
+	sort.Strings(keyNames)
+	content := ""
+	for _, k := range keyNames {
+		content += k + "=" + string(sc.Data[k]) + "\n"
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+}
+
+func getSecret(t *testing.T, cl client.Client, name string) *core.Secret {
+	secret := &core.Secret{}
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, secret))
+	return secret
+}
+
+func getFernetKeyManager(t *testing.T, cl client.Client, req reconcile.Request) *contrail.FernetKeyManager {
+	fkm := &contrail.FernetKeyManager{}
+	require.NoError(t, cl.Get(context.Background(), req.NamespacedName, fkm))
+	return fkm
+}
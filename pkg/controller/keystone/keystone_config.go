@@ -13,6 +13,7 @@ type keystoneConfig struct {
 	RabbitMQServer   string
 	PostgreSQLServer string
 	MemcacheServer   string
+	keystoneIdentityConfig
 }
 
 type keystonePodConfig struct {
@@ -21,16 +22,18 @@ type keystonePodConfig struct {
 	RabbitMQServer   string
 	PostgreSQLServer string
 	MemcacheServer   string
+	keystoneIdentityConfig
 }
 
 func (c *keystoneConfig) FillConfigMap(cm *core.ConfigMap) {
 	for _, pod := range c.PodIPs {
 		conf := keystonePodConfig{
-			ListenAddress:    pod,
-			ListenPort:       c.ListenPort,
-			RabbitMQServer:   c.RabbitMQServer,
-			PostgreSQLServer: c.PostgreSQLServer,
-			MemcacheServer:   c.MemcacheServer,
+			ListenAddress:          pod,
+			ListenPort:             c.ListenPort,
+			RabbitMQServer:         c.RabbitMQServer,
+			PostgreSQLServer:       c.PostgreSQLServer,
+			MemcacheServer:         c.MemcacheServer,
+			keystoneIdentityConfig: c.keystoneIdentityConfig,
 		}
 		conf.fillConfigMapForPod(cm)
 	}
@@ -72,7 +75,13 @@ var keystoneKollaServiceConfig = template.Must(template.New("").Parse(`{
             "owner": "keystone",
             "perm": "0600",
             "optional": true
-        },
+        },{{ range .Domains }}
+        {
+            "source": "/var/lib/kolla/domain_files/keystone.{{ .Name }}.conf",
+            "dest": "/etc/keystone/domains/keystone.{{ .Name }}.conf",
+            "owner": "keystone",
+            "perm": "0600"
+        },{{ end }}
         {
             "source": "/var/lib/kolla/config_files/wsgi-keystone{{ .ListenAddress }}.conf",
             "dest": "/etc/httpd/conf.d/wsgi-keystone.conf",
@@ -119,6 +128,28 @@ max_active_keys = 3
 backend = dogpile.cache.memcached
 enabled = True
 memcache_servers = {{ .MemcacheServer }}
+{{- if .Domains }}
+
+[identity]
+domain_specific_drivers_enabled = True
+domain_config_dir = /etc/keystone/domains
+{{- end }}
+{{- if .IdentityProviders }}
+
+[auth]
+methods = password,token,application_credential,mapped,saml2,openid
+
+[federation]
+{{- range .TrustedDashboards }}
+trusted_dashboard = {{ . }}
+{{- end }}
+
+[saml2]
+remote_id_attribute = MELLON_IDP
+
+[openid]
+remote_id_attribute = HTTP_OIDC_ISS
+{{- end }}
 
 `))
 
@@ -154,5 +185,43 @@ TraceEnable off
     LogFormat "%{X-Forwarded-For}i %l %u %t \"%r\" %>s %b %D \"%{Referer}i\" \"%{User-Agent}i\"" logformat
     ErrorLog "|/usr/sbin/rotatelogs /var/log/kolla/keystone/keystone-apache-public-error.log"
     CustomLog "|/usr/sbin/rotatelogs /var/log/kolla/keystone/keystone-apache-public-access.log 604800" logformat
+{{- range .IdentityProviders }}
+{{- if eq .Protocol "saml2" }}
+    <Location /v3/OS-FEDERATION/identity_providers/{{ .Name }}/protocols/saml2/auth>
+{{- template "mellon" . }}
+    </Location>
+    <Location /v3/auth/OS-FEDERATION/identity_providers/{{ .Name }}/protocols/saml2/websso>
+{{- template "mellon" . }}
+    </Location>
+{{- else if eq .Protocol "openid" }}
+    OIDCClaimPrefix "OIDC-"
+    OIDCResponseType "id_token"
+    OIDCScope "{{ .OIDCScope }}"
+    OIDCProviderMetadataURL {{ .OIDC.ProviderMetadataURL }}
+    OIDCClientID {{ .OIDC.ClientID }}
+    OIDCClientSecret ${OIDC_CLIENT_SECRET}
+    OIDCCryptoPassphrase ${OIDC_CRYPTO_PASSPHRASE}
+    OIDCRedirectURI /v3/OS-FEDERATION/identity_providers/{{ .Name }}/protocols/openid/auth/redirect
+    <Location /v3/OS-FEDERATION/identity_providers/{{ .Name }}/protocols/openid/auth>
+        AuthType "openid-connect"
+        Require valid-user
+    </Location>
+    <Location /v3/auth/OS-FEDERATION/identity_providers/{{ .Name }}/protocols/openid/websso>
+        AuthType "openid-connect"
+        Require valid-user
+    </Location>
+{{- end }}
+{{- end }}
 </VirtualHost>
+{{- define "mellon" }}
+        AuthType "Mellon"
+        MellonEnable "auth"
+        MellonSPPrivateKeyFile /etc/httpd/mellon/{{ .Name }}/sp-key.pem
+        MellonSPCertFile /etc/httpd/mellon/{{ .Name }}/sp-cert.pem
+        MellonSPMetadataFile /etc/httpd/mellon/{{ .Name }}/sp-metadata.xml
+        MellonIdPMetadataFile /etc/httpd/mellon/{{ .Name }}/idp-metadata.xml
+        MellonEndpointPath /v3/OS-FEDERATION/identity_providers/{{ .Name }}/protocols/saml2/auth/mellon
+        MellonIdP "IDP"
+        Require valid-user
+{{- end }}
 `))
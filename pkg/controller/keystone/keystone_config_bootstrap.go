@@ -17,6 +17,7 @@ type keystoneBootstrapConf struct {
 	MemcacheServer   string
 	AdminPassword    string
 	Region           string
+	keystoneIdentityConfig
 }
 
 func (c *keystoneBootstrapConf) FillConfigMap(cm *core.ConfigMap) {
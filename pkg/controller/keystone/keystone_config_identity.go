@@ -0,0 +1,198 @@
+package keystone
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	core "k8s.io/api/core/v1"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+const identityConfigHashAnnotation = "keystone.contrail.juniper.net/identity-config-hash"
+
+// Values read from secrets are left out of the configuration hash, which is published as
+// a pod annotation. The hash covers resource versions of the secrets instead.
+type keystoneDomainConfig struct {
+	contrail.KeystoneDomain
+	BindUser                     string `json:"-"`
+	BindPassword                 string `json:"-"`
+	BindCredentialsSecretVersion string
+}
+
+type keystoneIdentityProviderConfig struct {
+	contrail.KeystoneIdentityProvider
+	OIDCClientSecret        string `json:"-"`
+	OIDCClientSecretVersion string
+}
+
+func (c keystoneIdentityProviderConfig) OIDCScope() string {
+	if c.OIDC == nil || c.OIDC.Scope == "" {
+		return "openid email profile"
+	}
+	return c.OIDC.Scope
+}
+
+type keystoneIdentityConfig struct {
+	Domains           []keystoneDomainConfig
+	TrustedDashboards []string
+	IdentityProviders []keystoneIdentityProviderConfig
+	// CreatedDomains are only used to compute hash of the configuration
+	// because keystone has to be restarted to load configuration of
+	// domains created after its start.
+	CreatedDomains []string
+}
+
+func (c *keystoneIdentityConfig) empty() bool {
+	return len(c.Domains) == 0 && len(c.IdentityProviders) == 0
+}
+
+func (c *keystoneIdentityConfig) validate() error {
+	oidcProviders := 0
+	for _, idp := range c.IdentityProviders {
+		switch idp.Protocol {
+		case "saml2":
+			if idp.SAML2 == nil {
+				return fmt.Errorf("identity provider %q: saml2 configuration is required", idp.Name)
+			}
+		case "openid":
+			if idp.OIDC == nil {
+				return fmt.Errorf("identity provider %q: oidc configuration is required", idp.Name)
+			}
+			oidcProviders++
+		}
+	}
+	if oidcProviders > 1 {
+		return fmt.Errorf("only one openid identity provider is supported, got %d", oidcProviders)
+	}
+	return nil
+}
+
+func (c *keystoneIdentityConfig) hash() string {
+	if c.empty() {
+		return ""
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// FillSecret renders configuration of domains into a secret instead of the config map,
+// because it contains LDAP bind passwords.
+func (c *keystoneIdentityConfig) FillSecret(sc *core.Secret) error {
+	sc.Data = map[string][]byte{}
+	for _, domain := range c.Domains {
+		var buffer bytes.Buffer
+		if err := keystoneDomainConf.Execute(&buffer, domain); err != nil {
+			return err
+		}
+		sc.Data["keystone."+domain.Name+".conf"] = buffer.Bytes()
+	}
+	return nil
+}
+
+func (c *keystoneIdentityConfig) openIDProvider() *keystoneIdentityProviderConfig {
+	for i := range c.IdentityProviders {
+		if c.IdentityProviders[i].Protocol == "openid" {
+			return &c.IdentityProviders[i]
+		}
+	}
+	return nil
+}
+
+// oidcCryptoPassphrase generates a passphrase used by mod_auth_openidc to encrypt its
+// session state. It is generated once and kept in its own secret.
+type oidcCryptoPassphrase struct{}
+
+func (oidcCryptoPassphrase) FillSecret(sc *core.Secret) error {
+	if len(sc.Data[oidcCryptoPassphraseKey]) != 0 {
+		return nil
+	}
+	passphrase, err := generateKey()
+	if err != nil {
+		return err
+	}
+	sc.Data = map[string][]byte{oidcCryptoPassphraseKey: passphrase}
+	return nil
+}
+
+const oidcCryptoPassphraseKey = "passphrase"
+
+func domainsSecretName(keystone *contrail.Keystone) string {
+	return keystone.Name + "-keystone-domains"
+}
+
+func oidcCryptoPassphraseSecretName(keystone *contrail.Keystone) string {
+	return keystone.Name + "-keystone-oidc-crypto-passphrase"
+}
+
+var keystoneDomainConf = template.Must(template.New("").Parse(`[identity]
+driver = ldap
+
+[ldap]
+url = {{ .LDAP.URL }}
+suffix = {{ .LDAP.Suffix }}
+{{- if .BindUser }}
+user = {{ .BindUser }}
+password = {{ .BindPassword }}
+{{- end }}
+{{- with .LDAP.QueryScope }}
+query_scope = {{ . }}
+{{- end }}
+{{- with .LDAP.PageSize }}
+page_size = {{ . }}
+{{- end }}
+{{- if .LDAP.UseTLS }}
+use_tls = True
+{{- end }}
+{{- with .LDAP.TLSRequireCert }}
+tls_req_cert = {{ . }}
+{{- end }}
+{{- with .LDAP.UserTreeDN }}
+user_tree_dn = {{ . }}
+{{- end }}
+{{- with .LDAP.UserFilter }}
+user_filter = {{ . }}
+{{- end }}
+{{- with .LDAP.UserObjectClass }}
+user_objectclass = {{ . }}
+{{- end }}
+{{- with .LDAP.UserIDAttribute }}
+user_id_attribute = {{ . }}
+{{- end }}
+{{- with .LDAP.UserNameAttribute }}
+user_name_attribute = {{ . }}
+{{- end }}
+{{- with .LDAP.UserMailAttribute }}
+user_mail_attribute = {{ . }}
+{{- end }}
+{{- with .LDAP.GroupTreeDN }}
+group_tree_dn = {{ . }}
+{{- end }}
+{{- with .LDAP.GroupFilter }}
+group_filter = {{ . }}
+{{- end }}
+{{- with .LDAP.GroupObjectClass }}
+group_objectclass = {{ . }}
+{{- end }}
+{{- with .LDAP.GroupIDAttribute }}
+group_id_attribute = {{ . }}
+{{- end }}
+{{- with .LDAP.GroupNameAttribute }}
+group_name_attribute = {{ . }}
+{{- end }}
+{{- with .LDAP.GroupMemberAttribute }}
+group_member_attribute = {{ . }}
+{{- end }}
+user_allow_create = False
+user_allow_update = False
+user_allow_delete = False
+group_allow_create = False
+group_allow_update = False
+group_allow_delete = False
+`))
@@ -21,13 +21,14 @@ func (r *ReconcileKeystone) configMap(configMapName, ownerType string, keystone
 	}
 }
 
-func (c *configMaps) ensureKeystoneExists(postgresNode, memcachedNode string, podIPs []string) error {
+func (c *configMaps) ensureKeystoneExists(postgresNode, memcachedNode string, podIPs []string, identityConfig keystoneIdentityConfig) error {
 	cc := &keystoneConfig{
-		PodIPs:           podIPs,
-		ListenPort:       c.keystoneSpec.ServiceConfiguration.ListenPort,
-		RabbitMQServer:   "localhost:5672",
-		PostgreSQLServer: postgresNode,
-		MemcacheServer:   memcachedNode,
+		PodIPs:                 podIPs,
+		ListenPort:             c.keystoneSpec.ServiceConfiguration.ListenPort,
+		RabbitMQServer:         "localhost:5672",
+		PostgreSQLServer:       postgresNode,
+		MemcacheServer:         memcachedNode,
+		keystoneIdentityConfig: identityConfig,
 	}
 	return c.cm.EnsureExists(cc)
 }
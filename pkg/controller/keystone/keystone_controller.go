@@ -3,6 +3,7 @@ package keystone
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	apps "k8s.io/api/apps/v1"
@@ -142,17 +143,13 @@ func (r *ReconcileKeystone) Reconcile(request reconcile.Request) (reconcile.Resu
 		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: adminPasswordSecretName, Namespace: keystone.Namespace}, adminPasswordSecret); err != nil {
 			return reconcile.Result{}, err
 		}
-		ready, err := r.externalKeystoneReady(keystone, string(adminPasswordSecret.Data["password"]))
-		if ready {
-			return reconcile.Result{}, r.updateStatusWithExternalKeystone(keystone)
-		}
-		reqLogger.Info(err.Error())
-		return reconcile.Result{RequeueAfter: time.Duration(keystone.Spec.ServiceConfiguration.ExternalAddressRetrySec) * time.Second}, nil
+		return r.reconcileExternalKeystone(keystone, string(adminPasswordSecret.Data["password"]))
 	}
 
 	fernetKeyManagerName := keystone.Name + "-fernet-key-manager"
+	credentialKeysSecretName := keystone.Name + "-credential-keys-repository"
 
-	if err := r.ensureFernetKeyManagerExists(fernetKeyManagerName, keystone.Namespace); err != nil {
+	if err := r.ensureFernetKeyManagerExists(fernetKeyManagerName, keystone, credentialKeysSecretName); err != nil {
 		return reconcile.Result{}, err
 	}
 
@@ -208,18 +205,25 @@ func (r *ReconcileKeystone) Reconcile(request reconcile.Request) (reconcile.Resu
 		podIPs = append(podIPs, pod.Status.PodIP)
 	}
 
+	identityConfig, err := r.identityConfig(keystone)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err = r.ensureIdentitySecretsExist(keystone, &identityConfig); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	kcName := keystone.Name + "-keystone"
-	if err = r.configMap(kcName, "keystone", keystone, adminPasswordSecret).ensureKeystoneExists(psql.Status.Endpoint, memcached.Status.Endpoint, podIPs); err != nil {
+	if err = r.configMap(kcName, "keystone", keystone, adminPasswordSecret).ensureKeystoneExists(psql.Status.Endpoint, memcached.Status.GetServers(), podIPs, identityConfig); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	kcbName := keystone.Name + "-keystone-bootstrap"
 	if err = r.configMap(kcbName, "keystone", keystone, adminPasswordSecret).ensureKeystoneInitExist(
-		psql.Status.Endpoint, memcached.Status.Endpoint, svc.ClusterIP(), svc.NodePort("api")); err != nil {
+		psql.Status.Endpoint, memcached.Status.GetServers(), svc.ClusterIP(), svc.NodePort("api")); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	credentialKeysSecretName := keystone.Name + "-credential-keys-repository"
 	if err = r.secret(credentialKeysSecretName, "keystone", keystone).ensureCredentialKeysSecretExists(); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -240,7 +244,7 @@ func (r *ReconcileKeystone) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
-	sts, err := r.ensureStatefulSetExists(keystone, kcName, fernetKeysSecretName, credentialKeysSecretName)
+	sts, err := r.ensureStatefulSetExists(keystone, kcName, fernetKeysSecretName, credentialKeysSecretName, identityConfig, memcached)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -250,14 +254,21 @@ func (r *ReconcileKeystone) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, r.updateStatus(keystone, sts, svc.ClusterIP())
+	if err = r.updateStatus(keystone, sts, svc.ClusterIP()); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !keystone.Status.Active {
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.ensureIdentityResourcesExist(keystone, string(adminPasswordSecret.Data["password"]))
 }
 
-func (r *ReconcileKeystone) ensureFernetKeyManagerExists(name, namespace string) error {
+func (r *ReconcileKeystone) ensureFernetKeyManagerExists(name string, keystone *contrail.Keystone, credentialKeysSecretName string) error {
 	keyManager := &contrail.FernetKeyManager{
 		ObjectMeta: meta.ObjectMeta{
 			Name:      name,
-			Namespace: namespace,
+			Namespace: keystone.Namespace,
 		},
 	}
 	_, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, keyManager, func() error {
@@ -267,6 +278,8 @@ func (r *ReconcileKeystone) ensureFernetKeyManagerExists(name, namespace string)
 		keyManager.Spec.TokenAllowExpiredWindow = 172800
 		// Three days
 		keyManager.Spec.RotationInterval = 259200
+		keyManager.Spec.KeystonePodSelector = map[string]string{"contrail_manager": "keystone", "keystone": keystone.Name}
+		keyManager.Spec.CredentialKeysSecretName = credentialKeysSecretName
 		return nil
 	})
 	return err
@@ -280,11 +293,13 @@ func (r *ReconcileKeystone) getFernetKeyManager(name, namespace string) (*contra
 }
 
 func (r *ReconcileKeystone) ensureStatefulSetExists(keystone *contrail.Keystone,
-	kcName, fernetKeysSecretName, credentialKeysSecretName string,
+	kcName, fernetKeysSecretName, credentialKeysSecretName string, identityConfig keystoneIdentityConfig, memcached *contrail.Memcached,
 ) (*apps.StatefulSet, error) {
 	sts := newKeystoneSTS(keystone)
 	_, err := controllerutil.CreateOrUpdate(context.Background(), r.client, sts, func() error {
 		updateKeystoneSTS(keystone, sts, kcName, fernetKeysSecretName, credentialKeysSecretName)
+		updateKeystoneSTSIdentity(keystone, sts, identityConfig)
+		memcached.Status.SetServersAnnotation(&sts.Spec.Template)
 		req := reconcile.Request{
 			NamespacedName: types.NamespacedName{Name: keystone.Name, Namespace: keystone.Namespace},
 		}
@@ -297,7 +312,7 @@ func (r *ReconcileKeystone) updateStatus(
 	k *contrail.Keystone,
 	sts *apps.StatefulSet, cip string,
 ) error {
-	k.Status = contrail.KeystoneStatus{}
+	k.Status = contrail.KeystoneStatus{Domains: k.Status.Domains}
 	intendentReplicas := int32(1)
 	if sts.Spec.Replicas != nil {
 		intendentReplicas = *sts.Spec.Replicas
@@ -310,27 +325,46 @@ func (r *ReconcileKeystone) updateStatus(
 	return r.client.Status().Update(context.Background(), k)
 }
 
-func (r *ReconcileKeystone) externalKeystoneReady(k *contrail.Keystone, adminPasswordSecret string) (bool, error) {
-	keystoneClient, err := keystone.NewClient(r.client, r.scheme, r.restConfig, k)
-	if err != nil {
-		return false, err
+// reconcileExternalKeystone checks health of external keystone addresses in order and
+// sets the first healthy one as keystone endpoint.
+func (r *ReconcileKeystone) reconcileExternalKeystone(k *contrail.Keystone, adminPassword string) (reconcile.Result, error) {
+	result := reconcile.Result{RequeueAfter: time.Duration(k.Spec.ServiceConfiguration.ExternalAddressRetrySec) * time.Second}
+	addresses := append([]string{k.Spec.ServiceConfiguration.ExternalAddress}, k.Spec.ServiceConfiguration.ExternalAddresses...)
+	var errs []string
+	for _, address := range addresses {
+		if err := r.externalKeystoneReady(k, address, adminPassword); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+			continue
+		}
+		return result, r.updateStatusWithExternalKeystone(k, address, strings.Join(errs, "; "))
 	}
-	_, err = keystoneClient.PostAuthTokens("admin", adminPasswordSecret, "admin")
+	log.Info("No healthy external keystone address", "errors", errs)
+	k.Status.Active = false
+	k.Status.External = true
+	k.Status.LastError = strings.Join(errs, "; ")
+	return result, r.client.Status().Update(context.Background(), k)
+}
 
+func (r *ReconcileKeystone) externalKeystoneReady(k *contrail.Keystone, address, adminPassword string) error {
+	keystoneClient, err := keystone.NewExternalClient(r.client, r.scheme, k, address)
 	if err != nil {
-		return false, fmt.Errorf("failed to get keystone token: %v", err)
+		return err
 	}
-	return true, nil
+	if _, err = keystoneClient.PostAuthTokens("admin", adminPassword, "admin"); err != nil {
+		return fmt.Errorf("failed to get keystone token: %v", err)
+	}
+	return nil
 }
 
 func (r *ReconcileKeystone) updateStatusWithExternalKeystone(
-	k *contrail.Keystone,
+	k *contrail.Keystone, address, lastError string,
 ) error {
 	k.Status = contrail.KeystoneStatus{}
 	k.Status.Active = true
 	k.Status.External = true
 	k.Status.Port = k.Spec.ServiceConfiguration.ListenPort
-	k.Status.Endpoint = k.Spec.ServiceConfiguration.ExternalAddress
+	k.Status.Endpoint = address
+	k.Status.LastError = lastError
 	return r.client.Status().Update(context.Background(), k)
 }
 
@@ -2,12 +2,15 @@ package keystone_test
 
 import (
 	"context"
+	"encoding/pem"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	apps "k8s.io/api/apps/v1"
@@ -453,6 +456,88 @@ func TestExternalKeystone(t *testing.T) {
 	assert.Equal(t, expectedStatus, k.Status)
 }
 
+func TestExternalKeystoneFailover(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	assert.NoError(t, err)
+	assert.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "keystone", Namespace: "default"},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Host, "localhost") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token": {}}`))
+	})
+
+	t.Run("should use first healthy address", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+		u, err := url.Parse(srv.URL)
+		assert.NoError(t, err)
+		port, _ := strconv.Atoi(u.Port())
+		k := newExtKeystone(u.Scheme, "localhost", port)
+		k.Spec.ServiceConfiguration.ExternalAddresses = []string{"127.0.0.1"}
+		k.Spec.ServiceConfiguration.ExternalAddressRetrySec = 60
+		cl := fake.NewFakeClientWithScheme(scheme, newAdminSecret(), k)
+		r := keystone.NewReconciler(cl, scheme, k8s.New(cl, scheme), &rest.Config{})
+
+		res, err := r.Reconcile(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 60*time.Second, res.RequeueAfter)
+		got := &contrail.Keystone{}
+		assert.NoError(t, cl.Get(context.Background(), req.NamespacedName, got))
+		assert.True(t, got.Status.Active)
+		assert.Equal(t, "127.0.0.1", got.Status.Endpoint)
+		assert.Equal(t, "localhost: failed to get keystone token: invalid status code returned: 503", got.Status.LastError)
+	})
+
+	t.Run("should set inactive status when no address is healthy", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+		u, err := url.Parse(srv.URL)
+		assert.NoError(t, err)
+		port, _ := strconv.Atoi(u.Port())
+		k := newExtKeystone(u.Scheme, "localhost", port)
+		k.Status = contrail.KeystoneStatus{Active: true, External: true, Endpoint: "localhost", Port: port}
+		cl := fake.NewFakeClientWithScheme(scheme, newAdminSecret(), k)
+		r := keystone.NewReconciler(cl, scheme, k8s.New(cl, scheme), &rest.Config{})
+
+		_, err = r.Reconcile(req)
+		assert.NoError(t, err)
+		got := &contrail.Keystone{}
+		assert.NoError(t, cl.Get(context.Background(), req.NamespacedName, got))
+		assert.False(t, got.Status.Active)
+		assert.NotEmpty(t, got.Status.LastError)
+	})
+
+	t.Run("should trust CA bundle from secret", func(t *testing.T) {
+		srv := httptest.NewTLSServer(handler)
+		defer srv.Close()
+		u, err := url.Parse(srv.URL)
+		assert.NoError(t, err)
+		port, _ := strconv.Atoi(u.Port())
+		caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+		k := newExtKeystone("https", "127.0.0.1", port)
+		k.Spec.ServiceConfiguration.ExternalCABundleSecretName = "corporate-ca"
+		caSecret := &core.Secret{
+			ObjectMeta: meta.ObjectMeta{Name: "corporate-ca", Namespace: "default"},
+			Data:       map[string][]byte{"ca.crt": caBundle},
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, newAdminSecret(), caSecret, k)
+		r := keystone.NewReconciler(cl, scheme, k8s.New(cl, scheme), &rest.Config{})
+
+		_, err = r.Reconcile(req)
+		assert.NoError(t, err)
+		got := &contrail.Keystone{}
+		assert.NoError(t, cl.Get(context.Background(), req.NamespacedName, got))
+		assert.True(t, got.Status.Active)
+		assert.Empty(t, got.Status.LastError)
+	})
+}
+
 func newKeystone() *contrail.Keystone {
 	trueVal := true
 	oneVal := int32(1)
@@ -0,0 +1,255 @@
+package keystone
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/keystone"
+)
+
+func (r *ReconcileKeystone) identityConfig(k *contrail.Keystone) (keystoneIdentityConfig, error) {
+	config := keystoneIdentityConfig{CreatedDomains: k.Status.Domains}
+	for _, domain := range k.Spec.ServiceConfiguration.Domains {
+		domainConfig := keystoneDomainConfig{KeystoneDomain: domain}
+		if secretName := domain.LDAP.BindCredentialsSecretName; secretName != "" {
+			secret, err := r.getSecret(secretName, k.Namespace)
+			if err != nil {
+				return config, err
+			}
+			domainConfig.BindUser = string(secret.Data["user"])
+			domainConfig.BindPassword = string(secret.Data["password"])
+			domainConfig.BindCredentialsSecretVersion = secret.ResourceVersion
+		}
+		config.Domains = append(config.Domains, domainConfig)
+	}
+	federation := k.Spec.ServiceConfiguration.Federation
+	if federation == nil {
+		return config, nil
+	}
+	config.TrustedDashboards = federation.TrustedDashboards
+	for _, idp := range federation.IdentityProviders {
+		idpConfig := keystoneIdentityProviderConfig{KeystoneIdentityProvider: idp}
+		if idp.Protocol == "openid" && idp.OIDC != nil {
+			secret, err := r.getSecret(idp.OIDC.ClientSecretName, k.Namespace)
+			if err != nil {
+				return config, err
+			}
+			idpConfig.OIDCClientSecret = string(secret.Data["client-secret"])
+			idpConfig.OIDCClientSecretVersion = secret.ResourceVersion
+		}
+		config.IdentityProviders = append(config.IdentityProviders, idpConfig)
+	}
+	return config, config.validate()
+}
+
+// ensureIdentitySecretsExist stores configuration of domains and the passphrase of the openid
+// provider in secrets mounted into keystone pods.
+func (r *ReconcileKeystone) ensureIdentitySecretsExist(k *contrail.Keystone, config *keystoneIdentityConfig) error {
+	if err := r.kubernetes.Secret(domainsSecretName(k), "keystone", k).EnsureExists(config); err != nil {
+		return err
+	}
+	if config.openIDProvider() == nil {
+		return nil
+	}
+	return r.kubernetes.Secret(oidcCryptoPassphraseSecretName(k), "keystone", k).EnsureExists(oidcCryptoPassphrase{})
+}
+
+func (r *ReconcileKeystone) getSecret(name, namespace string) (*core.Secret, error) {
+	secret := &core.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	return secret, err
+}
+
+func (r *ReconcileKeystone) ensureIdentityResourcesExist(k *contrail.Keystone, adminPassword string) error {
+	federation := k.Spec.ServiceConfiguration.Federation
+	if len(k.Spec.ServiceConfiguration.Domains) == 0 && (federation == nil || len(federation.IdentityProviders) == 0) {
+		return nil
+	}
+	keystoneClient, err := keystone.NewClient(r.client, r.scheme, r.restConfig, k)
+	if err != nil {
+		return err
+	}
+	tokens, err := keystoneClient.PostAuthTokens("admin", adminPassword, "admin")
+	if err != nil {
+		return fmt.Errorf("failed to get keystone token: %v", err)
+	}
+	token := tokens.XAuthTokenHeader
+	var createdDomains []string
+	for _, domain := range k.Spec.ServiceConfiguration.Domains {
+		if _, err = keystoneClient.EnsureDomain(token, domain.Name, domain.Description); err != nil {
+			return fmt.Errorf("failed to create domain %q: %v", domain.Name, err)
+		}
+		createdDomains = append(createdDomains, domain.Name)
+	}
+	if federation != nil {
+		for _, idp := range federation.IdentityProviders {
+			if err = ensureIdentityProviderExists(keystoneClient, token, idp); err != nil {
+				return fmt.Errorf("failed to create identity provider %q: %v", idp.Name, err)
+			}
+		}
+	}
+	sort.Strings(createdDomains)
+	if equalStrings(createdDomains, k.Status.Domains) {
+		return nil
+	}
+	k.Status.Domains = createdDomains
+	return r.client.Status().Update(context.Background(), k)
+}
+
+func ensureIdentityProviderExists(keystoneClient *keystone.Client, token string, idp contrail.KeystoneIdentityProvider) error {
+	provider := keystone.IdentityProvider{RemoteIDs: idp.RemoteIDs, Enabled: true}
+	if idp.Domain != "" {
+		domainID, err := keystoneClient.EnsureDomain(token, idp.Domain, "")
+		if err != nil {
+			return err
+		}
+		provider.DomainID = domainID
+	}
+	if provider.RemoteIDs == nil {
+		provider.RemoteIDs = []string{}
+	}
+	if err := keystoneClient.PutIdentityProvider(token, idp.Name, provider); err != nil {
+		return err
+	}
+	mappingID := idp.Name + "_mapping"
+	if err := keystoneClient.PutMapping(token, mappingID, newMapping(idp.Mapping)); err != nil {
+		return err
+	}
+	return keystoneClient.PutProtocol(token, idp.Name, idp.Protocol, mappingID)
+}
+
+func newMapping(rules []contrail.KeystoneMappingRule) keystone.Mapping {
+	mapping := keystone.Mapping{Rules: []keystone.MappingRule{}}
+	for _, rule := range rules {
+		mappingRule := keystone.MappingRule{}
+		for _, local := range rule.Local {
+			mappingLocal := keystone.MappingLocal{
+				User:  newMappingObject(local.User),
+				Group: newMappingObject(local.Group),
+			}
+			for _, project := range local.Projects {
+				mappingProject := keystone.MappingProject{Name: project.Name}
+				for _, role := range project.Roles {
+					mappingProject.Roles = append(mappingProject.Roles, keystone.MappingRole{Name: role})
+				}
+				mappingLocal.Projects = append(mappingLocal.Projects, mappingProject)
+			}
+			mappingRule.Local = append(mappingRule.Local, mappingLocal)
+		}
+		for _, remote := range rule.Remote {
+			mappingRule.Remote = append(mappingRule.Remote, keystone.MappingRemote{
+				Type:     remote.Type,
+				AnyOneOf: remote.AnyOneOf,
+				NotAnyOf: remote.NotAnyOf,
+				Regex:    remote.Regex,
+			})
+		}
+		mapping.Rules = append(mapping.Rules, mappingRule)
+	}
+	return mapping
+}
+
+func newMappingObject(object *contrail.KeystoneMappingObject) *keystone.MappingObject {
+	if object == nil {
+		return nil
+	}
+	mappingObject := &keystone.MappingObject{Name: object.Name}
+	if object.Domain != "" {
+		mappingObject.Domain = &keystone.MappingDomain{Name: object.Domain}
+	}
+	return mappingObject
+}
+
+func updateKeystoneSTSIdentity(keystone *contrail.Keystone, sts *apps.StatefulSet, identityConfig keystoneIdentityConfig) {
+	if identityConfigHash := identityConfig.hash(); identityConfigHash != "" {
+		if sts.Spec.Template.Annotations == nil {
+			sts.Spec.Template.Annotations = map[string]string{}
+		}
+		sts.Spec.Template.Annotations[identityConfigHashAnnotation] = identityConfigHash
+	} else {
+		delete(sts.Spec.Template.Annotations, identityConfigHashAnnotation)
+	}
+	if len(identityConfig.Domains) > 0 {
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, core.Volume{
+			Name: "keystone-domains",
+			VolumeSource: core.VolumeSource{
+				Secret: &core.SecretVolumeSource{
+					SecretName: domainsSecretName(keystone),
+				},
+			},
+		})
+		addKeystoneContainerVolumeMount(sts, core.VolumeMount{Name: "keystone-domains", MountPath: "/var/lib/kolla/domain_files"})
+	}
+	if idp := identityConfig.openIDProvider(); idp != nil {
+		addKeystoneContainerEnv(sts, core.EnvVar{
+			Name: "OIDC_CLIENT_SECRET",
+			ValueFrom: &core.EnvVarSource{
+				SecretKeyRef: &core.SecretKeySelector{
+					LocalObjectReference: core.LocalObjectReference{Name: idp.OIDC.ClientSecretName},
+					Key:                  "client-secret",
+				},
+			},
+		}, core.EnvVar{
+			Name: "OIDC_CRYPTO_PASSPHRASE",
+			ValueFrom: &core.EnvVarSource{
+				SecretKeyRef: &core.SecretKeySelector{
+					LocalObjectReference: core.LocalObjectReference{Name: oidcCryptoPassphraseSecretName(keystone)},
+					Key:                  oidcCryptoPassphraseKey,
+				},
+			},
+		})
+	}
+	federation := keystone.Spec.ServiceConfiguration.Federation
+	if federation == nil {
+		return
+	}
+	for _, idp := range federation.IdentityProviders {
+		if idp.Protocol != "saml2" || idp.SAML2 == nil {
+			continue
+		}
+		volumeName := "mellon-" + idp.Name
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, core.Volume{
+			Name: volumeName,
+			VolumeSource: core.VolumeSource{
+				Secret: &core.SecretVolumeSource{
+					SecretName: idp.SAML2.MetadataSecretName,
+				},
+			},
+		})
+		addKeystoneContainerVolumeMount(sts, core.VolumeMount{Name: volumeName, MountPath: "/etc/httpd/mellon/" + idp.Name})
+	}
+}
+
+func addKeystoneContainerVolumeMount(sts *apps.StatefulSet, volumeMount core.VolumeMount) {
+	for i, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == "keystone" {
+			sts.Spec.Template.Spec.Containers[i].VolumeMounts = append(container.VolumeMounts, volumeMount)
+		}
+	}
+}
+
+func addKeystoneContainerEnv(sts *apps.StatefulSet, env ...core.EnvVar) {
+	for i, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == "keystone" {
+			sts.Spec.Template.Spec.Containers[i].Env = append(container.Env, env...)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
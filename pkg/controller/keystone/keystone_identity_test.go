@@ -0,0 +1,258 @@
+package keystone_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/controller/keystone"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
+)
+
+func TestKeystoneIdentityBackends(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	assert.NoError(t, err)
+	assert.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	assert.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	assert.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "keystone", Namespace: "default"},
+	}
+	initObjs := func(k *contrail.Keystone) []runtime.Object {
+		return []runtime.Object{
+			&core.Pod{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: "keystone-keystone-statefulset-0", Labels: map[string]string{
+					"contrail_manager": "keystone",
+					"keystone":         "keystone",
+				}},
+				Status: core.PodStatus{PodIP: "1.1.1.1"},
+			},
+			k,
+			&contrail.Postgres{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: "psql"},
+				Status:     contrail.PostgresStatus{Status: contrail.Status{Active: true}, Endpoint: "10.10.10.20:5432"},
+			},
+			&contrail.FernetKeyManager{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: "keystone-fernet-key-manager"},
+				Status:     contrail.FernetKeyManagerStatus{SecretName: "fernet-keys-repository"},
+			},
+			newMemcached(),
+			newCertSecret(),
+			newAdminSecret(),
+			newKeystoneService(),
+			newFernetSecret(),
+			&core.Secret{
+				ObjectMeta: meta.ObjectMeta{Name: "ldap-bind", Namespace: "default"},
+				Data:       map[string][]byte{"user": []byte("cn=keystone,dc=example,dc=com"), "password": []byte("secret")},
+			},
+			&core.Secret{
+				ObjectMeta: meta.ObjectMeta{Name: "oidc-client", Namespace: "default"},
+				Data:       map[string][]byte{"client-secret": []byte("oidc-secret")},
+			},
+		}
+	}
+
+	t.Run("should render domain configs and federation settings", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs(newKeystoneWithIdentityBackends())...)
+		r := keystone.NewReconciler(cl, scheme, k8s.New(cl, scheme), &rest.Config{})
+
+		_, err := r.Reconcile(req)
+		assert.NoError(t, err)
+
+		configMap := &core.ConfigMap{}
+		assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "keystone-keystone", Namespace: "default"}, configMap))
+		assert.Equal(t, expectedFederatedKeystoneConfig, configMap.Data["keystone1.1.1.1.conf"])
+		assert.Contains(t, configMap.Data["config1.1.1.1.json"], `"source": "/var/lib/kolla/domain_files/keystone.corporate.conf"`)
+		assert.Contains(t, configMap.Data["config1.1.1.1.json"], `"dest": "/etc/keystone/domains/keystone.corporate.conf"`)
+		assert.Contains(t, configMap.Data["wsgi-keystone1.1.1.1.conf"], "MellonIdPMetadataFile /etc/httpd/mellon/adfs/idp-metadata.xml")
+		assert.Contains(t, configMap.Data["wsgi-keystone1.1.1.1.conf"], "OIDCClientSecret ${OIDC_CLIENT_SECRET}")
+		assert.Contains(t, configMap.Data["wsgi-keystone1.1.1.1.conf"], "OIDCCryptoPassphrase ${OIDC_CRYPTO_PASSPHRASE}")
+		for key, value := range configMap.Data {
+			assert.NotContains(t, value, "password = secret", key)
+			assert.NotContains(t, value, "oidc-secret", key)
+		}
+
+		domainsSecret := &core.Secret{}
+		assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "keystone-keystone-domains", Namespace: "default"}, domainsSecret))
+		assert.Equal(t, expectedLDAPDomainConfig, string(domainsSecret.Data["keystone.corporate.conf"]))
+		passphraseSecret := &core.Secret{}
+		assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "keystone-keystone-oidc-crypto-passphrase", Namespace: "default"}, passphraseSecret))
+		passphrase := passphraseSecret.Data["passphrase"]
+		assert.NotEmpty(t, passphrase)
+		assert.NotEqual(t, "oidc-secret", string(passphrase))
+		assert.Contains(t, configMap.Data["wsgi-keystone1.1.1.1.conf"],
+			"OIDCRedirectURI /v3/OS-FEDERATION/identity_providers/okta/protocols/openid/auth/redirect")
+
+		sts := &apps.StatefulSet{}
+		assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "keystone-keystone-statefulset", Namespace: "default"}, sts))
+		assert.NotEmpty(t, sts.Spec.Template.Annotations["keystone.contrail.juniper.net/identity-config-hash"])
+		assert.Contains(t, sts.Spec.Template.Spec.Volumes, core.Volume{
+			Name:         "mellon-adfs",
+			VolumeSource: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: "adfs-metadata"}},
+		})
+		assert.Contains(t, sts.Spec.Template.Spec.Containers[0].VolumeMounts, core.VolumeMount{
+			Name: "mellon-adfs", MountPath: "/etc/httpd/mellon/adfs",
+		})
+		assert.Contains(t, sts.Spec.Template.Spec.Volumes, core.Volume{
+			Name:         "keystone-domains",
+			VolumeSource: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: "keystone-keystone-domains"}},
+		})
+		assert.Contains(t, sts.Spec.Template.Spec.Containers[0].VolumeMounts, core.VolumeMount{
+			Name: "keystone-domains", MountPath: "/var/lib/kolla/domain_files",
+		})
+		assert.Contains(t, sts.Spec.Template.Spec.Containers[0].Env, core.EnvVar{
+			Name: "OIDC_CLIENT_SECRET",
+			ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: "oidc-client"}, Key: "client-secret",
+			}},
+		})
+		assert.Contains(t, sts.Spec.Template.Spec.Containers[0].Env, core.EnvVar{
+			Name: "OIDC_CRYPTO_PASSPHRASE",
+			ValueFrom: &core.EnvVarSource{SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: "keystone-keystone-oidc-crypto-passphrase"}, Key: "passphrase",
+			}},
+		})
+
+		t.Run("should keep the generated passphrase", func(t *testing.T) {
+			_, err := r.Reconcile(req)
+			assert.NoError(t, err)
+			passphraseSecret := &core.Secret{}
+			assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "keystone-keystone-oidc-crypto-passphrase", Namespace: "default"}, passphraseSecret))
+			assert.Equal(t, passphrase, passphraseSecret.Data["passphrase"])
+		})
+	})
+
+	t.Run("should hash resource versions of secrets instead of their values", func(t *testing.T) {
+		identityConfigHash := func(cl client.Client) string {
+			sts := &apps.StatefulSet{}
+			assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "keystone-keystone-statefulset", Namespace: "default"}, sts))
+			return sts.Spec.Template.Annotations["keystone.contrail.juniper.net/identity-config-hash"]
+		}
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs(newKeystoneWithIdentityBackends())...)
+		r := keystone.NewReconciler(cl, scheme, k8s.New(cl, scheme), &rest.Config{})
+		_, err := r.Reconcile(req)
+		assert.NoError(t, err)
+		hash := identityConfigHash(cl)
+
+		objs := initObjs(newKeystoneWithIdentityBackends())
+		for _, obj := range objs {
+			if secret, ok := obj.(*core.Secret); ok && secret.Name == "ldap-bind" {
+				secret.Data["password"] = []byte("other-secret")
+			}
+		}
+		otherCl := fake.NewFakeClientWithScheme(scheme, objs...)
+		_, err = keystone.NewReconciler(otherCl, scheme, k8s.New(otherCl, scheme), &rest.Config{}).Reconcile(req)
+		assert.NoError(t, err)
+		assert.Equal(t, hash, identityConfigHash(otherCl), "hash should not depend on secret values")
+
+		bindSecret := &core.Secret{}
+		assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "ldap-bind", Namespace: "default"}, bindSecret))
+		bindSecret.Data["password"] = []byte("new-secret")
+		assert.NoError(t, cl.Update(context.Background(), bindSecret))
+		_, err = r.Reconcile(req)
+		assert.NoError(t, err)
+		assert.NotEqual(t, hash, identityConfigHash(cl), "hash should change when the secret changes")
+	})
+
+	t.Run("should fail when more than one openid provider is defined", func(t *testing.T) {
+		k := newKeystoneWithIdentityBackends()
+		idps := k.Spec.ServiceConfiguration.Federation.IdentityProviders
+		second := idps[1]
+		second.Name = "google"
+		k.Spec.ServiceConfiguration.Federation.IdentityProviders = append(idps, second)
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs(k)...)
+		r := keystone.NewReconciler(cl, scheme, k8s.New(cl, scheme), &rest.Config{})
+
+		_, err := r.Reconcile(req)
+		assert.Error(t, err)
+	})
+}
+
+func newKeystoneWithIdentityBackends() *contrail.Keystone {
+	k := newKeystone()
+	k.Spec.ServiceConfiguration.Domains = []contrail.KeystoneDomain{{
+		Name: "corporate",
+		LDAP: contrail.KeystoneLDAPBackend{
+			URL:                       "ldaps://ldap.example.com:636",
+			Suffix:                    "dc=example,dc=com",
+			BindCredentialsSecretName: "ldap-bind",
+			QueryScope:                "sub",
+			UserTreeDN:                "ou=Users,dc=example,dc=com",
+			UserObjectClass:           "inetOrgPerson",
+			GroupTreeDN:               "ou=Groups,dc=example,dc=com",
+		},
+	}}
+	k.Spec.ServiceConfiguration.Federation = &contrail.KeystoneFederation{
+		TrustedDashboards: []string{"https://webui.example.com/auth/websso"},
+		IdentityProviders: []contrail.KeystoneIdentityProvider{
+			{
+				Name:      "adfs",
+				RemoteIDs: []string{"https://adfs.example.com/adfs/services/trust"},
+				Protocol:  "saml2",
+				SAML2:     &contrail.KeystoneSAML2Provider{MetadataSecretName: "adfs-metadata"},
+			},
+			{
+				Name:      "okta",
+				RemoteIDs: []string{"https://example.okta.com"},
+				Protocol:  "openid",
+				OIDC: &contrail.KeystoneOIDCProvider{
+					ProviderMetadataURL: "https://example.okta.com/.well-known/openid-configuration",
+					ClientID:            "contrail",
+					ClientSecretName:    "oidc-client",
+				},
+			},
+		},
+	}
+	return k
+}
+
+const expectedLDAPDomainConfig = `[identity]
+driver = ldap
+
+[ldap]
+url = ldaps://ldap.example.com:636
+suffix = dc=example,dc=com
+user = cn=keystone,dc=example,dc=com
+password = secret
+query_scope = sub
+user_tree_dn = ou=Users,dc=example,dc=com
+user_objectclass = inetOrgPerson
+group_tree_dn = ou=Groups,dc=example,dc=com
+user_allow_create = False
+user_allow_update = False
+user_allow_delete = False
+group_allow_create = False
+group_allow_update = False
+group_allow_delete = False
+`
+
+const expectedFederatedKeystoneConfig = expectedKeystoneConfig + `[identity]
+domain_specific_drivers_enabled = True
+domain_config_dir = /etc/keystone/domains
+
+[auth]
+methods = password,token,application_credential,mapped,saml2,openid
+
+[federation]
+trusted_dashboard = https://webui.example.com/auth/websso
+
+[saml2]
+remote_id_attribute = MELLON_IDP
+
+[openid]
+remote_id_attribute = HTTP_OIDC_ISS
+
+`
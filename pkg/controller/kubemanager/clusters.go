@@ -0,0 +1,112 @@
+package kubemanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// instanceClusterInfo returns information about the cluster watched by the instance. Remote clusters
+// are reached with the kubeconfig kept in the Secret referenced by the instance.
+func (r *ReconcileKubemanager) instanceClusterInfo(instance *v1alpha1.Kubemanager) (v1alpha1.KubemanagerClusterInfo, error) {
+	remoteCluster := instance.Spec.ServiceConfiguration.RemoteCluster
+	if remoteCluster == nil {
+		return r.clusterInfo, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: remoteCluster.KubeconfigSecret, Namespace: instance.Namespace}, secret); err != nil {
+		return nil, err
+	}
+	kubeconfig, ok := secret.Data[v1alpha1.KubemanagerKubeconfigKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no %s key", secret.Name, v1alpha1.KubemanagerKubeconfigKey)
+	}
+	return r.remoteClusterInfo(kubeconfig)
+}
+
+// validateCluster checks that the cluster watched by the instance does not conflict with clusters
+// watched by other Kubemanagers sharing the Contrail control plane. Cluster names have to be unique,
+// pod and service subnets must not overlap. IP fabric subnets may be shared by the clusters.
+func (r *ReconcileKubemanager) validateCluster(instance *v1alpha1.Kubemanager, cinfo v1alpha1.KubemanagerClusterInfo) (*v1alpha1.KubemanagerClusterStatus, error) {
+	kubemanagerConfig, err := instance.ClusterConfiguration(cinfo)
+	if err != nil {
+		return nil, err
+	}
+	if instance.Spec.ServiceConfiguration.RemoteCluster != nil && instance.Spec.ServiceConfiguration.KubernetesClusterName == "" && !*kubemanagerConfig.UseKubeadmConfig {
+		return nil, fmt.Errorf("kubemanager %s watches a remote cluster and needs a cluster name", instance.Name)
+	}
+	cluster := &v1alpha1.KubemanagerClusterStatus{
+		Name:           kubemanagerConfig.KubernetesClusterName,
+		PodSubnets:     kubemanagerConfig.PodSubnets,
+		ServiceSubnets: kubemanagerConfig.ServiceSubnets,
+	}
+	podSubnets, err := parseSubnets(cluster.PodSubnets)
+	if err != nil {
+		return nil, err
+	}
+	serviceSubnets, err := parseSubnets(cluster.ServiceSubnets)
+	if err != nil {
+		return nil, err
+	}
+	if subnet, other := overlapping(podSubnets, serviceSubnets); subnet != nil {
+		return nil, fmt.Errorf("pod subnet %s of kubemanager %s overlaps its service subnet %s", subnet, instance.Name, other)
+	}
+	subnets := append(podSubnets, serviceSubnets...)
+
+	kubemanagers := &v1alpha1.KubemanagerList{}
+	if err := r.Client.List(context.TODO(), kubemanagers, client.InNamespace(instance.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, kubemanager := range kubemanagers.Items {
+		if kubemanager.Name == instance.Name || kubemanager.Status.Cluster == nil {
+			continue
+		}
+		if kubemanager.Status.Cluster.Name == cluster.Name {
+			return nil, fmt.Errorf("cluster name %s of kubemanager %s is already used by kubemanager %s", cluster.Name, instance.Name, kubemanager.Name)
+		}
+		otherSubnets, err := parseSubnets(kubemanager.Status.Cluster.PodSubnets + "," + kubemanager.Status.Cluster.ServiceSubnets)
+		if err != nil {
+			return nil, err
+		}
+		if subnet, other := overlapping(subnets, otherSubnets); subnet != nil {
+			return nil, fmt.Errorf("subnet %s of kubemanager %s overlaps subnet %s of kubemanager %s", subnet, instance.Name, other, kubemanager.Name)
+		}
+	}
+	return cluster, nil
+}
+
+// parseSubnets parses a comma separated list of CIDRs
+func parseSubnets(subnets string) ([]*net.IPNet, error) {
+	var parsed []*net.IPNet
+	for _, subnet := range strings.Split(subnets, ",") {
+		subnet = strings.TrimSpace(subnet)
+		if subnet == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, ipNet)
+	}
+	return parsed, nil
+}
+
+// overlapping returns the first pair of overlapping subnets from the two lists
+func overlapping(subnets, others []*net.IPNet) (*net.IPNet, *net.IPNet) {
+	for _, subnet := range subnets {
+		for _, other := range others {
+			if subnet.Contains(other.IP) || other.Contains(subnet.IP) {
+				return subnet, other
+			}
+		}
+	}
+	return nil, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Juniper/contrail-operator/pkg/certificates"
 
 	"github.com/Juniper/contrail-operator/pkg/controller/utils"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,7 +26,6 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -93,7 +93,7 @@ func resourceHandler(myclient client.Client) handler.Funcs {
 // Add creates a new Kubemanager Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, ci v1alpha1.KubemanagerClusterInfo) error {
-	return add(mgr, newReconciler(mgr, ci))
+	return add(mgr, newReconciler(mgr, ci), ci)
 }
 
 func newReconciler(mgr manager.Manager, ci v1alpha1.KubemanagerClusterInfo) reconcile.Reconciler {
@@ -102,12 +102,18 @@ func newReconciler(mgr manager.Manager, ci v1alpha1.KubemanagerClusterInfo) reco
 
 // NewReconciler returns a new reconcile.Reconciler.
 func NewReconciler(client client.Client, scheme *runtime.Scheme, cfg *rest.Config, ci v1alpha1.KubemanagerClusterInfo) reconcile.Reconciler {
-	return &ReconcileKubemanager{Client: client, Scheme: scheme,
-		Config: cfg, clusterInfo: ci}
+	r := &ReconcileKubemanager{Client: client, Scheme: scheme,
+		Config: cfg, clusterInfo: ci, remoteClusterInfo: newRemoteClusterInfo}
+	r.configAPI = r.newConfigAPI
+	return r
+}
+
+func newRemoteClusterInfo(kubeconfig []byte) (v1alpha1.KubemanagerRemoteClusterInfo, error) {
+	return k8s.NewRemoteClusterConfig(kubeconfig)
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, ci v1alpha1.KubemanagerClusterInfo) error {
 	// Create a new controller.
 	c, err := controller.New("kubemanager-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -176,7 +182,21 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	return nil
+	// Watch for changes to resources the cluster information is gathered from
+	return utils.WatchClusterInfo(c, ci, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(handler.MapObject) []reconcile.Request {
+			list := &v1alpha1.KubemanagerList{}
+			_ = mgr.GetClient().List(context.TODO(), list)
+			requests := []reconcile.Request{}
+			for _, app := range list.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      app.GetName(),
+					Namespace: app.GetNamespace(),
+				}})
+			}
+			return requests
+		}),
+	})
 }
 
 // blank assignment to verify that ReconcileKubemanager implements reconcile.Reconciler.
@@ -190,6 +210,10 @@ type ReconcileKubemanager struct {
 	Scheme      *runtime.Scheme
 	Config      *rest.Config
 	clusterInfo v1alpha1.KubemanagerClusterInfo
+	// remoteClusterInfo gathers information about a remote cluster from its kubeconfig
+	remoteClusterInfo func(kubeconfig []byte) (v1alpha1.KubemanagerRemoteClusterInfo, error)
+	// configAPI returns a client of the Config API used to check referenced virtual networks
+	configAPI func(instance *v1alpha1.Kubemanager) (configAPI, error)
 }
 
 // Reconcile reads that state of the cluster for a Kubemanager object and makes changes based on the state read
@@ -213,6 +237,22 @@ func (r *ReconcileKubemanager) Reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, nil
 	}
 
+	clusterInfo, err := r.instanceClusterInfo(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	cluster, err := r.validateCluster(instance, clusterInfo)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	instance.Status.Cluster = cluster
+	if err = instance.Spec.ServiceConfiguration.Validate(); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err = r.ensureVirtualNetworksExist(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	currentConfigMap, currentConfigExists := instance.CurrentConfigMapExists(request.Name+"-"+instanceType+"-configmap", r.Client, r.Scheme, request)
 
 	configMap, err := instance.CreateConfigMap(request.Name+"-"+instanceType+"-configmap", r.Client, r.Scheme, request)
@@ -230,11 +270,6 @@ func (r *ReconcileKubemanager) Reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, err
 	}
 
-	if err = v1alpha1.CreateAccount("statusmonitor-kubemanager", request.Namespace, r.Client, r.Scheme, instance); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	statefulSet.Spec.Template.Spec.ServiceAccountName = "serviceaccount-statusmonitor-kubemanager"
 	statefulSet.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
@@ -278,25 +313,9 @@ func (r *ReconcileKubemanager) Reconcile(request reconcile.Request) (reconcile.R
 		clusterRoleBindingName = "contrail-cluster-role-binding"
 	}
 
-	existingServiceAccount := &corev1.ServiceAccount{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: instance.Namespace}, existingServiceAccount)
-	if err != nil && errors.IsNotFound(err) {
-		serviceAccount := &corev1.ServiceAccount{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "v1",
-				Kind:       "ServiceAccount",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceAccountName,
-				Namespace: instance.Namespace,
-			},
-		}
-		if err = controllerutil.SetControllerReference(instance, serviceAccount, r.Scheme); err != nil {
-			return reconcile.Result{}, err
-		}
-		if err = r.Client.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
-			return reconcile.Result{}, err
-		}
+	if err = k8s.New(r.Client, r.Scheme).ServiceAccount(serviceAccountName, clusterRoleName, clusterRoleBindingName, instance).
+		EnsureExists(clusterRules, namespaceRules); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	existingSecret := &corev1.Secret{}
@@ -320,63 +339,6 @@ func (r *ReconcileKubemanager) Reconcile(request reconcile.Request) (reconcile.R
 			return reconcile.Result{}, err
 		}
 	}
-
-	existingClusterRole := &rbacv1.ClusterRole{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleName}, existingClusterRole)
-	if err != nil && errors.IsNotFound(err) {
-		clusterRole := &rbacv1.ClusterRole{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "rbac/v1",
-				Kind:       "ClusterRole",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      clusterRoleName,
-				Namespace: instance.Namespace,
-			},
-			Rules: []rbacv1.PolicyRule{{
-				Verbs: []string{
-					"*",
-				},
-				APIGroups: []string{
-					"*",
-				},
-				Resources: []string{
-					"*",
-				},
-			}},
-		}
-		if err = r.Client.Create(context.TODO(), clusterRole); err != nil {
-			return reconcile.Result{}, err
-		}
-	}
-
-	existingClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleBindingName}, existingClusterRoleBinding)
-	if err != nil && errors.IsNotFound(err) {
-		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "rbac/v1",
-				Kind:       "ClusterRoleBinding",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      clusterRoleBindingName,
-				Namespace: instance.Namespace,
-			},
-			Subjects: []rbacv1.Subject{{
-				Kind:      "ServiceAccount",
-				Name:      serviceAccountName,
-				Namespace: instance.Namespace,
-			}},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: "rbac.authorization.k8s.io",
-				Kind:     "ClusterRole",
-				Name:     clusterRoleName,
-			},
-		}
-		if err = r.Client.Create(context.TODO(), clusterRoleBinding); err != nil {
-			return reconcile.Result{}, err
-		}
-	}
 	statefulSet.Spec.Template.Spec.ServiceAccountName = serviceAccountName
 	statefulSet.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
@@ -486,7 +448,7 @@ func (r *ReconcileKubemanager) Reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, err
 	}
 	if len(podIPList.Items) > 0 {
-		if err = instance.InstanceConfiguration(request, podIPList, r.Client, r.clusterInfo); err != nil {
+		if err = instance.InstanceConfiguration(request, podIPList, r.Client, clusterInfo); err != nil {
 			return reconcile.Result{}, err
 		}
 
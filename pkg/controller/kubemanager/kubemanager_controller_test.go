@@ -2,18 +2,28 @@ package kubemanager
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 
 	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/config"
 	mocking "github.com/Juniper/contrail-operator/pkg/controller/mock"
 
 	fakeClusterInfo "github.com/Juniper/contrail-operator/pkg/controller/kubemanager/fake"
+	fakeCInfo "github.com/Juniper/contrail-operator/pkg/k8s/fake"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apps "k8s.io/api/apps/v1"
 	batch "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -123,6 +133,7 @@ func TestKubemanagerController(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
 
 	fakeClient := fake.NewFakeClientWithScheme(scheme, kubemanagerCR, cassandraCR, zookeeperCR,
@@ -192,6 +203,18 @@ func TestKubemanagerController(t *testing.T) {
 		}}
 		assert.Equal(t, expectedOwnerRefs, sa.OwnerReferences)
 	})
+
+	t.Run("should bind serviceAccount to least privilege roles", func(t *testing.T) {
+		clusterRole := &rbac.ClusterRole{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "contrail-cluster-role"}, clusterRole))
+		assert.Equal(t, clusterRules, clusterRole.Rules)
+		clusterRoleBinding := &rbac.ClusterRoleBinding{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "contrail-cluster-role-binding"}, clusterRoleBinding))
+		assert.Equal(t, []rbac.Subject{{Kind: "ServiceAccount", Name: "contrail-service-account", Namespace: "default"}}, clusterRoleBinding.Subjects)
+		role := &rbac.Role{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "contrail-cluster-role", Namespace: "default"}, role))
+		assert.Equal(t, namespaceRules, role.Rules)
+	})
 }
 
 func TestKubemanagerControllerTwo(t *testing.T) {
@@ -325,3 +348,201 @@ func TestKubemanagerControllerTwo(t *testing.T) {
 	})
 
 }
+
+func TestKubemanagerControllerRemoteCluster(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
+
+	remoteName := types.NamespacedName{Namespace: "default", Name: "remote-kubemanager"}
+	kubeconfigSecret := &core.Secret{
+		ObjectMeta: v1.ObjectMeta{Namespace: "default", Name: "remote-kubeconfig"},
+		Data:       map[string][]byte{contrail.KubemanagerKubeconfigKey: []byte("remote kubeconfig")},
+	}
+	newRemoteKubemanager := func() *contrail.Kubemanager {
+		kubemanager := kubemanagerCR.DeepCopy()
+		kubemanager.Name = remoteName.Name
+		kubemanager.Spec.ServiceConfiguration.RemoteCluster = &contrail.KubemanagerRemoteCluster{KubeconfigSecret: kubeconfigSecret.Name}
+		return kubemanager
+	}
+	newLocalKubemanager := func(cluster contrail.KubemanagerClusterStatus) *contrail.Kubemanager {
+		kubemanager := kubemanagerCR.DeepCopy()
+		kubemanager.Status.Cluster = &cluster
+		return kubemanager
+	}
+	localCluster := contrail.KubemanagerClusterStatus{Name: "test", PodSubnets: "10.244.0.0/16", ServiceSubnets: "10.96.0.0/12"}
+	reconcileRemote := func(objects ...runtime.Object) (*ReconcileKubemanager, error) {
+		objects = append(objects, cassandraCR, zookeeperCR, rabbitmqCR, configCR)
+		reconciler := NewReconciler(fake.NewFakeClientWithScheme(scheme, objects...), scheme, &rest.Config{}, fakeClusterInfo.Cluster{}).(*ReconcileKubemanager)
+		reconciler.remoteClusterInfo = func(kubeconfig []byte) (contrail.KubemanagerRemoteClusterInfo, error) {
+			assert.Equal(t, "remote kubeconfig", string(kubeconfig))
+			return fakeCInfo.FakeRemoteClusterInfo{}, nil
+		}
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: remoteName})
+		return reconciler, err
+	}
+
+	t.Run("should record remote cluster in status", func(t *testing.T) {
+		reconciler, err := reconcileRemote(newRemoteKubemanager(), newLocalKubemanager(localCluster), kubeconfigSecret)
+		require.NoError(t, err)
+		kubemanager := &contrail.Kubemanager{}
+		require.NoError(t, reconciler.Client.Get(context.Background(), remoteName, kubemanager))
+		expected := &contrail.KubemanagerClusterStatus{Name: "remote_cluster", PodSubnets: "192.168.1.0/24", ServiceSubnets: "10.2.2.0/24"}
+		assert.Equal(t, expected, kubemanager.Status.Cluster)
+	})
+
+	t.Run("should fail without kubeconfig secret", func(t *testing.T) {
+		_, err := reconcileRemote(newRemoteKubemanager())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "remote-kubeconfig\" not found")
+	})
+
+	t.Run("should require cluster name of remote cluster", func(t *testing.T) {
+		kubemanager := newRemoteKubemanager()
+		kubemanager.Spec.ServiceConfiguration.UseKubeadmConfig = &falseVal
+		_, err := reconcileRemote(kubemanager, kubeconfigSecret)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs a cluster name")
+	})
+
+	t.Run("should reject cluster name used by another kubemanager", func(t *testing.T) {
+		cluster := localCluster
+		cluster.Name = "remote_cluster"
+		_, err := reconcileRemote(newRemoteKubemanager(), newLocalKubemanager(cluster), kubeconfigSecret)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cluster name remote_cluster of kubemanager remote-kubemanager is already used by kubemanager test-kubemanager")
+	})
+
+	t.Run("should reject pod subnet overlapping subnets of another kubemanager", func(t *testing.T) {
+		cluster := localCluster
+		cluster.ServiceSubnets = "192.168.0.0/16"
+		_, err := reconcileRemote(newRemoteKubemanager(), newLocalKubemanager(cluster), kubeconfigSecret)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "subnet 192.168.1.0/24 of kubemanager remote-kubemanager overlaps subnet 192.168.0.0/16 of kubemanager test-kubemanager")
+	})
+
+	t.Run("should reject pod subnet overlapping own service subnet", func(t *testing.T) {
+		kubemanager := newRemoteKubemanager()
+		kubemanager.Spec.ServiceConfiguration.UseKubeadmConfig = &falseVal
+		kubemanager.Spec.ServiceConfiguration.KubernetesClusterName = "remote_cluster"
+		kubemanager.Spec.ServiceConfiguration.PodSubnets = "10.32.0.0/12"
+		kubemanager.Spec.ServiceConfiguration.ServiceSubnets = "10.32.0.0/24"
+		_, err := reconcileRemote(kubemanager, kubeconfigSecret)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pod subnet 10.32.0.0/12 of kubemanager remote-kubemanager overlaps its service subnet 10.32.0.0/24")
+	})
+}
+
+type fakeConfigAPI map[string]string
+
+func (f fakeConfigAPI) FQNameToID(resourceType string, fqName []string) (string, error) {
+	id, ok := f[resourceType+":"+strings.Join(fqName, ":")]
+	if !ok {
+		return "", config.ErrNotFound
+	}
+	return id, nil
+}
+
+func TestKubemanagerControllerVirtualNetworks(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
+
+	newKubemanager := func() *contrail.Kubemanager {
+		kubemanager := kubemanagerCR.DeepCopy()
+		kubemanager.Spec.ServiceConfiguration.PodNetwork = &contrail.VirtualNetworkFQName{Project: "k8s", Name: "pods"}
+		kubemanager.Spec.ServiceConfiguration.PublicFIPPool = &contrail.FloatingIPPoolFQName{
+			VirtualNetworkFQName: contrail.VirtualNetworkFQName{Project: "admin", Name: "public"},
+			Pool:                 "public-pool",
+		}
+		return kubemanager
+	}
+	reconcileWithConfigAPI := func(kubemanager *contrail.Kubemanager, api configAPI) (*ReconcileKubemanager, error) {
+		fakeClient := fake.NewFakeClientWithScheme(scheme, kubemanager, cassandraCR, zookeeperCR, rabbitmqCR, configCR, stsCD)
+		reconciler := NewReconciler(fakeClient, scheme, &rest.Config{}, fakeClusterInfo.Cluster{}).(*ReconcileKubemanager)
+		reconciler.configAPI = func(instance *contrail.Kubemanager) (configAPI, error) {
+			return api, nil
+		}
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: kubemanagerName})
+		return reconciler, err
+	}
+
+	t.Run("should configure kubemanager when virtual networks exist", func(t *testing.T) {
+		api := fakeConfigAPI{
+			"virtual-network:default-domain:k8s:pods":     "pods-uuid",
+			"virtual-network:default-domain:admin:public": "public-uuid",
+		}
+		reconciler, err := reconcileWithConfigAPI(newKubemanager(), api)
+		require.NoError(t, err)
+		cm := &core.ConfigMap{}
+		assert.NoError(t, reconciler.Client.Get(context.Background(), types.NamespacedName{
+			Name:      "test-kubemanager-kubemanager-configmap",
+			Namespace: "default",
+		}, cm))
+	})
+
+	t.Run("should not configure kubemanager when virtual network is missing", func(t *testing.T) {
+		api := fakeConfigAPI{"virtual-network:default-domain:k8s:pods": "pods-uuid"}
+		reconciler, err := reconcileWithConfigAPI(newKubemanager(), api)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "virtual network default-domain:admin:public of kubemanager test-kubemanager does not exist")
+		cm := &core.ConfigMap{}
+		err = reconciler.Client.Get(context.Background(), types.NamespacedName{
+			Name:      "test-kubemanager-kubemanager-configmap",
+			Namespace: "default",
+		}, cm)
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("should authenticate in keystone when config API requires it", func(t *testing.T) {
+		keystoneServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "/v3/auth/tokens", r.URL.Path)
+			assert.Contains(t, string(body), `"password":"secret"`)
+			w.Header().Set("X-Subject-Token", "admin-token")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("{}"))
+		}))
+		defer keystoneServer.Close()
+		keystoneURL, err := url.Parse(keystoneServer.URL)
+		require.NoError(t, err)
+		port, err := strconv.Atoi(keystoneURL.Port())
+		require.NoError(t, err)
+		keystoneConfig := configCR.DeepCopy()
+		keystoneConfig.Spec.ServiceConfiguration.AuthMode = contrail.AuthenticationModeKeystone
+		keystoneConfig.Spec.ServiceConfiguration.KeystoneInstance = "keystone"
+		keystoneConfig.Spec.ServiceConfiguration.KeystoneSecretName = "keystone-adminpass-secret"
+		keystone := &contrail.Keystone{
+			ObjectMeta: v1.ObjectMeta{Name: "keystone", Namespace: "default"},
+			Spec: contrail.KeystoneSpec{ServiceConfiguration: contrail.KeystoneConfiguration{
+				ExternalAddress: keystoneURL.Hostname(),
+				ListenPort:      port,
+				AuthProtocol:    "http",
+			}},
+			Status: contrail.KeystoneStatus{Endpoint: keystoneURL.Hostname()},
+		}
+		adminPassword := &core.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "keystone-adminpass-secret", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		}
+		fakeClient := fake.NewFakeClientWithScheme(scheme, keystoneConfig, keystone, adminPassword)
+		reconciler := NewReconciler(fakeClient, scheme, &rest.Config{}, fakeClusterInfo.Cluster{}).(*ReconcileKubemanager)
+
+		token, err := reconciler.keystoneToken(keystoneConfig)
+
+		require.NoError(t, err)
+		assert.Equal(t, "admin-token", token)
+	})
+
+	t.Run("should reject invalid floating IP pool", func(t *testing.T) {
+		kubemanager := newKubemanager()
+		kubemanager.Spec.ServiceConfiguration.PublicFIPPool.Pool = ""
+		_, err := reconcileWithConfigAPI(kubemanager, fakeConfigAPI{})
+		assert.Error(t, err)
+	})
+}
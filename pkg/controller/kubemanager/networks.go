@@ -0,0 +1,92 @@
+package kubemanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/config"
+	"github.com/Juniper/contrail-operator/pkg/client/keystone"
+	"github.com/Juniper/contrail-operator/pkg/client/kubeproxy"
+)
+
+// configAPI looks up resources in the Contrail Config API
+type configAPI interface {
+	FQNameToID(resourceType string, fqName []string) (string, error)
+}
+
+// newConfigAPI returns a client of the Config API of the Contrail cluster the instance belongs to.
+// When the Config API requires keystone authentication the client uses an admin token.
+func (r *ReconcileKubemanager) newConfigAPI(instance *v1alpha1.Kubemanager) (configAPI, error) {
+	configs := &v1alpha1.ConfigList{}
+	labelSelector := labels.SelectorFromSet(map[string]string{"contrail_cluster": instance.Labels["contrail_cluster"]})
+	if err := r.Client.List(context.TODO(), configs, &client.ListOptions{Namespace: instance.Namespace, LabelSelector: labelSelector}); err != nil {
+		return nil, err
+	}
+	if len(configs.Items) == 0 {
+		return nil, fmt.Errorf("config of kubemanager %s not found", instance.Name)
+	}
+	configInstance := &configs.Items[0]
+	token := ""
+	if configInstance.ConfigurationParameters().AuthMode == v1alpha1.AuthenticationModeKeystone {
+		var err error
+		if token, err = r.keystoneToken(configInstance); err != nil {
+			return nil, err
+		}
+	}
+	proxy, err := kubeproxy.New(r.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubeproxy: %v", err)
+	}
+	configProxy := proxy.NewSecureClientForService(instance.Namespace, configInstance.Name+"-config", v1alpha1.ConfigApiPort)
+	return config.NewClient(configProxy, token)
+}
+
+// keystoneToken returns an admin token of the keystone instance the Config API authenticates with.
+func (r *ReconcileKubemanager) keystoneToken(configInstance *v1alpha1.Config) (string, error) {
+	authParameters, err := configInstance.AuthParameters(r.Client)
+	if err != nil {
+		return "", err
+	}
+	keystoneInstance := &v1alpha1.Keystone{}
+	keystoneName := types.NamespacedName{Namespace: configInstance.Namespace, Name: configInstance.Spec.ServiceConfiguration.KeystoneInstance}
+	if err := r.Client.Get(context.TODO(), keystoneName, keystoneInstance); err != nil {
+		return "", err
+	}
+	keystoneClient, err := keystone.NewClient(r.Client, r.Scheme, r.Config, keystoneInstance)
+	if err != nil {
+		return "", err
+	}
+	tokens, err := keystoneClient.PostAuthTokens(authParameters.AdminUsername, authParameters.AdminPassword, "admin")
+	if err != nil {
+		return "", fmt.Errorf("failed to get keystone token: %v", err)
+	}
+	return tokens.XAuthTokenHeader, nil
+}
+
+// ensureVirtualNetworksExist checks that virtual networks referenced by the instance exist in the
+// Config API, so that the kube-manager is not configured with networks it cannot use.
+func (r *ReconcileKubemanager) ensureVirtualNetworksExist(instance *v1alpha1.Kubemanager) error {
+	networks := instance.Spec.ServiceConfiguration.VirtualNetworks()
+	if len(networks) == 0 {
+		return nil
+	}
+	api, err := r.configAPI(instance)
+	if err != nil {
+		return err
+	}
+	for _, network := range networks {
+		fqName := network.FQName()
+		if _, err := api.FQNameToID("virtual-network", fqName); err == config.ErrNotFound {
+			return fmt.Errorf("virtual network %s of kubemanager %s does not exist", strings.Join(fqName, ":"), instance.Name)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
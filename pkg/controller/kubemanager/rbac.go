@@ -0,0 +1,52 @@
+package kubemanager
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// clusterRules are permissions of kube-manager in the whole cluster. It watches
+// workloads, services and network policies of all namespaces, annotates pods and
+// publishes addresses of load balancers and ingresses.
+var clusterRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"namespaces", "nodes", "pods", "services", "endpoints"},
+	Verbs:     []string{"get", "list", "watch"},
+}, {
+	APIGroups: []string{""},
+	Resources: []string{"pods", "services"},
+	Verbs:     []string{"update", "patch"},
+}, {
+	APIGroups: []string{""},
+	Resources: []string{"services/status"},
+	Verbs:     []string{"update", "patch"},
+}, {
+	APIGroups: []string{"extensions", "networking.k8s.io"},
+	Resources: []string{"ingresses", "networkpolicies"},
+	Verbs:     []string{"get", "list", "watch"},
+}, {
+	APIGroups: []string{"extensions", "networking.k8s.io"},
+	Resources: []string{"ingresses/status"},
+	Verbs:     []string{"update", "patch"},
+}, {
+	APIGroups: []string{"k8s.cni.cncf.io"},
+	Resources: []string{"network-attachment-definitions"},
+	Verbs:     []string{"get", "list", "watch"},
+}}
+
+// namespaceRules are permissions of kube-manager pods in the namespace of the Kubemanager.
+// The status monitor reads its pod and reports the state of the kube-manager.
+var namespaceRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"pods"},
+	Verbs:     []string{"get", "list"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"kubemanagers"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"kubemanagers/status"},
+	Verbs:     []string{"get", "update"},
+}}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -1012,13 +1013,43 @@ func (r *ReconcileManager) processCSRSignerCaConfigMap(manager *v1alpha1.Manager
 		if err != nil {
 			return err
 		}
-		csrSignerCaConfigMap.Data = map[string]string{certificates.SignerCAFilename: string(csrSignerCAValue)}
+		externalKeystoneCA, err := r.externalKeystoneCABundle(manager)
+		if err != nil {
+			return err
+		}
+		caBundle := string(csrSignerCAValue)
+		if externalKeystoneCA != "" {
+			caBundle += "\n" + externalKeystoneCA
+		}
+		csrSignerCaConfigMap.Data = map[string]string{certificates.SignerCAFilename: caBundle}
 		return controllerutil.SetControllerReference(manager, csrSignerCaConfigMap, r.scheme)
 	})
 
 	return err
 }
 
+// externalKeystoneCABundle returns the CA bundle of the external keystone. It is added to the
+// CA bundle mounted into contrail services so that they trust the external keystone.
+func (r *ReconcileManager) externalKeystoneCABundle(manager *v1alpha1.Manager) (string, error) {
+	if manager.Spec.Services.Keystone == nil {
+		return "", nil
+	}
+	keystoneConfig := manager.Spec.Services.Keystone.Spec.ServiceConfiguration
+	if keystoneConfig.ExternalAddress == "" || keystoneConfig.ExternalCABundleSecretName == "" {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Name: keystoneConfig.ExternalCABundleSecretName, Namespace: manager.Namespace}
+	if err := r.client.Get(context.Background(), name, secret); err != nil {
+		return "", err
+	}
+	caBundle, ok := secret.Data["ca.crt"]
+	if !ok {
+		return "", fmt.Errorf("secret %s does not contain CA bundle under ca.crt key", name.Name)
+	}
+	return string(caBundle), nil
+}
+
 func (r *ReconcileManager) processContrailmonitor(manager *v1alpha1.Manager) error {
 	if manager.Spec.Services.Contrailmonitor == nil {
 		return nil
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1936,6 +1937,82 @@ func TestManagerController(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should add external keystone CA bundle to csr signer configmap", func(t *testing.T) {
+		//given
+		managerCR := &contrail.Manager{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      "test-manager",
+				Namespace: "default",
+				UID:       "manager-uid-1",
+			},
+			Spec: contrail.ManagerSpec{
+				Services: contrail.Services{
+					Keystone: &contrail.KeystoneService{
+						Spec: contrail.KeystoneSpec{
+							ServiceConfiguration: contrail.KeystoneConfiguration{
+								ExternalAddress:            "keystone.example.com",
+								ExternalCABundleSecretName: "corporate-ca",
+							},
+						},
+					},
+				},
+			},
+		}
+		caSecret := &core.Secret{
+			ObjectMeta: meta.ObjectMeta{Name: "corporate-ca", Namespace: "default"},
+			Data:       map[string][]byte{"ca.crt": []byte("corporate CA bundle\n")},
+		}
+		fakeClient := fake.NewFakeClientWithScheme(scheme, managerCR, caSecret)
+		reconciler := ReconcileManager{
+			client:     fakeClient,
+			scheme:     scheme,
+			kubernetes: k8s.New(fakeClient, scheme),
+		}
+		// when
+		err := reconciler.processCSRSignerCaConfigMap(managerCR)
+		// then
+		require.NoError(t, err)
+		configMap := &core.ConfigMap{}
+		err = fakeClient.Get(context.Background(), types.NamespacedName{
+			Name:      certificates.SignerCAConfigMapName,
+			Namespace: "default",
+		}, configMap)
+		require.NoError(t, err)
+		caBundle := configMap.Data[certificates.SignerCAFilename]
+		assert.Contains(t, caBundle, "-----BEGIN CERTIFICATE-----")
+		assert.True(t, strings.HasSuffix(caBundle, "\ncorporate CA bundle\n"))
+	})
+
+	t.Run("should fail when external keystone CA bundle secret is missing", func(t *testing.T) {
+		managerCR := &contrail.Manager{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      "test-manager",
+				Namespace: "default",
+				UID:       "manager-uid-1",
+			},
+			Spec: contrail.ManagerSpec{
+				Services: contrail.Services{
+					Keystone: &contrail.KeystoneService{
+						Spec: contrail.KeystoneSpec{
+							ServiceConfiguration: contrail.KeystoneConfiguration{
+								ExternalAddress:            "keystone.example.com",
+								ExternalCABundleSecretName: "corporate-ca",
+							},
+						},
+					},
+				},
+			},
+		}
+		fakeClient := fake.NewFakeClientWithScheme(scheme, managerCR)
+		reconciler := ReconcileManager{
+			client:     fakeClient,
+			scheme:     scheme,
+			kubernetes: k8s.New(fakeClient, scheme),
+		}
+
+		assert.Error(t, reconciler.processCSRSignerCaConfigMap(managerCR))
+	})
+
 	//  Verification of memchache/swift
 	t.Run("Verification of swift/memcached", func(t *testing.T) {
 		// given
@@ -2,6 +2,8 @@ package memcached
 
 import (
 	"bytes"
+	"strconv"
+	"strings"
 	"text/template"
 
 	core "k8s.io/api/core/v1"
@@ -23,20 +25,45 @@ func (r *ReconcileMemcached) configMap(configMapName string, memcached *contrail
 }
 
 func (c *configMaps) ensureExists() error {
-	spc := &memcachedConfig{
-		ListenPort:      c.memcachedSpec.ServiceConfiguration.GetListenPort(),
-		ConnectionLimit: c.memcachedSpec.ServiceConfiguration.GetConnectionLimit(),
-		MaxMemory:       c.memcachedSpec.ServiceConfiguration.GetMaxMemory(),
-	}
+	spc := newMemcachedConfig(localListenAddress, c.memcachedSpec.ServiceConfiguration)
 	return c.cm.EnsureExists(spc)
 }
 
+// localListenAddress makes memcached available only on localhost
+const localListenAddress = "127.0.0.1"
+
+// clusteredListenAddress makes clustered memcached available on localhost and the pod IP only, so
+// that memcached pods in host network are not reachable on other addresses of the host.
+// POD_IP is expanded by Kubernetes in the container command.
+const clusteredListenAddress = localListenAddress + ",$(POD_IP)"
+
 type memcachedConfig struct {
+	ListenAddress   string
 	ListenPort      int32
 	ConnectionLimit int32
 	MaxMemory       int32
 }
 
+func newMemcachedConfig(listenAddress string, serviceConfiguration contrail.MemcachedConfiguration) *memcachedConfig {
+	return &memcachedConfig{
+		ListenAddress:   listenAddress,
+		ListenPort:      serviceConfiguration.GetListenPort(),
+		ConnectionLimit: serviceConfiguration.GetConnectionLimit(),
+		MaxMemory:       serviceConfiguration.GetMaxMemory(),
+	}
+}
+
+// Command returns the memcached command line
+func (c *memcachedConfig) Command() []string {
+	return []string{"/usr/bin/memcached", "-vv",
+		"-l", c.ListenAddress,
+		"-p", strconv.Itoa(int(c.ListenPort)),
+		"-c", strconv.Itoa(int(c.ConnectionLimit)),
+		"-U", "0",
+		"-m", strconv.Itoa(int(c.MaxMemory)),
+	}
+}
+
 func (c *memcachedConfig) FillConfigMap(cm *core.ConfigMap) {
 	cm.Data["config.json"] = c.String()
 }
@@ -44,14 +71,13 @@ func (c *memcachedConfig) FillConfigMap(cm *core.ConfigMap) {
 func (c *memcachedConfig) String() string {
 	memcachedConfig := template.Must(template.New("").Parse(memcachedConfigTemplate))
 	var buffer bytes.Buffer
-	if err := memcachedConfig.Execute(&buffer, c); err != nil {
+	if err := memcachedConfig.Execute(&buffer, strings.Join(c.Command(), " ")); err != nil {
 		panic(err)
 	}
 	return buffer.String()
 }
 
-// -l 127.0.0.1 makes memcached available only on localhost
 const memcachedConfigTemplate = `{
-	"command": "/usr/bin/memcached -vv -l 127.0.0.1 -p {{ .ListenPort }} -c {{ .ConnectionLimit }} -U 0 -m {{ .MaxMemory }}",
+	"command": "{{ . }}",
 	"config_files": []
 }`
@@ -3,11 +3,13 @@ package memcached
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -60,7 +62,19 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		IsController: true,
 		OwnerType:    &contrail.Memcached{},
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	// Pods are watched to keep endpoints of clustered memcached up to date when pods are rescheduled
+	return c.Watch(&source.Kind{Type: &core.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(pod handler.MapObject) []reconcile.Request {
+			name, ok := pod.Meta.GetLabels()["Memcached"]
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: pod.Meta.GetNamespace()}}}
+		}),
+	})
 }
 
 // blank assignment to verify that ReconcileMemcached implements reconcile.Reconciler
@@ -138,6 +152,24 @@ func (r *ReconcileMemcached) updateStatus(memcachedCR *contrail.Memcached, deplo
 	ip := "127.0.0.1" // memcached is available only on localhost for security reasons, after configuring SSL this should be changed to pods.Items[0].Status.PodIP
 	port := memcachedCR.Spec.ServiceConfiguration.GetListenPort()
 	memcachedCR.Status.Endpoint = fmt.Sprintf("%s:%d", ip, port)
+	memcachedCR.Status.Endpoints = nil
+	if memcachedCR.Spec.ServiceConfiguration.Clustered {
+		pods := &core.PodList{}
+		listOpts := client.ListOptions{
+			Namespace:     memcachedCR.Namespace,
+			LabelSelector: labels.SelectorFromSet(map[string]string{"Memcached": memcachedCR.Name}),
+		}
+		if err = r.client.List(context.Background(), pods, &listOpts); err != nil {
+			return err
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+				continue
+			}
+			memcachedCR.Status.Endpoints = append(memcachedCR.Status.Endpoints, fmt.Sprintf("%s:%d", pod.Status.PodIP, port))
+		}
+		sort.Strings(memcachedCR.Status.Endpoints)
+	}
 	memcachedCR.Status.Status.FromDeployment(deployment)
 	return r.client.Status().Update(context.Background(), memcachedCR)
 }
@@ -185,7 +217,7 @@ func updateMemcachedPodSpec(podSpec *core.PodSpec, memcachedCR *contrail.Memcach
 func memcachedContainer(memcachedCR *contrail.Memcached) core.Container {
 	instanceContainer := utils.GetContainerFromList("memcached", memcachedCR.Spec.ServiceConfiguration.Containers)
 	port := int(memcachedCR.Spec.ServiceConfiguration.GetListenPort())
-	return core.Container{
+	container := core.Container{
 		Name:            "memcached",
 		Image:           instanceContainer.Image,
 		ImagePullPolicy: core.PullIfNotPresent,
@@ -228,4 +260,16 @@ func memcachedContainer(memcachedCR *contrail.Memcached) core.Container {
 			},
 		},
 	}
+	if memcachedCR.Spec.ServiceConfiguration.Clustered {
+		// The pod IP is known only when the pod is started, so memcached is run directly with the
+		// pod IP expanded in the command instead of by kolla from the config map.
+		container.Env = append(container.Env, core.EnvVar{
+			Name: "POD_IP",
+			ValueFrom: &core.EnvVarSource{
+				FieldRef: &core.ObjectFieldSelector{FieldPath: "status.podIP"},
+			},
+		})
+		container.Command = newMemcachedConfig(clusteredListenAddress, memcachedCR.Spec.ServiceConfiguration).Command()
+	}
+	return container
 }
@@ -171,6 +171,44 @@ func TestMemcachedController(t *testing.T) {
 			assertMemcachedIsInactive(t, fakeClient)
 		})
 	})
+
+	t.Run("when clustered Memcached CR is reconciled", func(t *testing.T) {
+		// given
+		memcachedCR := newMemcachedCR(contrail.MemcachedStatus{})
+		memcachedCR.Spec.ServiceConfiguration.Clustered = true
+		fakeClient := fake.NewFakeClientWithScheme(scheme, memcachedCR)
+		reconciler := memcached.NewReconcileMemcached(fakeClient, scheme, k8s.New(fakeClient, scheme))
+		for name, ip := range map[string]string{"pod-b": "10.0.0.2", "pod-a": "10.0.0.1", "pod-c": ""} {
+			pod := &core.Pod{
+				ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"Memcached": "test-memcached"}},
+				Status:     core.PodStatus{PodIP: ip},
+			}
+			require.NoError(t, fakeClient.Create(context.Background(), pod))
+		}
+		// when
+		_, err = reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-memcached"}})
+		// then
+		assert.NoError(t, err)
+		t.Run("should listen on localhost and pod IP only", func(t *testing.T) {
+			deployment := apps.Deployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-memcached-deployment"}, &deployment))
+			container := deployment.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, []string{"/usr/bin/memcached", "-vv", "-l", "127.0.0.1,$(POD_IP)", "-p", "11211", "-c", "5000", "-U", "0", "-m", "256"}, container.Command)
+			assert.Contains(t, container.Env, core.EnvVar{
+				Name:      "POD_IP",
+				ValueFrom: &core.EnvVarSource{FieldRef: &core.ObjectFieldSelector{FieldPath: "status.podIP"}},
+			})
+			configMap := core.ConfigMap{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-memcached-config"}, &configMap))
+			assert.NotContains(t, configMap.Data["config.json"], "0.0.0.0")
+		})
+		t.Run("should publish endpoints of all pods", func(t *testing.T) {
+			memcachedCR := contrail.Memcached{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-memcached"}, &memcachedCR))
+			assert.Equal(t, []string{"10.0.0.1:11211", "10.0.0.2:11211"}, memcachedCR.Status.Endpoints)
+			assert.Equal(t, "10.0.0.1:11211,10.0.0.2:11211", memcachedCR.Status.GetServers())
+		})
+	})
 }
 
 func setMemcachedDeploymentStatus(t *testing.T, c client.Client, status apps.DeploymentStatus) {
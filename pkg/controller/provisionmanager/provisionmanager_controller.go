@@ -28,6 +28,7 @@ import (
 	"github.com/Juniper/contrail-operator/pkg/certificates"
 	"github.com/Juniper/contrail-operator/pkg/configuration"
 	"github.com/Juniper/contrail-operator/pkg/controller/utils"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
 )
 
 const RequiredAnnotationsKey = "managed_by"
@@ -160,9 +161,40 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err = c.Watch(srcVrouter, vrouterHandler, predVrouterActiveChange); err != nil {
 		return err
 	}
+
+	// Watch for changes to secrets with authentication keys of BGP peers, so that rotated keys reach the peers
+	srcSecret := &source.Kind{Type: &corev1.Secret{}}
+	if err = c.Watch(srcSecret, authKeySecretHandler(mgr.GetClient())); err != nil {
+		return err
+	}
 	return nil
 }
 
+// authKeySecretHandler enqueues ProvisionManagers which read authentication keys of BGP peers from the secret
+func authKeySecretHandler(cl client.Client) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(secret handler.MapObject) []reconcile.Request {
+			var provisionManagers v1alpha1.ProvisionManagerList
+			_ = cl.List(context.TODO(), &provisionManagers, client.InNamespace(secret.Meta.GetNamespace()))
+			var requests []reconcile.Request
+			for _, provisionManager := range provisionManagers.Items {
+				for _, peer := range provisionManager.Spec.ServiceConfiguration.BGPPeers {
+					if peer.AuthKeySecretName == secret.Meta.GetName() {
+						requests = append(requests, reconcile.Request{
+							NamespacedName: types.NamespacedName{
+								Name:      provisionManager.Name,
+								Namespace: provisionManager.Namespace,
+							},
+						})
+						break
+					}
+				}
+			}
+			return requests
+		}),
+	}
+}
+
 // blank assignment to verify that ReconcileProvisionManager implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileProvisionManager{}
 
@@ -232,11 +264,21 @@ func (r *ReconcileProvisionManager) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, err
 	}
 
+	configMapControlPlaneConf, err := instance.CreateConfigMap(request.Name+"-"+instanceType+"-configmap-controlplane", r.Client, r.Scheme, request)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	secretCertificates, err := instance.CreateSecret(request.Name+"-secret-certificates", r.Client, r.Scheme, request)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	secretBGPPeers, err := instance.CreateSecret(request.Name+"-"+instanceType+"-secret-bgppeers", r.Client, r.Scheme, request)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	statefulSet := GetSTS()
 	if err = instance.PrepareSTS(statefulSet, &instance.Spec.CommonConfiguration, request, r.Scheme, r.Client); err != nil {
 		return reconcile.Result{}, err
@@ -254,9 +296,22 @@ func (r *ReconcileProvisionManager) Reconcile(request reconcile.Request) (reconc
 		configMapAPIServer.Name:            request.Name + "-" + instanceType + "-apiserver-volume",
 		configMapKeystoneAuthConf.Name:     request.Name + "-" + instanceType + "-keystoneauth-volume",
 		configMapGlobalVrouterConf.Name:    request.Name + "-" + instanceType + "-globalvrouter-volume",
+		configMapControlPlaneConf.Name:     request.Name + "-" + instanceType + "-controlplane-volume",
 		certificates.SignerCAConfigMapName: csrSignerCaVolumeName,
 	})
-	instance.AddSecretVolumesToIntendedSTS(statefulSet, map[string]string{secretCertificates.Name: request.Name + "-secret-certificates"})
+	instance.AddSecretVolumesToIntendedSTS(statefulSet, map[string]string{
+		secretCertificates.Name: request.Name + "-secret-certificates",
+		secretBGPPeers.Name:     request.Name + "-" + instanceType + "-bgppeers-volume",
+	})
+
+	watchResources := instance.Spec.ServiceConfiguration.ProvisionMode == v1alpha1.ProvisionModeKubernetes
+	if watchResources {
+		if err = k8s.New(r.Client, r.Scheme).ServiceAccount("serviceaccount-provisionmanager", "clusterrole-provisionmanager", "clusterrolebinding-provisionmanager", instance).
+			EnsureExists(clusterRules, namespaceRules); err != nil {
+			return reconcile.Result{}, err
+		}
+		statefulSet.Spec.Template.Spec.ServiceAccountName = "serviceaccount-provisionmanager"
+	}
 
 	for idx, container := range statefulSet.Spec.Template.Spec.Containers {
 		if container.Name == "provisioner" {
@@ -270,8 +325,26 @@ func (r *ReconcileProvisionManager) Reconcile(request reconcile.Request) (reconc
 					-apiserver /etc/provision/apiserver/apiserver-${POD_IP}.yaml \
 					-keystoneAuthConf /etc/provision/keystone/keystone-auth-${POD_IP}.yaml \
 					-globalVrouterConf /etc/provision/globalvrouter/globalvrouter.json \
+					-globalSystemConf /etc/provision/controlplane/globalsystem.yaml \
+					-virtualDNS /etc/provision/controlplane/virtualdns.yaml \
+					-bgpPeers /etc/provision/bgppeers/bgppeers.yaml \
+					-status /var/log/provisionmanager/status.json \
 					-mode watch`,
 			}
+			if watchResources {
+				command = []string{"sh", "-c",
+					`/app/contrail-provisioner/contrail-provisioner-image.binary \
+					-apiserver /etc/provision/apiserver/apiserver-${POD_IP}.yaml \
+					-keystoneAuthConf /etc/provision/keystone/keystone-auth-${POD_IP}.yaml \
+					-globalVrouterConf /etc/provision/globalvrouter/globalvrouter.json \
+					-globalSystemConf /etc/provision/controlplane/globalsystem.yaml \
+					-virtualDNS /etc/provision/controlplane/virtualdns.yaml \
+					-bgpPeers /etc/provision/bgppeers/bgppeers.yaml \
+					-status /var/log/provisionmanager/status.json \
+					-namespace ${POD_NAMESPACE} \
+					-mode kubernetes`,
+				}
+			}
 			instanceContainer := utils.GetContainerFromList(container.Name, instance.Spec.ServiceConfiguration.Containers)
 			if instanceContainer.Command == nil {
 				(&statefulSet.Spec.Template.Spec.Containers[idx]).Command = command
@@ -327,6 +400,16 @@ func (r *ReconcileProvisionManager) Reconcile(request reconcile.Request) (reconc
 				MountPath: "/etc/provision/globalvrouter",
 			}
 			volumeMountList = append(volumeMountList, volumeMount)
+			volumeMount = corev1.VolumeMount{
+				Name:      request.Name + "-" + instanceType + "-controlplane-volume",
+				MountPath: "/etc/provision/controlplane",
+			}
+			volumeMountList = append(volumeMountList, volumeMount)
+			volumeMount = corev1.VolumeMount{
+				Name:      request.Name + "-" + instanceType + "-bgppeers-volume",
+				MountPath: "/etc/provision/bgppeers",
+			}
+			volumeMountList = append(volumeMountList, volumeMount)
 			volumeMount = corev1.VolumeMount{
 				Name:      csrSignerCaVolumeName,
 				MountPath: certificates.SignerCAMountPath,
@@ -453,8 +536,22 @@ func instanceConfiguration(
 		return err
 	}
 
+	configMapControlPlane := &corev1.ConfigMap{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: request.Name + "-" + "provisionmanager" + "-configmap-controlplane", Namespace: request.Namespace}, configMapControlPlane)
+	if err != nil {
+		return err
+	}
+
+	secretBGPPeers := &corev1.Secret{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: request.Name + "-" + "provisionmanager" + "-secret-bgppeers", Namespace: request.Namespace}, secretBGPPeers)
+	if err != nil {
+		return err
+	}
+
 	configNodesInformation := c.Spec.ServiceConfiguration.ConfigNodesConfiguration
 	configNodesInformation.FillWithDefaultValues()
+	// In kubernetes mode provisioner discovers nodes itself
+	renderNodes := c.Spec.ServiceConfiguration.ProvisionMode != v1alpha1.ProvisionModeKubernetes
 
 	listOps := &client.ListOptions{Namespace: request.Namespace}
 	configList := &v1alpha1.ConfigList{}
@@ -487,6 +584,35 @@ func instanceConfiguration(
 		return err
 	}
 	globalVrouterData["globalvrouter.json"] = string(globalVrouterJson)
+	if c.Status.GlobalConfiguration, err = c.GetGlobalConfiguration(globalVrouter); err != nil {
+		return err
+	}
+
+	var controlPlaneData = make(map[string]string)
+	globalSystemYaml, err := yaml.Marshal(c.Spec.ServiceConfiguration.GlobalSystemConfiguration)
+	if err != nil {
+		return err
+	}
+	controlPlaneData["globalsystem.yaml"] = string(globalSystemYaml)
+	virtualDNSServers := c.Spec.ServiceConfiguration.VirtualDNSServers
+	if virtualDNSServers == nil {
+		virtualDNSServers = []v1alpha1.VirtualDNSServer{}
+	}
+	virtualDNSYaml, err := yaml.Marshal(virtualDNSServers)
+	if err != nil {
+		return err
+	}
+	controlPlaneData["virtualdns.yaml"] = string(virtualDNSYaml)
+
+	bgpPeers, err := c.GetBGPPeerNodes(cl)
+	if err != nil {
+		return err
+	}
+	bgpPeersYaml, err := yaml.Marshal(bgpPeers)
+	if err != nil {
+		return err
+	}
+	bgpPeersData := map[string][]byte{"bgppeers.yaml": bgpPeersYaml}
 
 	if configNodesInformation.AuthMode == v1alpha1.AuthenticationModeKeystone {
 		for _, pod := range podList.Items {
@@ -526,14 +652,16 @@ func instanceConfiguration(
 			}
 			apiPort = configService.Status.Ports.APIPort
 		}
-		sort.SliceStable(nodeList, func(i, j int) bool { return nodeList[i].IPAddress < nodeList[j].IPAddress })
-		nodeYaml, err := yaml.Marshal(nodeList)
-		if err != nil {
-			return err
+		if renderNodes {
+			sort.SliceStable(nodeList, func(i, j int) bool { return nodeList[i].IPAddress < nodeList[j].IPAddress })
+			nodeYaml, err := yaml.Marshal(nodeList)
+			if err != nil {
+				return err
+			}
+			configNodeData["confignodes.yaml"] = string(nodeYaml)
 		}
-		configNodeData["confignodes.yaml"] = string(nodeYaml)
 	}
-	if len(configList.Items) > 0 {
+	if renderNodes && len(configList.Items) > 0 {
 		nodeList := []*v1alpha1.AnalyticsNode{}
 		for _, configService := range configList.Items {
 			for podName, ipAddress := range configService.Status.Nodes {
@@ -567,7 +695,7 @@ func instanceConfiguration(
 	if err = cl.List(context.TODO(), controlList, listOps); err != nil {
 		return err
 	}
-	if len(controlList.Items) > 0 {
+	if renderNodes && len(controlList.Items) > 0 {
 		nodeList := []*v1alpha1.ControlNode{}
 		for _, controlService := range controlList.Items {
 			for podName, ipAddress := range controlService.Status.Nodes {
@@ -616,7 +744,7 @@ func instanceConfiguration(
 	if err = cl.List(context.TODO(), vrouterList, listOps); err != nil {
 		return err
 	}
-	if len(vrouterList.Items) > 0 {
+	if renderNodes && len(vrouterList.Items) > 0 {
 		nodeList := []*v1alpha1.VrouterNode{}
 		for _, vrouterService := range vrouterList.Items {
 			for podName, ipAddress := range vrouterService.Status.Nodes {
@@ -667,7 +795,7 @@ func instanceConfiguration(
 	if err = cl.List(context.TODO(), cassandras, listOps); err != nil {
 		return err
 	}
-	if len(cassandras.Items) > 0 {
+	if renderNodes && len(cassandras.Items) > 0 {
 		databaseNodeList := []v1alpha1.DatabaseNode{}
 		for _, db := range cassandras.Items {
 			for podName, ipAddress := range db.Status.Nodes {
@@ -745,6 +873,18 @@ func instanceConfiguration(
 		return err
 	}
 
+	configMapControlPlane.Data = controlPlaneData
+	err = cl.Update(context.TODO(), configMapControlPlane)
+	if err != nil {
+		return err
+	}
+
+	secretBGPPeers.Data = bgpPeersData
+	err = cl.Update(context.TODO(), secretBGPPeers)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
 	meta1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -62,12 +63,41 @@ func TestProvisionManager(t *testing.T) {
 	}
 }
 
+func TestAuthKeySecretHandler(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err, "Failed to build scheme")
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme), "Failed core.SchemeBuilder.AddToScheme()")
+
+	withPeer := newProvisionManager()
+	withPeer.Spec.ServiceConfiguration.BGPPeers = []contrail.ExternalBGPPeer{{Name: "mx1", Address: "10.0.0.1", ASN: 64513, AuthKeySecretName: "mx1-auth"}}
+	withoutPeer := newProvisionManager()
+	withoutPeer.Name = "other"
+	cl := fake.NewFakeClientWithScheme(scheme, withPeer, withoutPeer)
+	wq := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+
+	t.Run("should enqueue ProvisionManagers using the rotated secret", func(t *testing.T) {
+		secret := &core.Secret{ObjectMeta: meta1.ObjectMeta{Name: "mx1-auth", Namespace: "default"}}
+		authKeySecretHandler(cl).Update(event.UpdateEvent{MetaOld: secret, ObjectOld: secret, MetaNew: secret, ObjectNew: secret}, wq)
+		require.Equal(t, 1, wq.Len())
+		item, _ := wq.Get()
+		assert.Equal(t, reconcile.Request{NamespacedName: types.NamespacedName{Name: withPeer.Name, Namespace: "default"}}, item)
+		wq.Done(item)
+	})
+
+	t.Run("should ignore other secrets", func(t *testing.T) {
+		secret := &core.Secret{ObjectMeta: meta1.ObjectMeta{Name: "unrelated", Namespace: "default"}}
+		authKeySecretHandler(cl).Update(event.UpdateEvent{MetaOld: secret, ObjectOld: secret, MetaNew: secret, ObjectNew: secret}, wq)
+		assert.Equal(t, 0, wq.Len())
+	})
+}
+
 func TestProvisionManagerController(t *testing.T) {
 
 	scheme, err := contrail.SchemeBuilder.Build()
 	require.NoError(t, err, "Failed to build scheme")
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme), "Failed core.SchemeBuilder.AddToScheme()")
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme), "Failed apps.SchemeBuilder.AddToScheme()")
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme), "Failed rbac.SchemeBuilder.AddToScheme()")
 
 	falseVal := false
 
@@ -234,6 +264,201 @@ func TestProvisionManagerController(t *testing.T) {
 			"analyticsnodes.yaml": analyticsnodes,
 		}, cm.Data)
 	})
+
+	t.Run("Create control plane configuration of BGP peers, global system config and virtual DNS servers", func(t *testing.T) {
+		asn := 64513
+		pmr := newProvisionManager()
+		pmr.Spec.ServiceConfiguration.GlobalSystemConfiguration = contrail.GlobalSystemConfiguration{AutonomousSystem: &asn}
+		pmr.Spec.ServiceConfiguration.BGPPeers = []contrail.ExternalBGPPeer{{
+			Name:              "mx1",
+			Address:           "10.0.0.1",
+			ASN:               64513,
+			AuthKeySecretName: "mx1-auth",
+		}}
+		pmr.Spec.ServiceConfiguration.VirtualDNSServers = []contrail.VirtualDNSServer{{
+			Name:       "default-dns",
+			DomainName: "example.com",
+		}}
+		initObjs := []runtime.Object{
+			newConfigInst(),
+			pmr,
+			newProvisionManagerPod(),
+			newNode(),
+			&core.Secret{
+				ObjectMeta: meta1.ObjectMeta{Name: "mx1-auth", Namespace: "default"},
+				Data:       map[string][]byte{"authKey": []byte("secret")},
+			},
+		}
+		for _, p := range newConfigPodList() {
+			initObjs = append(initObjs, p)
+		}
+
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs...)
+		caCertificate := certificates.NewCACertificate(cl, scheme, pmr, "provisionmanager")
+		assert.NoError(t, caCertificate.EnsureExists())
+
+		r := &ReconcileProvisionManager{Client: cl, Scheme: scheme}
+
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      "provisionmanager",
+				Namespace: "default",
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err, "r.Reconcile failed")
+
+		cm := core.ConfigMap{}
+		err = cl.Get(context.Background(), types.NamespacedName{
+			Name:      "provisionmanager-provisionmanager-configmap-controlplane",
+			Namespace: "default",
+		}, &cm)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"globalsystem.yaml": "autonomousSystem: 64513\n",
+			"virtualdns.yaml":   "- name: default-dns\n  domainName: example.com\n",
+		}, cm.Data)
+
+		secret := core.Secret{}
+		err = cl.Get(context.Background(), types.NamespacedName{
+			Name:      "provisionmanager-provisionmanager-secret-bgppeers",
+			Namespace: "default",
+		}, &secret)
+		require.NoError(t, err)
+		assert.Equal(t, "- ipAddress: 10.0.0.1\n  hostname: mx1\n  asn: 64513\n  authKey: secret\n", string(secret.Data["bgppeers.yaml"]))
+	})
+
+	t.Run("Render global vRouter configuration and report it in status", func(t *testing.T) {
+		flowExportRate := 100
+		pmr := newProvisionManager()
+		pmr.Spec.ServiceConfiguration.GlobalVrouterConfiguration = contrail.GlobalVrouterConfiguration{
+			ForwardingMode: "l3",
+			FlowExportRate: &flowExportRate,
+			LinklocalServices: []contrail.LinklocalService{{
+				Name:       "metadata",
+				IP:         "169.254.169.254",
+				Port:       80,
+				FabricIPs:  []string{"127.0.0.1"},
+				FabricPort: 8775,
+			}},
+			PortTranslationPools: []contrail.PortTranslationPool{{Protocol: "udp", PortCount: 1024}},
+		}
+		pmr.Spec.ServiceConfiguration.GlobalSystemConfiguration = contrail.GlobalSystemConfiguration{
+			FastConvergence: &contrail.FastConvergenceParameters{Enable: true, XMPPHoldTime: 10},
+		}
+		initObjs := []runtime.Object{
+			newConfigInst(),
+			pmr,
+			newProvisionManagerPod(),
+			newNode(),
+		}
+		for _, p := range newConfigPodList() {
+			initObjs = append(initObjs, p)
+		}
+
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs...)
+		caCertificate := certificates.NewCACertificate(cl, scheme, pmr, "provisionmanager")
+		assert.NoError(t, caCertificate.EnsureExists())
+
+		r := &ReconcileProvisionManager{Client: cl, Scheme: scheme}
+
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      "provisionmanager",
+				Namespace: "default",
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err, "r.Reconcile failed")
+
+		cm := core.ConfigMap{}
+		err = cl.Get(context.Background(), types.NamespacedName{
+			Name:      "provisionmanager-provisionmanager-configmap-globalvrouter",
+			Namespace: "default",
+		}, &cm)
+		require.NoError(t, err)
+		assert.Contains(t, cm.Data["globalvrouter.json"], `"forwardingMode":"l3"`)
+		assert.Contains(t, cm.Data["globalvrouter.json"], `"linklocalServices":[{"name":"metadata","ip":"169.254.169.254","port":80,"fabricIPs":["127.0.0.1"],"fabricPort":8775}]`)
+
+		instance := contrail.ProvisionManager{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &instance))
+		status := instance.Status.GlobalConfiguration
+		assert.Equal(t, `"l3"`, status["globalVrouterConfig.forwardingMode"])
+		assert.Equal(t, "100", status["globalVrouterConfig.flowExportRate"])
+		assert.Equal(t, `"VXLAN,MPLSoGRE,MPLSoUDP"`, status["globalVrouterConfig.encapPriority"])
+		assert.Equal(t, `[{"protocol":"udp","portCount":1024}]`, status["globalVrouterConfig.portTranslationPools"])
+		assert.Equal(t, `{"enable":true,"xmppHoldTime":10}`, status["globalSystemConfig.fastConvergence"])
+		assert.NotContains(t, status, "globalVrouterConfig.flowAgingTimeouts")
+	})
+
+	t.Run("Provisioner watches resources in kubernetes mode", func(t *testing.T) {
+		pmr := newProvisionManager()
+		pmr.Spec.ServiceConfiguration.ProvisionMode = contrail.ProvisionModeKubernetes
+		initObjs := []runtime.Object{
+			newConfigInst(),
+			pmr,
+			newProvisionManagerPod(),
+			newNode(),
+		}
+		for _, p := range newConfigPodList() {
+			initObjs = append(initObjs, p)
+		}
+
+		cl := fake.NewFakeClientWithScheme(scheme, initObjs...)
+		caCertificate := certificates.NewCACertificate(cl, scheme, pmr, "provisionmanager")
+		assert.NoError(t, caCertificate.EnsureExists())
+
+		r := &ReconcileProvisionManager{Client: cl, Scheme: scheme}
+
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      "provisionmanager",
+				Namespace: "default",
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err, "r.Reconcile failed")
+
+		for _, name := range []string{"analyticsnodes", "confignodes"} {
+			cm := core.ConfigMap{}
+			err = cl.Get(context.Background(), types.NamespacedName{
+				Name:      "provisionmanager-provisionmanager-configmap-" + name,
+				Namespace: "default",
+			}, &cm)
+			require.NoError(t, err)
+			assert.Empty(t, cm.Data, "node lists should not be rendered in kubernetes mode")
+		}
+
+		sts := apps.StatefulSet{}
+		err = cl.Get(context.Background(), types.NamespacedName{
+			Name:      "provisionmanager-provisionmanager-statefulset",
+			Namespace: "default",
+		}, &sts)
+		require.NoError(t, err)
+		assert.Equal(t, "serviceaccount-provisionmanager", sts.Spec.Template.Spec.ServiceAccountName)
+		var provisioner *core.Container
+		for i := range sts.Spec.Template.Spec.Containers {
+			if sts.Spec.Template.Spec.Containers[i].Name == "provisioner" {
+				provisioner = &sts.Spec.Template.Spec.Containers[i]
+			}
+		}
+		require.NotNil(t, provisioner)
+		assert.Contains(t, provisioner.Command[2], "-mode kubernetes")
+		assert.Contains(t, provisioner.Command[2], "-status /var/log/provisionmanager/status.json")
+		assert.NotContains(t, provisioner.Command[2], "-controlNodes")
+
+		sa := core.ServiceAccount{}
+		assert.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "serviceaccount-provisionmanager", Namespace: "default"}, &sa))
+		clusterRole := rbac.ClusterRole{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "clusterrole-provisionmanager"}, &clusterRole))
+		assert.Equal(t, clusterRules, clusterRole.Rules)
+		role := rbac.Role{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "clusterrole-provisionmanager", Namespace: "default"}, &role))
+		assert.Equal(t, namespaceRules, role.Rules)
+		roleBinding := rbac.RoleBinding{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "clusterrolebinding-provisionmanager", Namespace: "default"}, &roleBinding))
+		assert.Equal(t, []rbac.Subject{{Kind: "ServiceAccount", Name: "serviceaccount-provisionmanager", Namespace: "default"}}, roleBinding.Subjects)
+	})
 }
 
 var falseVal = false
@@ -0,0 +1,27 @@
+package provisionmanager
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// clusterRules are permissions of the provisioner in the whole cluster. In kubernetes mode
+// it reads nodes which Contrail pods are scheduled on to find their hostnames.
+var clusterRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"nodes"},
+	Verbs:     []string{"get", "list"},
+}}
+
+// namespaceRules are permissions of the provisioner in the namespace of the ProvisionManager.
+// In kubernetes mode it watches Contrail resources and their pods to find nodes to provision.
+var namespaceRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"pods"},
+	Verbs:     []string{"get", "list", "watch"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"configs", "controls", "vrouters", "cassandras"},
+	Verbs:     []string{"get", "list", "watch"},
+}}
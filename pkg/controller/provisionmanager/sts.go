@@ -58,6 +58,10 @@ spec:
           valueFrom:
             fieldRef:
               fieldPath: metadata.name
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
         image: docker.io/kaweue/contrail-provisioner:master.1175
         imagePullPolicy: IfNotPresent
         volumeMounts:
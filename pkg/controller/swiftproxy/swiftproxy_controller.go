@@ -214,7 +214,7 @@ func (r *ReconcileSwiftProxy) Reconcile(request reconcile.Request) (reconcile.Re
 	}
 	swiftConfigName := swiftProxy.Name + "-swiftproxy-config"
 	cm := r.configMap(swiftConfigName, swiftProxy, keystoneData, adminPasswordSecret, passwordSecret)
-	if err = cm.ensureExists(memcached.Status.Endpoint); err != nil {
+	if err = cm.ensureExists(memcached.Status.GetServers()); err != nil {
 		return reconcile.Result{}, err
 	}
 
@@ -274,6 +274,7 @@ func (r *ReconcileSwiftProxy) Reconcile(request reconcile.Request) (reconcile.Re
 			swiftProxy.Spec.ServiceConfiguration.Containers,
 			listenPort,
 		)
+		memcached.Status.SetServersAnnotation(&deployment.Spec.Template)
 
 		return controllerutil.SetControllerReference(swiftProxy, deployment, r.scheme)
 	})
@@ -9,22 +9,32 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 	"github.com/Juniper/contrail-operator/pkg/k8s"
+	"github.com/Juniper/contrail-operator/pkg/label"
 )
 
 var log = logf.Log.WithName("utils")
 var reqLogger = log.WithValues()
 
+// StatusMonitorLabel is set on Services exposing statusmonitor metrics, so they can be
+// selected by a Prometheus ServiceMonitor.
+const StatusMonitorLabel = "contrail_statusmonitor"
+
 // const defines the Group constants.
 const (
 	CASSANDRA   = "Cassandra.contrail.juniper.net"
@@ -253,6 +263,34 @@ func PodIPChange(appLabel map[string]string) predicate.Funcs {
 	}
 }
 
+// NodeKernelVersionChange returns predicate function which passes node updates changing kernel version.
+func NodeKernelVersionChange() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				reqLogger.Info("type conversion mismatch")
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				reqLogger.Info("type conversion mismatch")
+				return false
+			}
+			return oldNode.Status.NodeInfo.KernelVersion != newNode.Status.NodeInfo.KernelVersion
+		},
+	}
+}
+
+// NodeLabelsChange returns predicate function which passes updates of node labels.
+func NodeLabelsChange() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !labels.Equals(e.MetaOld.GetLabels(), e.MetaNew.GetLabels())
+		},
+	}
+}
+
 // PodInitStatusChange returns predicate function based on group kind.
 func PodInitStatusChange(appLabel map[string]string) predicate.Funcs {
 	return predicate.Funcs{
@@ -709,3 +747,35 @@ func getPodInitStatus(reconcileClient client.Client,
 	}
 	return podNameIPMap, nil
 }
+
+// StatusMonitorMetricsService returns Service which exposes Prometheus metrics served by the
+// statusmonitor sidecars of the instance pods on the "metrics" port. The node type is the one
+// the statusmonitor runs with, e.g. "database" for Cassandra pods.
+func StatusMonitorMetricsService(kubernetes *k8s.Kubernetes, instanceType, nodeType string, owner metav1.Object) *k8s.Service {
+	ports := map[int32]string{int32(v1alpha1.StatusMonitorMetricsPort(nodeType)): "metrics"}
+	serviceLabels := label.New(instanceType, owner.GetName())
+	serviceLabels[StatusMonitorLabel] = nodeType
+	return kubernetes.Service(owner.GetName()+"-"+nodeType+"-statusmonitor", corev1.ServiceTypeClusterIP, ports, instanceType, owner).
+		WithLabels(serviceLabels).
+		WithSelector(label.New(instanceType, owner.GetName()))
+}
+
+// ClusterInfoSources is implemented by cluster information gathered from cluster wide resources.
+type ClusterInfoSources interface {
+	Sources() []runtime.Object
+}
+
+// WatchClusterInfo watches resources the cluster information is gathered from, so that it is
+// re-evaluated when they change. Cluster information without such resources is not watched.
+func WatchClusterInfo(c controller.Controller, clusterInfo interface{}, h handler.EventHandler) error {
+	sources, ok := clusterInfo.(ClusterInfoSources)
+	if !ok {
+		return nil
+	}
+	for _, object := range sources.Sources() {
+		if err := c.Watch(&source.Kind{Type: object}, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package vrouter
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
+	"github.com/Juniper/contrail-operator/pkg/client/kubeproxy"
+)
+
+// agentConnector returns a connector to the introspect server of the vRouter agent running in the pod.
+type agentConnector func(namespace, pod string) (introspect.Connector, error)
+
+// kubeproxyAgentConnector reaches vRouter agents through the API server proxy.
+func kubeproxyAgentConnector(cfg *rest.Config) agentConnector {
+	return func(namespace, pod string) (introspect.Connector, error) {
+		proxy, err := kubeproxy.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubeproxy: %v", err)
+		}
+		return proxy.NewSecureClient(namespace, pod, v1alpha1.VrouterAgentIntrospectPort), nil
+	}
+}
+
+// manageControlConnections asks the agent of every vRouter pod for its XMPP sessions and
+// sets the ControlConnected condition. Agents which cannot be queried count as disconnected.
+func (r *ReconcileVrouter) manageControlConnections(instance *v1alpha1.Vrouter, podNameIPMap map[string]string) {
+	var disconnectedPods []string
+	for pod := range podNameIPMap {
+		connected, err := r.agentConnected(instance.Namespace, pod)
+		if err != nil {
+			log.Info("Failed to get vRouter agent state", "Pod", pod, "Error", err.Error())
+		}
+		if !connected {
+			disconnectedPods = append(disconnectedPods, pod)
+		}
+	}
+	instance.SetControlConnected(disconnectedPods)
+}
+
+func (r *ReconcileVrouter) agentConnected(namespace, pod string) (bool, error) {
+	connector, err := r.agentConnector(namespace, pod)
+	if err != nil {
+		return false, err
+	}
+	agent, err := introspect.NewClient(connector).VrouterAgent()
+	if err != nil {
+		return false, err
+	}
+	for _, peer := range agent.XmppPeers {
+		if peer.Status {
+			return true, nil
+		}
+	}
+	return false, nil
+}
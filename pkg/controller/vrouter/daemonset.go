@@ -3,7 +3,10 @@ package vrouter
 import (
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 )
 
 //GetDaemonset returns DaemonSet object for vRouter
@@ -29,7 +32,7 @@ func GetDaemonset() *apps.DaemonSet {
 		Name: "PHYSICAL_INTERFACE",
 		ValueFrom: &core.EnvVarSource{
 			FieldRef: &core.ObjectFieldSelector{
-				FieldPath: "metadata.annotations['physicalInterface']",
+				FieldPath: "metadata.annotations['vrouter.contrail.juniper.net/physical-interface']",
 			},
 		},
 	}
@@ -76,6 +79,7 @@ func GetDaemonset() *apps.DaemonSet {
 			Name:  "vrouterkernelinit",
 			Image: "docker.io/michaelhenkel/contrail-vrouter-kernel-init:5.2.0-dev1",
 			Env: []core.EnvVar{
+				physicalInterfaceEnv,
 				podIPEnv,
 			},
 			VolumeMounts: []core.VolumeMount{
@@ -191,6 +195,12 @@ func GetDaemonset() *apps.DaemonSet {
 			},
 			ImagePullPolicy: "IfNotPresent",
 		},
+		{
+			Name:            "statusmonitor",
+			Image:           "docker.io/kaweue/contrail-statusmonitor:debug",
+			Env:             []core.EnvVar{podIPEnv},
+			ImagePullPolicy: "IfNotPresent",
+		},
 	}
 
 	var podVolumes = []core.Volume{
@@ -347,3 +357,184 @@ func GetDaemonset() *apps.DaemonSet {
 
 	return &daemonSet
 }
+
+// addDpdkDatapath adds the vrouterdpdk container which runs the forwarding plane in user
+// space. It gets huge pages and shares the vhost-user socket directory with the agent.
+func addDpdkDatapath(ds *apps.DaemonSet, dpdk v1alpha1.VrouterDpdkConfiguration, dpdkContainer *v1alpha1.Container, envConfigMapName string) error {
+	hugePages, err := resource.ParseQuantity(dpdk.HugePages)
+	if err != nil {
+		return err
+	}
+	var trueVal = true
+	hugePagesResource := core.ResourceName(core.ResourceHugePagesPrefix + dpdk.HugePageSize)
+	hostPathDirectoryOrCreate := core.HostPathDirectoryOrCreate
+	podSpec := &ds.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes,
+		core.Volume{
+			Name: "hugepages",
+			VolumeSource: core.VolumeSource{
+				EmptyDir: &core.EmptyDirVolumeSource{
+					Medium: core.StorageMediumHugePages,
+				},
+			},
+		},
+		core.Volume{
+			Name: "vhost-user",
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: dpdk.VhostUserSocketDir,
+					Type: &hostPathDirectoryOrCreate,
+				},
+			},
+		},
+	)
+	vhostUserMount := core.VolumeMount{
+		Name:      "vhost-user",
+		MountPath: dpdk.VhostUserSocketDir,
+	}
+	for idx := range podSpec.Containers {
+		if podSpec.Containers[idx].Name == "vrouteragent" {
+			podSpec.Containers[idx].VolumeMounts = append(podSpec.Containers[idx].VolumeMounts, vhostUserMount)
+		}
+	}
+
+	command := []string{"bash", "-c", "/entrypoint.sh /usr/bin/contrail-vrouter-dpdk"}
+	if dpdkContainer.Command != nil {
+		command = dpdkContainer.Command
+	}
+	podSpec.Containers = append(podSpec.Containers, core.Container{
+		Name:    "vrouterdpdk",
+		Image:   dpdkContainer.Image,
+		Command: command,
+		Env: []core.EnvVar{
+			{
+				Name: "POD_IP",
+				ValueFrom: &core.EnvVarSource{
+					FieldRef: &core.ObjectFieldSelector{
+						FieldPath: "status.podIP",
+					},
+				},
+			},
+			{
+				Name: "PHYSICAL_INTERFACE",
+				ValueFrom: &core.EnvVarSource{
+					FieldRef: &core.ObjectFieldSelector{
+						FieldPath: "metadata.annotations['vrouter.contrail.juniper.net/physical-interface']",
+					},
+				},
+			},
+		},
+		EnvFrom: []core.EnvFromSource{{
+			ConfigMapRef: &core.ConfigMapEnvSource{
+				LocalObjectReference: core.LocalObjectReference{
+					Name: envConfigMapName,
+				},
+			},
+		}},
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      "vrouter-logs",
+				MountPath: "/var/log/contrail",
+			},
+			{
+				Name:      "dev",
+				MountPath: "/dev",
+			},
+			{
+				Name:      "hugepages",
+				MountPath: "/dev/hugepages",
+			},
+			{
+				Name:      "network-scripts",
+				MountPath: "/etc/sysconfig/network-scripts",
+			},
+			{
+				Name:      "host-usr-local-bin",
+				MountPath: "/host/bin",
+			},
+			{
+				Name:      "lib-modules",
+				MountPath: "/lib/modules",
+			},
+			{
+				Name:      "var-crashes",
+				MountPath: "/var/contrail/crashes",
+			},
+			vhostUserMount,
+		},
+		// Huge pages have to be requested together with memory or CPU and their request
+		// has to be equal to the limit.
+		Resources: core.ResourceRequirements{
+			Requests: core.ResourceList{
+				hugePagesResource:   hugePages,
+				core.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			Limits: core.ResourceList{
+				hugePagesResource: hugePages,
+			},
+		},
+		ImagePullPolicy: "IfNotPresent",
+		SecurityContext: &core.SecurityContext{
+			Privileged: &trueVal,
+		},
+	})
+	return nil
+}
+
+// addSriovInitContainer adds the init container which creates virtual functions on the
+// physical function given in the environment config map. The number of virtual
+// functions can only be changed after they were removed. Without a container in the spec
+// the default image and command are used.
+func addSriovInitContainer(ds *apps.DaemonSet, sriovContainer *v1alpha1.Container, envConfigMapName string) {
+	var trueVal = true
+	image := "busybox"
+	command := []string{"sh", "-c",
+		"numvfs=/sys/class/net/${SRIOV_PHYSICAL_INTERFACE}/device/sriov_numvfs; " +
+			"if [ \"$(cat ${numvfs})\" != \"${SRIOV_VF}\" ]; then " +
+			"echo 0 > ${numvfs} && echo ${SRIOV_VF} > ${numvfs}; fi"}
+	if sriovContainer != nil {
+		if sriovContainer.Image != "" {
+			image = sriovContainer.Image
+		}
+		if sriovContainer.Command != nil {
+			command = sriovContainer.Command
+		}
+	}
+	ds.Spec.Template.Spec.InitContainers = append(ds.Spec.Template.Spec.InitContainers, core.Container{
+		Name:    "sriovinit",
+		Image:   image,
+		Command: command,
+		EnvFrom: []core.EnvFromSource{{
+			ConfigMapRef: &core.ConfigMapEnvSource{
+				LocalObjectReference: core.LocalObjectReference{
+					Name: envConfigMapName,
+				},
+			},
+		}},
+		ImagePullPolicy: "IfNotPresent",
+		SecurityContext: &core.SecurityContext{
+			Privileged: &trueVal,
+		},
+	})
+}
+
+// excludeNodes keeps vRouter pods away from the given nodes with node affinity.
+func excludeNodes(ds *apps.DaemonSet, nodes []string) {
+	if len(nodes) == 0 {
+		return
+	}
+	if ds.Spec.Template.Spec.Affinity == nil {
+		ds.Spec.Template.Spec.Affinity = &core.Affinity{}
+	}
+	ds.Spec.Template.Spec.Affinity.NodeAffinity = &core.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+			NodeSelectorTerms: []core.NodeSelectorTerm{{
+				MatchFields: []core.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: core.NodeSelectorOpNotIn,
+					Values:   nodes,
+				}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package vrouter
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// clusterRules are permissions of vRouter pods in the whole cluster. The node init and
+// CNI containers look up the node they run on and pods scheduled there.
+var clusterRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"nodes", "pods"},
+	Verbs:     []string{"get", "list", "watch"},
+}}
+
+// namespaceRules are permissions of vRouter pods in the namespace of the Vrouter.
+// The status monitor reads its pod and reports the state of the vRouter.
+var namespaceRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"pods"},
+	Verbs:     []string{"get", "list"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"vrouters"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"vrouters/status"},
+	Verbs:     []string{"get", "update"},
+}}
@@ -10,7 +10,6 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -19,13 +18,12 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 	"github.com/Juniper/contrail-operator/pkg/certificates"
 	"github.com/Juniper/contrail-operator/pkg/controller/utils"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
 )
 
 var log = logf.Log.WithName("controller_vrouter")
@@ -103,7 +101,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // NewReconciler returns a new reconcile.Reconciler.
 func NewReconciler(client client.Client, scheme *runtime.Scheme, cfg *rest.Config) reconcile.Reconciler {
 	return &ReconcileVrouter{Client: client, Scheme: scheme,
-		Config: cfg}
+		Config: cfg, agentConnector: kubeproxyAgentConnector(cfg)}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
@@ -151,6 +149,38 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Node profiles change configuration rendered for vRouters in their namespace.
+	srcProfile := &source.Kind{Type: &v1alpha1.VrouterNodeProfile{}}
+	if err = c.Watch(srcProfile, resourceHandler(mgr.GetClient())); err != nil {
+		return err
+	}
+
+	// Kernel module compatibility is checked again when kernels of nodes change and
+	// node profiles are matched again when labels of nodes change.
+	srcNode := &source.Kind{Type: &corev1.Node{}}
+	nodeHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(nodeObject handler.MapObject) []reconcile.Request {
+			var vrouters v1alpha1.VrouterList
+			_ = mgr.GetClient().List(context.TODO(), &vrouters)
+			var requests []reconcile.Request
+			for _, vrouter := range vrouters.Items {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      vrouter.Name,
+						Namespace: vrouter.Namespace,
+					},
+				})
+			}
+			return requests
+		}),
+	}
+	if err = c.Watch(srcNode, nodeHandler, utils.NodeKernelVersionChange()); err != nil {
+		return err
+	}
+	if err = c.Watch(srcNode, nodeHandler, utils.NodeLabelsChange()); err != nil {
+		return err
+	}
+
 	srcDS := &source.Kind{Type: &appsv1.DaemonSet{}}
 	dsHandler := &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -174,6 +204,8 @@ type ReconcileVrouter struct {
 	Client client.Client
 	Scheme *runtime.Scheme
 	Config *rest.Config
+
+	agentConnector agentConnector
 }
 
 // Reconcile reads that state of the cluster for a Vrouter object and makes changes based on the state read
@@ -192,6 +224,18 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, nil
 	}
 
+	if err := instance.ValidateDatapath(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := instance.ValidateAgent(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := instance.ValidateKernelModule(); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	configMap, err := instance.CreateConfigMap(request.Name+"-"+instanceType+"-configmap", r.Client, r.Scheme, request)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -240,83 +284,9 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 		clusterRoleBindingName = "contrail-cluster-role-binding-cni"
 	}
 
-	existingServiceAccount := &corev1.ServiceAccount{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: instance.Namespace}, existingServiceAccount)
-	if err != nil && errors.IsNotFound(err) {
-		serviceAccount := &corev1.ServiceAccount{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "v1",
-				Kind:       "ServiceAccount",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceAccountName,
-				Namespace: instance.Namespace,
-			},
-		}
-		err = controllerutil.SetControllerReference(instance, serviceAccount, r.Scheme)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-		if err = r.Client.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
-			return reconcile.Result{}, err
-		}
-	}
-
-	existingClusterRole := &rbacv1.ClusterRole{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleName}, existingClusterRole)
-	if err != nil && errors.IsNotFound(err) {
-		clusterRole := &rbacv1.ClusterRole{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "rbac/v1",
-				Kind:       "ClusterRole",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      clusterRoleName,
-				Namespace: instance.Namespace,
-			},
-			Rules: []rbacv1.PolicyRule{{
-				Verbs: []string{
-					"*",
-				},
-				APIGroups: []string{
-					"*",
-				},
-				Resources: []string{
-					"*",
-				},
-			}},
-		}
-		if err = r.Client.Create(context.TODO(), clusterRole); err != nil {
-			return reconcile.Result{}, err
-		}
-	}
-
-	existingClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleBindingName}, existingClusterRoleBinding)
-	if err != nil && errors.IsNotFound(err) {
-		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "rbac/v1",
-				Kind:       "ClusterRoleBinding",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      clusterRoleBindingName,
-				Namespace: instance.Namespace,
-			},
-			Subjects: []rbacv1.Subject{{
-				Kind:      "ServiceAccount",
-				Name:      serviceAccountName,
-				Namespace: instance.Namespace,
-			}},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: "rbac.authorization.k8s.io",
-				Kind:     "ClusterRole",
-				Name:     clusterRoleName,
-			},
-		}
-		if err = r.Client.Create(context.TODO(), clusterRoleBinding); err != nil {
-			return reconcile.Result{}, err
-		}
+	if err = k8s.New(r.Client, r.Scheme).ServiceAccount(serviceAccountName, clusterRoleName, clusterRoleBindingName, instance).
+		EnsureExists(clusterRules, namespaceRules); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	daemonSet.Spec.Template.Spec.ServiceAccountName = serviceAccountName
@@ -325,6 +295,7 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 	if nodemgrContainer == nil {
 		nodemgr = false
 	}
+	statusMonitor := utils.GetContainerFromList("statusmonitor", instance.Spec.ServiceConfiguration.Containers) != nil
 	for idx, container := range daemonSet.Spec.Template.Spec.Containers {
 		if container.Name == "vrouteragent" {
 			command := []string{"bash", "-c",
@@ -398,6 +369,31 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 				(&daemonSet.Spec.Template.Spec.Containers[idx]).Image = instanceContainer.Image
 			}
 		}
+		if container.Name == "statusmonitor" && statusMonitor {
+			command := []string{"sh", "-c",
+				"/app/statusmonitor/contrail-statusmonitor-image.binary -config /etc/contrailconfigmaps/monitorconfig.${POD_IP}.yaml"}
+			instanceContainer := utils.GetContainerFromList(container.Name, instance.Spec.ServiceConfiguration.Containers)
+			if instanceContainer.Command == nil {
+				(&daemonSet.Spec.Template.Spec.Containers[idx]).Command = command
+			} else {
+				(&daemonSet.Spec.Template.Spec.Containers[idx]).Command = instanceContainer.Command
+			}
+			(&daemonSet.Spec.Template.Spec.Containers[idx]).VolumeMounts = []corev1.VolumeMount{
+				{
+					Name:      request.Name + "-" + instanceType + "-volume",
+					MountPath: "/etc/contrailconfigmaps",
+				},
+				{
+					Name:      request.Name + "-secret-certificates",
+					MountPath: "/etc/certificates",
+				},
+				{
+					Name:      csrSignerCaVolumeName,
+					MountPath: certificates.SignerCAMountPath,
+				},
+			}
+			(&daemonSet.Spec.Template.Spec.Containers[idx]).Image = instanceContainer.Image
+		}
 	}
 
 	if !nodemgr {
@@ -408,7 +404,20 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 		}
 	}
 
-	ubuntu := v1alpha1.UBUNTU
+	// The statusmonitor sidecar is optional, it is deployed only when its image is specified.
+	if statusMonitor {
+		if err = utils.StatusMonitorMetricsService(k8s.New(r.Client, r.Scheme), instanceType, instanceType, instance).EnsureExists(); err != nil {
+			return reconcile.Result{}, err
+		}
+	} else {
+		for idx, container := range daemonSet.Spec.Template.Spec.Containers {
+			if container.Name == "statusmonitor" {
+				daemonSet.Spec.Template.Spec.Containers = utils.RemoveIndex(daemonSet.Spec.Template.Spec.Containers, idx)
+				break
+			}
+		}
+	}
+
 	for idx, container := range daemonSet.Spec.Template.Spec.InitContainers {
 		instanceContainer := utils.GetContainerFromList(container.Name, instance.Spec.ServiceConfiguration.Containers)
 		(&daemonSet.Spec.Template.Spec.InitContainers[idx]).Image = instanceContainer.Image
@@ -423,8 +432,21 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 					},
 				},
 			}}
-			if instance.Spec.ServiceConfiguration.Distribution != nil || instance.Spec.ServiceConfiguration.Distribution == &ubuntu {
-				(&daemonSet.Spec.Template.Spec.InitContainers[idx]).Image = instanceContainer.Image
+			// The module is compiled against kernel headers of the node instead of installing a prebuilt one
+			if instance.KernelModuleSource() == v1alpha1.BuiltKernelModule {
+				buildInitContainer := utils.GetContainerFromList("vrouterkernelbuildinit", instance.Spec.ServiceConfiguration.Containers)
+				(&daemonSet.Spec.Template.Spec.InitContainers[idx]).Image = buildInitContainer.Image
+				if buildInitContainer.Command != nil {
+					(&daemonSet.Spec.Template.Spec.InitContainers[idx]).Command = buildInitContainer.Command
+				}
+			}
+			// With DPDK the interface is bound to the poll mode driver instead of loading the kernel module
+			if instance.Spec.ServiceConfiguration.Datapath == v1alpha1.DpdkDatapath {
+				dpdkInitContainer := utils.GetContainerFromList("vrouterkernelinitdpdk", instance.Spec.ServiceConfiguration.Containers)
+				(&daemonSet.Spec.Template.Spec.InitContainers[idx]).Image = dpdkInitContainer.Image
+				if dpdkInitContainer.Command != nil {
+					(&daemonSet.Spec.Template.Spec.InitContainers[idx]).Command = dpdkInitContainer.Command
+				}
 			}
 		}
 		if container.Name == "vroutercni" {
@@ -490,6 +512,38 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 		}
 
 	}
+
+	vrouterConfig := instance.ConfigurationParameters()
+	envConfigMapName := request.Name + "-" + instanceType + "-configmap-1"
+	if vrouterConfig.Dpdk != nil {
+		dpdkContainer := utils.GetContainerFromList("vrouterdpdk", instance.Spec.ServiceConfiguration.Containers)
+		if err = addDpdkDatapath(daemonSet, *vrouterConfig.Dpdk, dpdkContainer, envConfigMapName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if vrouterConfig.Sriov != nil {
+		sriovContainer := utils.GetContainerFromList("sriovinit", instance.Spec.ServiceConfiguration.Containers)
+		if sriovContainer == nil {
+			sriovContainer = utils.GetContainerFromList("init", instance.Spec.ServiceConfiguration.Containers)
+		}
+		addSriovInitContainer(daemonSet, sriovContainer, envConfigMapName)
+	}
+
+	incompatibleNodes, err := instance.ManageKernelModules(r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	excludeNodes(daemonSet, incompatibleNodes)
+
+	agentConfigHash, err := instance.AgentConfigHash(r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if daemonSet.Spec.Template.Annotations == nil {
+		daemonSet.Spec.Template.Annotations = map[string]string{}
+	}
+	daemonSet.Spec.Template.Annotations[v1alpha1.VrouterAgentConfigHashAnnotation] = agentConfigHash
+
 	if err = instance.CreateDS(daemonSet, &instance.Spec.CommonConfiguration, instanceType, request,
 		r.Scheme, r.Client); err != nil {
 		return reconcile.Result{}, err
@@ -524,6 +578,10 @@ func (r *ReconcileVrouter) Reconcile(request reconcile.Request) (reconcile.Resul
 			return reconcile.Result{}, err
 		}
 
+		if instance.Status.Active != nil && *instance.Status.Active {
+			r.manageControlConnections(instance, podIPMap)
+		}
+
 		if err = instance.ManageNodeStatus(podIPMap, r.Client); err != nil {
 			return reconcile.Result{}, err
 		}
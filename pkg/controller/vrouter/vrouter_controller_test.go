@@ -2,6 +2,9 @@ package vrouter
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +14,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batch "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -18,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
 )
 
 func TestVrouterController(t *testing.T) {
@@ -25,6 +30,7 @@ func TestVrouterController(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
 	require.NoError(t, batch.SchemeBuilder.AddToScheme(scheme))
 
 	trueVal := true
@@ -54,6 +60,7 @@ func TestVrouterController(t *testing.T) {
 						{Name: "vrouterkernelbuildinit", Image: "image5"},
 						{Name: "vrouterkernelinit", Image: "image6"},
 						{Name: "nodeinit", Image: "image7"},
+						{Name: "statusmonitor", Image: "image8"},
 					},
 				},
 			},
@@ -164,6 +171,18 @@ func TestVrouterController(t *testing.T) {
 		assert.Equal(t, expectedOwnerRefs, sa.OwnerReferences)
 	})
 
+	t.Run("should bind service account to least privilege roles", func(t *testing.T) {
+		clusterRole := &rbac.ClusterRole{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "contrail-cluster-role-cni"}, clusterRole))
+		assert.Equal(t, clusterRules, clusterRole.Rules)
+		role := &rbac.Role{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "contrail-cluster-role-cni", Namespace: "default"}, role))
+		assert.Equal(t, namespaceRules, role.Rules)
+		roleBinding := &rbac.RoleBinding{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "contrail-cluster-role-binding-cni", Namespace: "default"}, roleBinding))
+		assert.Equal(t, []rbac.Subject{{Kind: "ServiceAccount", Name: "contrail-service-account-cni", Namespace: "default"}}, roleBinding.Subjects)
+	})
+
 	t.Run("should create DaemonSet for vrouter", func(t *testing.T) {
 		ds := &appsv1.DaemonSet{}
 		err = fakeClient.Get(context.Background(), types.NamespacedName{
@@ -177,5 +196,340 @@ func TestVrouterController(t *testing.T) {
 			Controller: &trueVal, BlockOwnerDeletion: &trueVal,
 		}}
 		assert.Equal(t, expectedOwnerRefs, ds.OwnerReferences)
+		var statusMonitor *core.Container
+		for idx, container := range ds.Spec.Template.Spec.Containers {
+			if container.Name == "statusmonitor" {
+				statusMonitor = &ds.Spec.Template.Spec.Containers[idx]
+			}
+		}
+		assert.NotEmpty(t, ds.Spec.Template.Annotations[contrail.VrouterAgentConfigHashAnnotation])
+		require.NotNil(t, statusMonitor)
+		assert.Equal(t, "image8", statusMonitor.Image)
+		assert.Contains(t, statusMonitor.Command[2], "monitorconfig.${POD_IP}.yaml")
+	})
+
+	t.Run("should create statusmonitor metrics Service for vrouter", func(t *testing.T) {
+		svc := &core.Service{}
+		err = fakeClient.Get(context.Background(), types.NamespacedName{
+			Name:      "test-vrouter-vrouter-statusmonitor",
+			Namespace: "default",
+		}, svc)
+		require.NoError(t, err)
+		assert.Equal(t, "vrouter", svc.Labels["contrail_statusmonitor"])
+		assert.Equal(t, map[string]string{"contrail_manager": "vrouter", "vrouter": "test-vrouter"}, svc.Spec.Selector)
+		require.Len(t, svc.Spec.Ports, 1)
+		assert.Equal(t, "metrics", svc.Spec.Ports[0].Name)
+		assert.Equal(t, int32(contrail.VrouterStatusMonitorMetricsPort), svc.Spec.Ports[0].Port)
+	})
+}
+
+func TestVrouterControllerDatapath(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
+
+	vrouterName := types.NamespacedName{
+		Namespace: "default",
+		Name:      "test-vrouter",
+	}
+	newVrouter := func(configuration contrail.VrouterConfiguration) *contrail.Vrouter {
+		configuration.Containers = append([]*contrail.Container{
+			{Name: "init", Image: "image1"},
+			{Name: "nodemanager", Image: "image2"},
+			{Name: "vrouteragent", Image: "image3"},
+			{Name: "vrouterkernelinit", Image: "image6"},
+			{Name: "nodeinit", Image: "image7"},
+		}, configuration.Containers...)
+		return &contrail.Vrouter{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: vrouterName.Namespace,
+				Name:      vrouterName.Name,
+			},
+			Spec: contrail.VrouterSpec{
+				ServiceConfiguration: contrail.VrouterServiceConfiguration{
+					VrouterConfiguration: configuration,
+				},
+			},
+		}
+	}
+	getDaemonSet := func(t *testing.T, vrouter *contrail.Vrouter) *appsv1.DaemonSet {
+		fakeClient := fake.NewFakeClientWithScheme(scheme, vrouter)
+		reconciler := NewReconciler(fakeClient, scheme, &rest.Config{})
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: vrouterName})
+		require.NoError(t, err)
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{
+			Name:      "test-vrouter-vrouter-daemonset",
+			Namespace: "default",
+		}, ds))
+		return ds
+	}
+	findContainer := func(containers []core.Container, name string) *core.Container {
+		for idx := range containers {
+			if containers[idx].Name == name {
+				return &containers[idx]
+			}
+		}
+		return nil
+	}
+
+	t.Run("should add DPDK container with huge pages and vhost-user sockets", func(t *testing.T) {
+		ds := getDaemonSet(t, newVrouter(contrail.VrouterConfiguration{
+			Datapath: contrail.DpdkDatapath,
+			Dpdk:     &contrail.VrouterDpdkConfiguration{HugePages: "4Gi"},
+			Containers: []*contrail.Container{
+				{Name: "vrouterdpdk", Image: "dpdk"},
+				{Name: "vrouterkernelinitdpdk", Image: "kernel-init-dpdk"},
+			},
+		}))
+
+		dpdk := findContainer(ds.Spec.Template.Spec.Containers, "vrouterdpdk")
+		require.NotNil(t, dpdk)
+		assert.Equal(t, "dpdk", dpdk.Image)
+		hugePages := dpdk.Resources.Limits[core.ResourceName("hugepages-1Gi")]
+		assert.Equal(t, "4Gi", hugePages.String())
+		assert.Contains(t, dpdk.VolumeMounts, core.VolumeMount{Name: "vhost-user", MountPath: "/var/run/vrouter"})
+		agent := findContainer(ds.Spec.Template.Spec.Containers, "vrouteragent")
+		require.NotNil(t, agent)
+		assert.Contains(t, agent.VolumeMounts, core.VolumeMount{Name: "vhost-user", MountPath: "/var/run/vrouter"})
+		kernelInit := findContainer(ds.Spec.Template.Spec.InitContainers, "vrouterkernelinit")
+		require.NotNil(t, kernelInit)
+		assert.Equal(t, "kernel-init-dpdk", kernelInit.Image)
+	})
+
+	t.Run("should require DPDK containers", func(t *testing.T) {
+		vrouter := newVrouter(contrail.VrouterConfiguration{Datapath: contrail.DpdkDatapath})
+		reconciler := NewReconciler(fake.NewFakeClientWithScheme(scheme, vrouter), scheme, &rest.Config{})
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: vrouterName})
+		assert.Error(t, err)
+	})
+
+	t.Run("should add SR-IOV init container", func(t *testing.T) {
+		ds := getDaemonSet(t, newVrouter(contrail.VrouterConfiguration{
+			Datapath: contrail.SriovDatapath,
+			Sriov:    &contrail.VrouterSriovConfiguration{PhysicalFunction: "ens1f0", NumVFs: 8},
+		}))
+
+		sriovInit := findContainer(ds.Spec.Template.Spec.InitContainers, "sriovinit")
+		require.NotNil(t, sriovInit)
+		assert.Equal(t, "image1", sriovInit.Image)
+		assert.Nil(t, findContainer(ds.Spec.Template.Spec.Containers, "vrouterdpdk"))
+	})
+
+	t.Run("should use default SR-IOV init image and command without a container in the spec", func(t *testing.T) {
+		ds := &appsv1.DaemonSet{}
+		addSriovInitContainer(ds, nil, "env-configmap")
+
+		sriovInit := findContainer(ds.Spec.Template.Spec.InitContainers, "sriovinit")
+		require.NotNil(t, sriovInit)
+		assert.Equal(t, "busybox", sriovInit.Image)
+		assert.Contains(t, sriovInit.Command[2], "sriov_numvfs")
+	})
+
+	t.Run("should reject SR-IOV configuration without physical function or VF count", func(t *testing.T) {
+		for _, sriov := range []*contrail.VrouterSriovConfiguration{
+			{NumVFs: 8},
+			{PhysicalFunction: "ens1f0"},
+		} {
+			for _, datapath := range []contrail.VrouterDatapath{contrail.SriovDatapath, ""} {
+				vrouter := newVrouter(contrail.VrouterConfiguration{Datapath: datapath, Sriov: sriov})
+				reconciler := NewReconciler(fake.NewFakeClientWithScheme(scheme, vrouter), scheme, &rest.Config{})
+				_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: vrouterName})
+				assert.Error(t, err)
+			}
+		}
+	})
+
+	t.Run("should not change kernel datapath", func(t *testing.T) {
+		ds := getDaemonSet(t, newVrouter(contrail.VrouterConfiguration{}))
+
+		assert.Nil(t, findContainer(ds.Spec.Template.Spec.Containers, "vrouterdpdk"))
+		assert.Nil(t, findContainer(ds.Spec.Template.Spec.InitContainers, "sriovinit"))
+		kernelInit := findContainer(ds.Spec.Template.Spec.InitContainers, "vrouterkernelinit")
+		require.NotNil(t, kernelInit)
+		assert.Equal(t, "image6", kernelInit.Image)
+	})
+}
+
+func TestVrouterControllerKernelModule(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, apps.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
+
+	vrouterName := types.NamespacedName{
+		Namespace: "default",
+		Name:      "test-vrouter",
+	}
+	newNode := func(name, kernelVersion string) *core.Node {
+		return &core.Node{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"node-role.opencontrail.org": "vrouter"}},
+			Status: core.NodeStatus{NodeInfo: core.NodeSystemInfo{
+				KernelVersion: kernelVersion,
+				OSImage:       "CentOS Linux 7 (Core)",
+			}},
+		}
+	}
+	newVrouter := func(configuration contrail.VrouterConfiguration) *contrail.Vrouter {
+		configuration.Containers = append([]*contrail.Container{
+			{Name: "init", Image: "image1"},
+			{Name: "nodemanager", Image: "image2"},
+			{Name: "vrouteragent", Image: "image3"},
+			{Name: "vrouterkernelinit", Image: "kernel-init"},
+			{Name: "nodeinit", Image: "image7"},
+		}, configuration.Containers...)
+		return &contrail.Vrouter{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: vrouterName.Namespace,
+				Name:      vrouterName.Name,
+			},
+			Spec: contrail.VrouterSpec{
+				CommonConfiguration: contrail.PodConfiguration{
+					NodeSelector: map[string]string{"node-role.opencontrail.org": "vrouter"},
+				},
+				ServiceConfiguration: contrail.VrouterServiceConfiguration{
+					VrouterConfiguration: configuration,
+				},
+			},
+		}
+	}
+	reconcileVrouter := func(t *testing.T, vrouter *contrail.Vrouter) (*appsv1.DaemonSet, *contrail.Vrouter) {
+		fakeClient := fake.NewFakeClientWithScheme(scheme, vrouter,
+			newNode("node1", "3.10.0-1127.el7.x86_64"), newNode("node2", "5.4.0-42-generic"))
+		reconciler := NewReconciler(fakeClient, scheme, &rest.Config{})
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: vrouterName})
+		require.NoError(t, err)
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{
+			Name:      "test-vrouter-vrouter-daemonset",
+			Namespace: "default",
+		}, ds))
+		updatedVrouter := &contrail.Vrouter{}
+		require.NoError(t, fakeClient.Get(context.Background(), vrouterName, updatedVrouter))
+		return ds, updatedVrouter
+	}
+
+	t.Run("should exclude nodes without prebuilt kernel module", func(t *testing.T) {
+		ds, vrouter := reconcileVrouter(t, newVrouter(contrail.VrouterConfiguration{
+			KernelModule: &contrail.VrouterKernelModuleConfiguration{SupportedKernels: []string{"3.10.0-1127*"}},
+		}))
+
+		require.NotNil(t, ds.Spec.Template.Spec.Affinity.NodeAffinity)
+		terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Equal(t, []core.NodeSelectorTerm{{MatchFields: []core.NodeSelectorRequirement{{
+			Key: "metadata.name", Operator: core.NodeSelectorOpNotIn, Values: []string{"node2"},
+		}}}}, terms)
+		assert.Equal(t, map[string]contrail.VrouterKernelModuleStatus{
+			"node1": {KernelVersion: "3.10.0-1127.el7.x86_64", OSImage: "CentOS Linux 7 (Core)", Source: contrail.PrebuiltKernelModule, Compatible: true},
+			"node2": {KernelVersion: "5.4.0-42-generic", OSImage: "CentOS Linux 7 (Core)", Source: contrail.PrebuiltKernelModule, Compatible: false},
+		}, vrouter.Status.KernelModules)
+		assert.Equal(t, []contrail.VrouterCondition{{
+			Type:    contrail.VrouterKernelModuleCompatible,
+			Status:  contrail.ConditionFalse,
+			Reason:  "IncompatibleKernel",
+			Message: "no prebuilt vRouter kernel module for nodes node2 (5.4.0-42-generic)",
+		}}, vrouter.Status.Conditions)
+	})
+
+	t.Run("should build kernel module on Ubuntu", func(t *testing.T) {
+		ubuntu := contrail.UBUNTU
+		ds, vrouter := reconcileVrouter(t, newVrouter(contrail.VrouterConfiguration{
+			Distribution: &ubuntu,
+			KernelModule: &contrail.VrouterKernelModuleConfiguration{SupportedKernels: []string{"3.10.0-1127*"}},
+			Containers:   []*contrail.Container{{Name: "vrouterkernelbuildinit", Image: "kernel-build-init"}},
+		}))
+
+		assert.Nil(t, ds.Spec.Template.Spec.Affinity.NodeAffinity)
+		var kernelInit *core.Container
+		for idx, container := range ds.Spec.Template.Spec.InitContainers {
+			if container.Name == "vrouterkernelinit" {
+				kernelInit = &ds.Spec.Template.Spec.InitContainers[idx]
+			}
+		}
+		require.NotNil(t, kernelInit)
+		assert.Equal(t, "kernel-build-init", kernelInit.Image)
+		assert.Equal(t, contrail.BuiltKernelModule, vrouter.Status.KernelModules["node2"].Source)
+		assert.Equal(t, []contrail.VrouterCondition{{Type: contrail.VrouterKernelModuleCompatible, Status: contrail.ConditionTrue}}, vrouter.Status.Conditions)
+	})
+
+	t.Run("should require build container when building is requested", func(t *testing.T) {
+		build := true
+		vrouter := newVrouter(contrail.VrouterConfiguration{
+			KernelModule: &contrail.VrouterKernelModuleConfiguration{Build: &build},
+		})
+		reconciler := NewReconciler(fake.NewFakeClientWithScheme(scheme, vrouter), scheme, &rest.Config{})
+		_, err := reconciler.Reconcile(reconcile.Request{NamespacedName: vrouterName})
+		assert.Error(t, err)
+	})
+}
+
+func TestVrouterControllerControlConnections(t *testing.T) {
+	agentResponse := func(peerStatus bool) string {
+		return fmt.Sprintf(`<?xml-stylesheet type="text/xsl" href="/universal_parse.xsl"?>
+<__UveVrouterAgent_list type="slist">
+  <UveVrouterAgent type="sandesh">
+    <data type="struct" identifier="1">
+      <VrouterAgent>
+        <name type="string" identifier="1" key="ObjectVRouter">node1</name>
+        <xmpp_peer_list type="list" identifier="19">
+          <list type="struct" size="1">
+            <AgentXmppPeer>
+              <ip type="string" identifier="1">10.0.0.1</ip>
+              <status type="bool" identifier="2">%t</status>
+              <primary type="bool" identifier="4">true</primary>
+            </AgentXmppPeer>
+          </list>
+        </xmpp_peer_list>
+      </VrouterAgent>
+    </data>
+  </UveVrouterAgent>
+</__UveVrouterAgent_list>`, peerStatus)
+	}
+	agents := map[string]*httptest.Server{
+		"vrouter-connected": httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "/Snh_SandeshUVECacheReq?x=VrouterAgent", req.URL.RequestURI())
+			_, _ = w.Write([]byte(agentResponse(true)))
+		})),
+		"vrouter-disconnected": httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte(agentResponse(false)))
+		})),
+		"vrouter-unreachable": httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})),
+	}
+	for _, agent := range agents {
+		defer agent.Close()
+	}
+	reconciler := &ReconcileVrouter{
+		agentConnector: func(namespace, pod string) (introspect.Connector, error) {
+			assert.Equal(t, "default", namespace)
+			return introspect.NewConnector(agents[pod].Listener.Addr().String(), agents[pod].Client()), nil
+		},
+	}
+
+	t.Run("should set ControlConnected when all agents have an XMPP session", func(t *testing.T) {
+		vrouter := &contrail.Vrouter{ObjectMeta: v1.ObjectMeta{Namespace: "default", Name: "vrouter1"}}
+		reconciler.manageControlConnections(vrouter, map[string]string{"vrouter-connected": "10.0.0.10"})
+
+		assert.Equal(t, []contrail.VrouterCondition{{Type: contrail.VrouterControlConnected, Status: contrail.ConditionTrue}}, vrouter.Status.Conditions)
+	})
+
+	t.Run("should list pods of disconnected and unreachable agents", func(t *testing.T) {
+		vrouter := &contrail.Vrouter{ObjectMeta: v1.ObjectMeta{Namespace: "default", Name: "vrouter1"}}
+		reconciler.manageControlConnections(vrouter, map[string]string{
+			"vrouter-connected":    "10.0.0.10",
+			"vrouter-disconnected": "10.0.0.11",
+			"vrouter-unreachable":  "10.0.0.12",
+		})
+
+		assert.Equal(t, []contrail.VrouterCondition{{
+			Type:    contrail.VrouterControlConnected,
+			Status:  contrail.ConditionFalse,
+			Reason:  "NoXMPPSession",
+			Message: "vRouter agents of pods vrouter-disconnected, vrouter-unreachable are not connected to a control node",
+		}}, vrouter.Status.Conditions)
 	})
 }
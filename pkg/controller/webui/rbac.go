@@ -0,0 +1,23 @@
+package webui
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// namespaceRules are permissions of WebUI pods in the namespace of the Webui. Only the
+// status monitor uses the Kubernetes API, so no cluster wide permissions are needed.
+var namespaceRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"pods"},
+	Verbs:     []string{"get", "list"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"webuis"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{v1alpha1.SchemeGroupVersion.Group},
+	Resources: []string{"webuis/status"},
+	Verbs:     []string{"get", "update"},
+}}
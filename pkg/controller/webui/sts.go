@@ -87,6 +87,14 @@ spec:
               name: webui-logs
             - mountPath: /var/lib/redis
               name: webui-data
+        - name: statusmonitor
+          image: docker.io/kaweue/contrail-statusmonitor:debug
+          env:
+            - name: POD_IP
+              valueFrom:
+                fieldRef:
+                  fieldPath: status.podIP
+          imagePullPolicy: IfNotPresent
       dnsPolicy: ClusterFirst
       hostNetwork: true
       nodeSelector:
@@ -6,7 +6,6 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -16,7 +15,6 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -209,6 +207,9 @@ func (r *ReconcileWebui) Reconcile(request reconcile.Request) (reconcile.Result,
 	if err := webuiService.EnsureExists(); err != nil {
 		return reconcile.Result{}, err
 	}
+	if err := utils.StatusMonitorMetricsService(r.Kubernetes, instanceType, instanceType, instance).EnsureExists(); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	configActive := configInstance.IsActive(instance.Labels["contrail_cluster"], request.Namespace, r.Client)
 	if !configActive {
@@ -259,85 +260,12 @@ func (r *ReconcileWebui) Reconcile(request reconcile.Request) (reconcile.Result,
 		clusterRoleBindingName = "contrail-webui-cluster-role-binding"
 	}
 
-	existingServiceAccount := &corev1.ServiceAccount{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: instance.Namespace}, existingServiceAccount)
-	if err != nil && errors.IsNotFound(err) {
-		serviceAccount := &corev1.ServiceAccount{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "v1",
-				Kind:       "ServiceAccount",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceAccountName,
-				Namespace: instance.Namespace,
-			},
-		}
-		err = controllerutil.SetControllerReference(instance, serviceAccount, r.Scheme)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-		if err = r.Client.Create(context.TODO(), serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
-			return reconcile.Result{}, err
-		}
-	}
-
-	existingClusterRole := &rbacv1.ClusterRole{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleName}, existingClusterRole)
-	if err != nil && errors.IsNotFound(err) {
-		clusterRole := &rbacv1.ClusterRole{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "rbac/v1",
-				Kind:       "ClusterRole",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      clusterRoleName,
-				Namespace: instance.Namespace,
-			},
-			Rules: []rbacv1.PolicyRule{{
-				Verbs: []string{
-					"*",
-				},
-				APIGroups: []string{
-					"*",
-				},
-				Resources: []string{
-					"*",
-				},
-			}},
-		}
-		if err = r.Client.Create(context.TODO(), clusterRole); err != nil {
-			return reconcile.Result{}, err
-		}
-	}
-
-	existingClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleBindingName}, existingClusterRoleBinding)
-	if err != nil && errors.IsNotFound(err) {
-		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "rbac/v1",
-				Kind:       "ClusterRoleBinding",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      clusterRoleBindingName,
-				Namespace: instance.Namespace,
-			},
-			Subjects: []rbacv1.Subject{{
-				Kind:      "ServiceAccount",
-				Name:      serviceAccountName,
-				Namespace: instance.Namespace,
-			}},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: "rbac.authorization.k8s.io",
-				Kind:     "ClusterRole",
-				Name:     clusterRoleName,
-			},
-		}
-		if err = r.Client.Create(context.TODO(), clusterRoleBinding); err != nil {
-			return reconcile.Result{}, err
-		}
+	if err = r.Kubernetes.ServiceAccount(serviceAccountName, clusterRoleName, clusterRoleBindingName, instance).
+		EnsureExists(nil, namespaceRules); err != nil {
+		return reconcile.Result{}, err
 	}
 	statefulSet.Spec.Template.Spec.ServiceAccountName = serviceAccountName
+	statusMonitor := utils.GetContainerFromList("statusmonitor", instance.Spec.ServiceConfiguration.Containers) != nil
 	for idx, container := range statefulSet.Spec.Template.Spec.Containers {
 		if container.Name == "webuiweb" {
 			command := []string{"bash", "-c",
@@ -445,6 +373,40 @@ func (r *ReconcileWebui) Reconcile(request reconcile.Request) (reconcile.Result,
 			}
 			(&statefulSet.Spec.Template.Spec.Containers[idx]).ReadinessProbe = &probe
 		}
+		if container.Name == "statusmonitor" && statusMonitor {
+			command := []string{"sh", "-c",
+				"/app/statusmonitor/contrail-statusmonitor-image.binary -config /etc/contrailconfigmaps/monitorconfig.${POD_IP}.yaml"}
+			instanceContainer := utils.GetContainerFromList(container.Name, instance.Spec.ServiceConfiguration.Containers)
+			if instanceContainer.Command == nil {
+				(&statefulSet.Spec.Template.Spec.Containers[idx]).Command = command
+			} else {
+				(&statefulSet.Spec.Template.Spec.Containers[idx]).Command = instanceContainer.Command
+			}
+			(&statefulSet.Spec.Template.Spec.Containers[idx]).VolumeMounts = []corev1.VolumeMount{
+				{
+					Name:      request.Name + "-" + instanceType + "-volume",
+					MountPath: "/etc/contrailconfigmaps",
+				},
+				{
+					Name:      request.Name + "-secret-certificates",
+					MountPath: "/etc/certificates",
+				},
+				{
+					Name:      csrSignerCaVolumeName,
+					MountPath: certificates.SignerCAMountPath,
+				},
+			}
+			(&statefulSet.Spec.Template.Spec.Containers[idx]).Image = instanceContainer.Image
+		}
+	}
+	// The statusmonitor sidecar is optional, it is deployed only when its image is specified.
+	if !statusMonitor {
+		for idx, container := range statefulSet.Spec.Template.Spec.Containers {
+			if container.Name == "statusmonitor" {
+				statefulSet.Spec.Template.Spec.Containers = utils.RemoveIndex(statefulSet.Spec.Template.Spec.Containers, idx)
+				break
+			}
+		}
 	}
 	statefulSet.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
@@ -534,11 +496,16 @@ func (r *ReconcileWebui) updateServiceStatus(cr *v1alpha1.Webui) error {
 	for _, pod := range pods.Items {
 		podStatus := v1alpha1.WebUIServiceStatusMap{}
 		for _, containerStatus := range pod.Status.ContainerStatuses {
+			name := strings.Title(containerStatus.Name)
 			status := "Non-Functional"
 			if containerStatus.Ready {
 				status = "Functional"
+				// Keep the state reported by the statusmonitor, it also checks if the service responds.
+				if reported, ok := cr.Status.ServiceStatus[pod.Spec.NodeName][name]; ok && reported.ModuleState != "Non-Functional" {
+					status = reported.ModuleState
+				}
 			}
-			podStatus[strings.Title(containerStatus.Name)] = v1alpha1.WebUIServiceStatus{ModuleName: containerStatus.Name, ModuleState: status}
+			podStatus[name] = v1alpha1.WebUIServiceStatus{ModuleName: containerStatus.Name, ModuleState: status}
 		}
 		serviceStatuses[pod.Spec.NodeName] = podStatus
 	}
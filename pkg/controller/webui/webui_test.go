@@ -1 +1,44 @@
 package webui_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	contrail "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/controller/webui"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
+)
+
+func TestWebuiControllerStatusMonitorMetricsService(t *testing.T) {
+	scheme, err := contrail.SchemeBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	webUI := &contrail.Webui{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "webui",
+			Namespace: "default",
+			Labels:    map[string]string{"contrail_cluster": "cluster1"},
+		},
+	}
+	cl := fake.NewFakeClientWithScheme(scheme, webUI)
+	r := &webui.ReconcileWebui{Client: cl, Scheme: scheme, Kubernetes: k8s.New(cl, scheme)}
+
+	_, err = r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "webui", Namespace: "default"}})
+	require.NoError(t, err)
+
+	svc := &core.Service{}
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "webui-webui-statusmonitor", Namespace: "default"}, svc))
+	assert.Equal(t, "webui", svc.Labels["contrail_statusmonitor"])
+	assert.Equal(t, map[string]string{"contrail_manager": "webui", "webui": "webui"}, svc.Spec.Selector)
+	require.Len(t, svc.Spec.Ports, 1)
+	assert.Equal(t, "metrics", svc.Spec.Ports[0].Name)
+	assert.Equal(t, int32(contrail.WebuiStatusMonitorMetricsPort), svc.Spec.Ports[0].Port)
+}
@@ -123,6 +123,11 @@ func (c ClusterConfig) DeploymentType() string {
 	return "k8s"
 }
 
+// NetworkMTU returns 0 as kubeadm does not configure pod interfaces MTU, the CNI plugin default is used
+func (c ClusterConfig) NetworkMTU() (int, error) {
+	return 0, nil
+}
+
 type configMap struct {
 	ControlPlaneEndpoint string     `yaml:"controlPlaneEndpoint"`
 	ClusterName          string     `yaml:"clusterName"`
@@ -32,3 +32,23 @@ func (c FakeClusterInfo) ServiceSubnets() (string, error) {
 func (c FakeClusterInfo) KubernetesAPISSLPort() (int, error) {
 	return 6443, nil
 }
+
+// FakeRemoteClusterInfo is a remote cluster reached with a kubeconfig token
+type FakeRemoteClusterInfo struct {
+	FakeClusterInfo
+}
+
+// KubernetesAPIServer gathers API Server from the kubeconfig of remote cluster
+func (c FakeRemoteClusterInfo) KubernetesAPIServer() (string, error) {
+	return "2.2.2.2", nil
+}
+
+// KubernetesClusterName gathers cluster name from remote cluster via kubeadm-config ConfigMap
+func (c FakeRemoteClusterInfo) KubernetesClusterName() (string, error) {
+	return "remote_cluster", nil
+}
+
+// KubernetesAPIToken gathers token from the kubeconfig of remote cluster
+func (c FakeRemoteClusterInfo) KubernetesAPIToken() string {
+	return "remote_token"
+}
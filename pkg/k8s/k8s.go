@@ -51,3 +51,8 @@ func (k *Kubernetes) Secret(name, ownerType string, owner v1.Object) *Secret {
 func (k *Kubernetes) Service(name string, servType core.ServiceType, ports map[int32]string, ownerType string, owner v1.Object) *Service {
 	return &Service{name: name, servType: servType, ports: ports, ownerType: ownerType, owner: owner, client: k.client, scheme: k.scheme}
 }
+
+// ServiceAccount is used to create ServiceAccount object bound to roles of the given names
+func (k *Kubernetes) ServiceAccount(name, roleName, roleBindingName string, owner v1.Object) *ServiceAccount {
+	return &ServiceAccount{name: name, roleName: roleName, roleBindingName: roleBindingName, owner: owner, client: k.client, scheme: k.scheme}
+}
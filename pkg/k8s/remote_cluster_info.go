@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// RemoteClusterConfig is a struct that incorporates v1alpha1.KubemanagerRemoteClusterInfo interface.
+// It describes a cluster other than the one running the operator, reached with the current context of a kubeconfig.
+type RemoteClusterConfig struct {
+	ClusterConfig
+	apiServer  string
+	apiSSLPort int
+	token      string
+}
+
+// NewRemoteClusterConfig creates cluster information of the cluster the kubeconfig points to.
+// Cluster name and subnets are gathered from the kubeadm-config ConfigMap of that cluster.
+func NewRemoteClusterConfig(kubeconfig []byte) (*RemoteClusterConfig, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if restConfig.BearerToken == "" {
+		return nil, errors.New("kubeconfig of a remote cluster has to authenticate with a token")
+	}
+	apiServer, apiSSLPort, err := apiEndpoint(restConfig.Host)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteClusterConfig{
+		ClusterConfig: ClusterConfig{Client: clientset.CoreV1()},
+		apiServer:     apiServer,
+		apiSSLPort:    apiSSLPort,
+		token:         restConfig.BearerToken,
+	}, nil
+}
+
+// KubernetesAPISSLPort returns port of the API server from the kubeconfig
+func (c RemoteClusterConfig) KubernetesAPISSLPort() (int, error) {
+	return c.apiSSLPort, nil
+}
+
+// KubernetesAPIServer returns address of the API server from the kubeconfig
+func (c RemoteClusterConfig) KubernetesAPIServer() (string, error) {
+	return c.apiServer, nil
+}
+
+// KubernetesAPIToken returns token of the kubeconfig user
+func (c RemoteClusterConfig) KubernetesAPIToken() string {
+	return c.token
+}
+
+func apiEndpoint(host string) (string, int, error) {
+	apiURL, err := url.Parse(host)
+	if err != nil {
+		return "", 0, err
+	}
+	if apiURL.Hostname() == "" {
+		return "", 0, errors.New("kubeconfig of a remote cluster has no server address")
+	}
+	if apiURL.Port() == "" {
+		return apiURL.Hostname(), 443, nil
+	}
+	port, err := strconv.Atoi(apiURL.Port())
+	if err != nil {
+		return "", 0, err
+	}
+	return apiURL.Hostname(), port, nil
+}
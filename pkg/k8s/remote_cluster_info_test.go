@@ -0,0 +1,59 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/k8s"
+)
+
+func remoteKubeconfig(server, user string) []byte {
+	return []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: ` + server + `
+users:
+- name: remote-user
+  user:
+` + user + `
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote-user
+current-context: remote
+`)
+}
+
+func TestNewRemoteClusterConfig(t *testing.T) {
+	t.Run("should read API endpoint and token from kubeconfig", func(t *testing.T) {
+		clusterInfo, err := k8s.NewRemoteClusterConfig(remoteKubeconfig("https://10.0.0.1:6443", "    token: remote-token"))
+		require.NoError(t, err)
+		var _ v1alpha1.KubemanagerRemoteClusterInfo = clusterInfo
+		server, err := clusterInfo.KubernetesAPIServer()
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.1", server)
+		port, err := clusterInfo.KubernetesAPISSLPort()
+		require.NoError(t, err)
+		assert.Equal(t, 6443, port)
+		assert.Equal(t, "remote-token", clusterInfo.KubernetesAPIToken())
+	})
+
+	t.Run("should use default https port", func(t *testing.T) {
+		clusterInfo, err := k8s.NewRemoteClusterConfig(remoteKubeconfig("https://api.remote.example.com", "    token: remote-token"))
+		require.NoError(t, err)
+		port, err := clusterInfo.KubernetesAPISSLPort()
+		require.NoError(t, err)
+		assert.Equal(t, 443, port)
+	})
+
+	t.Run("should require token authentication", func(t *testing.T) {
+		_, err := k8s.NewRemoteClusterConfig(remoteKubeconfig("https://10.0.0.1:6443", "    username: admin\n    password: secret"))
+		assert.Error(t, err)
+	})
+}
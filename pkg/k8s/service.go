@@ -20,6 +20,7 @@ type Service struct {
 	ports       map[int32]string
 	ownerType   string
 	labels      map[string]string
+	selector    map[string]string
 	annotations map[string]string
 	owner       v1.Object
 	scheme      *runtime.Scheme
@@ -33,6 +34,10 @@ func (s *Service) EnsureExists() error {
 	if len(labels) == 0 {
 		labels = label.New(s.ownerType, s.owner.GetName())
 	}
+	selector := s.selector
+	if len(selector) == 0 {
+		selector = labels
+	}
 	s.svc = core.Service{
 		ObjectMeta: meta.ObjectMeta{
 			Name:        s.name,
@@ -59,7 +64,7 @@ func (s *Service) EnsureExists() error {
 			servicePortList = append(servicePortList, svcPort)
 		}
 		s.svc.Spec.Ports = servicePortList
-		s.svc.Spec.Selector = labels
+		s.svc.Spec.Selector = selector
 		s.svc.Spec.Type = s.servType
 		return controllerutil.SetControllerReference(s.owner, &s.svc, s.scheme)
 	})
@@ -100,6 +105,12 @@ func (s *Service) WithLabels(labels map[string]string) *Service {
 	return s
 }
 
+// WithSelector is used to select pods of the Service with labels other than the Service labels
+func (s *Service) WithSelector(selector map[string]string) *Service {
+	s.selector = selector
+	return s
+}
+
 // WithAnnotations is used to set annotations on Service
 func (s *Service) WithAnnotations(annotations map[string]string) *Service {
 	s.annotations = annotations
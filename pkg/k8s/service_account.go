@@ -0,0 +1,158 @@
+package k8s
+
+import (
+	"context"
+
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ServiceAccount is used to create and manage a service account together with its RBAC roles.
+// Cluster wide permissions are granted with a ClusterRole and a ClusterRoleBinding, permissions
+// in the namespace of the owner with a Role and a RoleBinding of the same names.
+type ServiceAccount struct {
+	name            string
+	roleName        string
+	roleBindingName string
+	owner           v1.Object
+	scheme          *runtime.Scheme
+	client          client.Client
+}
+
+// EnsureExists is used to make sure that the service account exists and is bound to roles
+// having exactly the given rules. Roles which are not needed because no rules are given
+// for them are removed.
+func (s *ServiceAccount) EnsureExists(clusterRules, namespaceRules []rbac.PolicyRule) error {
+	if err := s.ensureServiceAccount(); err != nil {
+		return err
+	}
+	if err := s.ensureClusterRole(clusterRules); err != nil {
+		return err
+	}
+	return s.ensureRole(namespaceRules)
+}
+
+func (s *ServiceAccount) ensureServiceAccount() error {
+	sa := &core.ServiceAccount{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      s.name,
+			Namespace: s.owner.GetNamespace(),
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(context.Background(), s.client, sa, func() error {
+		return s.setControllerReferenceIfMissing(sa)
+	})
+	return err
+}
+
+func (s *ServiceAccount) ensureClusterRole(rules []rbac.PolicyRule) error {
+	clusterRole := &rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: s.roleName}}
+	clusterRoleBinding := &rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: s.roleBindingName}}
+	if len(rules) == 0 {
+		if err := s.deleteIfExists(clusterRoleBinding); err != nil {
+			return err
+		}
+		return s.deleteIfExists(clusterRole)
+	}
+	if _, err := controllerutil.CreateOrUpdate(context.Background(), s.client, clusterRole, func() error {
+		clusterRole.Rules = rules
+		return nil
+	}); err != nil {
+		return err
+	}
+	roleRef := rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: s.roleName}
+	existingClusterRoleBinding := &rbac.ClusterRoleBinding{}
+	if err := s.deleteIfRoleRefDiffers(existingClusterRoleBinding, types.NamespacedName{Name: s.roleBindingName}, roleRef); err != nil {
+		return err
+	}
+	_, err := controllerutil.CreateOrUpdate(context.Background(), s.client, clusterRoleBinding, func() error {
+		clusterRoleBinding.Subjects = s.subjects()
+		clusterRoleBinding.RoleRef = roleRef
+		return nil
+	})
+	return err
+}
+
+func (s *ServiceAccount) ensureRole(rules []rbac.PolicyRule) error {
+	objectMeta := meta.ObjectMeta{Name: s.roleName, Namespace: s.owner.GetNamespace()}
+	role := &rbac.Role{ObjectMeta: objectMeta}
+	objectMeta.Name = s.roleBindingName
+	roleBinding := &rbac.RoleBinding{ObjectMeta: objectMeta}
+	if len(rules) == 0 {
+		if err := s.deleteIfExists(roleBinding); err != nil {
+			return err
+		}
+		return s.deleteIfExists(role)
+	}
+	if _, err := controllerutil.CreateOrUpdate(context.Background(), s.client, role, func() error {
+		role.Rules = rules
+		return s.setControllerReferenceIfMissing(role)
+	}); err != nil {
+		return err
+	}
+	roleRef := rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: s.roleName}
+	existingRoleBinding := &rbac.RoleBinding{}
+	if err := s.deleteIfRoleRefDiffers(existingRoleBinding, types.NamespacedName{Name: s.roleBindingName, Namespace: s.owner.GetNamespace()}, roleRef); err != nil {
+		return err
+	}
+	_, err := controllerutil.CreateOrUpdate(context.Background(), s.client, roleBinding, func() error {
+		roleBinding.Subjects = s.subjects()
+		roleBinding.RoleRef = roleRef
+		return s.setControllerReferenceIfMissing(roleBinding)
+	})
+	return err
+}
+
+// setControllerReferenceIfMissing makes the owner a controller of the object, unless the object is
+// already controlled by another one, e.g. when the service account is shared by several instances.
+func (s *ServiceAccount) setControllerReferenceIfMissing(obj v1.Object) error {
+	if meta.GetControllerOf(obj) != nil {
+		return nil
+	}
+	return controllerutil.SetControllerReference(s.owner, obj, s.scheme)
+}
+
+func (s *ServiceAccount) subjects() []rbac.Subject {
+	return []rbac.Subject{{
+		Kind:      rbac.ServiceAccountKind,
+		Name:      s.name,
+		Namespace: s.owner.GetNamespace(),
+	}}
+}
+
+// deleteIfRoleRefDiffers removes the binding when it refers to another role,
+// because role reference of a binding cannot be updated.
+func (s *ServiceAccount) deleteIfRoleRefDiffers(binding runtime.Object, name types.NamespacedName, roleRef rbac.RoleRef) error {
+	err := s.client.Get(context.Background(), name, binding)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var currentRoleRef rbac.RoleRef
+	switch b := binding.(type) {
+	case *rbac.ClusterRoleBinding:
+		currentRoleRef = b.RoleRef
+	case *rbac.RoleBinding:
+		currentRoleRef = b.RoleRef
+	}
+	if currentRoleRef == roleRef {
+		return nil
+	}
+	return s.client.Delete(context.Background(), binding)
+}
+
+func (s *ServiceAccount) deleteIfExists(obj runtime.Object) error {
+	if err := s.client.Delete(context.Background(), obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
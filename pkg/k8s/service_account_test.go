@@ -0,0 +1,132 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Juniper/contrail-operator/pkg/k8s"
+)
+
+func TestEnsureServiceAccountExists(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, core.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, rbac.SchemeBuilder.AddToScheme(scheme))
+	owner := &core.Pod{ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: "test-pod"}}
+	podReadRules := []rbac.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}}}
+	nodeReadRules := []rbac.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}}}
+	wildcardRules := []rbac.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}}
+	subjects := []rbac.Subject{{Kind: "ServiceAccount", Name: "test-sa", Namespace: "default"}}
+
+	t.Run("should create service account and roles with given rules", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme)
+		err := k8s.New(cl, scheme).ServiceAccount("test-sa", "test-role", "test-role-binding", owner).
+			EnsureExists(nodeReadRules, podReadRules)
+		require.NoError(t, err)
+
+		sa := &core.ServiceAccount{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-sa", Namespace: "default"}, sa))
+		require.Len(t, sa.OwnerReferences, 1)
+		assert.Equal(t, "test-pod", sa.OwnerReferences[0].Name)
+
+		clusterRole := &rbac.ClusterRole{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role"}, clusterRole))
+		assert.Equal(t, nodeReadRules, clusterRole.Rules)
+		clusterRoleBinding := &rbac.ClusterRoleBinding{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role-binding"}, clusterRoleBinding))
+		assert.Equal(t, subjects, clusterRoleBinding.Subjects)
+		assert.Equal(t, rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "test-role"}, clusterRoleBinding.RoleRef)
+
+		role := &rbac.Role{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role", Namespace: "default"}, role))
+		assert.Equal(t, podReadRules, role.Rules)
+		roleBinding := &rbac.RoleBinding{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role-binding", Namespace: "default"}, roleBinding))
+		assert.Equal(t, subjects, roleBinding.Subjects)
+		assert.Equal(t, rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: "test-role"}, roleBinding.RoleRef)
+	})
+
+	t.Run("should restore rules and subjects which drifted", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme,
+			&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "test-role"}, Rules: wildcardRules},
+			&rbac.ClusterRoleBinding{
+				ObjectMeta: meta.ObjectMeta{Name: "test-role-binding"},
+				Subjects:   []rbac.Subject{{Kind: "ServiceAccount", Name: "other-sa", Namespace: "default"}},
+				RoleRef:    rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "test-role"},
+			},
+		)
+		err := k8s.New(cl, scheme).ServiceAccount("test-sa", "test-role", "test-role-binding", owner).
+			EnsureExists(nodeReadRules, nil)
+		require.NoError(t, err)
+
+		clusterRole := &rbac.ClusterRole{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role"}, clusterRole))
+		assert.Equal(t, nodeReadRules, clusterRole.Rules)
+		clusterRoleBinding := &rbac.ClusterRoleBinding{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role-binding"}, clusterRoleBinding))
+		assert.Equal(t, subjects, clusterRoleBinding.Subjects)
+	})
+
+	t.Run("should recreate binding referring to another role", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme,
+			&rbac.ClusterRoleBinding{
+				ObjectMeta: meta.ObjectMeta{Name: "test-role-binding"},
+				Subjects:   subjects,
+				RoleRef:    rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "cluster-admin"},
+			},
+		)
+		err := k8s.New(cl, scheme).ServiceAccount("test-sa", "test-role", "test-role-binding", owner).
+			EnsureExists(nodeReadRules, nil)
+		require.NoError(t, err)
+
+		clusterRoleBinding := &rbac.ClusterRoleBinding{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role-binding"}, clusterRoleBinding))
+		assert.Equal(t, "test-role", clusterRoleBinding.RoleRef.Name)
+	})
+
+	t.Run("should remove cluster role when no cluster rules are given", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme,
+			&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "test-role"}, Rules: wildcardRules},
+			&rbac.ClusterRoleBinding{
+				ObjectMeta: meta.ObjectMeta{Name: "test-role-binding"},
+				Subjects:   subjects,
+				RoleRef:    rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "test-role"},
+			},
+		)
+		err := k8s.New(cl, scheme).ServiceAccount("test-sa", "test-role", "test-role-binding", owner).
+			EnsureExists(nil, podReadRules)
+		require.NoError(t, err)
+
+		err = cl.Get(context.Background(), types.NamespacedName{Name: "test-role"}, &rbac.ClusterRole{})
+		assert.True(t, errors.IsNotFound(err))
+		err = cl.Get(context.Background(), types.NamespacedName{Name: "test-role-binding"}, &rbac.ClusterRoleBinding{})
+		assert.True(t, errors.IsNotFound(err))
+		role := &rbac.Role{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-role", Namespace: "default"}, role))
+		assert.Equal(t, podReadRules, role.Rules)
+	})
+
+	t.Run("should not take over service account controlled by another owner", func(t *testing.T) {
+		trueVal := true
+		otherOwner := []meta.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "other-pod", Controller: &trueVal}}
+		cl := fake.NewFakeClientWithScheme(scheme, &core.ServiceAccount{
+			ObjectMeta: meta.ObjectMeta{Name: "test-sa", Namespace: "default", OwnerReferences: otherOwner},
+		})
+		err := k8s.New(cl, scheme).ServiceAccount("test-sa", "test-role", "test-role-binding", owner).
+			EnsureExists(nil, podReadRules)
+		require.NoError(t, err)
+
+		sa := &core.ServiceAccount{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test-sa", Namespace: "default"}, sa))
+		assert.Equal(t, otherOwner, sa.OwnerReferences)
+	})
+}
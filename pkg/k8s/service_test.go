@@ -190,3 +190,22 @@ func TestExternalIP(t *testing.T) {
 		assert.Equal(t, "10.255.254.4", sc.ExternalIP())
 	})
 }
+
+func TestServiceSelector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := core.SchemeBuilder.AddToScheme(scheme)
+	require.NoError(t, err)
+	owner := &core.Pod{ObjectMeta: meta.ObjectMeta{Name: "owner"}}
+
+	t.Run("Select pods with selector different than labels", func(t *testing.T) {
+		cl := fake.NewFakeClientWithScheme(scheme)
+		sc := k8s.New(cl, scheme).Service("test", core.ServiceTypeClusterIP, map[int32]string{5555: "metrics"}, "pod", owner).
+			WithLabels(map[string]string{"app": "metrics"}).
+			WithSelector(map[string]string{"pod": "owner"})
+		require.NoError(t, sc.EnsureExists())
+		service := &core.Service{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "test"}, service))
+		assert.Equal(t, map[string]string{"app": "metrics"}, service.Labels)
+		assert.Equal(t, map[string]string{"pod": "owner"}, service.Spec.Selector)
+	})
+}
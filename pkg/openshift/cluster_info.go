@@ -3,11 +3,18 @@ package openshift
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,14 +24,53 @@ import (
 
 var log = logf.Log.WithName("openshift_cluster_info")
 
-// ClusterConfig is a struct that incorporates v1alpha1.KubemanagerClusterInfo interface
+var configGroupVersion = schema.GroupVersion{Group: "config.openshift.io", Version: "v1"}
+
+// IsOpenShift tells whether the cluster is an OpenShift cluster by looking for the config.openshift.io API group
+func IsOpenShift(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups.Groups {
+		if group.Name == configGroupVersion.Group {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ClusterConfig is a struct that incorporates v1alpha1.KubemanagerClusterInfo interface.
+// Information is gathered from the Infrastructure and Network cluster configuration resources.
+// Clusters which do not publish it there yet fall back to the install-config and the kubernetes endpoint.
 type ClusterConfig struct {
 	Client        typedCorev1.CoreV1Interface
 	DynamicClient dynamic.Interface
 }
 
-// KubernetesAPISSLPort gathers SSL Port from Openshift Cluster via console-config ConfigMap
+// Sources returns the cluster configuration resources the information is gathered from
+func (c ClusterConfig) Sources() []runtime.Object {
+	var sources []runtime.Object
+	for _, kind := range []string{"Infrastructure", "Network"} {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(configGroupVersion.WithKind(kind))
+		sources = append(sources, u)
+	}
+	return sources
+}
+
+// KubernetesAPISSLPort gathers SSL Port from Openshift Cluster via Infrastructure resource or kubernetes Endpoints
 func (c ClusterConfig) KubernetesAPISSLPort() (int, error) {
+	apiServerURL, err := c.apiServerURL()
+	if err != nil {
+		return 0, err
+	}
+	if apiServerURL != nil {
+		if apiServerURL.Port() == "" {
+			return 443, nil
+		}
+		return strconv.Atoi(apiServerURL.Port())
+	}
 	kubernetesEndpoint, err := c.Client.Endpoints("default").Get(context.Background(), "kubernetes", metav1.GetOptions{})
 	if err != nil {
 		return 0, err
@@ -40,14 +86,16 @@ func (c ClusterConfig) KubernetesAPISSLPort() (int, error) {
 	return 0, errors.New("No https port found")
 }
 
-// KubernetesAPIServer gathers API Server name from Openshift Cluster via console-config ConfigMap
+// KubernetesAPIServer gathers API Server name from Openshift Cluster via Infrastructure or DNS resource
 func (c ClusterConfig) KubernetesAPIServer() (string, error) {
-	u, err := c.DynamicClient.Resource(
-		schema.GroupVersionResource{
-			Group:    "config.openshift.io",
-			Version:  "v1",
-			Resource: "dnses",
-		}).Get(context.Background(), "cluster", metav1.GetOptions{})
+	apiServerURL, err := c.apiServerURL()
+	if err != nil {
+		return "", err
+	}
+	if apiServerURL != nil {
+		return apiServerURL.Hostname(), nil
+	}
+	u, err := c.DynamicClient.Resource(configGroupVersion.WithResource("dnses")).Get(context.Background(), "cluster", metav1.GetOptions{})
 	if err != nil {
 		return "", err
 	}
@@ -58,7 +106,8 @@ func (c ClusterConfig) KubernetesAPIServer() (string, error) {
 	return "api." + apiServer, nil
 }
 
-// KubernetesClusterName gathers cluster name from Openshift Cluster via cluster-config-v1 ConfigMap
+// KubernetesClusterName gathers cluster name from Openshift Cluster via cluster-config-v1 ConfigMap.
+// Infrastructure name is not used as it carries a random suffix.
 func (c ClusterConfig) KubernetesClusterName() (string, error) {
 	installConfigMap, err := getInstallConfig(c.Client)
 	if err != nil {
@@ -68,28 +117,61 @@ func (c ClusterConfig) KubernetesClusterName() (string, error) {
 	return kubernetesClusterName, nil
 }
 
-// PodSubnets gathers pods' subnet from Openshift Cluster via cluster-config-v1 ConfigMap
+// PodSubnets gathers pods' subnet from Openshift Cluster via Network resource or cluster-config-v1 ConfigMap
 func (c ClusterConfig) PodSubnets() (string, error) {
-	installConfigMap, err := getInstallConfig(c.Client)
+	var clusterNetwork []string
+	network, err := c.clusterResource("networks")
 	if err != nil {
 		return "", err
 	}
-	clusterNetwork := installConfigMap.Networking.ClusterNetwork
+	if network != nil {
+		clusterNetwork, err = clusterNetworkCIDRs(network)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(clusterNetwork) == 0 {
+		installConfigMap, err := getInstallConfig(c.Client)
+		if err != nil {
+			return "", err
+		}
+		for _, network := range installConfigMap.Networking.ClusterNetwork {
+			clusterNetwork = append(clusterNetwork, network.CIDR)
+		}
+	}
+	if len(clusterNetwork) == 0 {
+		return "", errors.New("No cluster networks found")
+	}
 	if len(clusterNetwork) > 1 {
 		netLogger := log.WithValues("clusterNetwork", clusterNetwork)
 		netLogger.Info("Found more than one cluster networks.")
 	}
-	podSubnets := clusterNetwork[0].CIDR
-	return podSubnets, nil
+	return clusterNetwork[0], nil
 }
 
-// ServiceSubnets gathers service subnet from Openshift Cluster via cluster-config-v1 ConfigMap
+// ServiceSubnets gathers service subnet from Openshift Cluster via Network resource or cluster-config-v1 ConfigMap
 func (c ClusterConfig) ServiceSubnets() (string, error) {
-	installConfigMap, err := getInstallConfig(c.Client)
+	var serviceNetwork []string
+	network, err := c.clusterResource("networks")
 	if err != nil {
 		return "", err
 	}
-	serviceNetwork := installConfigMap.Networking.ServiceNetwork
+	if network != nil {
+		serviceNetwork, err = networkField(network, "serviceNetwork", unstructured.NestedStringSlice)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(serviceNetwork) == 0 {
+		installConfigMap, err := getInstallConfig(c.Client)
+		if err != nil {
+			return "", err
+		}
+		serviceNetwork = installConfigMap.Networking.ServiceNetwork
+	}
+	if len(serviceNetwork) == 0 {
+		return "", errors.New("No service networks found")
+	}
 	if len(serviceNetwork) > 1 {
 		netLogger := log.WithValues("serviceNetwork", serviceNetwork)
 		netLogger.Info("Found more than one service networks.")
@@ -98,7 +180,9 @@ func (c ClusterConfig) ServiceSubnets() (string, error) {
 	return serviceSubnets, nil
 }
 
-// CNIBinariesDirectory returns directory containing CNI binaries specific for k8s cluster
+// CNIBinariesDirectory returns directory containing CNI binaries on OpenShift nodes. Multus on OpenShift 4
+// runs plugins from /var/lib/cni/bin, which is set by the node configuration. Neither the Network nor the
+// Infrastructure resource exposes it, so it cannot be derived from the cluster.
 func (c ClusterConfig) CNIBinariesDirectory() string {
 	return "/var/lib/cni/bin"
 }
@@ -108,6 +192,73 @@ func (c ClusterConfig) DeploymentType() string {
 	return "openshift"
 }
 
+// NetworkMTU gathers MTU of pod interfaces from Openshift Cluster via Network resource, 0 when it is not set
+func (c ClusterConfig) NetworkMTU() (int, error) {
+	network, err := c.clusterResource("networks")
+	if err != nil || network == nil {
+		return 0, err
+	}
+	mtu, _, err := unstructured.NestedInt64(network.Object, "status", "clusterNetworkMTU")
+	return int(mtu), err
+}
+
+// clusterResource gets the cluster wide configuration resource, nil is returned when it does not exist
+func (c ClusterConfig) clusterResource(resource string) (*unstructured.Unstructured, error) {
+	if c.DynamicClient == nil {
+		return nil, nil
+	}
+	u, err := c.DynamicClient.Resource(configGroupVersion.WithResource(resource)).Get(context.Background(), "cluster", metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return u, err
+}
+
+// apiServerURL returns the API server URL published in the Infrastructure resource, nil when it is not published
+func (c ClusterConfig) apiServerURL() (*url.URL, error) {
+	infrastructure, err := c.clusterResource("infrastructures")
+	if err != nil || infrastructure == nil {
+		return nil, err
+	}
+	apiServerURL, _, err := unstructured.NestedString(infrastructure.Object, "status", "apiServerURL")
+	if err != nil || apiServerURL == "" {
+		return nil, err
+	}
+	return url.Parse(apiServerURL)
+}
+
+// networkField returns a field of the Network status, or of its spec when the status is not filled yet
+func networkField(network *unstructured.Unstructured, field string, get func(map[string]interface{}, ...string) ([]string, bool, error)) ([]string, error) {
+	value, found, err := get(network.Object, "status", field)
+	if err != nil || (found && len(value) > 0) {
+		return value, err
+	}
+	value, _, err = get(network.Object, "spec", field)
+	return value, err
+}
+
+func clusterNetworkCIDRs(network *unstructured.Unstructured) ([]string, error) {
+	return networkField(network, "clusterNetwork", func(obj map[string]interface{}, fields ...string) ([]string, bool, error) {
+		value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+		if err != nil || !found {
+			return nil, found, err
+		}
+		entries, ok := value.([]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("%v of Network is not a list", strings.Join(fields, "."))
+		}
+		var cidrs []string
+		for _, entry := range entries {
+			if networkEntry, ok := entry.(map[string]interface{}); ok {
+				if cidr, ok := networkEntry["cidr"].(string); ok {
+					cidrs = append(cidrs, cidr)
+				}
+			}
+		}
+		return cidrs, true, nil
+	})
+}
+
 func getInstallConfig(client typedCorev1.CoreV1Interface) (installConfig, error) {
 	kubeadmConfigMapClient := client.ConfigMaps("kube-system")
 	ccm, err := kubeadmConfigMapClient.Get(context.Background(), "cluster-config-v1", metav1.GetOptions{})
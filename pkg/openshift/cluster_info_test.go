@@ -4,9 +4,11 @@ import (
 	"testing"
 
 	openshiftv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
@@ -276,3 +278,133 @@ func getDynamicClientWithDNS(dnsName, dnsURL string) (dynamic.Interface, error)
 	}
 	return dynamicFake.NewSimpleDynamicClient(scheme, dns(dnsName, dnsURL)), nil
 }
+
+func (suite *ClusterInfoSuite) TestClusterConfigurationResources() {
+	coreV1Interface := getClientWithConfigMaps(clusterConfigV1, endpointPorts())
+	dynamicClient, err := getDynamicClient(
+		dns("cluster", "test.user.test.com"),
+		infrastructure("https://api.cluster.example.com:7443"),
+		network([]string{"10.132.0.0/14"}, []string{"172.31.0.0/16"}, 1400),
+	)
+	suite.Require().NoError(err)
+	ci := openshift.ClusterConfig{
+		Client:        coreV1Interface,
+		DynamicClient: dynamicClient,
+	}
+	apiServer, err := ci.KubernetesAPIServer()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("api.cluster.example.com", apiServer)
+	apiSSLPort, err := ci.KubernetesAPISSLPort()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(7443, apiSSLPort)
+	podSubnets, err := ci.PodSubnets()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("10.132.0.0/14", podSubnets)
+	serviceSubnets, err := ci.ServiceSubnets()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("172.31.0.0/16", serviceSubnets)
+	mtu, err := ci.NetworkMTU()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(1400, mtu)
+	clusterName, err := ci.KubernetesClusterName()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("test", clusterName)
+}
+
+func (suite *ClusterInfoSuite) TestNetworkSpecBeforeStatus() {
+	coreV1Interface := getClientWithConfigMaps(clusterConfigV1, endpointPorts())
+	n := network(nil, nil, 0)
+	n.Spec.ClusterNetwork = []openshiftv1.ClusterNetworkEntry{{CIDR: "10.136.0.0/14"}}
+	n.Spec.ServiceNetwork = []string{"172.32.0.0/16"}
+	dynamicClient, err := getDynamicClient(n)
+	suite.Require().NoError(err)
+	ci := openshift.ClusterConfig{
+		Client:        coreV1Interface,
+		DynamicClient: dynamicClient,
+	}
+	podSubnets, err := ci.PodSubnets()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("10.136.0.0/14", podSubnets)
+	serviceSubnets, err := ci.ServiceSubnets()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("172.32.0.0/16", serviceSubnets)
+	mtu, err := ci.NetworkMTU()
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(0, mtu)
+}
+
+func (suite *ClusterInfoSuite) TestSources() {
+	ci := openshift.ClusterConfig{}
+	var kinds []string
+	for _, source := range ci.Sources() {
+		gvk := source.GetObjectKind().GroupVersionKind()
+		suite.Assert().Equal("config.openshift.io/v1", gvk.GroupVersion().String())
+		kinds = append(kinds, gvk.Kind)
+	}
+	suite.Assert().ElementsMatch([]string{"Infrastructure", "Network"}, kinds)
+}
+
+func TestIsOpenShift(t *testing.T) {
+	t.Run("should discover config.openshift.io group", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset()
+		clientset.Resources = []*meta.APIResourceList{{GroupVersion: "config.openshift.io/v1"}, {GroupVersion: "v1"}}
+		isOpenShift, err := openshift.IsOpenShift(clientset.Discovery())
+		assert.NoError(t, err)
+		assert.True(t, isOpenShift)
+	})
+
+	t.Run("should not discover OpenShift on kubernetes", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset()
+		clientset.Resources = []*meta.APIResourceList{{GroupVersion: "v1"}, {GroupVersion: "apps/v1"}}
+		isOpenShift, err := openshift.IsOpenShift(clientset.Discovery())
+		assert.NoError(t, err)
+		assert.False(t, isOpenShift)
+	})
+}
+
+func getDynamicClient(objects ...runtime.Object) (dynamic.Interface, error) {
+	scheme, err := v1alpha1.SchemeBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+	if err = openshiftv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return dynamicFake.NewSimpleDynamicClient(scheme, objects...), nil
+}
+
+func infrastructure(apiServerURL string) *openshiftv1.Infrastructure {
+	return &openshiftv1.Infrastructure{
+		ObjectMeta: meta.ObjectMeta{
+			Name: "cluster",
+		},
+		TypeMeta: meta.TypeMeta{
+			Kind:       "Infrastructure",
+			APIVersion: "config.openshift.io/v1",
+		},
+		Status: openshiftv1.InfrastructureStatus{
+			InfrastructureName: "test-x7k2p",
+			APIServerURL:       apiServerURL,
+		},
+	}
+}
+
+func network(clusterNetwork, serviceNetwork []string, mtu int) *openshiftv1.Network {
+	n := &openshiftv1.Network{
+		ObjectMeta: meta.ObjectMeta{
+			Name: "cluster",
+		},
+		TypeMeta: meta.TypeMeta{
+			Kind:       "Network",
+			APIVersion: "config.openshift.io/v1",
+		},
+		Status: openshiftv1.NetworkStatus{
+			ServiceNetwork:    serviceNetwork,
+			ClusterNetworkMTU: mtu,
+		},
+	}
+	for _, cidr := range clusterNetwork {
+		n.Status.ClusterNetwork = append(n.Status.ClusterNetwork, openshiftv1.ClusterNetworkEntry{CIDR: cidr, HostPrefix: 23})
+	}
+	return n
+}
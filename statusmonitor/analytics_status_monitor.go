@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
+)
+
+// AnalyticsContainerServiceNameMap maps containers of config pods to analytics services running in them
+var AnalyticsContainerServiceNameMap = map[string]string{
+	"analyticsapi": "contrail-analytics-api",
+	"collector":    "contrail-collector",
+	"queryengine":  "contrail-query-engine",
+}
+
+func getAnalyticsStatus(client http.Client, clientset kubernetes.Interface, restClient rest.Interface, config Config) error {
+	pod, err := clientset.CoreV1().Pods(config.Namespace).Get(context.Background(), config.PodName, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("Getting pod failed: %s", err)
+		return err
+	}
+	serviceAddressMap := map[string]string{}
+	for _, servicePort := range config.APIServerList {
+		servicePortList := strings.Split(servicePort, "::")
+		if len(servicePortList) == 2 {
+			serviceAddressMap[servicePortList[1]] = servicePortList[0]
+		}
+	}
+	analyticsStatusMap := contrailOperatorTypes.ConfigServiceStatusMap{}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		serviceFullName, ok := AnalyticsContainerServiceNameMap[containerStatus.Name]
+		if !ok {
+			continue
+		}
+		status := contrailOperatorTypes.ConfigServiceStatus{
+			ModuleName:  serviceFullName,
+			ModuleState: "initializing",
+		}
+		if containerStatus.Ready {
+			status = getAnalyticsStatusFromApiServer(serviceAddressMap[serviceFullName], serviceFullName, &client)
+		}
+		analyticsStatusMap[formatServiceName(serviceFullName)] = status
+	}
+	metrics.recordAnalyticsStatus(config.Hostname, analyticsStatusMap)
+	return updateAnalyticsStatus(&config, analyticsStatusMap, restClient)
+}
+
+// getAnalyticsStatusFromApiServer gets status of the analytics service from its introspect port
+func getAnalyticsStatusFromApiServer(serviceAddress, serviceName string, client *http.Client) contrailOperatorTypes.ConfigServiceStatus {
+	nodeStatus, err := introspect.NewClient(introspect.NewConnector(serviceAddress, client)).NodeStatus()
+	if err != nil {
+		log.Printf("warning: to get status for %s address %s failed: %v", serviceName, serviceAddress, err)
+		return contrailOperatorTypes.ConfigServiceStatus{
+			ModuleName:  serviceName,
+			ModuleState: "connection-error",
+		}
+	}
+	return analyticsServiceStatus(serviceName, nodeStatus)
+}
+
+func analyticsServiceStatus(serviceName string, nodeStatus *introspect.NodeStatus) contrailOperatorTypes.ConfigServiceStatus {
+	process := nodeProcessStatus(nodeStatus)
+	status := contrailOperatorTypes.ConfigServiceStatus{
+		NodeName:    nodeStatus.Name,
+		ModuleName:  serviceName,
+		ModuleState: process.State,
+	}
+	if process.State == "Non-Functional" {
+		status.Description = process.Description
+	}
+	return status
+}
+
+func updateAnalyticsStatus(config *Config, statusMap contrailOperatorTypes.ConfigServiceStatusMap, restClient rest.Interface) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configClient := &configClient{
+			ns:         config.Namespace,
+			restClient: restClient,
+		}
+		configObject, err := configClient.Get(config.NodeName, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error: updateAnalyticsStatus: Failed to get config status %v", err)
+			return err
+		}
+		if reflect.DeepEqual(configObject.Status.AnalyticsServiceStatus[config.Hostname], statusMap) {
+			return nil
+		}
+		if configObject.Status.AnalyticsServiceStatus == nil {
+			configObject.Status.AnalyticsServiceStatus = map[string]contrailOperatorTypes.ConfigServiceStatusMap{}
+		}
+		configObject.Status.AnalyticsServiceStatus[config.Hostname] = statusMap
+		_, err = configClient.UpdateStatus(config.NodeName, configObject)
+		if err != nil {
+			log.Printf("error: updateAnalyticsStatus: Failed to update config status %v", err)
+		}
+		return err
+	})
+	if retryErr != nil {
+		log.Printf("Error: Update retry failed: %v", retryErr)
+	}
+	return retryErr
+}
@@ -2,8 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/xml"
-	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -17,30 +15,16 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
 )
 
-type ConnectionInfo struct {
-	Name          string   `xml:"name"`
-	Status        string   `xml:"status"`
-	ServerAddress []string `xml:"server_addrs>list>element"`
-}
-
-type ServiceStatus struct {
-	XMLName        xml.Name         `xml:"__NodeStatusUVE_list"`
-	NodeName       string           `xml:"NodeStatusUVE>data>NodeStatus>name"`
-	ModuleName     string           `xml:"NodeStatusUVE>data>NodeStatus>process_status>list>ProcessStatus>module_id"`
-	ModuleState    string           `xml:"NodeStatusUVE>data>NodeStatus>process_status>list>ProcessStatus>state"`
-	Description    string           `xml:"NodeStatusUVE>data>NodeStatus>process_status>list>ProcessStatus>description"`
-	ConnectionInfo []ConnectionInfo `xml:"NodeStatusUVE>data>NodeStatus>process_status>list>ProcessStatus>connection_infos>list>ConnectionInfo"`
-}
-
+// ContainerServiceNameMap maps containers of config pods to config services running in them,
+// analytics services are reported by the analytics collector.
 var ContainerServiceNameMap = map[string]string{
-	"analyticsapi":      "contrail-analytics-api",
 	"api":               "contrail-api",
 	"devicemanager":     "contrail-device-manager",
 	"servicemonitor":    "contrail-svc-monitor",
 	"schematransformer": "contrail-schema",
-	"collector":         "contrail-collector",
 }
 
 func isBackupImplementedService(fullServiceName string) bool {
@@ -85,6 +69,7 @@ func getConfigStatus(client http.Client, clientset *kubernetes.Clientset, restCl
 			}
 		}
 	}
+	metrics.recordConfigStatus(config.Hostname, configStatusMap)
 	err = updateConfigStatus(&config, configStatusMap, restClient)
 	if err != nil {
 		log.Printf("Error in updateConfigStatus in func: %s", err)
@@ -150,14 +135,6 @@ func formatServiceName(serviceName string) string {
 	return serviceNameFmt
 }
 
-func ParseIntrospectResp(statusBody []byte) (*ServiceStatus, error) {
-	confSt := ServiceStatus{}
-	if err := xml.Unmarshal(statusBody, &confSt); err != nil {
-		return &confSt, fmt.Errorf("unmurshaling xml failed: %v", err)
-	}
-	return &confSt, nil
-}
-
 type configClient struct {
 	restClient rest.Interface
 	ns         string
@@ -241,14 +218,15 @@ func updateConfigStatus(config *Config, StatusMap map[string]contrailOperatorTyp
 
 func getConfigStatusFromResponse(statusBody []byte) (*contrailOperatorTypes.ConfigServiceStatus, string, error) {
 	configServiceStatus := contrailOperatorTypes.ConfigServiceStatus{}
-	serviceStatus, err := ParseIntrospectResp(statusBody)
+	nodeStatus, err := introspect.ParseNodeStatus(statusBody)
 	if err != nil {
 		return &configServiceStatus, "", err
 	}
-	configServiceStatus.ModuleName = serviceStatus.ModuleName
-	configServiceStatus.ModuleState = serviceStatus.ModuleState
-	if serviceStatus.ModuleState == "Non-Functional" {
-		configServiceStatus.Description = serviceStatus.Description
+	process := nodeProcessStatus(nodeStatus)
+	configServiceStatus.ModuleName = process.ModuleID
+	configServiceStatus.ModuleState = process.State
+	if process.State == "Non-Functional" {
+		configServiceStatus.Description = process.Description
 	}
-	return &configServiceStatus, serviceStatus.NodeName, nil
+	return &configServiceStatus, nodeStatus.Name, nil
 }
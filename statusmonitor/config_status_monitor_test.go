@@ -13,17 +13,16 @@ import (
 	"testing"
 )
 
-func TestParseIntrospectResp(t *testing.T) {
-	data, err := ParseIntrospectResp(introspectData())
+func TestGetConfigStatusFromResponse(t *testing.T) {
+	status, nodeName, err := getConfigStatusFromResponse(introspectData())
 	assert.NoError(t, err)
-	assert.Equal(t, data.NodeName, "kind-control-plane")
-	assert.Equal(t, data.ModuleName, "contrail-api")
-	assert.Equal(t, data.ModuleState, "Functional")
-	assert.Equal(t, len(data.ConnectionInfo), 5)
-	assert.Equal(t, data.ConnectionInfo[0].Name, "Zookeeper")
-	assert.Equal(t, data.ConnectionInfo[0].ServerAddress[0], "172.17.0.3:2181")
-	assert.Equal(t, data.ConnectionInfo[0].Status, "Up")
+	assert.Equal(t, nodeName, "kind-control-plane")
+	assert.Equal(t, status.ModuleName, "contrail-api")
+	assert.Equal(t, status.ModuleState, "Functional")
+	assert.Empty(t, status.Description)
 
+	_, _, err = getConfigStatusFromResponse([]byte("<invalid"))
+	assert.Error(t, err)
 }
 
 type RoundTripFunc func(req *http.Request) *http.Response
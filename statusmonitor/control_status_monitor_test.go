@@ -1,14 +1,27 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
 )
 
-func TestParseControlIntrospectResp(t *testing.T) {
-	service_status, err := getControlStatusFromResponse(routerIntrospectData(), processIntrospectData())
-	assert.NoError(t, err)
+func TestGetControlStatusFromApiServer(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"BgpRouterState": routerIntrospectData(),
+		"NodeStatus":     processIntrospectData(),
+	})
+	defer server.Close()
+	controlStatusMap := map[string]contrailOperatorTypes.ControlServiceStatus{}
+
+	err := GetControlStatusFromApiServer(server.Listener.Addr().String(), &Config{}, server.Client(), []string{"control1"}, controlStatusMap)
+	require.NoError(t, err)
+	service_status := controlStatusMap["control1"]
 	assert.Equal(t, service_status.NumberOfXMPPPeers, "1")
 	assert.Equal(t, service_status.NumberOfRoutingInstances, "387")
 	assert.Equal(t, service_status.State, "Functional")
@@ -16,10 +29,36 @@ func TestParseControlIntrospectResp(t *testing.T) {
 	assert.Equal(t, service_status.StaticRoutes.Number, "1")
 	assert.Equal(t, service_status.BGPPeer.Up, "3")
 	assert.Equal(t, service_status.BGPPeer.Number, "3")
-	assert.Equal(t, service_status.BGPPeer.Number, "3")
 	assert.Equal(t, len(service_status.Connections), 3)
 }
 
+func TestGetControlStatusFromApiServerInvalidResponse(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"BgpRouterState": []byte("<invalid"),
+		"NodeStatus":     processIntrospectData(),
+	})
+	defer server.Close()
+	controlStatusMap := map[string]contrailOperatorTypes.ControlServiceStatus{}
+
+	err := GetControlStatusFromApiServer(server.Listener.Addr().String(), &Config{}, server.Client(), []string{"control1"}, controlStatusMap)
+	assert.Error(t, err)
+	assert.Empty(t, controlStatusMap)
+}
+
+// newIntrospectServer serves the given UVE cache responses, keyed by the UVE name, the
+// way a Contrail introspect does.
+func newIntrospectServer(t *testing.T, responses map[string][]byte) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Query().Get("x")]
+		if r.URL.Path != "/Snh_SandeshUVECacheReq" || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write(body)
+		assert.NoError(t, err)
+	}))
+}
+
 func processIntrospectData() []byte {
 	return []byte(`<?xml-stylesheet type="text/xsl" href="/universal_parse.xsl"?>
 <__NodeStatusUVE_list type="slist">
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
+)
+
+// Database services reported by the database collector. Server addresses in the config are
+// suffixed with ::<service>, addresses without the suffix are nodemgr introspect addresses.
+const (
+	databaseNodemgrService = "contrail-database-nodemgr"
+	cassandraService       = "cassandra"
+)
+
+func getDatabaseStatus(client http.Client, restClient rest.Interface, config Config) error {
+	var lastErr error
+	serviceName := databaseNodemgrService
+	for _, apiServer := range config.APIServerList {
+		address := apiServer
+		serviceName = databaseNodemgrService
+		if servicePortList := strings.Split(apiServer, "::"); len(servicePortList) == 2 {
+			address, serviceName = servicePortList[0], servicePortList[1]
+		}
+		databaseStatus, err := getDatabaseStatusFromApiServer(address, serviceName, &client)
+		if err != nil {
+			log.Printf("warning: getting %s status from %s failed: %v", serviceName, address, err)
+			lastErr = err
+			continue
+		}
+		metrics.recordDatabaseStatus(config.Hostname, *databaseStatus)
+		return updateDatabaseStatus(&config, *databaseStatus, restClient)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no database address configured")
+	}
+	databaseStatus := contrailOperatorTypes.CassandraServiceStatus{
+		ModuleName:  serviceName,
+		ModuleState: "connection-error",
+		Description: lastErr.Error(),
+	}
+	metrics.recordDatabaseStatus(config.Hostname, databaseStatus)
+	return updateDatabaseStatus(&config, databaseStatus, restClient)
+}
+
+func getDatabaseStatusFromApiServer(address, serviceName string, client *http.Client) (*contrailOperatorTypes.CassandraServiceStatus, error) {
+	if serviceName == cassandraService {
+		// Cassandra has no introspect, it is functional when it accepts CQL connections
+		conn, err := net.DialTimeout("tcp", address, client.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		closeConn(conn)
+		return &contrailOperatorTypes.CassandraServiceStatus{
+			ModuleName:  cassandraService,
+			ModuleState: "Functional",
+		}, nil
+	}
+	nodeStatus, err := introspect.NewClient(introspect.NewConnector(address, client)).NodeStatus()
+	if err != nil {
+		return nil, err
+	}
+	return databaseServiceStatus(nodeStatus), nil
+}
+
+func closeConn(conn net.Conn) {
+	if err := conn.Close(); err != nil {
+		log.Printf("closing connection failed: %v", err)
+	}
+}
+
+func databaseServiceStatus(nodeStatus *introspect.NodeStatus) *contrailOperatorTypes.CassandraServiceStatus {
+	process := nodeProcessStatus(nodeStatus)
+	databaseStatus := contrailOperatorTypes.CassandraServiceStatus{
+		ModuleName:  process.ModuleID,
+		ModuleState: process.State,
+		Connections: processConnections(process),
+	}
+	if process.State == "Non-Functional" {
+		databaseStatus.Description = process.Description
+	}
+	return &databaseStatus
+}
+
+type cassandraClient struct {
+	restClient rest.Interface
+	ns         string
+}
+
+func (c *cassandraClient) Get(name string, opts metav1.GetOptions) (*contrailOperatorTypes.Cassandra, error) {
+	result := contrailOperatorTypes.Cassandra{}
+	err := c.restClient.
+		Get().
+		Namespace(c.ns).
+		Resource("cassandras").
+		Name(name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(context.Background()).
+		Into(&result)
+
+	return &result, err
+}
+
+func (c *cassandraClient) UpdateStatus(name string, object *contrailOperatorTypes.Cassandra) (*contrailOperatorTypes.Cassandra, error) {
+	result := contrailOperatorTypes.Cassandra{}
+	err := c.restClient.
+		Put().
+		Namespace(c.ns).
+		Resource("cassandras").
+		Name(name).
+		SubResource("status").
+		Body(object).
+		Do(context.Background()).
+		Into(&result)
+
+	return &result, err
+}
+
+func updateDatabaseStatus(config *Config, databaseStatus contrailOperatorTypes.CassandraServiceStatus, restClient rest.Interface) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cassandraClient := &cassandraClient{
+			ns:         config.Namespace,
+			restClient: restClient,
+		}
+		cassandraObject, err := cassandraClient.Get(config.NodeName, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error: updateDatabaseStatus: Failed to get cassandra status %v", err)
+			return err
+		}
+		if reflect.DeepEqual(cassandraObject.Status.ServiceStatus[config.Hostname], databaseStatus) {
+			return nil
+		}
+		if cassandraObject.Status.ServiceStatus == nil {
+			cassandraObject.Status.ServiceStatus = map[string]contrailOperatorTypes.CassandraServiceStatus{}
+		}
+		cassandraObject.Status.ServiceStatus[config.Hostname] = databaseStatus
+		_, err = cassandraClient.UpdateStatus(config.NodeName, cassandraObject)
+		if err != nil {
+			log.Printf("error: updateDatabaseStatus: Failed to update cassandra status %v", err)
+		}
+		return err
+	})
+	if retryErr != nil {
+		log.Printf("Error: Update retry failed: %v", retryErr)
+	}
+	return retryErr
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDatabaseStatusFromApiServer(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{"NodeStatus": introspectData()})
+	defer server.Close()
+
+	databaseStatus, err := getDatabaseStatusFromApiServer(server.Listener.Addr().String(), "contrail-api", server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "contrail-api", databaseStatus.ModuleName)
+	assert.Equal(t, "Functional", databaseStatus.ModuleState)
+	assert.Empty(t, databaseStatus.Description)
+	require.Len(t, databaseStatus.Connections, 5)
+	assert.Equal(t, "Zookeeper", databaseStatus.Connections[0].Name)
+	assert.Equal(t, "Up", databaseStatus.Connections[0].Status)
+	assert.Equal(t, []string{"172.17.0.3:2181"}, databaseStatus.Connections[0].Nodes)
+}
+
+func TestGetDatabaseStatusFromApiServerInvalidResponse(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{"NodeStatus": []byte("<invalid")})
+	defer server.Close()
+
+	_, err := getDatabaseStatusFromApiServer(server.Listener.Addr().String(), "contrail-api", server.Client())
+	assert.Error(t, err)
+}
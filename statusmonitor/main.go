@@ -3,19 +3,16 @@ package main
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
-	"encoding/xml"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"time"
 
-	yaml "gopkg.in/yaml.v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -26,6 +23,7 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
 )
 
 //NodeType definition
@@ -43,8 +41,13 @@ type Config struct {
 	NodeName       string     `yaml:"nodeName,omitempty"`
 	Namespace      string     `yaml:"namespace,omitempty"`
 	PodName        string     `yaml:"podName,omitempty"`
+	MetricsPort    int        `yaml:"metricsPort,omitempty"`
+	Timeout        int64      `yaml:"timeout,omitempty"`
 }
 
+// Timeout of a single introspect request when it is not set in the config
+const defaultRequestTimeout = 5 * time.Second
+
 type encryption struct {
 	CA       *string `yaml:"ca,omitempty"`
 	Cert     *string `yaml:"cert,omitempty"`
@@ -52,90 +55,15 @@ type encryption struct {
 	Insecure bool    `yaml:"insecure,omitempty"`
 }
 
-type ControlConnectionInfo struct {
-	Type   string   `xml:"type"`
-	Name   string   `xml:"name"`
-	Status string   `xml:"status"`
-	Nodes  []string `xml:"server_addrs>list>element"`
-}
-
-type ProcessControlStatus struct {
-	XMLName           xml.Name                `xml:"__NodeStatusUVE_list"`
-	State             string                  `xml:"NodeStatusUVE>data>NodeStatus>process_status>list>ProcessStatus>state"`
-	ConConnectionInfo []ControlConnectionInfo `xml:"NodeStatusUVE>data>NodeStatus>process_status>list>ProcessStatus>connection_infos>list>ConnectionInfo"`
-}
-
-type ServiceStatusControl struct {
-	XMLName                  xml.Name `xml:"__BGPRouterInfo_list"`
-	NumberOfXMPPPeers        int      `xml:"BGPRouterInfo>data>BgpRouterState>num_xmpp_peer"`
-	NumberOfRoutingInstances int      `xml:"BGPRouterInfo>data>BgpRouterState>num_routing_instance"`
-	NumStaticRoutes          int      `xml:"BGPRouterInfo>data>BgpRouterState>num_static_routes"`
-	NumDownStaticRoutes      int      `xml:"BGPRouterInfo>data>BgpRouterState>num_down_static_routes"`
-	NumBgpPeer               int      `xml:"BGPRouterInfo>data>BgpRouterState>num_bgp_peer"`
-	NumUpBgpPeer             int      `xml:"BGPRouterInfo>data>BgpRouterState>num_up_bgp_peer"`
-}
-
 func main() {
 	log.Println("Starting status monitor")
 	configPtr := flag.String("config", "/config.yaml", "path to config yaml file")
-	intervalPtr := flag.Int64("interval", 1, "interval for getting status")
+	intervalPtr := flag.Int64("interval", 10, "interval in seconds for getting status, used when it is not set in the config")
 	flag.Parse()
 
-	var config Config
-	configYaml, err := ioutil.ReadFile(*configPtr)
-	if err != nil {
-		panic(err)
-	}
-	err = yaml.Unmarshal(configYaml, &config)
-	if err != nil {
-		panic(err)
-	}
-	client, err := CreateRestClient(config)
-	if err != nil {
-		log.Printf("error: rest client creation failed: %v", err)
-		panic(err)
-	}
-	clientset, restClient, err := kubeClient(config)
-	if err != nil {
-		log.Printf("kubernates client creation failed: %v", err)
-		panic(err)
-	}
-
-	ticker := time.NewTicker(time.Duration(*intervalPtr) * time.Second)
-	done := make(chan bool)
-	go func() {
-		for {
-			select {
-			case t := <-ticker.C:
-				log.Println("Tick at", t)
-				var updatedConfig Config
-				updatedConfigYaml, err := ioutil.ReadFile(*configPtr)
-				if err != nil {
-					panic(err)
-				}
-				err = yaml.Unmarshal(updatedConfigYaml, &updatedConfig)
-				if err != nil {
-					panic(err)
-				}
-				switch config.NodeType {
-				case "control":
-					err := getControlStatus(client, clientset, restClient, updatedConfig)
-					if err != nil {
-						log.Printf("warning: Error in  getControlStatus func: %v", err)
-						continue
-					}
-				case "config":
-					err := getConfigStatus(client, clientset, restClient, updatedConfig)
-					if err != nil {
-						log.Printf("warning: Error in  getConfigStatus func: %v", err)
-						continue
-					}
-				}
-			}
-		}
-	}()
-	done <- true
-	log.Println("Ticker stopped")
+	m := newMonitor(*configPtr, time.Duration(*intervalPtr)*time.Second)
+	metrics.serve(m.config.MetricsPort)
+	m.run(make(chan struct{}))
 }
 
 func getControlStatus(client http.Client, clientset *kubernetes.Clientset, restClient *rest.RESTClient, config Config) error {
@@ -156,6 +84,7 @@ func getControlStatus(client http.Client, clientset *kubernetes.Clientset, restC
 			break
 		}
 	}
+	metrics.recordControlStatus(controlStatusMap)
 	err := updateControlStatus(&config, controlStatusMap, restClient)
 	if err != nil {
 		log.Printf("warning: Getting error in updateControlStatus: %v", err)
@@ -165,26 +94,26 @@ func getControlStatus(client http.Client, clientset *kubernetes.Clientset, restC
 }
 
 func CreateRestClient(config Config) (http.Client, error) {
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: config.Encryption.Insecure,
-	}
+	tlsConfig := &tls.Config{}
 	if config.Encryption.Key != nil && config.Encryption.Cert != nil && config.Encryption.CA != nil {
-		caCert, err := ioutil.ReadFile(*config.Encryption.CA)
-		pool := x509.NewCertPool()
-		pool.AppendCertsFromPEM(caCert)
-		tlsConfig.RootCAs = pool
-		cer, err := tls.LoadX509KeyPair(*config.Encryption.Cert, *config.Encryption.Key)
+		var err error
+		tlsConfig, err = introspect.TLSConfig(*config.Encryption.CA, *config.Encryption.Cert, *config.Encryption.Key)
 		if err != nil {
 			log.Println(err)
 			return http.Client{}, err
 		}
-		tlsConfig.Certificates = []tls.Certificate{cer}
 	}
+	tlsConfig.InsecureSkipVerify = config.Encryption.Insecure
 	transport := http.Transport{
 		TLSClientConfig: tlsConfig,
 	}
+	timeout := defaultRequestTimeout
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
 	client := http.Client{
 		Transport: &transport,
+		Timeout:   timeout,
 	}
 	return client, nil
 }
@@ -192,51 +121,19 @@ func CreateRestClient(config Config) (http.Client, error) {
 func GetControlStatusFromApiServer(apiServer string, config *Config, client *http.Client, hostnameList []string,
 	controlStatusMap map[string]contrailOperatorTypes.ControlServiceStatus) error {
 
+	introspectClient := introspect.NewClient(introspect.NewConnector(apiServer, client))
 	for _, hostname := range hostnameList {
-		var url string
-		var process_url string
-		url = "https://" + apiServer + "/Snh_SandeshUVECacheReq?x=BgpRouterState"
-		process_url = "https://" + apiServer + "/Snh_SandeshUVECacheReq?x=NodeStatus"
-		resp, err := client.Get(url)
-		if resp != nil {
-			defer closeResp(resp)
-		}
-		process_resp, err_p := client.Get(process_url)
-		if process_resp != nil {
-			defer closeResp(process_resp)
-		}
+		bgpRouterState, err := introspectClient.BgpRouterState()
 		if err != nil {
-			log.Println(err)
+			log.Printf("Error while getting BgpRouterState from %s: %v", apiServer, err)
 			return err
 		}
-		if err_p != nil {
-			log.Println(err_p)
-			return err_p
-		}
-		log.Print(url)
-		log.Print(process_url)
-		if resp != nil && process_resp != nil {
-			log.Printf("resp not nil %d ", resp.StatusCode)
-			log.Printf("Process resp not nil %d ", process_resp.StatusCode)
-			if resp.StatusCode == http.StatusOK && process_resp.StatusCode == http.StatusOK {
-				bodyBytes, err := ioutil.ReadAll(resp.Body)
-				bodyBytes_p, err_p := ioutil.ReadAll(process_resp.Body)
-				if err != nil {
-					log.Printf("Error while reading BgpRouterState response: %v", err)
-					return err
-				}
-				if err_p != nil {
-					log.Printf("Error while reading NodeStatus response: %v", err_p)
-					return err_p
-				}
-				controlStatus, err := getControlStatusFromResponse(bodyBytes, bodyBytes_p)
-				if err != nil {
-					log.Printf("Error while reading ControlStatus response: %v", err)
-					return err
-				}
-				controlStatusMap[hostname] = *controlStatus
-			}
+		nodeStatus, err := introspectClient.NodeStatus()
+		if err != nil {
+			log.Printf("Error while getting NodeStatus from %s: %v", apiServer, err)
+			return err
 		}
+		controlStatusMap[hostname] = controlStatus(bgpRouterState, nodeStatus)
 	}
 	return nil
 }
@@ -402,6 +299,9 @@ func updateControlStatus(config *Config, controlStatusMap map[string]contrailOpe
 			log.Printf("error: updateControlStatus: Failed to get status: %s", err)
 			return err
 		}
+		if reflect.DeepEqual(controlObject.Status.ServiceStatus, controlStatusMap) {
+			return nil
+		}
 		controlObject.Status.ServiceStatus = controlStatusMap
 		_, err = controlCient.UpdateStatus(config.NodeName, controlObject)
 		if err != nil {
@@ -415,53 +315,42 @@ func updateControlStatus(config *Config, controlStatusMap map[string]contrailOpe
 	return retryErr
 }
 
-func getControlStatusFromResponse(statusBody []byte, statusBody_p []byte) (*contrailOperatorTypes.ControlServiceStatus, error) {
-	controlst := ServiceStatusControl{}
-	processst := ProcessControlStatus{}
-
-	err := xml.Unmarshal(statusBody, &controlst)
-	if err != nil {
-		log.Printf("Error while unmarshalling ServiceStatusControl: %v", err)
-		return nil, err
+func controlStatus(bgpRouterState *introspect.BgpRouterState, nodeStatus *introspect.NodeStatus) contrailOperatorTypes.ControlServiceStatus {
+	process := nodeProcessStatus(nodeStatus)
+	return contrailOperatorTypes.ControlServiceStatus{
+		Connections:              processConnections(process),
+		NumberOfXMPPPeers:        strconv.Itoa(bgpRouterState.NumXmppPeer),
+		NumberOfRoutingInstances: strconv.Itoa(bgpRouterState.NumRoutingInstance),
+		StaticRoutes: contrailOperatorTypes.StaticRoutes{
+			Down:   strconv.Itoa(bgpRouterState.NumDownStaticRoutes),
+			Number: strconv.Itoa(bgpRouterState.NumStaticRoutes),
+		},
+		BGPPeer: contrailOperatorTypes.BGPPeer{
+			Up:     strconv.Itoa(bgpRouterState.NumUpBgpPeer),
+			Number: strconv.Itoa(bgpRouterState.NumBgpPeer),
+		},
+		State: process.State,
 	}
+}
 
-	err = xml.Unmarshal(statusBody_p, &processst)
-	if err != nil {
-		log.Printf("Error while unmarshalling ProcessControlStatus: %v", err)
-		return nil, err
-	}
-
-	staticRoutes := contrailOperatorTypes.StaticRoutes{
-		Down:   strconv.Itoa(controlst.NumDownStaticRoutes),
-		Number: strconv.Itoa(controlst.NumStaticRoutes),
-	}
-	bgpPeer := contrailOperatorTypes.BGPPeer{
-		Up:     strconv.Itoa(controlst.NumUpBgpPeer),
-		Number: strconv.Itoa(controlst.NumBgpPeer),
-	}
-	numUpXMPPPeer := strconv.Itoa(controlst.NumberOfXMPPPeers)
-	numRoutingInstance := strconv.Itoa(controlst.NumberOfRoutingInstances)
-
-	connectionList := []contrailOperatorTypes.Connection{}
-
-	for _, ConnectionInfo := range processst.ConConnectionInfo {
-		connection_ps := contrailOperatorTypes.Connection{
-			Type:   ConnectionInfo.Type,
-			Name:   ConnectionInfo.Name,
-			Status: ConnectionInfo.Status,
-			Nodes:  ConnectionInfo.Nodes,
-		}
-		connectionList = append(connectionList, connection_ps)
+// nodeProcessStatus returns status of the process reporting the NodeStatus. Contrail
+// processes report exactly one ProcessStatus about themselves.
+func nodeProcessStatus(nodeStatus *introspect.NodeStatus) introspect.ProcessStatus {
+	if len(nodeStatus.ProcessStatus) == 0 {
+		return introspect.ProcessStatus{}
 	}
+	return nodeStatus.ProcessStatus[0]
+}
 
-	controlStatus := contrailOperatorTypes.ControlServiceStatus{
-		Connections:              connectionList,
-		NumberOfXMPPPeers:        numUpXMPPPeer,
-		NumberOfRoutingInstances: numRoutingInstance,
-		StaticRoutes:             staticRoutes,
-		BGPPeer:                  bgpPeer,
-		State:                    processst.State,
+func processConnections(process introspect.ProcessStatus) []contrailOperatorTypes.Connection {
+	connections := []contrailOperatorTypes.Connection{}
+	for _, connectionInfo := range process.ConnectionInfos {
+		connections = append(connections, contrailOperatorTypes.Connection{
+			Type:   connectionInfo.Type,
+			Name:   connectionInfo.Name,
+			Status: connectionInfo.Status,
+			Nodes:  connectionInfo.ServerAddrs,
+		})
 	}
-
-	return &controlStatus, err
+	return connections
 }
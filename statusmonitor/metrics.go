@@ -0,0 +1,219 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+const metricsNamespace = "contrail"
+
+// statusMetrics keeps the last collected status as Prometheus metrics
+type statusMetrics struct {
+	registry *prometheus.Registry
+
+	updates      *prometheus.CounterVec
+	updateErrors *prometheus.CounterVec
+
+	processState    *prometheus.GaugeVec
+	connectionState *prometheus.GaugeVec
+
+	bgpPeers         *prometheus.GaugeVec
+	xmppPeers        *prometheus.GaugeVec
+	routingInstances *prometheus.GaugeVec
+	staticRoutes     *prometheus.GaugeVec
+
+	vrouterXMPPPeers   *prometheus.GaugeVec
+	vrouterInterfaces  *prometheus.GaugeVec
+	vrouterActiveFlows *prometheus.GaugeVec
+}
+
+var metrics = newStatusMetrics()
+
+func newStatusMetrics() *statusMetrics {
+	m := &statusMetrics{
+		registry: prometheus.NewRegistry(),
+		updates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "statusmonitor",
+			Name:      "updates_total",
+			Help:      "Number of status collections.",
+		}, []string{"node_type"}),
+		updateErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "statusmonitor",
+			Name:      "update_errors_total",
+			Help:      "Number of failed status collections.",
+		}, []string{"node_type"}),
+		processState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "process_state",
+			Help:      "State of the contrail process, set to 1 for the current state.",
+		}, []string{"node_type", "hostname", "module", "state"}),
+		connectionState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "connection_up",
+			Help:      "Whether the connection of the contrail process is up.",
+		}, []string{"node_type", "hostname", "module", "type", "name"}),
+		bgpPeers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "control",
+			Name:      "bgp_peers",
+			Help:      "Number of BGP peers of the control node by state.",
+		}, []string{"hostname", "state"}),
+		xmppPeers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "control",
+			Name:      "xmpp_peers",
+			Help:      "Number of XMPP peers of the control node.",
+		}, []string{"hostname"}),
+		routingInstances: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "control",
+			Name:      "routing_instances",
+			Help:      "Number of routing instances of the control node.",
+		}, []string{"hostname"}),
+		staticRoutes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "control",
+			Name:      "static_routes",
+			Help:      "Number of static routes of the control node by state.",
+		}, []string{"hostname", "state"}),
+		vrouterXMPPPeers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "vrouter",
+			Name:      "xmpp_peers",
+			Help:      "Number of XMPP sessions of the vRouter agent by state.",
+		}, []string{"hostname", "state"}),
+		vrouterInterfaces: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "vrouter",
+			Name:      "interfaces",
+			Help:      "Number of interfaces of the vRouter agent by state.",
+		}, []string{"hostname", "state"}),
+		vrouterActiveFlows: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "vrouter",
+			Name:      "active_flows",
+			Help:      "Number of active flows of the vRouter.",
+		}, []string{"hostname"}),
+	}
+	m.registry.MustRegister(m.updates, m.updateErrors, m.processState, m.connectionState,
+		m.bgpPeers, m.xmppPeers, m.routingInstances, m.staticRoutes,
+		m.vrouterXMPPPeers, m.vrouterInterfaces, m.vrouterActiveFlows)
+	return m
+}
+
+func (m *statusMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// serve exposes metrics on /metrics, it does nothing when port is not set
+func (m *statusMetrics) serve(port int) {
+	if port == 0 {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.handler())
+	go func() {
+		if err := http.ListenAndServe(":"+strconv.Itoa(port), mux); err != nil {
+			log.Printf("error: serving metrics failed: %v", err)
+		}
+	}()
+}
+
+func (m *statusMetrics) recordUpdate(nodeType NodeType, err error) {
+	m.updates.WithLabelValues(string(nodeType)).Inc()
+	if err != nil {
+		m.updateErrors.WithLabelValues(string(nodeType)).Inc()
+	}
+}
+
+func (m *statusMetrics) recordControlStatus(statusMap map[string]contrailOperatorTypes.ControlServiceStatus) {
+	m.processState.Reset()
+	m.connectionState.Reset()
+	for hostname, status := range statusMap {
+		m.recordProcess("control", hostname, "contrail-control", status.State, status.Connections)
+		bgpPeers := atoi(status.BGPPeer.Number)
+		bgpPeersUp := atoi(status.BGPPeer.Up)
+		m.bgpPeers.WithLabelValues(hostname, "up").Set(float64(bgpPeersUp))
+		m.bgpPeers.WithLabelValues(hostname, "down").Set(float64(bgpPeers - bgpPeersUp))
+		m.xmppPeers.WithLabelValues(hostname).Set(float64(atoi(status.NumberOfXMPPPeers)))
+		m.routingInstances.WithLabelValues(hostname).Set(float64(atoi(status.NumberOfRoutingInstances)))
+		staticRoutes := atoi(status.StaticRoutes.Number)
+		staticRoutesDown := atoi(status.StaticRoutes.Down)
+		m.staticRoutes.WithLabelValues(hostname, "up").Set(float64(staticRoutes - staticRoutesDown))
+		m.staticRoutes.WithLabelValues(hostname, "down").Set(float64(staticRoutesDown))
+	}
+}
+
+func (m *statusMetrics) recordConfigStatus(hostname string, statusMap map[string]contrailOperatorTypes.ConfigServiceStatus) {
+	m.processState.Reset()
+	for _, status := range statusMap {
+		m.recordProcess("config", hostname, status.ModuleName, status.ModuleState, nil)
+	}
+}
+
+// recordAnalyticsStatus does not reset process states, analytics services run in config
+// pods and are recorded after config services.
+func (m *statusMetrics) recordAnalyticsStatus(hostname string, statusMap map[string]contrailOperatorTypes.ConfigServiceStatus) {
+	for _, status := range statusMap {
+		m.recordProcess("analytics", hostname, status.ModuleName, status.ModuleState, nil)
+	}
+}
+
+func (m *statusMetrics) recordVrouterStatus(hostname string, status contrailOperatorTypes.VrouterServiceStatus, activeFlows int) {
+	m.processState.Reset()
+	m.connectionState.Reset()
+	m.recordProcess("vrouter", hostname, "contrail-vrouter-agent", status.State, status.Connections)
+	xmppPeersUp := 0
+	for _, peer := range status.XMPPPeers {
+		if peer.Status == "Up" {
+			xmppPeersUp++
+		}
+	}
+	m.vrouterXMPPPeers.WithLabelValues(hostname, "up").Set(float64(xmppPeersUp))
+	m.vrouterXMPPPeers.WithLabelValues(hostname, "down").Set(float64(len(status.XMPPPeers) - xmppPeersUp))
+	interfaces := atoi(status.NumberOfInterfaces)
+	interfacesDown := atoi(status.NumberOfDownInterfaces)
+	m.vrouterInterfaces.WithLabelValues(hostname, "up").Set(float64(interfaces - interfacesDown))
+	m.vrouterInterfaces.WithLabelValues(hostname, "down").Set(float64(interfacesDown))
+	m.vrouterActiveFlows.WithLabelValues(hostname).Set(float64(activeFlows))
+}
+
+func (m *statusMetrics) recordDatabaseStatus(hostname string, status contrailOperatorTypes.CassandraServiceStatus) {
+	m.processState.Reset()
+	m.connectionState.Reset()
+	m.recordProcess("database", hostname, status.ModuleName, status.ModuleState, status.Connections)
+}
+
+func (m *statusMetrics) recordWebuiStatus(hostname string, statusMap contrailOperatorTypes.WebUIServiceStatusMap) {
+	m.processState.Reset()
+	for _, status := range statusMap {
+		m.recordProcess("webui", hostname, WebuiContainerServiceNameMap[status.ModuleName], status.ModuleState, nil)
+	}
+}
+
+func (m *statusMetrics) recordProcess(nodeType, hostname, module, state string, connections []contrailOperatorTypes.Connection) {
+	m.processState.WithLabelValues(nodeType, hostname, module, state).Set(1)
+	for _, connection := range connections {
+		up := 0.0
+		if connection.Status == "Up" {
+			up = 1
+		}
+		m.connectionState.WithLabelValues(nodeType, hostname, module, connection.Type, connection.Name).Set(up)
+	}
+}
+
+func atoi(value string) int {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return number
+}
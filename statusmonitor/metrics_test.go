@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+func scrapeMetrics(t *testing.T, m *statusMetrics) string {
+	recorder := httptest.NewRecorder()
+	m.handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestControlMetrics(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"BgpRouterState": routerIntrospectData(),
+		"NodeStatus":     processIntrospectData(),
+	})
+	defer server.Close()
+	controlStatusMap := map[string]contrailOperatorTypes.ControlServiceStatus{}
+	require.NoError(t, GetControlStatusFromApiServer(server.Listener.Addr().String(), &Config{}, server.Client(), []string{"control1"}, controlStatusMap))
+	controlStatus := controlStatusMap["control1"]
+	controlStatus.BGPPeer.Up = "2"
+	m := newStatusMetrics()
+
+	m.recordControlStatus(map[string]contrailOperatorTypes.ControlServiceStatus{"control1": controlStatus})
+	m.recordUpdate("control", nil)
+
+	metricsBody := scrapeMetrics(t, m)
+	assert.Contains(t, metricsBody, `contrail_control_bgp_peers{hostname="control1",state="up"} 2`)
+	assert.Contains(t, metricsBody, `contrail_control_bgp_peers{hostname="control1",state="down"} 1`)
+	assert.Contains(t, metricsBody, `contrail_control_xmpp_peers{hostname="control1"} 1`)
+	assert.Contains(t, metricsBody, `contrail_control_routing_instances{hostname="control1"} 387`)
+	assert.Contains(t, metricsBody, `contrail_control_static_routes{hostname="control1",state="up"} 1`)
+	assert.Contains(t, metricsBody, `contrail_process_state{hostname="control1",module="contrail-control",node_type="control",state="Functional"} 1`)
+	assert.Contains(t, metricsBody, `contrail_connection_up{hostname="control1",module="contrail-control",name="Cassandra",node_type="control",type="Database"} 1`)
+	assert.Contains(t, metricsBody, `contrail_statusmonitor_updates_total{node_type="control"} 1`)
+}
+
+func TestProcessStateMetricsAreReplaced(t *testing.T) {
+	m := newStatusMetrics()
+
+	m.recordConfigStatus("config1", map[string]contrailOperatorTypes.ConfigServiceStatus{
+		"api": {ModuleName: "contrail-api", ModuleState: "initializing"},
+	})
+	m.recordConfigStatus("config1", map[string]contrailOperatorTypes.ConfigServiceStatus{
+		"api": {ModuleName: "contrail-api", ModuleState: "Functional"},
+	})
+
+	metricsBody := scrapeMetrics(t, m)
+	assert.Contains(t, metricsBody, `contrail_process_state{hostname="config1",module="contrail-api",node_type="config",state="Functional"} 1`)
+	assert.NotContains(t, metricsBody, `state="initializing"`)
+}
+
+func TestVrouterMetrics(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"VrouterAgent":      vrouterAgentIntrospectData(),
+		"VrouterStatsAgent": vrouterStatsIntrospectData(),
+		"NodeStatus":        processIntrospectData(),
+	})
+	defer server.Close()
+	vrouterStatus, stats, err := getVrouterStatusFromApiServer(server.Listener.Addr().String(), server.Client())
+	require.NoError(t, err)
+	m := newStatusMetrics()
+
+	m.recordVrouterStatus("compute1", *vrouterStatus, stats.ActiveFlows)
+
+	metricsBody := scrapeMetrics(t, m)
+	assert.Contains(t, metricsBody, `contrail_vrouter_xmpp_peers{hostname="compute1",state="up"} 1`)
+	assert.Contains(t, metricsBody, `contrail_vrouter_xmpp_peers{hostname="compute1",state="down"} 1`)
+	assert.Contains(t, metricsBody, `contrail_vrouter_interfaces{hostname="compute1",state="up"} 11`)
+	assert.Contains(t, metricsBody, `contrail_vrouter_active_flows{hostname="compute1"} 42`)
+}
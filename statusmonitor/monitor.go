@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"gopkg.in/fsnotify.v1"
+	yaml "gopkg.in/yaml.v2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	maxBackoff = 5 * time.Minute
+	// Kubernetes updates mounted ConfigMaps by swapping the ..data symlink
+	configMapDataLink = "..data"
+)
+
+// backoff returns delay before the next status collection. The delay grows
+// exponentially after consecutive failures up to the max delay.
+type backoff struct {
+	initial  time.Duration
+	max      time.Duration
+	failures uint
+}
+
+func (b *backoff) next(err error) time.Duration {
+	if err == nil {
+		b.failures = 0
+		return b.initial
+	}
+	b.failures++
+	delay := b.initial
+	for i := uint(0); i < b.failures && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	return delay
+}
+
+// monitor periodically collects status of the node and reloads its config when
+// the config file changes.
+type monitor struct {
+	configPath      string
+	defaultInterval time.Duration
+	config          Config
+	client          http.Client
+	clientset       *kubernetes.Clientset
+	restClient      *rest.RESTClient
+	backoff         backoff
+}
+
+// newMonitor waits until config can be read and clients are created, failures are
+// retried with backoff.
+func newMonitor(configPath string, defaultInterval time.Duration) *monitor {
+	m := &monitor{
+		configPath:      configPath,
+		defaultInterval: defaultInterval,
+		backoff:         backoff{initial: defaultInterval, max: maxBackoff},
+	}
+	retry := backoff{initial: time.Second, max: time.Minute}
+	for {
+		err := m.init()
+		if err == nil {
+			return m
+		}
+		delay := retry.next(err)
+		log.Printf("warning: status monitor initialization failed, retrying in %v: %v", delay, err)
+		time.Sleep(delay)
+	}
+}
+
+func (m *monitor) init() error {
+	config, err := loadConfig(m.configPath)
+	if err != nil {
+		return err
+	}
+	client, err := CreateRestClient(config)
+	if err != nil {
+		return fmt.Errorf("rest client creation failed: %v", err)
+	}
+	clientset, restClient, err := kubeClient(config)
+	if err != nil {
+		return fmt.Errorf("kubernetes client creation failed: %v", err)
+	}
+	m.client, m.clientset, m.restClient = client, clientset, restClient
+	m.setConfig(config)
+	return nil
+}
+
+func loadConfig(path string) (Config, error) {
+	config := Config{}
+	configYaml, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("reading config failed: %v", err)
+	}
+	if err = yaml.Unmarshal(configYaml, &config); err != nil {
+		return config, fmt.Errorf("parsing config failed: %v", err)
+	}
+	return config, nil
+}
+
+func (m *monitor) setConfig(config Config) {
+	m.config = config
+	m.backoff.initial = m.defaultInterval
+	if config.Interval > 0 {
+		m.backoff.initial = time.Duration(config.Interval) * time.Second
+	}
+}
+
+// reloadConfig applies the config file when it changed. An invalid config is
+// logged and the previous one is kept.
+func (m *monitor) reloadConfig() {
+	config, err := loadConfig(m.configPath)
+	if err != nil {
+		log.Printf("warning: keeping previous config: %v", err)
+		return
+	}
+	if reflect.DeepEqual(config, m.config) {
+		return
+	}
+	if !reflect.DeepEqual(config.Encryption, m.config.Encryption) || config.Timeout != m.config.Timeout {
+		client, err := CreateRestClient(config)
+		if err != nil {
+			log.Printf("warning: keeping previous config, rest client creation failed: %v", err)
+			return
+		}
+		m.client = client
+	}
+	log.Printf("Config %s reloaded", m.configPath)
+	m.setConfig(config)
+}
+
+func (m *monitor) isConfigEvent(event fsnotify.Event) bool {
+	name := filepath.Base(event.Name)
+	return name == filepath.Base(m.configPath) || name == configMapDataLink
+}
+
+// run collects status until stop is closed
+func (m *monitor) run(stop <-chan struct{}) {
+	var events <-chan fsnotify.Event
+	var watchErrors <-chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		defer watcher.Close()
+		err = watcher.Add(filepath.Dir(m.configPath))
+		events, watchErrors = watcher.Events, watcher.Errors
+	}
+	if err != nil {
+		log.Printf("warning: watching config failed, config changes will not be reloaded: %v", err)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case event := <-events:
+			if m.isConfigEvent(event) {
+				m.reloadConfig()
+			}
+		case err := <-watchErrors:
+			log.Printf("warning: watching config failed: %v", err)
+		case <-timer.C:
+			err := m.collect()
+			metrics.recordUpdate(m.config.NodeType, err)
+			delay := m.backoff.next(err)
+			if err != nil {
+				log.Printf("warning: getting %s status failed, retrying in %v: %v", m.config.NodeType, delay, err)
+			}
+			timer.Reset(delay)
+		}
+	}
+}
+
+func (m *monitor) collect() error {
+	switch m.config.NodeType {
+	case "control":
+		return getControlStatus(m.client, m.clientset, m.restClient, m.config)
+	case "config":
+		// Analytics services run in config pods
+		if err := getConfigStatus(m.client, m.clientset, m.restClient, m.config); err != nil {
+			return err
+		}
+		return getAnalyticsStatus(m.client, m.clientset, m.restClient, m.config)
+	case "analytics":
+		return getAnalyticsStatus(m.client, m.clientset, m.restClient, m.config)
+	case "vrouter":
+		return getVrouterStatus(m.client, m.restClient, m.config)
+	case "webui":
+		return getWebuiStatus(m.client, m.clientset, m.restClient, m.config)
+	case "database":
+		return getDatabaseStatus(m.client, m.restClient, m.config)
+	}
+	return fmt.Errorf("unsupported node type %q", m.config.NodeType)
+}
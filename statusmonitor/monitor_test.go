@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/fsnotify.v1"
+)
+
+func TestBackoff(t *testing.T) {
+	b := backoff{initial: time.Second, max: 5 * time.Second}
+	failure := errors.New("failure")
+
+	assert.Equal(t, time.Second, b.next(nil))
+	assert.Equal(t, 2*time.Second, b.next(failure))
+	assert.Equal(t, 4*time.Second, b.next(failure))
+	assert.Equal(t, 5*time.Second, b.next(failure))
+	assert.Equal(t, 5*time.Second, b.next(failure))
+	assert.Equal(t, time.Second, b.next(nil))
+	assert.Equal(t, 2*time.Second, b.next(failure))
+}
+
+func TestLoadShippedConfig(t *testing.T) {
+	config, err := loadConfig("config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1:8088"}, config.APIServerList)
+	assert.Equal(t, NodeType("control"), config.NodeType)
+	require.NotNil(t, config.InCluster)
+	assert.False(t, *config.InCluster)
+	assert.Equal(t, 9181, config.MetricsPort)
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statusmonitor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configPath := filepath.Join(dir, "monitorconfig.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, ioutil.WriteFile(configPath, []byte(content), 0644))
+	}
+	writeConfig("nodeType: config\nhostname: host1\n")
+	config, err := loadConfig(configPath)
+	require.NoError(t, err)
+	m := &monitor{configPath: configPath, defaultInterval: 10 * time.Second, backoff: backoff{max: maxBackoff}}
+	m.setConfig(config)
+
+	t.Run("should use default interval when it is not set", func(t *testing.T) {
+		assert.Equal(t, 10*time.Second, m.backoff.initial)
+	})
+
+	t.Run("should apply changed config", func(t *testing.T) {
+		writeConfig("nodeType: config\nhostname: host2\ninterval: 30\n")
+		m.reloadConfig()
+		assert.Equal(t, "host2", m.config.Hostname)
+		assert.Equal(t, 30*time.Second, m.backoff.initial)
+	})
+
+	t.Run("should keep previous config when new one is invalid", func(t *testing.T) {
+		writeConfig("nodeType: [config\n")
+		m.reloadConfig()
+		assert.Equal(t, "host2", m.config.Hostname)
+	})
+
+	t.Run("should keep previous config when file is removed", func(t *testing.T) {
+		require.NoError(t, os.Remove(configPath))
+		m.reloadConfig()
+		assert.Equal(t, "host2", m.config.Hostname)
+	})
+}
+
+func TestIsConfigEvent(t *testing.T) {
+	m := &monitor{configPath: "/etc/contrailconfigmaps/monitorconfig.1.1.1.1.yaml"}
+	assert.True(t, m.isConfigEvent(fsnotify.Event{Name: "/etc/contrailconfigmaps/monitorconfig.1.1.1.1.yaml", Op: fsnotify.Write}))
+	assert.True(t, m.isConfigEvent(fsnotify.Event{Name: "/etc/contrailconfigmaps/..data", Op: fsnotify.Create}))
+	assert.False(t, m.isConfigEvent(fsnotify.Event{Name: "/etc/contrailconfigmaps/control.1.1.1.1", Op: fsnotify.Write}))
+}
+
+func TestCollectUnsupportedNodeType(t *testing.T) {
+	m := &monitor{config: Config{NodeType: "unknown"}}
+	assert.Error(t, m.collect())
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+// fakeAPIServer serves a single contrail custom resource and stores status updates of it
+type fakeAPIServer struct {
+	mu     sync.Mutex
+	path   string
+	object runtime.Object
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.TrimSuffix(req.URL.Path, "/status") != s.path {
+		http.NotFound(w, req)
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := json.Unmarshal(body, s.object); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.object); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func newFakeAPIServer(t *testing.T, resource string, object runtime.Object) (*httptest.Server, rest.Interface) {
+	require.NoError(t, contrailOperatorTypes.SchemeBuilder.AddToScheme(scheme.Scheme))
+	accessor := object.(metav1.Object)
+	server := httptest.NewServer(&fakeAPIServer{
+		path:   "/apis/contrail.juniper.net/v1alpha1/namespaces/" + accessor.GetNamespace() + "/" + resource + "/" + accessor.GetName(),
+		object: object,
+	})
+	restClient, err := rest.UnversionedRESTClientFor(&rest.Config{
+		Host:    server.URL,
+		APIPath: "/apis",
+		ContentConfig: rest.ContentConfig{
+			GroupVersion:         &schema.GroupVersion{Group: contrailOperatorTypes.SchemeGroupVersion.Group, Version: contrailOperatorTypes.SchemeGroupVersion.Version},
+			NegotiatedSerializer: serializer.WithoutConversionCodecFactory{CodecFactory: scheme.Codecs},
+		},
+	})
+	require.NoError(t, err)
+	return server, restClient
+}
+
+func newTestPod(nodeName string, containers map[string]bool) *core.Pod {
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+		Spec:       core.PodSpec{NodeName: nodeName},
+	}
+	for name, ready := range containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, core.ContainerStatus{Name: name, Ready: ready})
+	}
+	return pod
+}
+
+func TestGetWebuiStatusUpdatesWebuiStatus(t *testing.T) {
+	webui := &contrailOperatorTypes.Webui{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "contrail.juniper.net/v1alpha1", Kind: "Webui"},
+		ObjectMeta: metav1.ObjectMeta{Name: "webui1", Namespace: "default"},
+	}
+	server, restClient := newFakeAPIServer(t, "webuis", webui)
+	defer server.Close()
+	clientset := fake.NewSimpleClientset(newTestPod("node1", map[string]bool{
+		"webuiweb":      true,
+		"webuijob":      false,
+		"statusmonitor": true,
+	}))
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://1.1.7.1:8143/", req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		}
+	})
+	config := Config{
+		NodeName:      "webui1",
+		NodeType:      "webui",
+		Namespace:     "default",
+		PodName:       "pod-1",
+		Hostname:      "pod-1",
+		APIServerList: []string{"1.1.7.1:8143::contrail-webui"},
+	}
+
+	require.NoError(t, getWebuiStatus(*client, clientset, restClient, config))
+
+	assert.Equal(t, contrailOperatorTypes.WebUIServiceStatusMap{
+		"Webuiweb": {ModuleName: "webuiweb", ModuleState: "Functional"},
+		"Webuijob": {ModuleName: "webuijob", ModuleState: "Non-Functional"},
+	}, webui.Status.ServiceStatus["node1"])
+}
+
+func TestGetDatabaseStatusUpdatesCassandraStatus(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	require.NoError(t, closedListener.Close())
+	defer listener.Close()
+
+	tests := map[string]struct {
+		address       string
+		expectedState string
+	}{
+		"cassandra accepting CQL connections": {
+			address:       listener.Addr().String(),
+			expectedState: "Functional",
+		},
+		"cassandra not listening": {
+			address:       closedAddress,
+			expectedState: "connection-error",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cassandra := &contrailOperatorTypes.Cassandra{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "contrail.juniper.net/v1alpha1", Kind: "Cassandra"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cassandra1", Namespace: "default"},
+			}
+			server, restClient := newFakeAPIServer(t, "cassandras", cassandra)
+			defer server.Close()
+			config := Config{
+				NodeName:      "cassandra1",
+				NodeType:      "database",
+				Namespace:     "default",
+				Hostname:      "host1",
+				APIServerList: []string{test.address + "::cassandra"},
+			}
+
+			require.NoError(t, getDatabaseStatus(http.Client{}, restClient, config))
+
+			status := cassandra.Status.ServiceStatus["host1"]
+			assert.Equal(t, "cassandra", status.ModuleName)
+			assert.Equal(t, test.expectedState, status.ModuleState)
+		})
+	}
+}
+
+func TestGetAnalyticsStatusUpdatesConfigStatus(t *testing.T) {
+	config := &contrailOperatorTypes.Config{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "contrail.juniper.net/v1alpha1", Kind: "Config"},
+		ObjectMeta: metav1.ObjectMeta{Name: "config1", Namespace: "default"},
+	}
+	server, restClient := newFakeAPIServer(t, "configs", config)
+	defer server.Close()
+	clientset := fake.NewSimpleClientset(newTestPod("node1", map[string]bool{
+		"api":          true,
+		"analyticsapi": false,
+		"collector":    true,
+		"queryengine":  true,
+	}))
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Host == "1.1.1.1:8089" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBuffer(introspectData())),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		}
+	})
+	monitorConfig := Config{
+		NodeName:  "config1",
+		NodeType:  "config",
+		Namespace: "default",
+		PodName:   "pod-1",
+		Hostname:  "host1",
+		APIServerList: []string{
+			"1.1.1.1:8082::contrail-api",
+			"1.1.1.1:8089::contrail-collector",
+			"1.1.1.1:8091::contrail-query-engine",
+		},
+	}
+
+	require.NoError(t, getAnalyticsStatus(*client, clientset, restClient, monitorConfig))
+
+	assert.Equal(t, contrailOperatorTypes.ConfigServiceStatusMap{
+		"analyticsapi": {ModuleName: "contrail-analytics-api", ModuleState: "initializing"},
+		"collector":    {NodeName: "kind-control-plane", ModuleName: "contrail-collector", ModuleState: "Functional"},
+		"queryengine":  {ModuleName: "contrail-query-engine", ModuleState: "connection-error"},
+	}, config.Status.AnalyticsServiceStatus["host1"])
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+	"github.com/Juniper/contrail-operator/pkg/client/introspect"
+)
+
+func getVrouterStatus(client http.Client, restClient rest.Interface, config Config) error {
+	var lastErr error
+	for _, apiServer := range config.APIServerList {
+		vrouterStatus, stats, err := getVrouterStatusFromApiServer(apiServer, &client)
+		if err != nil {
+			log.Printf("warning: getting vrouter status from %s failed: %v", apiServer, err)
+			lastErr = err
+			continue
+		}
+		metrics.recordVrouterStatus(config.Hostname, *vrouterStatus, stats.ActiveFlows)
+		return updateVrouterStatus(&config, *vrouterStatus, restClient)
+	}
+	return lastErr
+}
+
+// getVrouterStatusFromApiServer returns the status of the vRouter agent and its flow
+// statistics. Statistics change on every poll, so they are only exported as metrics and
+// not written to the Vrouter status.
+func getVrouterStatusFromApiServer(apiServer string, client *http.Client) (*contrailOperatorTypes.VrouterServiceStatus, *introspect.VrouterStats, error) {
+	introspectClient := introspect.NewClient(introspect.NewConnector(apiServer, client))
+	agent, err := introspectClient.VrouterAgent()
+	if err != nil {
+		return nil, nil, err
+	}
+	stats, err := introspectClient.VrouterStats()
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeStatus, err := introspectClient.NodeStatus()
+	if err != nil {
+		return nil, nil, err
+	}
+	status := vrouterStatus(agent, nodeStatus)
+	return &status, stats, nil
+}
+
+func vrouterStatus(agent *introspect.VrouterAgent, nodeStatus *introspect.NodeStatus) contrailOperatorTypes.VrouterServiceStatus {
+	xmppPeers := []contrailOperatorTypes.VrouterXMPPPeer{}
+	for _, peer := range agent.XmppPeers {
+		status := "Down"
+		if peer.Status {
+			status = "Up"
+		}
+		xmppPeers = append(xmppPeers, contrailOperatorTypes.VrouterXMPPPeer{
+			IP:      peer.IP,
+			Status:  status,
+			Primary: peer.Primary,
+		})
+	}
+	process := nodeProcessStatus(nodeStatus)
+	return contrailOperatorTypes.VrouterServiceStatus{
+		Connections:            processConnections(process),
+		XMPPPeers:              xmppPeers,
+		NumberOfInterfaces:     strconv.Itoa(agent.TotalInterfaceCount),
+		NumberOfDownInterfaces: strconv.Itoa(agent.DownInterfaceCount),
+		State:                  process.State,
+	}
+}
+
+func updateVrouterStatus(config *Config, vrouterStatus contrailOperatorTypes.VrouterServiceStatus, restClient rest.Interface) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		vrouterClient := &vrouterClient{
+			ns:         config.Namespace,
+			restClient: restClient,
+		}
+		vrouterObject, err := vrouterClient.Get(config.NodeName, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error: updateVrouterStatus: Failed to get vrouter status %v", err)
+			return err
+		}
+		if reflect.DeepEqual(vrouterObject.Status.ServiceStatus[config.Hostname], vrouterStatus) {
+			return nil
+		}
+		if vrouterObject.Status.ServiceStatus == nil {
+			vrouterObject.Status.ServiceStatus = map[string]contrailOperatorTypes.VrouterServiceStatus{}
+		}
+		vrouterObject.Status.ServiceStatus[config.Hostname] = vrouterStatus
+		_, err = vrouterClient.UpdateStatus(config.NodeName, vrouterObject)
+		if err != nil {
+			log.Printf("error: updateVrouterStatus: Failed to update vrouter status %v", err)
+		}
+		return err
+	})
+	if retryErr != nil {
+		log.Printf("Error: Update retry failed: %v", retryErr)
+	}
+	return retryErr
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVrouterStatusFromApiServer(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"VrouterAgent":      vrouterAgentIntrospectData(),
+		"VrouterStatsAgent": vrouterStatsIntrospectData(),
+		"NodeStatus":        processIntrospectData(),
+	})
+	defer server.Close()
+
+	vrouterStatus, stats, err := getVrouterStatusFromApiServer(server.Listener.Addr().String(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "Functional", vrouterStatus.State)
+	assert.Equal(t, "12", vrouterStatus.NumberOfInterfaces)
+	assert.Equal(t, "1", vrouterStatus.NumberOfDownInterfaces)
+	require.Len(t, vrouterStatus.XMPPPeers, 2)
+	assert.Equal(t, "10.0.0.1", vrouterStatus.XMPPPeers[0].IP)
+	assert.Equal(t, "Up", vrouterStatus.XMPPPeers[0].Status)
+	assert.True(t, vrouterStatus.XMPPPeers[0].Primary)
+	assert.Equal(t, "10.0.0.2", vrouterStatus.XMPPPeers[1].IP)
+	assert.Equal(t, "Down", vrouterStatus.XMPPPeers[1].Status)
+	assert.False(t, vrouterStatus.XMPPPeers[1].Primary)
+	assert.Len(t, vrouterStatus.Connections, 3)
+	assert.Equal(t, 42, stats.ActiveFlows)
+}
+
+func TestGetVrouterStatusFromApiServerInvalidResponse(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"VrouterAgent":      []byte("<invalid"),
+		"VrouterStatsAgent": vrouterStatsIntrospectData(),
+		"NodeStatus":        processIntrospectData(),
+	})
+	defer server.Close()
+
+	_, _, err := getVrouterStatusFromApiServer(server.Listener.Addr().String(), server.Client())
+	assert.Error(t, err)
+}
+
+func TestGetVrouterStatusFromApiServerMissingStats(t *testing.T) {
+	server := newIntrospectServer(t, map[string][]byte{
+		"VrouterAgent": vrouterAgentIntrospectData(),
+		"NodeStatus":   processIntrospectData(),
+	})
+	defer server.Close()
+
+	_, _, err := getVrouterStatusFromApiServer(server.Listener.Addr().String(), server.Client())
+	assert.Error(t, err)
+}
+
+func vrouterAgentIntrospectData() []byte {
+	return []byte(`<?xml-stylesheet type="text/xsl" href="/universal_parse.xsl"?>
+<__UveVrouterAgent_list type="slist">
+  <UveVrouterAgent type="sandesh">
+    <data type="struct" identifier="1">
+      <VrouterAgent>
+        <name type="string" identifier="1" key="ObjectVRouter">compute-1</name>
+        <xmpp_peer_list type="list" identifier="19">
+          <list type="struct" size="2">
+            <AgentXmppPeer>
+              <ip type="string" identifier="1">10.0.0.1</ip>
+              <status type="bool" identifier="2">true</status>
+              <setup_time type="u64" identifier="3">1600000000000000</setup_time>
+              <primary type="bool" identifier="4">true</primary>
+            </AgentXmppPeer>
+            <AgentXmppPeer>
+              <ip type="string" identifier="1">10.0.0.2</ip>
+              <status type="bool" identifier="2">false</status>
+              <setup_time type="u64" identifier="3">0</setup_time>
+              <primary type="bool" identifier="4">false</primary>
+            </AgentXmppPeer>
+          </list>
+        </xmpp_peer_list>
+        <total_interface_count type="u32" identifier="49">12</total_interface_count>
+        <down_interface_count type="u32" identifier="50">1</down_interface_count>
+      </VrouterAgent>
+    </data>
+  </UveVrouterAgent>
+</__UveVrouterAgent_list>`)
+}
+
+func vrouterStatsIntrospectData() []byte {
+	return []byte(`<?xml-stylesheet type="text/xsl" href="/universal_parse.xsl"?>
+<__UveVrouterStatsAgent_list type="slist">
+  <UveVrouterStatsAgent type="sandesh">
+    <data type="struct" identifier="1">
+      <VrouterStatsAgent>
+        <name type="string" identifier="1" key="ObjectVRouter">compute-1</name>
+        <flow_rate type="struct" identifier="35">
+          <FlowRateInfo>
+            <added_flows type="u64" identifier="1">3</added_flows>
+            <deleted_flows type="u64" identifier="2">1</deleted_flows>
+            <active_flows type="u64" identifier="5">42</active_flows>
+          </FlowRateInfo>
+        </flow_rate>
+      </VrouterStatsAgent>
+    </data>
+  </UveVrouterStatsAgent>
+</__UveVrouterStatsAgent_list>`)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	contrailOperatorTypes "github.com/Juniper/contrail-operator/pkg/apis/contrail/v1alpha1"
+)
+
+var WebuiContainerServiceNameMap = map[string]string{
+	"webuiweb": "contrail-webui",
+	"webuijob": "contrail-webui-middleware",
+}
+
+func getWebuiStatus(client http.Client, clientset kubernetes.Interface, restClient rest.Interface, config Config) error {
+	pod, err := clientset.CoreV1().Pods(config.Namespace).Get(context.Background(), config.PodName, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("Getting pod failed: %s", err)
+		return err
+	}
+	serviceAddressMap := map[string]string{}
+	for _, servicePort := range config.APIServerList {
+		servicePortList := strings.Split(servicePort, "::")
+		if len(servicePortList) == 2 {
+			serviceAddressMap[servicePortList[1]] = servicePortList[0]
+		}
+	}
+	webuiStatusMap := contrailOperatorTypes.WebUIServiceStatusMap{}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		serviceFullName, ok := WebuiContainerServiceNameMap[containerStatus.Name]
+		if !ok {
+			continue
+		}
+		state := "Non-Functional"
+		if containerStatus.Ready {
+			state = "Functional"
+			if serviceAddress, ok := serviceAddressMap[serviceFullName]; ok {
+				state = getWebuiStateFromServer(serviceAddress, &client)
+			}
+		}
+		webuiStatusMap[strings.Title(containerStatus.Name)] = contrailOperatorTypes.WebUIServiceStatus{
+			ModuleName:  containerStatus.Name,
+			ModuleState: state,
+		}
+	}
+	metrics.recordWebuiStatus(pod.Spec.NodeName, webuiStatusMap)
+	return updateWebuiStatus(&config, pod.Spec.NodeName, webuiStatusMap, restClient)
+}
+
+// getWebuiStateFromServer checks if the webui server responds to requests
+func getWebuiStateFromServer(serviceAddress string, client *http.Client) string {
+	resp, err := client.Get("https://" + serviceAddress + "/")
+	if err != nil {
+		log.Printf("warning: to get status for webui address %s failed: %v", serviceAddress, err)
+		return "connection-error"
+	}
+	defer closeResp(resp)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "Non-Functional"
+	}
+	return "Functional"
+}
+
+type webuiClient struct {
+	restClient rest.Interface
+	ns         string
+}
+
+func (c *webuiClient) Get(name string, opts metav1.GetOptions) (*contrailOperatorTypes.Webui, error) {
+	result := contrailOperatorTypes.Webui{}
+	err := c.restClient.
+		Get().
+		Namespace(c.ns).
+		Resource("webuis").
+		Name(name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(context.Background()).
+		Into(&result)
+
+	return &result, err
+}
+
+func (c *webuiClient) UpdateStatus(name string, object *contrailOperatorTypes.Webui) (*contrailOperatorTypes.Webui, error) {
+	result := contrailOperatorTypes.Webui{}
+	err := c.restClient.
+		Put().
+		Namespace(c.ns).
+		Resource("webuis").
+		Name(name).
+		SubResource("status").
+		Body(object).
+		Do(context.Background()).
+		Into(&result)
+
+	return &result, err
+}
+
+func updateWebuiStatus(config *Config, nodeName string, statusMap contrailOperatorTypes.WebUIServiceStatusMap, restClient rest.Interface) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		webuiClient := &webuiClient{
+			ns:         config.Namespace,
+			restClient: restClient,
+		}
+		webuiObject, err := webuiClient.Get(config.NodeName, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error: updateWebuiStatus: Failed to get webui status %v", err)
+			return err
+		}
+		if reflect.DeepEqual(webuiObject.Status.ServiceStatus[nodeName], statusMap) {
+			return nil
+		}
+		if webuiObject.Status.ServiceStatus == nil {
+			webuiObject.Status.ServiceStatus = map[string]contrailOperatorTypes.WebUIServiceStatusMap{}
+		}
+		webuiObject.Status.ServiceStatus[nodeName] = statusMap
+		_, err = webuiClient.UpdateStatus(config.NodeName, webuiObject)
+		if err != nil {
+			log.Printf("error: updateWebuiStatus: Failed to update webui status %v", err)
+		}
+		return err
+	})
+	if retryErr != nil {
+		log.Printf("Error: Update retry failed: %v", retryErr)
+	}
+	return retryErr
+}